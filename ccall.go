@@ -8,6 +8,7 @@ package sys
 
 import (
 	"bytes"
+	"sync/atomic"
 	"unsafe"
 
 	"github.com/go-darwin/sys/unsafeheader"
@@ -176,6 +177,25 @@ func RawCcall6(fn, a1, a2, a3, a4, a5, a6 uintptr) (r1, r2 uintptr, err Errno) {
 //go:nosplit
 func RawCcall9(fn, a1, a2, a3, a4, a5, a6, a7, a8, a9 uintptr) (r1, r2 uintptr, err Errno)
 
+// TracedCcall9 is like RawCcall9, and reports the call to the syscall trace
+// set by SetSyscallTrace, if any.
+//
+// The report allocates, locks and writes to the trace, so TracedCcall9 must
+// not be called where only RawCcall9 is, such as in the child of a fork.
+func TracedCcall9(fn, a1, a2, a3, a4, a5, a6, a7, a8, a9 uintptr) (r1, r2 uintptr, err Errno) {
+	r1, r2, err = RawCcall9(fn, a1, a2, a3, a4, a5, a6, a7, a8, a9)
+	if atomic.LoadUint32(&syscallTraceOn) != 0 {
+		TraceSyscall(&SyscallCall{
+			Num:  fn,
+			Args: []uintptr{a1, a2, a3, a4, a5, a6, a7, a8, a9},
+			R1:   r1,
+			Err:  uintptr(err),
+		})
+	}
+
+	return r1, r2, err
+}
+
 // ByteSliceFromString returns a NUL-terminated slice of bytes
 // containing the text of s.
 func ByteSliceFromString(s string) []byte {
@@ -1,3 +1,6 @@
+//go:build amd64 && gc
+// +build amd64,gc
+
 package sys
 
 import "testing"
@@ -1,9 +1,6 @@
 // Copyright 2021 The Go Darwin Authors
 // SPDX-License-Identifier: BSD-3-Clause
 
-//go:build amd64 && gc
-// +build amd64,gc
-
 package sys
 
 // itoa converts val to a decimal string.
@@ -0,0 +1,122 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys
+
+import "runtime"
+
+// CKind is the representation class of a C scalar type.
+type CKind uint8
+
+// list of CKind.
+const (
+	CKindInt CKind = iota + 1
+	CKindFloat
+	CKindBool
+)
+
+// String returns a string representation of the CKind.
+func (k CKind) String() string {
+	switch k {
+	case CKindInt:
+		return "int"
+	case CKindFloat:
+		return "float"
+	case CKindBool:
+		return "bool"
+	default:
+		return "CKind(" + uitoa(uint(k)) + ")"
+	}
+}
+
+// CType describes the layout of a C scalar type on a GOOS/GOARCH target.
+type CType struct {
+	// Name is the C spelling of the type, such as "unsigned int".
+	Name string
+
+	// GoName is the name of the corresponding C_* type of this package.
+	GoName string
+
+	// Kind is the representation class of the type.
+	Kind CKind
+
+	// Size is the size of the type in bytes.
+	Size uintptr
+
+	// Align is the alignment of the type in bytes.
+	Align uintptr
+
+	// Signed reports whether the type is a signed type.
+	Signed bool
+}
+
+// ctypeTarget is the key of the C type registry.
+type ctypeTarget struct {
+	goos   string
+	goarch string
+}
+
+// ctypes is the C type registry of each supported GOOS/GOARCH target.
+//
+// All supported targets are LP64. They differ in the signedness of char
+// (unsigned on linux/arm64) and in the layout of long double (same as double
+// on darwin/arm64, 80-bit x87 on amd64 and IEEE quad on linux/arm64).
+var ctypes = map[ctypeTarget][]CType{
+	{"darwin", "amd64"}: lp64CTypes(true, 16, 16),
+	{"darwin", "arm64"}: lp64CTypes(true, 8, 8),
+	{"linux", "amd64"}:  lp64CTypes(true, 16, 16),
+	{"linux", "arm64"}:  lp64CTypes(false, 16, 16),
+}
+
+// lp64CTypes returns the C type descriptors of a LP64 target.
+func lp64CTypes(signedChar bool, longDoubleSize, longDoubleAlign uintptr) []CType {
+	return []CType{
+		{Name: "short", GoName: "C_short", Kind: CKindInt, Size: 2, Align: 2, Signed: true},
+		{Name: "int", GoName: "C_int", Kind: CKindInt, Size: 4, Align: 4, Signed: true},
+		{Name: "int8_t", GoName: "C_int8", Kind: CKindInt, Size: 1, Align: 1, Signed: true},
+		{Name: "int16_t", GoName: "C_int16", Kind: CKindInt, Size: 2, Align: 2, Signed: true},
+		{Name: "int32_t", GoName: "C_int32", Kind: CKindInt, Size: 4, Align: 4, Signed: true},
+		{Name: "int64_t", GoName: "C_int64", Kind: CKindInt, Size: 8, Align: 8, Signed: true},
+		{Name: "long", GoName: "C_long", Kind: CKindInt, Size: 8, Align: 8, Signed: true},
+		{Name: "long long", GoName: "C_longLong", Kind: CKindInt, Size: 8, Align: 8, Signed: true},
+		{Name: "unsigned int", GoName: "C_uint", Kind: CKindInt, Size: 4, Align: 4},
+		{Name: "uint8_t", GoName: "C_uint8", Kind: CKindInt, Size: 1, Align: 1},
+		{Name: "uint16_t", GoName: "C_uint16", Kind: CKindInt, Size: 2, Align: 2},
+		{Name: "uint32_t", GoName: "C_uint32", Kind: CKindInt, Size: 4, Align: 4},
+		{Name: "uint64_t", GoName: "C_uint64", Kind: CKindInt, Size: 8, Align: 8},
+		{Name: "char", GoName: "C_char", Kind: CKindInt, Size: 1, Align: 1, Signed: signedChar},
+		{Name: "float", GoName: "C_float", Kind: CKindFloat, Size: 4, Align: 4, Signed: true},
+		{Name: "double", GoName: "C_double", Kind: CKindFloat, Size: 8, Align: 8, Signed: true},
+		{Name: "long double", GoName: "C_long_double", Kind: CKindFloat, Size: longDoubleSize, Align: longDoubleAlign, Signed: true},
+		{Name: "size_t", GoName: "C_size_t", Kind: CKindInt, Size: 8, Align: 8},
+		{Name: "ssize_t", GoName: "C_ssize_t", Kind: CKindInt, Size: 8, Align: 8, Signed: true},
+		{Name: "uintptr_t", GoName: "C_uintptr_t", Kind: CKindInt, Size: 8, Align: 8},
+		{Name: "bool", GoName: "C_bool", Kind: CKindBool, Size: 1, Align: 1},
+	}
+}
+
+// CTypes returns the C type descriptors of the goos/goarch target.
+//
+// It returns nil if the target is not supported.
+// The returned slice must not be modified.
+func CTypes(goos, goarch string) []CType {
+	return ctypes[ctypeTarget{goos, goarch}]
+}
+
+// LookupCType returns the descriptor of the C type spelled name on the goos/goarch target.
+//
+// The name may be either the C spelling ("unsigned int") or the Go name ("C_uint").
+func LookupCType(goos, goarch, name string) (CType, bool) {
+	for _, t := range ctypes[ctypeTarget{goos, goarch}] {
+		if t.Name == name || t.GoName == name {
+			return t, true
+		}
+	}
+
+	return CType{}, false
+}
+
+// HostCType is like LookupCType but uses the runtime.GOOS and runtime.GOARCH target.
+func HostCType(name string) (CType, bool) {
+	return LookupCType(runtime.GOOS, runtime.GOARCH, name)
+}
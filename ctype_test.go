@@ -0,0 +1,157 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys_test
+
+import (
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/go-darwin/sys"
+)
+
+var hostCTypes = map[string]reflect.Type{
+	"C_short":       reflect.TypeOf((*sys.C_short)(nil)).Elem(),
+	"C_int":         reflect.TypeOf((*sys.C_int)(nil)).Elem(),
+	"C_int8":        reflect.TypeOf((*sys.C_int8)(nil)).Elem(),
+	"C_int16":       reflect.TypeOf((*sys.C_int16)(nil)).Elem(),
+	"C_int32":       reflect.TypeOf((*sys.C_int32)(nil)).Elem(),
+	"C_int64":       reflect.TypeOf((*sys.C_int64)(nil)).Elem(),
+	"C_long":        reflect.TypeOf((*sys.C_long)(nil)).Elem(),
+	"C_longLong":    reflect.TypeOf((*sys.C_longLong)(nil)).Elem(),
+	"C_uint":        reflect.TypeOf((*sys.C_uint)(nil)).Elem(),
+	"C_uint8":       reflect.TypeOf((*sys.C_uint8)(nil)).Elem(),
+	"C_uint16":      reflect.TypeOf((*sys.C_uint16)(nil)).Elem(),
+	"C_uint32":      reflect.TypeOf((*sys.C_uint32)(nil)).Elem(),
+	"C_uint64":      reflect.TypeOf((*sys.C_uint64)(nil)).Elem(),
+	"C_char":        reflect.TypeOf((*sys.C_char)(nil)).Elem(),
+	"C_float":       reflect.TypeOf((*sys.C_float)(nil)).Elem(),
+	"C_double":      reflect.TypeOf((*sys.C_double)(nil)).Elem(),
+	"C_size_t":      reflect.TypeOf((*sys.C_size_t)(nil)).Elem(),
+	"C_ssize_t":     reflect.TypeOf((*sys.C_ssize_t)(nil)).Elem(),
+	"C_uintptr_t":   reflect.TypeOf((*sys.C_uintptr_t)(nil)).Elem(),
+	"C_bool":        reflect.TypeOf((*sys.C_bool)(nil)).Elem(),
+	"C_long_double": reflect.TypeOf((*sys.C_long_double)(nil)).Elem(),
+}
+
+func TestHostCTypes(t *testing.T) {
+	ctypes := sys.CTypes(runtime.GOOS, runtime.GOARCH)
+	if len(ctypes) != len(hostCTypes) {
+		t.Fatalf("CTypes(%q, %q) has %d entries, want %d", runtime.GOOS, runtime.GOARCH, len(ctypes), len(hostCTypes))
+	}
+
+	for _, ct := range ctypes {
+		typ, ok := hostCTypes[ct.GoName]
+		if !ok {
+			t.Errorf("%s: unknown Go name %q", ct.Name, ct.GoName)
+			continue
+		}
+		if got := typ.Size(); got != ct.Size {
+			t.Errorf("%s: Go size is %d, registry says %d", ct.GoName, got, ct.Size)
+		}
+
+		switch typ.Kind() {
+		case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if ct.Kind != sys.CKindInt || !ct.Signed {
+				t.Errorf("%s: Go type is %s, registry says %s signed=%t", ct.GoName, typ.Kind(), ct.Kind, ct.Signed)
+			}
+		case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if ct.Kind != sys.CKindInt || ct.Signed {
+				t.Errorf("%s: Go type is %s, registry says %s signed=%t", ct.GoName, typ.Kind(), ct.Kind, ct.Signed)
+			}
+		case reflect.Float32, reflect.Float64:
+			if ct.Kind != sys.CKindFloat {
+				t.Errorf("%s: Go type is %s, registry says %s", ct.GoName, typ.Kind(), ct.Kind)
+			}
+		case reflect.Bool:
+			if ct.Kind != sys.CKindBool {
+				t.Errorf("%s: Go type is %s, registry says %s", ct.GoName, typ.Kind(), ct.Kind)
+			}
+		case reflect.Array:
+			// long double has no Go equivalent and is represented as opaque storage.
+			continue
+		}
+
+		if got := uintptr(typ.Align()); got != ct.Align {
+			t.Errorf("%s: Go alignment is %d, registry says %d", ct.GoName, got, ct.Align)
+		}
+	}
+}
+
+func TestLookupCType(t *testing.T) {
+	tests := []struct {
+		goos, goarch, name string
+		want               sys.CType
+		ok                 bool
+	}{
+		{
+			goos: "darwin", goarch: "amd64", name: "char",
+			want: sys.CType{Name: "char", GoName: "C_char", Kind: sys.CKindInt, Size: 1, Align: 1, Signed: true},
+			ok:   true,
+		},
+		{
+			goos: "darwin", goarch: "arm64", name: "char",
+			want: sys.CType{Name: "char", GoName: "C_char", Kind: sys.CKindInt, Size: 1, Align: 1, Signed: true},
+			ok:   true,
+		},
+		{
+			goos: "linux", goarch: "arm64", name: "C_char",
+			want: sys.CType{Name: "char", GoName: "C_char", Kind: sys.CKindInt, Size: 1, Align: 1},
+			ok:   true,
+		},
+		{
+			goos: "darwin", goarch: "arm64", name: "long double",
+			want: sys.CType{Name: "long double", GoName: "C_long_double", Kind: sys.CKindFloat, Size: 8, Align: 8, Signed: true},
+			ok:   true,
+		},
+		{
+			goos: "linux", goarch: "amd64", name: "long double",
+			want: sys.CType{Name: "long double", GoName: "C_long_double", Kind: sys.CKindFloat, Size: 16, Align: 16, Signed: true},
+			ok:   true,
+		},
+		{
+			goos: "darwin", goarch: "amd64", name: "ssize_t",
+			want: sys.CType{Name: "ssize_t", GoName: "C_ssize_t", Kind: sys.CKindInt, Size: 8, Align: 8, Signed: true},
+			ok:   true,
+		},
+		{
+			goos: "darwin", goarch: "amd64", name: "bool",
+			want: sys.CType{Name: "bool", GoName: "C_bool", Kind: sys.CKindBool, Size: 1, Align: 1},
+			ok:   true,
+		},
+		{goos: "darwin", goarch: "amd64", name: "wchar_t"},
+		{goos: "windows", goarch: "386", name: "int"},
+	}
+	for _, tt := range tests {
+		got, ok := sys.LookupCType(tt.goos, tt.goarch, tt.name)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("LookupCType(%q, %q, %q) = %+v, %t; want %+v, %t", tt.goos, tt.goarch, tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestSizeofCTypes(t *testing.T) {
+	tests := map[string]uintptr{
+		"C_short":       sys.Sizeof_C_short,
+		"C_int":         sys.Sizeof_C_int,
+		"C_int64":       sys.Sizeof_C_int64,
+		"C_long":        sys.Sizeof_C_long,
+		"C_char":        sys.Sizeof_C_char,
+		"C_float":       sys.Sizeof_C_float,
+		"C_double":      sys.Sizeof_C_double,
+		"C_size_t":      sys.Sizeof_C_size_t,
+		"C_bool":        sys.Sizeof_C_bool,
+		"C_long_double": sys.Sizeof_C_long_double,
+	}
+	for name, size := range tests {
+		ct, ok := sys.HostCType(name)
+		if !ok {
+			t.Errorf("HostCType(%q) not found", name)
+			continue
+		}
+		if size != ct.Size {
+			t.Errorf("Sizeof_%s = %d, want %d", name, size, ct.Size)
+		}
+	}
+}
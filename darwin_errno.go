@@ -0,0 +1,121 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys
+
+import (
+	"context"
+	"io/fs"
+	"os"
+)
+
+//go:generate go run ./internal/mkdarwinerrno
+
+// DarwinErrno is an errno value in the numbering of darwin, such as
+// DarwinEAGAIN (35), whatever the host is.
+//
+// It describes the errno of darwin data decoded on another host, such as a
+// core file or a syscall trace, and the darwin specific errno values which the
+// host Errno has no name for, such as DarwinEAUTH or DarwinENOATTR.
+//
+// DarwinErrno values can be tested against error values from the io/fs, os
+// and context packages using errors.Is, like Errno.
+type DarwinErrno uintptr
+
+// Error returns the error message of the DarwinErrno, such as
+// "resource temporarily unavailable".
+func (e DarwinErrno) Error() string {
+	if e < DarwinErrno(len(darwinErrors)) {
+		s := darwinErrors[e]
+		if s != "" {
+			return s
+		}
+	}
+
+	return "errno " + uitoa(uint(e))
+}
+
+// Is reports whether the DarwinErrno matches the target error.
+//
+// A target Errno matches when it is the host Errno of the same name.
+func (e DarwinErrno) Is(target error) bool {
+	switch target {
+	case fs.ErrPermission:
+		return e == DarwinEACCES || e == DarwinEPERM
+	case fs.ErrExist:
+		return e == DarwinEEXIST || e == DarwinENOTEMPTY
+	case fs.ErrNotExist:
+		return e == DarwinENOENT
+	case os.ErrDeadlineExceeded, context.DeadlineExceeded:
+		return e.Timeout()
+	}
+
+	if errno, ok := target.(Errno); ok {
+		host, ok := e.Errno()
+		return ok && host == errno
+	}
+
+	return false
+}
+
+// Temporary reports whether the operation may succeed if retried.
+func (e DarwinErrno) Temporary() bool {
+	return e == DarwinEINTR || e == DarwinEMFILE || e == DarwinENFILE || e.Timeout()
+}
+
+// Timeout reports whether the DarwinErrno is a timeout.
+func (e DarwinErrno) Timeout() bool {
+	return e == DarwinEAGAIN || e == DarwinEWOULDBLOCK || e == DarwinETIMEDOUT
+}
+
+// String returns the C constant name of the DarwinErrno, such as "EAGAIN".
+func (e DarwinErrno) String() string {
+	if e < DarwinErrno(len(darwinErrnoNames)) {
+		s := darwinErrnoNames[e]
+		if s != "" {
+			return s
+		}
+	}
+
+	return "errno(" + uitoa(uint(e)) + ")"
+}
+
+// GoString returns the C constant name of the DarwinErrno, so that %#v
+// prints EAGAIN rather than 35.
+func (e DarwinErrno) GoString() string {
+	return e.String()
+}
+
+// Errno returns the host Errno of the same name as the DarwinErrno, and
+// whether the host has one.
+//
+// On darwin, it is the Errno of the same value for every named DarwinErrno.
+func (e DarwinErrno) Errno() (Errno, bool) {
+	if e < DarwinErrno(len(darwinErrnoHost)) {
+		errno := darwinErrnoHost[e]
+		if errno != 0 {
+			return errno, true
+		}
+	}
+
+	return 0, false
+}
+
+// DarwinErrnoFromErrno returns the DarwinErrno of the same name as the host
+// Errno, and whether darwin has one.
+//
+// When several names share the value of errno on the host, such as ENOTSUP and
+// EOPNOTSUPP on linux, the lowest DarwinErrno of these names is returned.
+func DarwinErrnoFromErrno(errno Errno) (DarwinErrno, bool) {
+	if errno == 0 {
+		return 0, false
+	}
+
+	for i, host := range darwinErrnoHost {
+		if host == errno {
+			return DarwinErrno(i), true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,30 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build linux
+// +build linux
+
+package sys_test
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/go-darwin/sys"
+)
+
+func TestDarwinErrnoHostLinux(t *testing.T) {
+	if got := uintptr(sys.DarwinEAGAIN); got == uintptr(syscall.EAGAIN) {
+		t.Fatalf("DarwinEAGAIN = %d, the linux EAGAIN", got)
+	}
+	for _, e := range []sys.DarwinErrno{sys.DarwinEAUTH, sys.DarwinEBADRPC, sys.DarwinENOATTR, sys.DarwinEQFULL} {
+		if host, ok := e.Errno(); ok {
+			t.Errorf("%v.Errno() = %v, want no linux errno", e, host)
+		}
+	}
+	for _, host := range []syscall.Errno{syscall.ECHRNG, syscall.EKEYEXPIRED} {
+		if e, ok := sys.DarwinErrnoFromErrno(host); ok {
+			t.Errorf("DarwinErrnoFromErrno(%v) = %v, want no darwin errno", host, e)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys_test
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/go-darwin/sys"
+)
+
+func TestDarwinErrnoString(t *testing.T) {
+	tests := []struct {
+		err       sys.DarwinErrno
+		name, msg string
+	}{
+		{sys.DarwinEPERM, "EPERM", "operation not permitted"},
+		{sys.DarwinEAGAIN, "EAGAIN", "resource temporarily unavailable"},
+		{sys.DarwinEWOULDBLOCK, "EAGAIN", "resource temporarily unavailable"},
+		{sys.DarwinEAUTH, "EAUTH", "authentication error"},
+		{sys.DarwinEBADRPC, "EBADRPC", "RPC struct is bad"},
+		{sys.DarwinENOATTR, "ENOATTR", "attribute not found"},
+		{sys.DarwinENOLINK, "ENOLINK", "ENOLINK (Reserved)"},
+		{sys.DarwinEQFULL, "EQFULL", "interface output queue is full"},
+		{sys.DarwinErrno(0), "errno(0)", "errno 0"},
+		{sys.DarwinErrno(1000), "errno(1000)", "errno 1000"},
+	}
+	for _, tt := range tests {
+		if got := tt.err.String(); got != tt.name {
+			t.Errorf("DarwinErrno(%d).String() = %q, want %q", uintptr(tt.err), got, tt.name)
+		}
+		if got := fmt.Sprintf("%#v", tt.err); got != tt.name {
+			t.Errorf("Sprintf(%%#v, %d) = %q, want %q", uintptr(tt.err), got, tt.name)
+		}
+		if got := tt.err.Error(); got != tt.msg {
+			t.Errorf("DarwinErrno(%d).Error() = %q, want %q", uintptr(tt.err), got, tt.msg)
+		}
+	}
+}
+
+func TestDarwinErrnoIs(t *testing.T) {
+	tests := []struct {
+		err    sys.DarwinErrno
+		target error
+		want   bool
+	}{
+		{sys.DarwinENOENT, fs.ErrNotExist, true},
+		{sys.DarwinEACCES, fs.ErrPermission, true},
+		{sys.DarwinEPERM, fs.ErrPermission, true},
+		{sys.DarwinENOTEMPTY, fs.ErrExist, true},
+		{sys.DarwinETIMEDOUT, os.ErrDeadlineExceeded, true},
+		{sys.DarwinENOATTR, fs.ErrNotExist, false},
+		{sys.DarwinENOENT, syscall.ENOENT, true},
+		{sys.DarwinEAGAIN, syscall.EAGAIN, true},
+		{sys.DarwinEAGAIN, syscall.ENOENT, false},
+	}
+	for _, tt := range tests {
+		err := fmt.Errorf("getxattr: %w", tt.err)
+		if got := errors.Is(err, tt.target); got != tt.want {
+			t.Errorf("errors.Is(%v, %v) = %t, want %t", tt.err, tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestDarwinErrnoTemporary(t *testing.T) {
+	for _, e := range []sys.DarwinErrno{sys.DarwinEINTR, sys.DarwinEMFILE, sys.DarwinEAGAIN, sys.DarwinETIMEDOUT} {
+		if !e.Temporary() {
+			t.Errorf("%v.Temporary() = false, want true", e)
+		}
+	}
+	if sys.DarwinEAUTH.Temporary() {
+		t.Error("EAUTH.Temporary() = true, want false")
+	}
+	if !sys.DarwinEWOULDBLOCK.Timeout() || sys.DarwinEINTR.Timeout() {
+		t.Error("unexpected DarwinErrno.Timeout")
+	}
+}
+
+func TestDarwinErrnoHost(t *testing.T) {
+	tests := []struct {
+		err  sys.DarwinErrno
+		host syscall.Errno
+	}{
+		{sys.DarwinEPERM, syscall.EPERM},
+		{sys.DarwinENOENT, syscall.ENOENT},
+		{sys.DarwinEAGAIN, syscall.EAGAIN},
+		{sys.DarwinEDEADLK, syscall.EDEADLK},
+		{sys.DarwinENAMETOOLONG, syscall.ENAMETOOLONG},
+		{sys.DarwinENOTSUP, syscall.ENOTSUP},
+		{sys.DarwinETIMEDOUT, syscall.ETIMEDOUT},
+	}
+	for _, tt := range tests {
+		host, ok := tt.err.Errno()
+		if !ok || host != tt.host {
+			t.Errorf("%v.Errno() = %d, %t; want %d, true", tt.err, host, ok, tt.host)
+		}
+		e, ok := sys.DarwinErrnoFromErrno(tt.host)
+		if !ok || e != tt.err {
+			t.Errorf("DarwinErrnoFromErrno(%d) = %v, %t; want %v, true", tt.host, e, ok, tt.err)
+		}
+	}
+
+	if _, ok := sys.DarwinErrno(0).Errno(); ok {
+		t.Error("DarwinErrno(0).Errno() ok")
+	}
+	if _, ok := sys.DarwinErrnoFromErrno(0); ok {
+		t.Error("DarwinErrnoFromErrno(0) ok")
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build ignore
+// +build ignore
+
+/*
+Input to internal/mkztypes, which resolves the C declarations below against
+the layout fixture of each target to produce ztypes_<goos>_<goarch>.go.
+The file is also accepted by cmd/cgo -godefs on a darwin host.
+*/
+
+package sys
+
+/*
+#cgo CFLAGS: -mmacosx-version-min=12.0
+
+typedef long long long_long;
+typedef long double long_double;
+
+#include <stdlib.h>
+#include <sys/types.h>
+#include <stdint.h>
+#include <stdbool.h>
+#include <libproc.h>
+#include <mach/mach.h>
+*/
+import "C"
+
+type (
+	c_short       C.short
+	c_int         C.int
+	c_int8        C.int8_t
+	c_int16       C.int16_t
+	c_int32       C.int32_t
+	c_int64       C.int64_t
+	c_long        C.long
+	c_longLong    C.long_long
+	c_uint        C.uint
+	c_uint8       C.uint8_t
+	c_uint16      C.uint16_t
+	c_uint32      C.uint32_t
+	c_uint64      C.uint64_t
+	c_char        C.char
+	c_float       C.float
+	c_double      C.double
+	c_size_t      C.size_t
+	c_ssize_t     C.ssize_t
+	c_uintptr_t   C.uintptr_t
+	c_bool        C.bool
+	c_long_double C.long_double
+)
+
+type kernReturn C.kern_return_t
+
+type (
+	natural           C.natural_t
+	integer           C.integer_t
+	boolean           C.boolean_t
+	machPort          C.mach_port_t
+	machPortName      C.mach_port_name_t
+	task              C.task_t
+	threadAct         C.thread_act_t
+	vmAddress         C.vm_address_t
+	vmSize            C.vm_size_t
+	vmProt            C.vm_prot_t
+	machMsgTypeNumber C.mach_msg_type_number_t
+)
+
+const (
+	machPortNull machPort = C.MACH_PORT_NULL
+)
+
+const (
+	vmProtNone    vmProt = C.VM_PROT_NONE
+	vmProtRead    vmProt = C.VM_PROT_READ
+	vmProtWrite   vmProt = C.VM_PROT_WRITE
+	vmProtExecute vmProt = C.VM_PROT_EXECUTE
+	vmProtDefault vmProt = C.VM_PROT_DEFAULT
+	vmProtAll     vmProt = C.VM_PROT_ALL
+)
+
+type timeValue C.time_value_t
+
+type taskBasicInfo C.struct_task_basic_info
+
+type threadBasicInfo C.struct_thread_basic_info
+
+type vmStatistics64 C.struct_vm_statistics64
+
+const (
+	taskBasicInfoFlavor   = C.TASK_BASIC_INFO
+	threadBasicInfoFlavor = C.THREAD_BASIC_INFO
+	hostVMInfo64Flavor    = C.HOST_VM_INFO64
+)
+
+const (
+	taskBasicInfoCount   machMsgTypeNumber = C.TASK_BASIC_INFO_COUNT
+	threadBasicInfoCount machMsgTypeNumber = C.THREAD_BASIC_INFO_COUNT
+	hostVMInfo64Count    machMsgTypeNumber = C.HOST_VM_INFO64_COUNT
+)
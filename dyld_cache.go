@@ -0,0 +1,633 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// This file holds a reader of the dyld shared cache, which holds the system
+// dylibs of macOS 11 and later: the dylibs are not on the disk anymore, and
+// their symbols are read from the images of the cache. It reads the caches
+// split in subcaches of macOS 12 and later, and the .symbols file of their
+// local symbols.
+
+// list of the offsets of the fields of the dyld_cache_header of
+// mach-o/dyld_cache_format.h, of the dyld of macOS 13.
+const (
+	dyldCacheMappingOffset      = 16
+	dyldCacheMappingCount       = 20
+	dyldCacheImagesOffsetOld    = 24
+	dyldCacheImagesCountOld     = 28
+	dyldCacheLocalSymbolsOffset = 72
+	dyldCacheLocalSymbolsSize   = 80
+	dyldCacheUUID               = 88
+	dyldCachePlatform           = 216
+	dyldCacheSubCacheArray      = 392 // subCacheArrayOffset and subCacheArrayCount
+	dyldCacheSymbolFileUUID     = 400
+	dyldCacheImagesOffset       = 448
+	dyldCacheImagesCount        = 452
+	dyldCacheSubType            = 456
+	sizeofDyldCacheHeader       = 460
+
+	sizeofDyldCacheMappingInfo     = 32
+	sizeofDyldCacheImageInfo       = 32
+	sizeofDyldSubcacheEntryV1      = 24
+	sizeofDyldSubcacheEntry        = 56
+	sizeofDyldCacheLocalSymbolInfo = 24
+
+	// dyldCacheMaxCount bounds the counts of the tables of a cache.
+	dyldCacheMaxCount = 1 << 20
+)
+
+// DyldCacheFormatError reports a malformed dyld shared cache.
+type DyldCacheFormatError struct {
+	File string // suffix of the file, such as ".01", or "" for the main cache
+	Off  int64  // offset in the file
+	Msg  string // description of the defect
+}
+
+// Error implements error.
+func (e *DyldCacheFormatError) Error() string {
+	file := ""
+	if e.File != "" {
+		file = " " + e.File + " file"
+	}
+
+	return "sys: malformed dyld shared cache" + file + " at offset " + itoa(int(e.Off)) + ": " + e.Msg
+}
+
+// ErrImageNotFound is returned by the lookups of an image which is not in
+// the dyld shared cache.
+var ErrImageNotFound = errors.New("sys: image not in the dyld shared cache")
+
+// ErrNoLocalSymbols is returned by the lookups of the local symbols of a
+// dyld shared cache without them, such as the ones of the macOS 12 and later,
+// whose .symbols file is not installed.
+var ErrNoLocalSymbols = errors.New("sys: no local symbols in the dyld shared cache")
+
+// DyldCacheMapping is a mapping of a file of the dyld shared cache.
+type DyldCacheMapping struct {
+	Addr     uint64 // unslid address
+	Size     uint64
+	Offset   uint64 // in the file
+	MaxProt  VMProt
+	InitProt VMProt
+
+	// SubCache is the file of the mapping: 0 for the main cache, and the
+	// 1-based index of SubCaches otherwise.
+	SubCache int
+}
+
+// DyldCacheImage is an image of the dyld shared cache.
+type DyldCacheImage struct {
+	Path string // install name
+	Addr uint64 // unslid address of the mach_header
+}
+
+// DyldSubCache is a subcache of the dyld shared cache.
+type DyldSubCache struct {
+	UUID     [16]byte
+	VMOffset uint64 // of the subcache from the Base of the cache
+	Suffix   string // of the file name, such as ".01"
+}
+
+// DyldCache is a dyld shared cache, read from its files.
+//
+// The addresses of the cache are unslid: the cache is mapped in the
+// processes at Base plus the slide of the shared region, which the offsets
+// from Base of Lookup are relative to.
+type DyldCache struct {
+	Arch     string // such as "arm64e"
+	UUID     [16]byte
+	Platform uint32 // such as PLATFORM_MACOS
+
+	// Base is the address of the first mapping of the main cache.
+	Base uint64
+
+	Mappings  []DyldCacheMapping
+	Images    []DyldCacheImage
+	SubCaches []DyldSubCache
+
+	files   []io.ReaderAt // the main cache and the subcaches
+	symbols io.ReaderAt   // the file of the local symbols, or nil
+	closers []io.Closer
+
+	// the dyld_cache_local_symbols_info in symbols, with 64-bit entries
+	localSymbols [2]uint64
+	localFile    string
+	localEntry64 bool
+}
+
+// OpenDyldCache reads the dyld shared cache of the main cache file name,
+// such as /System/Volumes/Preboot/Cryptexes/OS/System/Library/dyld/dyld_shared_cache_arm64e
+// on macOS 13, and of its subcache files, name followed by the suffix of
+// the subcache. The local symbols are read from the name.symbols file if it
+// exists. The cache holds the files open until Close.
+func OpenDyldCache(name string) (*DyldCache, error) {
+	var closers []io.Closer
+	open := func(suffix string) (io.ReaderAt, error) {
+		f, err := os.Open(name + suffix)
+		if err != nil {
+			return nil, err
+		}
+		closers = append(closers, f)
+		return f, nil
+	}
+
+	r, err := open("")
+	if err != nil {
+		return nil, err
+	}
+	c, err := NewDyldCache(r, open)
+	if err != nil {
+		for _, f := range closers {
+			f.Close()
+		}
+		return nil, err
+	}
+	c.closers = closers
+
+	return c, nil
+}
+
+// Close closes the files of a cache opened by OpenDyldCache.
+func (c *DyldCache) Close() error {
+	var err error
+	for _, f := range c.closers {
+		if e := f.Close(); err == nil {
+			err = e
+		}
+	}
+	c.closers = nil
+
+	return err
+}
+
+// NewDyldCache reads the dyld shared cache of the main cache r, whose
+// subcaches and .symbols file are opened by open with their suffix, such as
+// ".01" or ".symbols". A .symbols file which open fails to open with an
+// os.ErrNotExist error is ignored.
+func NewDyldCache(r io.ReaderAt, open func(suffix string) (io.ReaderAt, error)) (*DyldCache, error) {
+	c := &DyldCache{}
+	hdr, err := readDyldCacheHeader(r, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := c.addFile(r, hdr, "", 0); err != nil {
+		return nil, err
+	}
+	le := binary.LittleEndian
+	has := func(field int) bool { return le.Uint32(hdr[dyldCacheMappingOffset:]) > uint32(field) }
+
+	c.Arch = strings.TrimLeft(cstring(hdr[7:16]), " ")
+	copy(c.UUID[:], hdr[dyldCacheUUID:])
+	c.Platform = le.Uint32(hdr[dyldCachePlatform:])
+	if len(c.Mappings) == 0 {
+		return nil, &DyldCacheFormatError{"", dyldCacheMappingCount, "no mapping"}
+	}
+	c.Base = c.Mappings[0].Addr
+
+	// the images
+	off, n := le.Uint32(hdr[dyldCacheImagesOffset:]), le.Uint32(hdr[dyldCacheImagesCount:])
+	if !has(dyldCacheImagesOffset) {
+		off, n = le.Uint32(hdr[dyldCacheImagesOffsetOld:]), le.Uint32(hdr[dyldCacheImagesCountOld:])
+	}
+	images, err := readDyldCacheTable(r, "", off, n, sizeofDyldCacheImageInfo)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < int(n); i++ {
+		b := images[i*sizeofDyldCacheImageInfo:]
+		pathOff := int64(le.Uint32(b[24:]))
+		path, err := readDyldCacheString(r, "", pathOff)
+		if err != nil {
+			return nil, err
+		}
+		c.Images = append(c.Images, DyldCacheImage{Path: path, Addr: le.Uint64(b)})
+	}
+
+	// the subcaches
+	if has(dyldCacheSubCacheArray) {
+		off, n := le.Uint32(hdr[dyldCacheSubCacheArray:]), le.Uint32(hdr[dyldCacheSubCacheArray+4:])
+		size := uint32(sizeofDyldSubcacheEntry)
+		if !has(dyldCacheSubType) {
+			size = sizeofDyldSubcacheEntryV1
+		}
+		entries, err := readDyldCacheTable(r, "", off, n, size)
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < int(n); i++ {
+			b := entries[i*int(size):]
+			sub := DyldSubCache{VMOffset: le.Uint64(b[16:]), Suffix: "." + itoa(i+1)}
+			copy(sub.UUID[:], b)
+			if size == sizeofDyldSubcacheEntry {
+				sub.Suffix = cstring(b[24:56])
+				// the suffix is appended to the file name of the main
+				// cache: it must not name another directory
+				if !strings.HasPrefix(sub.Suffix, ".") || strings.Contains(sub.Suffix, "/") {
+					return nil, &DyldCacheFormatError{"", int64(off) + int64(i)*int64(size) + 24, "invalid subcache suffix"}
+				}
+			}
+			c.SubCaches = append(c.SubCaches, sub)
+		}
+	}
+	for i, sub := range c.SubCaches {
+		f, err := open(sub.Suffix)
+		if err != nil {
+			return nil, err
+		}
+		subhdr, err := readDyldCacheHeader(f, sub.Suffix)
+		if err != nil {
+			return nil, err
+		}
+		if string(subhdr[dyldCacheUUID:dyldCacheUUID+16]) != string(sub.UUID[:]) {
+			return nil, &DyldCacheFormatError{sub.Suffix, dyldCacheUUID, "UUID mismatch with the main cache"}
+		}
+		if err := c.addFile(f, subhdr, sub.Suffix, i+1); err != nil {
+			return nil, err
+		}
+	}
+
+	// the local symbols, in the main cache or in the .symbols file
+	var symbolFileUUID [16]byte
+	if has(dyldCacheSymbolFileUUID) {
+		copy(symbolFileUUID[:], hdr[dyldCacheSymbolFileUUID:])
+	}
+	c.localEntry64 = has(dyldCacheSymbolFileUUID)
+	if symbolFileUUID == ([16]byte{}) {
+		c.symbols = r
+		c.localSymbols = [2]uint64{le.Uint64(hdr[dyldCacheLocalSymbolsOffset:]), le.Uint64(hdr[dyldCacheLocalSymbolsSize:])}
+		return c, nil
+	}
+	f, err := open(".symbols")
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	symhdr, err := readDyldCacheHeader(f, ".symbols")
+	if err != nil {
+		return nil, err
+	}
+	if string(symhdr[dyldCacheUUID:dyldCacheUUID+16]) != string(symbolFileUUID[:]) {
+		return nil, &DyldCacheFormatError{".symbols", dyldCacheUUID, "UUID mismatch with the main cache"}
+	}
+	c.symbols = f
+	c.localFile = ".symbols"
+	c.localSymbols = [2]uint64{le.Uint64(symhdr[dyldCacheLocalSymbolsOffset:]), le.Uint64(symhdr[dyldCacheLocalSymbolsSize:])}
+
+	return c, nil
+}
+
+// readDyldCacheHeader returns the dyld_cache_header of the file r of the
+// cache, zeroed past its mappingOffset.
+func readDyldCacheHeader(r io.ReaderAt, file string) ([]byte, error) {
+	hdr := make([]byte, sizeofDyldCacheHeader)
+	if err := readDyldCache(r, file, hdr[:dyldCacheMappingOffset+8], 0); err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(string(hdr), "dyld_v1 ") {
+		return nil, &DyldCacheFormatError{file, 0, "invalid magic"}
+	}
+	mappingOff := binary.LittleEndian.Uint32(hdr[dyldCacheMappingOffset:])
+	if mappingOff < dyldCacheMappingOffset+8 {
+		return nil, &DyldCacheFormatError{file, dyldCacheMappingOffset, "invalid mappingOffset"}
+	}
+	n := mappingOff
+	if n > sizeofDyldCacheHeader {
+		n = sizeofDyldCacheHeader
+	}
+	if err := readDyldCache(r, file, hdr[:n], 0); err != nil {
+		return nil, err
+	}
+
+	return hdr, nil
+}
+
+// addFile appends the file r of the subcache subcache of the cache, 0 for
+// the main cache, and the mappings of its header hdr to c.
+func (c *DyldCache) addFile(r io.ReaderAt, hdr []byte, file string, subcache int) error {
+	count := binary.LittleEndian.Uint32(hdr[dyldCacheMappingCount:])
+	mappings, err := readDyldCacheTable(r, file, binary.LittleEndian.Uint32(hdr[dyldCacheMappingOffset:]), count, sizeofDyldCacheMappingInfo)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < int(count); i++ {
+		b := mappings[i*sizeofDyldCacheMappingInfo:]
+		c.Mappings = append(c.Mappings, DyldCacheMapping{
+			Addr:     binary.LittleEndian.Uint64(b),
+			Size:     binary.LittleEndian.Uint64(b[8:]),
+			Offset:   binary.LittleEndian.Uint64(b[16:]),
+			MaxProt:  VMProt(binary.LittleEndian.Uint32(b[24:])),
+			InitProt: VMProt(binary.LittleEndian.Uint32(b[28:])),
+			SubCache: subcache,
+		})
+	}
+	c.files = append(c.files, r)
+
+	return nil
+}
+
+// readDyldCache reads b at off of the file r of the cache.
+func readDyldCache(r io.ReaderAt, file string, b []byte, off int64) error {
+	n, err := r.ReadAt(b, off)
+	if n == len(b) {
+		return nil
+	}
+	if err == nil || err == io.EOF {
+		return &DyldCacheFormatError{file, off, "data out of the file"}
+	}
+
+	return err
+}
+
+// readDyldCacheTable reads the n entries of size bytes at off of the file r
+// of the cache.
+func readDyldCacheTable(r io.ReaderAt, file string, off, n, size uint32) ([]byte, error) {
+	if n > dyldCacheMaxCount {
+		return nil, &DyldCacheFormatError{file, int64(off), "too many entries"}
+	}
+	if n == 0 {
+		return nil, nil
+	}
+	// the counts come from the file: its last byte is read before the
+	// allocation of the table, bounded by the size of the file
+	var last [1]byte
+	if err := readDyldCache(r, file, last[:], int64(off)+int64(n)*int64(size)-1); err != nil {
+		return nil, err
+	}
+	b := make([]byte, n*size)
+	if err := readDyldCache(r, file, b, int64(off)); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// readDyldCacheString reads the NUL-terminated string at off of the file r
+// of the cache.
+func readDyldCacheString(r io.ReaderAt, file string, off int64) (string, error) {
+	var s []byte
+	var buf [64]byte
+	for len(s) < 1<<12 {
+		n, err := r.ReadAt(buf[:], off+int64(len(s)))
+		for i, c := range buf[:n] {
+			if c == 0 {
+				return string(append(s, buf[:i]...)), nil
+			}
+		}
+		s = append(s, buf[:n]...)
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return "", err
+		}
+	}
+
+	return "", &DyldCacheFormatError{file, off, "unterminated string"}
+}
+
+// readVM reads b at the unslid address addr of the cache, in a single
+// mapping, and returns io.EOF out of the mappings.
+func (c *DyldCache) readVM(b []byte, addr uint64) error {
+	for _, m := range c.Mappings {
+		if m.Addr <= addr && addr+uint64(len(b)) <= m.Addr+m.Size {
+			return readDyldCache(c.files[m.SubCache], c.suffix(m.SubCache), b, int64(m.Offset+addr-m.Addr))
+		}
+	}
+
+	return io.EOF
+}
+
+// suffix returns the suffix of the file of the subcache i of a
+// DyldCacheMapping, "" for the main cache.
+func (c *DyldCache) suffix(i int) string {
+	if i == 0 {
+		return ""
+	}
+
+	return c.SubCaches[i-1].Suffix
+}
+
+// dyldCacheReader reads the memory of the cache c from the unslid address
+// base.
+type dyldCacheReader struct {
+	c    *DyldCache
+	base uint64
+}
+
+// ReadAt implements io.ReaderAt.
+func (r dyldCacheReader) ReadAt(p []byte, off int64) (int, error) {
+	if err := r.c.readVM(p, r.base+uint64(off)); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// readable returns the number of bytes readable at off, to the end of its
+// mapping, or 0 out of the mappings.
+func (r dyldCacheReader) readable(off int64) int64 {
+	addr := r.base + uint64(off)
+	for _, m := range r.c.Mappings {
+		if m.Addr <= addr && addr < m.Addr+m.Size {
+			return int64(m.Addr + m.Size - addr)
+		}
+	}
+
+	return 0
+}
+
+// image returns the image of the install name or base name name.
+func (c *DyldCache) image(name string) (*DyldCacheImage, error) {
+	for i, img := range c.Images {
+		if img.Path == name || path.Base(img.Path) == name {
+			return &c.Images[i], nil
+		}
+	}
+
+	return nil, ErrImageNotFound
+}
+
+// Image returns the Mach-O image of the install name or base name name,
+// such as "/usr/lib/system/libsystem_c.dylib" or "libsystem_c.dylib". Its
+// Base is the unslid address of its mach_header.
+//
+// The images of the cache have no fixup chains, the cache being fixed up by
+// dyld.
+func (c *DyldCache) Image(name string) (*MachOImage, error) {
+	img, err := c.image(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// the load commands are bounded by the mapping of the mach_header
+	r := dyldCacheReader{c, img.Addr}
+	return newMachOImage(r, r.readable(0), true)
+}
+
+// maxDyldCacheReexports bounds the reexports followed by Lookup.
+const maxDyldCacheReexports = 8
+
+// Lookup returns the offset from Base of the symbol symbol, of the form
+// "image!name", such as "libsystem_c.dylib!strlen", where image is the
+// install name or the base name of an image, and name the C name of the
+// symbol. The reexports are followed to their image, and the local
+// symbols of image are looked up for the names it does not export.
+//
+// Without an image, as "strlen", the symbol is looked up in the exports of
+// the images in their order, like a flat lookup of dyld.
+func (c *DyldCache) Lookup(symbol string) (uint64, error) {
+	i := strings.LastIndexByte(symbol, '!')
+	if i >= 0 {
+		return c.lookup(symbol[:i], symbol[i+1:], true, 0)
+	}
+
+	for _, img := range c.Images {
+		off, err := c.lookup(img.Path, symbol, false, 0)
+		if !errors.Is(err, ErrSymbolNotFound) {
+			return off, err
+		}
+	}
+
+	return 0, ErrSymbolNotFound
+}
+
+func (c *DyldCache) lookup(image, name string, locals bool, depth int) (uint64, error) {
+	m, err := c.Image(image)
+	if err != nil {
+		return 0, err
+	}
+	s, err := m.Lookup(name)
+	if errors.Is(err, ErrSymbolNotFound) && locals {
+		s, err = c.lookupLocal(m, "_"+name)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	switch s.Kind {
+	case MachOSymbolReexport:
+		if depth == maxDyldCacheReexports {
+			return 0, errors.New("sys: too many reexports of " + name)
+		}
+		if s.ImportName != "" {
+			name = strings.TrimPrefix(s.ImportName, "_")
+		}
+		return c.lookup(s.Dylib, name, false, depth+1)
+	case MachOSymbolAbsolute:
+		return 0, errors.New("sys: " + name + " is an absolute symbol of " + image)
+	}
+
+	return m.Address(s) - c.Base, nil
+}
+
+// lookupLocal returns the local symbol of the Mach-O name name of m.
+func (c *DyldCache) lookupLocal(m *MachOImage, name string) (*MachOSymbol, error) {
+	syms, err := c.localSymbolsOf(m.Base)
+	if errors.Is(err, ErrNoLocalSymbols) {
+		return nil, ErrSymbolNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range syms {
+		if s.Name == name {
+			return s, nil
+		}
+	}
+
+	return nil, ErrSymbolNotFound
+}
+
+// LocalSymbols returns the local symbols of the image of the install name
+// or base name name, which the cache strips from the symbol table of the
+// image.
+func (c *DyldCache) LocalSymbols(name string) ([]*MachOSymbol, error) {
+	img, err := c.image(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.localSymbolsOf(img.Addr)
+}
+
+// localSymbolsOf returns the local symbols of the image at addr.
+func (c *DyldCache) localSymbolsOf(addr uint64) ([]*MachOSymbol, error) {
+	if c.symbols == nil || c.localSymbols[1] == 0 {
+		return nil, ErrNoLocalSymbols
+	}
+	le := binary.LittleEndian
+	base := int64(c.localSymbols[0])
+	var info [sizeofDyldCacheLocalSymbolInfo]byte
+	if err := readDyldCache(c.symbols, c.localFile, info[:], base); err != nil {
+		return nil, err
+	}
+	nlistOff, nlistCount := le.Uint32(info[0:]), le.Uint32(info[4:])
+	stringsOff, stringsSize := le.Uint32(info[8:]), le.Uint32(info[12:])
+	entriesOff, entriesCount := le.Uint32(info[16:]), le.Uint32(info[20:])
+
+	// the dyld_cache_local_symbols_entry of the image
+	size := uint32(12)
+	if c.localEntry64 {
+		size = 16
+	}
+	entries, err := readDyldCacheTable(c.symbols, c.localFile, uint32(base)+entriesOff, entriesCount, size)
+	if err != nil {
+		return nil, err
+	}
+	var start, count uint32
+	found := false
+	for i := 0; i < int(entriesCount); i++ {
+		b := entries[i*int(size):]
+		dylibOff := uint64(le.Uint32(b))
+		if c.localEntry64 {
+			dylibOff = le.Uint64(b)
+		}
+		if dylibOff == addr-c.Base {
+			start, count = le.Uint32(b[size-8:]), le.Uint32(b[size-4:])
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, nil
+	}
+	if uint64(start)+uint64(count) > uint64(nlistCount) {
+		return nil, &DyldCacheFormatError{c.localFile, base + int64(entriesOff), "local symbols out of the symbol table"}
+	}
+
+	nlist, err := readDyldCacheTable(c.symbols, c.localFile, uint32(base)+nlistOff+start*sizeofNlist64, count, sizeofNlist64)
+	if err != nil {
+		return nil, err
+	}
+	var syms []*MachOSymbol
+	for i := 0; i < int(count); i++ {
+		b := nlist[i*sizeofNlist64:]
+		typ := b[4]
+		if typ&nStab != 0 || typ&nType != nSect {
+			continue
+		}
+		strx := le.Uint32(b)
+		if strx >= stringsSize {
+			return nil, &DyldCacheFormatError{c.localFile, base + int64(nlistOff) + int64(start+uint32(i))*sizeofNlist64, "symbol name out of the string table"}
+		}
+		name, err := readDyldCacheString(c.symbols, c.localFile, base+int64(stringsOff)+int64(strx))
+		if err != nil {
+			return nil, err
+		}
+		syms = append(syms, &MachOSymbol{Name: name, Offset: le.Uint64(b[8:]) - addr})
+	}
+
+	return syms, nil
+}
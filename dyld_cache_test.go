@@ -0,0 +1,272 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/go-darwin/sys"
+)
+
+const dyldCacheFixture = "dyld_shared_cache_arm64e"
+
+func TestDyldCache(t *testing.T) {
+	c, err := sys.OpenDyldCache(machoFixture(dyldCacheFixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if c.Arch != "arm64e" || c.Platform != 1 || c.Base != 0x180000000 || c.UUID[0] != 0xc0 {
+		t.Errorf("arch %q, platform %d, base %#x, UUID %x", c.Arch, c.Platform, c.Base, c.UUID)
+	}
+	wantMappings := []sys.DyldCacheMapping{
+		{Addr: 0x180000000, Size: 0x2000, MaxProt: 5, InitProt: 5},
+		{Addr: 0x180004000, Size: 0x2000, MaxProt: 5, InitProt: 5, SubCache: 1},
+		{Addr: 0x180008000, Size: 0x1000, Offset: 0x1000, MaxProt: 1, InitProt: 1, SubCache: 2},
+	}
+	if !reflect.DeepEqual(c.Mappings, wantMappings) {
+		t.Errorf("Mappings = %+v, want %+v", c.Mappings, wantMappings)
+	}
+	wantImages := []sys.DyldCacheImage{
+		{Path: "/usr/lib/system/libsystem_c.dylib", Addr: 0x180001000},
+		{Path: "/usr/lib/system/libsystem_kernel.dylib", Addr: 0x180005000},
+	}
+	if !reflect.DeepEqual(c.Images, wantImages) {
+		t.Errorf("Images = %+v, want %+v", c.Images, wantImages)
+	}
+	if len(c.SubCaches) != 2 || c.SubCaches[0].Suffix != ".01" || c.SubCaches[1].Suffix != ".02" ||
+		c.SubCaches[1].VMOffset != 0x8000 || c.SubCaches[1].UUID[0] != 0xc2 {
+		t.Errorf("SubCaches = %+v", c.SubCaches)
+	}
+
+	tests := []struct {
+		symbol string
+		off    uint64
+		err    error
+	}{
+		{"libsystem_c.dylib!strlen", 0x1200, nil},
+		{"/usr/lib/system/libsystem_c.dylib!strcpy", 0x1210, nil},
+		{"libsystem_kernel.dylib!write", 0x5200, nil},
+		// reexports of libsystem_kernel
+		{"libsystem_c.dylib!write", 0x5200, nil},
+		{"libsystem_c.dylib!c_getpid", 0x5210, nil},
+		// local symbols
+		{"libsystem_c.dylib!strlen_slow", 0x1280, nil},
+		{"libsystem_kernel.dylib!kernel_trap", 0x5280, nil},
+		// flat lookups, of the exports only
+		{"strlen", 0x1200, nil},
+		{"getpid", 0x5210, nil},
+		{"strlen_slow", 0, sys.ErrSymbolNotFound},
+		{"libsystem_c.dylib!getpid", 0, sys.ErrSymbolNotFound},
+		{"libsystem_c.dylib!_strlen", 0, sys.ErrSymbolNotFound},
+		{"libsystem_m.dylib!sqrt", 0, sys.ErrImageNotFound},
+		{"system_c.dylib!strlen", 0, sys.ErrImageNotFound},
+	}
+	for _, tt := range tests {
+		off, err := c.Lookup(tt.symbol)
+		if off != tt.off || !errors.Is(err, tt.err) {
+			t.Errorf("Lookup(%q) = %#x, %v, want %#x, %v", tt.symbol, off, err, tt.off, tt.err)
+		}
+	}
+
+	locals, err := c.LocalSymbols("libsystem_kernel.dylib")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []*sys.MachOSymbol{{Name: "_kernel_trap", Offset: 0x280}}; !reflect.DeepEqual(locals, want) {
+		t.Errorf("LocalSymbols = %+v, want %+v", locals, want)
+	}
+}
+
+func TestDyldCacheImage(t *testing.T) {
+	c, err := sys.OpenDyldCache(machoFixture(dyldCacheFixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	m, err := c.Image("libsystem_c.dylib")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Name != "/usr/lib/system/libsystem_c.dylib" || m.Base != 0x180001000 || m.CPUSubtype != 2 {
+		t.Errorf("name %q, base %#x, cpusubtype %d", m.Name, m.Base, m.CPUSubtype)
+	}
+	if want := []string{"/usr/lib/system/libsystem_kernel.dylib"}; !reflect.DeepEqual(m.Dylibs, want) {
+		t.Errorf("Dylibs = %q, want %q", m.Dylibs, want)
+	}
+	exports, err := m.Exports()
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make(map[string]sys.MachOSymbolKind)
+	for _, s := range exports {
+		names[s.Name] = s.Kind
+	}
+	want := map[string]sys.MachOSymbolKind{
+		"_strlen":   sys.MachOSymbolRegular,
+		"_strcpy":   sys.MachOSymbolRegular,
+		"_write":    sys.MachOSymbolReexport,
+		"_c_getpid": sys.MachOSymbolReexport,
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("Exports = %v, want %v", names, want)
+	}
+	if _, err := m.Fixups(); err == nil {
+		t.Error("Fixups of a cache image succeeded")
+	}
+}
+
+// TestDyldCacheImageSizeofcmds checks that the load commands of an image of
+// the cache are bounded by its mapping before their allocation.
+func TestDyldCacheImageSizeofcmds(t *testing.T) {
+	files := dyldCacheFiles(t)
+	c, err := newDyldCache(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := c.Image("libsystem_c.dylib")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range c.Mappings {
+		if m.Addr <= img.Base && img.Base < m.Addr+m.Size {
+			suffix := ""
+			if m.SubCache > 0 {
+				suffix = c.SubCaches[m.SubCache-1].Suffix
+			}
+			binary.LittleEndian.PutUint32(files[suffix][m.Offset+img.Base-m.Addr+20:], 0x48000000)
+		}
+	}
+
+	c, err = newDyldCache(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	_, err = c.Lookup("libsystem_c.dylib!strlen")
+	runtime.ReadMemStats(&after)
+	var fe *sys.MachOFormatError
+	if !errors.As(err, &fe) {
+		t.Errorf("error %v, want a *MachOFormatError", err)
+	}
+	if n := after.TotalAlloc - before.TotalAlloc; n > 1<<20 {
+		t.Errorf("Lookup allocated %d bytes", n)
+	}
+}
+
+// dyldCacheFiles returns the files of the cache fixture by suffix.
+func dyldCacheFiles(t *testing.T) map[string][]byte {
+	t.Helper()
+
+	files := make(map[string][]byte)
+	for _, suffix := range []string{"", ".01", ".02", ".symbols"} {
+		b, err := os.ReadFile(machoFixture(dyldCacheFixture + suffix))
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[suffix] = b
+	}
+
+	return files
+}
+
+func newDyldCache(files map[string][]byte) (*sys.DyldCache, error) {
+	return sys.NewDyldCache(bytes.NewReader(files[""]), func(suffix string) (io.ReaderAt, error) {
+		b, ok := files[suffix]
+		if !ok {
+			return nil, &os.PathError{Op: "open", Path: dyldCacheFixture + suffix, Err: os.ErrNotExist}
+		}
+		return bytes.NewReader(b), nil
+	})
+}
+
+func TestDyldCacheNoSymbols(t *testing.T) {
+	files := dyldCacheFiles(t)
+	delete(files, ".symbols")
+	c, err := newDyldCache(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if off, err := c.Lookup("libsystem_c.dylib!strlen"); off != 0x1200 || err != nil {
+		t.Errorf("Lookup(strlen) = %#x, %v", off, err)
+	}
+	if _, err := c.Lookup("libsystem_c.dylib!strlen_slow"); !errors.Is(err, sys.ErrSymbolNotFound) {
+		t.Errorf("Lookup(strlen_slow) = %v, want ErrSymbolNotFound", err)
+	}
+	if _, err := c.LocalSymbols("libsystem_c.dylib"); !errors.Is(err, sys.ErrNoLocalSymbols) {
+		t.Errorf("LocalSymbols = %v, want ErrNoLocalSymbols", err)
+	}
+}
+
+// TestDyldCacheTableSize checks that the counts of the tables past the file
+// are rejected before the allocation of the tables.
+func TestDyldCacheTableSize(t *testing.T) {
+	files := dyldCacheFiles(t)
+	// imagesCount, the largest count below dyldCacheMaxCount
+	binary.LittleEndian.PutUint32(files[""][452:], 1<<20)
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	_, err := newDyldCache(files)
+	runtime.ReadMemStats(&after)
+	var fe *sys.DyldCacheFormatError
+	if !errors.As(err, &fe) {
+		t.Errorf("error %v, want a *DyldCacheFormatError", err)
+	}
+	if n := after.TotalAlloc - before.TotalAlloc; n > 1<<20 {
+		t.Errorf("NewDyldCache allocated %d bytes", n)
+	}
+}
+
+func TestDyldCacheError(t *testing.T) {
+	if _, err := sys.OpenDyldCache(machoFixture("dyld_shared_cache_x86_64")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("OpenDyldCache of a missing cache: %v", err)
+	}
+
+	files := dyldCacheFiles(t)
+	delete(files, ".02")
+	if _, err := newDyldCache(files); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("missing subcache: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		corrupt func(files map[string][]byte)
+	}{
+		{"magic", func(files map[string][]byte) { files[""][0] = 'D' }},
+		{"subcache magic", func(files map[string][]byte) { files[".01"][0] = 'D' }},
+		{"subcache UUID", func(files map[string][]byte) { files[".02"] = files[".01"] }},
+		{"symbols UUID", func(files map[string][]byte) { files[".symbols"][88] = 0 }},
+		{"truncated", func(files map[string][]byte) { files[""] = files[""][:0x250] }},
+		{"mappingOffset", func(files map[string][]byte) { files[""][16] = 8; files[""][17] = 0 }},
+		{"images count", func(files map[string][]byte) { files[""][452+3] = 0x10 }},
+		{"subcache suffix", func(files map[string][]byte) {
+			// the suffix of the first subcache entry, at subCacheArrayOffset
+			copy(files[""][608+24:], "/../../x\x00")
+		}},
+		{"subcache suffix without dot", func(files map[string][]byte) { files[""][608+24] = '_' }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := dyldCacheFiles(t)
+			tt.corrupt(files)
+			_, err := newDyldCache(files)
+			var fe *sys.DyldCacheFormatError
+			if !errors.As(err, &fe) {
+				t.Errorf("error %v, want a *DyldCacheFormatError", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// readDarwinFixture returns the value of testdata/dir/name, such as the
+// one of a sysctl in testdata/sysctl, in the layout of a 64-bit darwin host.
+func readDarwinFixture(t *testing.T, dir, name string) []byte {
+	t.Helper()
+
+	b, err := os.ReadFile(filepath.Join("testdata", dir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
@@ -0,0 +1,239 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// sdkVersion returns the Version of the SDKSettings.json, or else of the
+// SDKSettings.plist, of the SDK, or "" if the SDK has neither.
+func sdkVersion(sdk string) (string, error) {
+	b, err := os.ReadFile(filepath.Join(sdk, "SDKSettings.json"))
+	if err == nil {
+		var settings struct{ Version string }
+		if err := json.Unmarshal(b, &settings); err != nil {
+			return "", fmt.Errorf("%s: %v", filepath.Join(sdk, "SDKSettings.json"), err)
+		}
+		return settings.Version, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return "", err
+	}
+
+	b, err = os.ReadFile(filepath.Join(sdk, "SDKSettings.plist"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	m := plistVersionRe.FindSubmatch(b)
+	if m == nil {
+		return "", errors.New(filepath.Join(sdk, "SDKSettings.plist") + ": no Version")
+	}
+
+	return string(m[1]), nil
+}
+
+var plistVersionRe = regexp.MustCompile(`<key>Version</key>\s*<string>([^<]*)</string>`)
+
+// compareVersions returns -1, 0 or +1 as the dotted version a is lower than,
+// equal to or greater than b, the missing components being zeros.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return +1
+		}
+	}
+
+	return 0
+}
+
+// introduced returns the macOS version introducing the Mach-O symbol sym,
+// if the headers of the SDK annotate it with a version later than the
+// minimum one, or "".
+//
+// The stubs of an SDK newer than the minimum version export the symbols of
+// the later versions too: the headers are only read for such an SDK, or one
+// without version.
+func (c *checker) introduced(sym string) (string, error) {
+	if c.available == nil {
+		if err := c.loadAvailability(); err != nil {
+			return "", err
+		}
+	}
+	if v := c.available[sym]; v != "" && compareVersions(v, c.minOS) > 0 {
+		return v, nil
+	}
+
+	return "", nil
+}
+
+// loadAvailability reads the availability annotations of the headers of
+// the SDK, unless its version is the minimum one or an older one.
+func (c *checker) loadAvailability() error {
+	c.available = make(map[string]string)
+	v, err := sdkVersion(c.sdk)
+	if err != nil {
+		return err
+	}
+	if v != "" && compareVersions(v, c.minOS) <= 0 {
+		return nil
+	}
+
+	for _, dir := range []string{"usr/include", "System/Library/Frameworks"} {
+		err := filepath.Walk(filepath.Join(c.sdk, filepath.FromSlash(dir)), func(path string, fi os.FileInfo, err error) error {
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					return nil
+				}
+				return err
+			}
+			if fi.IsDir() || !strings.HasSuffix(path, ".h") {
+				return nil
+			}
+			b, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			for sym, v := range headerAvailability(string(b)) {
+				if _, ok := c.available[sym]; !ok {
+					c.available[sym] = v
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var (
+	commentRe = regexp.MustCompile(`(?s)/\*.*?\*/|//[^\n]*`)
+
+	// the versions of macos(x.y), macosx(x.y), __OSX_AVAILABLE(x.y) and
+	// __OSX_AVAILABLE_STARTING(__MAC_x_y, ...) and its deprecated variants
+	macosRe    = regexp.MustCompile(`\bmacosx?\s*\(\s*([0-9]+(?:\.[0-9]+)*)\s*\)`)
+	osxRe      = regexp.MustCompile(`\b__OSX_AVAILABLE\s*\(\s*([0-9]+(?:\.[0-9]+)*)\s*\)`)
+	osxStartRe = regexp.MustCompile(`\b__OSX_AVAILABLE_(?:STARTING|BUT_DEPRECATED(?:_MSG)?)\s*\(\s*__MAC_([0-9]+(?:_[0-9]+)*)\b`)
+
+	macroCallRe = regexp.MustCompile(`\b(?:_*[A-Z][A-Z0-9_]*|__attribute__|__asm__|__asm)\s*\(`)
+	funcNameRe  = regexp.MustCompile(`([A-Za-z_][A-Za-z0-9_]*)\s*\(`)
+	identRe     = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+)
+
+// headerAvailability returns the versions introducing the functions and
+// the extern variables of the C header src, by Mach-O symbol, as their
+// declarations annotate them.
+//
+// The declarations are found by their shape, not parsed: the declarations
+// of the Objective-C methods and of the typedefs, and the annotations of
+// whole regions such as API_AVAILABLE_BEGIN, are ignored.
+func headerAvailability(src string) map[string]string {
+	src = commentRe.ReplaceAllString(src, " ")
+	var b strings.Builder
+	continued := false
+	for _, line := range strings.Split(src, "\n") {
+		directive := continued || strings.HasPrefix(strings.TrimSpace(line), "#")
+		continued = directive && strings.HasSuffix(line, "\\")
+		if !directive {
+			b.WriteString(line)
+			b.WriteByte('\n')
+		}
+	}
+
+	avail := make(map[string]string)
+	for _, decl := range strings.FieldsFunc(b.String(), func(r rune) bool {
+		return r == ';' || r == '{' || r == '}'
+	}) {
+		decl = strings.TrimSpace(decl)
+		v := declAvailability(decl)
+		if v == "" || strings.HasPrefix(decl, "typedef") || strings.HasPrefix(decl, "-") || strings.HasPrefix(decl, "+") || strings.Contains(decl, "@") {
+			continue
+		}
+		if name := declName(decl); name != "" {
+			if _, ok := avail["_"+name]; !ok {
+				avail["_"+name] = v
+			}
+		}
+	}
+
+	return avail
+}
+
+// declAvailability returns the macOS version of the availability
+// annotation of the declaration decl, or "".
+func declAvailability(decl string) string {
+	if m := macosRe.FindStringSubmatch(decl); m != nil {
+		return m[1]
+	}
+	if m := osxRe.FindStringSubmatch(decl); m != nil {
+		return m[1]
+	}
+	if m := osxStartRe.FindStringSubmatch(decl); m != nil {
+		return strings.ReplaceAll(m[1], "_", ".")
+	}
+
+	return ""
+}
+
+// declName returns the name of the function or the extern variable
+// declared by decl, or "".
+func declName(decl string) string {
+	// drop the macros and the attributes with their arguments
+	for {
+		loc := macroCallRe.FindStringIndex(decl)
+		if loc == nil {
+			break
+		}
+		end, depth := loc[1], 1
+		for ; end < len(decl) && depth > 0; end++ {
+			switch decl[end] {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+		}
+		decl = decl[:loc[0]] + " " + decl[end:]
+	}
+
+	if i := strings.IndexByte(decl, '('); i >= 0 {
+		m := funcNameRe.FindStringSubmatchIndex(decl)
+		if m == nil || m[0] != strings.LastIndexAny(decl[:i], " \t\n*")+1 || strings.TrimSpace(decl[:m[2]]) == "" {
+			// a function pointer, or no return type
+			return ""
+		}
+		return decl[m[2]:m[3]]
+	}
+	if !strings.HasPrefix(decl, "extern") {
+		return ""
+	}
+	idents := identRe.FindAllString(decl, -1)
+
+	return idents[len(idents)-1]
+}
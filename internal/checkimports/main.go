@@ -0,0 +1,356 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Command checkimports checks the symbols of the //go:cgo_import_dynamic
+// directives of Go packages against the TAPI text stubs of the macOS SDK,
+// the .tbd files of the v3 and v4 formats, for each target architecture and
+// the minimum macOS version, so that a binding naming a symbol libSystem
+// does not export fails the check rather than the launch of a program.
+//
+// A symbol exists on an architecture if its library, or a library it
+// reexports, exports it in the stubs, and no $ld$hide$os<version>$ symbol
+// hides it from the minimum version, unless a $ld$add$os<version>$ symbol
+// adds it. The stubs do not hold the versions which introduced the symbols,
+// so when the Version of the SDKSettings of the SDK is later than the
+// minimum version, as the one of xcrun usually is, a symbol must also not be
+// annotated in the headers of the SDK as introduced by a later version, with
+// API_AVAILABLE(macos(x.y)) and the like. Checking against the SDK of the
+// minimum version, such as MacOSX12.3.sdk, relies on the stubs alone.
+//
+// The minimum version is the -mmacosx-version-min of defs.go by default.
+//
+// Run from the repository root, with the directories of the packages, the
+// ones ending with /... being walked:
+//
+//	go run ./internal/checkimports -sdk $(xcrun --sdk macosx --show-sdk-path) ./...
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+var (
+	flagSDK    = flag.String("sdk", os.Getenv("SDKROOT"), "path of the macOS SDK, by default $SDKROOT or the one of xcrun on darwin")
+	flagGOARCH = flag.String("goarch", "amd64,arm64", "comma-separated list of the GOARCH values to check")
+	flagMacOS  = flag.String("macos", "", "minimum macOS version, by default the -mmacosx-version-min of -defs")
+	flagDefs   = flag.String("defs", "defs.go", "path of the defs.go file")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("checkimports: ")
+	flag.Parse()
+
+	sdk := *flagSDK
+	if sdk == "" && runtime.GOOS == "darwin" {
+		out, err := exec.Command("xcrun", "--sdk", "macosx", "--show-sdk-path").Output()
+		if err != nil {
+			log.Fatalf("xcrun: %v", err)
+		}
+		sdk = strings.TrimSpace(string(out))
+	}
+	if sdk == "" {
+		log.Fatal("no SDK: set -sdk or SDKROOT")
+	}
+	minOS := *flagMacOS
+	if minOS == "" {
+		var err error
+		if minOS, err = defsMinOS(*flagDefs); err != nil {
+			log.Fatal(err)
+		}
+	}
+	dirs := flag.Args()
+	if len(dirs) == 0 {
+		dirs = []string{"./..."}
+	}
+
+	imports, err := scan(dirs)
+	if err != nil {
+		log.Fatal(err)
+	}
+	c := newChecker(sdk, minOS)
+	if n := c.check(os.Stderr, imports, strings.Split(*flagGOARCH, ",")); n > 0 {
+		log.Fatalf("%d symbol(s) missing on macOS %s", n, minOS)
+	}
+}
+
+var minOSRe = regexp.MustCompile(`-mmacosx-version-min=([0-9.]+)`)
+
+// defsMinOS returns the -mmacosx-version-min of the CFLAGS of the defs file.
+func defsMinOS(defs string) (string, error) {
+	b, err := os.ReadFile(defs)
+	if err != nil {
+		return "", err
+	}
+	m := minOSRe.FindSubmatch(b)
+	if m == nil {
+		return "", errors.New(defs + ": no -mmacosx-version-min")
+	}
+
+	return string(m[1]), nil
+}
+
+// dynamicImport is a //go:cgo_import_dynamic directive of a symbol.
+type dynamicImport struct {
+	pos     string // file:line
+	symbol  string // Mach-O name, such as "_getpid"
+	library string // install name
+}
+
+// scan returns the //go:cgo_import_dynamic directives of the symbols of
+// the Go files of dirs, in the lexical order of the files.
+func scan(dirs []string) ([]dynamicImport, error) {
+	var imports []dynamicImport
+	for _, dir := range dirs {
+		walk := strings.HasSuffix(dir, "/...")
+		dir = strings.TrimSuffix(dir, "/...")
+		err := filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if fi.IsDir() {
+				if path == dir {
+					return nil
+				}
+				if !walk {
+					return filepath.SkipDir
+				}
+				name := fi.Name()
+				if name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+					return filepath.SkipDir
+				}
+				if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
+					// another module
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !strings.HasSuffix(path, ".go") {
+				return nil
+			}
+			imps, err := scanFile(path)
+			imports = append(imports, imps...)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return imports, nil
+}
+
+// scanFile returns the //go:cgo_import_dynamic directives of the symbols of
+// the Go file name.
+func scanFile(name string) ([]dynamicImport, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var imports []dynamicImport
+	s := bufio.NewScanner(f)
+	for num := 1; s.Scan(); num++ {
+		line := strings.TrimSpace(s.Text())
+		if !strings.HasPrefix(line, "//go:cgo_import_dynamic ") {
+			continue
+		}
+		pos := name + ":" + strconv.Itoa(num)
+		// //go:cgo_import_dynamic local remote "library"
+		fields := strings.Fields(line)[1:]
+		if len(fields) != 3 || fields[1] == "_" {
+			// not the import of a symbol from a library
+			continue
+		}
+		library, err := strconv.Unquote(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid library %s", pos, fields[2])
+		}
+		remote := fields[1]
+		if i := strings.IndexByte(remote, '#'); i >= 0 {
+			remote = remote[:i] // the version of an ELF symbol
+		}
+		imports = append(imports, dynamicImport{pos: pos, symbol: "_" + remote, library: library})
+	}
+
+	return imports, s.Err()
+}
+
+// archs are the architectures of the stubs of the GOARCH values, the arm64
+// binaries linking with the arm64e stubs of the SDK.
+var archs = map[string][]string{
+	"amd64": {"x86_64"},
+	"arm64": {"arm64", "arm64e"},
+}
+
+// checker checks the symbols against the stubs of an SDK.
+type checker struct {
+	sdk   string
+	minOS string
+
+	libs   map[string]*library // by install name
+	loaded map[string]error    // the stub files read
+
+	// the macOS versions introducing the symbols in the headers of an SDK
+	// newer than minOS, by Mach-O symbol, once read
+	available map[string]string
+}
+
+func newChecker(sdk, minOS string) *checker {
+	return &checker{
+		sdk:    sdk,
+		minOS:  minOS,
+		libs:   make(map[string]*library),
+		loaded: make(map[string]error),
+	}
+}
+
+// check writes the symbols of imports missing on the goarchs to w, and
+// returns their count.
+func (c *checker) check(w io.Writer, imports []dynamicImport, goarchs []string) int {
+	n := 0
+	for _, imp := range imports {
+		for _, goarch := range goarchs {
+			if err := c.checkSymbol(imp.library, goarch, imp.symbol); err != nil {
+				fmt.Fprintf(w, "%s: %s: %v\n", imp.pos, goarch, err)
+				n++
+			}
+		}
+	}
+
+	return n
+}
+
+// checkSymbol returns an error if the library of install name installName
+// does not export the Mach-O symbol sym on goarch at the minimum version.
+func (c *checker) checkSymbol(installName, goarch, sym string) error {
+	l, err := c.library(installName)
+	if err != nil {
+		return err
+	}
+	arch := ""
+	for _, a := range archs[goarch] {
+		for _, la := range l.archs {
+			if a == la && arch == "" {
+				arch = a
+			}
+		}
+	}
+	if arch == "" {
+		return fmt.Errorf("%s has no macOS target for %s in %s", installName, goarch, l.pos)
+	}
+
+	if ok, err := c.exports(installName, arch, "$ld$add$os"+c.minOS+"$"+sym); ok || err != nil {
+		return err
+	}
+	ok, err := c.exports(installName, arch, sym)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%s is not exported by %s on %s", sym, installName, arch)
+	}
+	if hidden, err := c.exports(installName, arch, "$ld$hide$os"+c.minOS+"$"+sym); hidden || err != nil {
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("%s of %s is hidden on macOS %s", sym, installName, c.minOS)
+	}
+	v, err := c.introduced(sym)
+	if err != nil {
+		return err
+	}
+	if v != "" {
+		return fmt.Errorf("%s of %s is introduced in macOS %s, after %s", sym, installName, v, c.minOS)
+	}
+
+	return nil
+}
+
+// exports reports whether the library of install name installName, or a
+// library it reexports, exports sym on arch.
+func (c *checker) exports(installName, arch, sym string) (bool, error) {
+	visited := make(map[string]bool)
+	var lookup func(name string) (bool, error)
+	lookup = func(name string) (bool, error) {
+		visited[name] = true
+		l, err := c.library(name)
+		if err != nil {
+			return false, err
+		}
+		if l.exports(arch, sym) {
+			return true, nil
+		}
+		for _, r := range l.reexported[arch] {
+			if visited[r] {
+				continue
+			}
+			if ok, err := lookup(r); ok || err != nil {
+				return ok, err
+			}
+		}
+		return false, nil
+	}
+
+	return lookup(installName)
+}
+
+// library returns the library of install name installName, of its stub in
+// the SDK unless a stub read before holds it, like the ones of the
+// libraries reexported by libSystem in libSystem.B.tbd.
+func (c *checker) library(installName string) (*library, error) {
+	if l, ok := c.libs[installName]; ok {
+		return l, nil
+	}
+	if !strings.HasPrefix(installName, "/") {
+		return nil, errors.New("no stub of the library " + installName + ", not an absolute install name")
+	}
+
+	file := filepath.Join(c.sdk, filepath.FromSlash(strings.TrimSuffix(installName, ".dylib")+".tbd"))
+	err, ok := c.loaded[file]
+	if !ok {
+		err = c.load(file)
+		c.loaded[file] = err
+	}
+	if err != nil {
+		return nil, err
+	}
+	l, ok := c.libs[installName]
+	if !ok {
+		return nil, fmt.Errorf("%s does not hold the stub of %s", file, installName)
+	}
+
+	return l, nil
+}
+
+// load reads the libraries of the stub file.
+func (c *checker) load(file string) error {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	libs, err := parseTBD(file, string(b))
+	if err != nil {
+		return err
+	}
+	for _, l := range libs {
+		if _, ok := c.libs[l.installName]; !ok {
+			c.libs[l.installName] = l
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,263 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const root = "../.."
+
+func TestParseTBD(t *testing.T) {
+	b, err := os.ReadFile("testdata/sdk/usr/lib/libSystem.B.tbd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	libs, err := parseTBD("libSystem.B.tbd", string(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(libs) != 3 {
+		t.Fatalf("%d libraries, want 3", len(libs))
+	}
+
+	c := libs[1]
+	if c.installName != "/usr/lib/system/libsystem_c.dylib" || c.pos != "libSystem.B.tbd:17" {
+		t.Errorf("install name %q at %s", c.installName, c.pos)
+	}
+	if want := []string{"x86_64", "arm64e"}; !reflect.DeepEqual(c.archs, want) {
+		t.Errorf("archs %q, want %q", c.archs, want)
+	}
+	for _, tt := range []struct {
+		arch, sym string
+		want      bool
+	}{
+		{"arm64e", "_strlen", true},
+		{"arm64e", "_strcpy", true},
+		{"arm64e", "$ld$hide$os12.0$_gets_hidden", true},
+		{"arm64e", "_malloc_weak", true},
+		{"x86_64", "_errno_tls", true},
+		{"x86_64", "_x86_only", true},
+		{"arm64e", "_x86_only", false},
+		{"x86_64", "_catalyst_only", false},
+		{"arm64e", "_getpid", false},
+	} {
+		if got := c.exports(tt.arch, tt.sym); got != tt.want {
+			t.Errorf("exports(%s, %s) = %v, want %v", tt.arch, tt.sym, got, tt.want)
+		}
+	}
+	if want := []string{"/usr/lib/system/libsystem_c.dylib", "/usr/lib/system/libsystem_kernel.dylib"}; !reflect.DeepEqual(libs[0].reexported["arm64e"], want) {
+		t.Errorf("reexported %q, want %q", libs[0].reexported["arm64e"], want)
+	}
+
+	b, err = os.ReadFile("testdata/sdk/usr/lib/libfoo.tbd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	libs, err = parseTBD("libfoo.tbd", string(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	foo := libs[0]
+	if foo.installName != "/usr/lib/libfoo.dylib" || !foo.exports("arm64e", "_foo_weak") || !foo.exports("arm64e", "_foo_arm") || foo.exports("x86_64", "_foo_arm") {
+		t.Errorf("libfoo.tbd: %+v", foo)
+	}
+}
+
+func TestParseTBDError(t *testing.T) {
+	for _, tt := range []struct {
+		src, err string
+	}{
+		{"--- !tapi-tbd\ntbd-version: 5\ninstall-name: /usr/lib/libfoo.dylib\n", `unsupported tbd-version "5"`},
+		{"--- !tapi-tbd-v2\ninstall-name: /usr/lib/libfoo.dylib\n", `unsupported text stub "!tapi-tbd-v2"`},
+		{"--- !tapi-tbd-v3\nplatform: macosx\n", "no install-name"},
+		{"--- !tapi-tbd-v3\nexports:\n  - archs: [ x86_64,\n", "line 3: unterminated flow collection"},
+		{"--- !tapi-tbd-v3\ninstall-name: 'foo\n", "line 2: unterminated quoted scalar"},
+		{"--- !tapi-tbd-v3\ninstall-name: foo\ninstall-name: bar\n", "line 3: duplicate key install-name"},
+		{"--- !tapi-tbd-v3\ninstall-name: foo\n    platform: macosx\n", "line 3: unexpected indentation"},
+		{"--- !tapi-tbd-v3\n- foo\n", "document is not a mapping"},
+	} {
+		_, err := parseTBD("x.tbd", tt.src)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("parseTBD(%q) = %v, want %q", tt.src, err, tt.err)
+		}
+	}
+}
+
+func TestYAML(t *testing.T) {
+	docs, err := splitYAML(`--- !tag
+a: 1
+b:
+  - c: [ x, 'y, z', "w\"" ] # comment
+    d: { e: f, 'g': [ ] }
+  -
+    h
+  - i
+l:
+- m
+n: |o p|
+...
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 1 || docs[0].tag != "!tag" {
+		t.Fatalf("documents %+v", docs)
+	}
+	v, err := docs[0].parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"a": "1",
+		"b": []interface{}{
+			map[string]interface{}{
+				"c": []interface{}{"x", "y, z", `w"`},
+				"d": map[string]interface{}{"e": "f", "g": []interface{}{}},
+			},
+			"h",
+			"i",
+		},
+		"l": []interface{}{"m"},
+		"n": "|o p|",
+	}
+	if !reflect.DeepEqual(v, want) {
+		t.Errorf("parse = %#v, want %#v", v, want)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	imports, err := scan([]string{"testdata/src/..."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(imports) != 15 {
+		t.Fatalf("%d imports, want 15", len(imports))
+	}
+
+	var out bytes.Buffer
+	c := newChecker(filepath.Join("testdata", "sdk"), "12.0")
+	n := c.check(&out, imports, []string{"amd64", "arm64"})
+	got := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	want := []string{
+		"testdata/src/a.go:6: arm64: _x86_only is not exported by /usr/lib/libSystem.B.dylib on arm64e",
+		"testdata/src/a.go:7: amd64: _catalyst_only is not exported by /usr/lib/libSystem.B.dylib on x86_64",
+		"testdata/src/a.go:7: arm64: _catalyst_only is not exported by /usr/lib/libSystem.B.dylib on arm64e",
+		"testdata/src/a.go:8: amd64: _gets_hidden of /usr/lib/libSystem.B.dylib is hidden on macOS 12.0",
+		"testdata/src/a.go:8: arm64: _gets_hidden of /usr/lib/libSystem.B.dylib is hidden on macOS 12.0",
+		"testdata/src/a.go:10: amd64: _nope is not exported by /usr/lib/libSystem.B.dylib on x86_64",
+		"testdata/src/a.go:10: arm64: _nope is not exported by /usr/lib/libSystem.B.dylib on arm64e",
+		"testdata/src/b/b.go:4: amd64: _foo_arm is not exported by /usr/lib/libbar.dylib on x86_64",
+		"testdata/src/b/b.go:6: arm64: /System/Library/Frameworks/Baz.framework/Versions/A/Baz has no macOS target for arm64 in testdata/sdk/System/Library/Frameworks/Baz.framework/Versions/A/Baz.tbd:1",
+		"testdata/src/b/b.go:7: amd64: open testdata/sdk/usr/lib/libmissing.tbd: no such file or directory",
+		"testdata/src/b/b.go:7: arm64: open testdata/sdk/usr/lib/libmissing.tbd: no such file or directory",
+		"testdata/src/c/c.go:3: amd64: _newer of /usr/lib/libSystem.B.dylib is introduced in macOS 13.0, after 12.0",
+		"testdata/src/c/c.go:3: arm64: _newer of /usr/lib/libSystem.B.dylib is introduced in macOS 13.0, after 12.0",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("check:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if n != len(want) {
+		t.Errorf("check = %d, want %d", n, len(want))
+	}
+
+	// the minimum version of the $ld$ symbols only
+	out.Reset()
+	c = newChecker(filepath.Join("testdata", "sdk"), "11.0")
+	c.check(&out, imports[6:7], []string{"arm64"})
+	if want := "testdata/src/a.go:9: arm64: _moved is not exported by /usr/lib/libSystem.B.dylib on arm64e\n"; out.String() != want {
+		t.Errorf("check on 11.0: %q, want %q", out.String(), want)
+	}
+}
+
+// TestCheckNewerSDK checks the symbols introduced after the minimum version
+// against the headers of an SDK newer than it.
+func TestCheckNewerSDK(t *testing.T) {
+	imports, err := scan([]string{"testdata/src/c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		minOS string
+		want  string
+	}{
+		{"12.0", "testdata/src/c/c.go:3: arm64: _newer of /usr/lib/libSystem.B.dylib is introduced in macOS 13.0, after 12.0\n"},
+		{"13.0", ""},
+		// the SDK of the minimum version: the stubs alone
+		{"14.0", ""},
+	} {
+		var out bytes.Buffer
+		c := newChecker(filepath.Join("testdata", "sdk"), tt.minOS)
+		c.check(&out, imports, []string{"arm64"})
+		if out.String() != tt.want {
+			t.Errorf("check on %s: %q, want %q", tt.minOS, out.String(), tt.want)
+		}
+	}
+}
+
+func TestSDKVersion(t *testing.T) {
+	v, err := sdkVersion(filepath.Join("testdata", "sdk"))
+	if err != nil || v != "14.0" {
+		t.Errorf("sdkVersion = %q, %v, want 14.0", v, err)
+	}
+
+	dir := t.TempDir()
+	if v, err := sdkVersion(dir); err != nil || v != "" {
+		t.Errorf("sdkVersion without settings = %q, %v", v, err)
+	}
+	plist := "<plist version=\"1.0\">\n<dict>\n\t<key>Version</key>\n\t<string>13.3</string>\n</dict>\n</plist>\n"
+	if err := os.WriteFile(filepath.Join(dir, "SDKSettings.plist"), []byte(plist), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := sdkVersion(dir); err != nil || v != "13.3" {
+		t.Errorf("sdkVersion of the plist = %q, %v, want 13.3", v, err)
+	}
+}
+
+func TestHeaderAvailability(t *testing.T) {
+	b, err := os.ReadFile("testdata/sdk/usr/include/newer.h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := headerAvailability(string(b))
+	want := map[string]string{
+		"_strcpy":     "10.0",
+		"_getpid":     "10.0",
+		"_newer":      "13.0",
+		"_newer_data": "13.0",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("headerAvailability = %v, want %v", got, want)
+	}
+
+	for _, tt := range []struct {
+		a, b string
+		want int
+	}{
+		{"12.0", "12", 0},
+		{"12.0.1", "12.0", +1},
+		{"10.15", "11.0", -1},
+		{"13.3", "13.10", -1},
+	} {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestDefsMinOS(t *testing.T) {
+	v, err := defsMinOS(filepath.Join(root, "defs.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != "12.0" {
+		t.Errorf("defsMinOS = %q, want 12.0", v)
+	}
+}
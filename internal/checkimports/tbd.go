@@ -0,0 +1,184 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// library is a library of a text stub: a document of a .tbd file.
+type library struct {
+	installName string
+	archs       []string // of the macOS targets
+
+	// the exported symbols and the install names of the reexported
+	// libraries, by architecture
+	symbols    map[string]map[string]bool
+	reexported map[string][]string
+
+	pos string // file:line of the document
+}
+
+// exports reports whether l exports the symbol sym on arch.
+func (l *library) exports(arch, sym string) bool {
+	return l.symbols[arch][sym]
+}
+
+// parseTBD returns the libraries of the text stub src, of TAPI v3 or v4,
+// read from file.
+func parseTBD(file, src string) ([]*library, error) {
+	docs, err := splitYAML(src)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+
+	var libs []*library
+	for _, doc := range docs {
+		v, err := doc.parse()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: document is not a mapping", file, doc.line)
+		}
+		l := &library{
+			symbols:    make(map[string]map[string]bool),
+			reexported: make(map[string][]string),
+			pos:        fmt.Sprintf("%s:%d", file, doc.line),
+		}
+		switch doc.tag {
+		case "!tapi-tbd-v3":
+			err = l.parseV3(m)
+		case "!tapi-tbd":
+			if str(m["tbd-version"]) != "4" {
+				err = fmt.Errorf("unsupported tbd-version %q", str(m["tbd-version"]))
+				break
+			}
+			err = l.parseV4(m)
+		default:
+			err = fmt.Errorf("unsupported text stub %q", doc.tag)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", l.pos, err)
+		}
+		libs = append(libs, l)
+	}
+
+	return libs, nil
+}
+
+// the keys of the C symbols of the export sections.
+var (
+	symbolKeysV3 = []string{"symbols", "weak-def-symbols", "thread-local-symbols"}
+	symbolKeysV4 = []string{"symbols", "weak-symbols", "thread-local-symbols"}
+)
+
+// parseV3 parses the document m of a !tapi-tbd-v3 stub, whose platform is
+// macosx for the macOS libraries.
+func (l *library) parseV3(m map[string]interface{}) error {
+	l.installName = str(m["install-name"])
+	if l.installName == "" {
+		return errors.New("no install-name")
+	}
+	if str(m["platform"]) != "macosx" {
+		return nil
+	}
+	l.archs = strs(m["archs"])
+
+	for _, sect := range list(m["exports"]) {
+		s, ok := sect.(map[string]interface{})
+		if !ok {
+			return errors.New("exports: invalid section")
+		}
+		archs := strs(s["archs"])
+		l.add(archs, strs(s["re-exports"]), symbolKeysV3, s)
+	}
+
+	return nil
+}
+
+// parseV4 parses the document m of a !tapi-tbd stub of tbd-version 4, whose
+// targets are arch-platform pairs, such as arm64e-macos.
+func (l *library) parseV4(m map[string]interface{}) error {
+	l.installName = str(m["install-name"])
+	if l.installName == "" {
+		return errors.New("no install-name")
+	}
+	l.archs = macOSArchs(strs(m["targets"]))
+
+	for _, sect := range list(m["reexported-libraries"]) {
+		s, ok := sect.(map[string]interface{})
+		if !ok {
+			return errors.New("reexported-libraries: invalid section")
+		}
+		l.add(macOSArchs(strs(s["targets"])), strs(s["libraries"]), nil, nil)
+	}
+	for _, key := range []string{"exports", "reexports"} {
+		for _, sect := range list(m[key]) {
+			s, ok := sect.(map[string]interface{})
+			if !ok {
+				return errors.New(key + ": invalid section")
+			}
+			l.add(macOSArchs(strs(s["targets"])), nil, symbolKeysV4, s)
+		}
+	}
+
+	return nil
+}
+
+// add adds the reexported libraries reexports and the symbols of the keys
+// of the section s to the architectures archs.
+func (l *library) add(archs, reexports []string, keys []string, s map[string]interface{}) {
+	for _, arch := range archs {
+		l.reexported[arch] = append(l.reexported[arch], reexports...)
+		syms := l.symbols[arch]
+		if syms == nil {
+			syms = make(map[string]bool)
+			l.symbols[arch] = syms
+		}
+		for _, key := range keys {
+			for _, sym := range strs(s[key]) {
+				syms[sym] = true
+			}
+		}
+	}
+}
+
+// macOSArchs returns the architectures of the macOS targets of targets.
+func macOSArchs(targets []string) []string {
+	var archs []string
+	for _, t := range targets {
+		if i := strings.LastIndexByte(t, '-'); i >= 0 && t[i+1:] == "macos" {
+			archs = append(archs, t[:i])
+		}
+	}
+
+	return archs
+}
+
+func str(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
+
+// list returns the sequence v, or nil.
+func list(v interface{}) []interface{} {
+	l, _ := v.([]interface{})
+	return l
+}
+
+// strs returns the strings of the sequence v.
+func strs(v interface{}) []string {
+	var s []string
+	for _, e := range list(v) {
+		if e, ok := e.(string); ok {
+			s = append(s, e)
+		}
+	}
+
+	return s
+}
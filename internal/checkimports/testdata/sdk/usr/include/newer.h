@@ -0,0 +1,20 @@
+#ifndef _NEWER_H_
+#define _NEWER_H_
+
+#include <Availability.h>
+#include <sys/cdefs.h>
+
+__BEGIN_DECLS
+size_t	 strlen(const char *__s);
+char	*strcpy(char *__dst, const char *__src) __OSX_AVAILABLE_STARTING(__MAC_10_0, __IPHONE_2_0);
+int	 getpid(void) API_AVAILABLE(macos(10.0));
+
+/* introduced after the minimum version of the tests */
+__API_AVAILABLE(macos(13.0), ios(16.0))
+int	 newer(int __fd,
+	    void (*__handler)(int)) __DARWIN_ALIAS(newer);
+extern const double newer_data API_AVAILABLE(macos(13.0));
+typedef void (*newer_handler_t)(int) API_AVAILABLE(macos(13.0));
+__END_DECLS
+
+#endif /* !_NEWER_H_ */
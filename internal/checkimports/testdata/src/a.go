@@ -0,0 +1,11 @@
+package a
+
+//go:cgo_import_dynamic libc_strlen strlen "/usr/lib/libSystem.B.dylib"
+//go:cgo_import_dynamic libc_getpid getpid "/usr/lib/libSystem.B.dylib"
+//go:cgo_import_dynamic libc_malloc_weak malloc_weak "/usr/lib/libSystem.B.dylib"
+//go:cgo_import_dynamic libc_x86_only x86_only "/usr/lib/libSystem.B.dylib"
+//go:cgo_import_dynamic libc_catalyst_only catalyst_only "/usr/lib/libSystem.B.dylib"
+//go:cgo_import_dynamic libc_gets_hidden gets_hidden "/usr/lib/libSystem.B.dylib"
+//go:cgo_import_dynamic libc_moved moved "/usr/lib/libSystem.B.dylib"
+//go:cgo_import_dynamic libc_nope nope "/usr/lib/libSystem.B.dylib"
+//go:cgo_import_dynamic _ _ "/usr/lib/libSystem.B.dylib"
@@ -0,0 +1,7 @@
+package b
+
+//go:cgo_import_dynamic foo_weak foo_weak "/usr/lib/libfoo.dylib"
+//go:cgo_import_dynamic foo_arm foo_arm "/usr/lib/libbar.dylib"
+//go:cgo_import_dynamic bar bar "/usr/lib/libbar.dylib"
+//go:cgo_import_dynamic baz_init BazInit "/System/Library/Frameworks/Baz.framework/Versions/A/Baz"
+//go:cgo_import_dynamic missing missing "/usr/lib/libmissing.dylib"
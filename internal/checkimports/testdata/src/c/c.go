@@ -0,0 +1,4 @@
+package c
+
+//go:cgo_import_dynamic libc_newer newer "/usr/lib/libSystem.B.dylib"
+//go:cgo_import_dynamic libc_strcpy strcpy "/usr/lib/libSystem.B.dylib"
@@ -0,0 +1,449 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package main
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// This file holds a parser of the subset of YAML written by TAPI in the
+// text stubs: block mappings and sequences, flow sequences and mappings
+// which may span lines, and plain and quoted scalars. The values are
+// strings, []interface{} and map[string]interface{}.
+
+// yamlLine is a logical line of a document, the lines of a flow collection
+// being joined.
+type yamlLine struct {
+	num    int // of the first line, 1-based
+	indent int
+	text   string
+}
+
+// yamlDocument is a document of a YAML stream.
+type yamlDocument struct {
+	tag   string // such as "!tapi-tbd"
+	line  int    // of the "---" line
+	lines []yamlLine
+}
+
+// yamlError reports a line the parser does not handle.
+type yamlError struct {
+	line int
+	msg  string
+}
+
+func (e *yamlError) Error() string {
+	return "line " + strconv.Itoa(e.line) + ": " + e.msg
+}
+
+// splitYAML returns the documents of the YAML stream src.
+func splitYAML(src string) ([]*yamlDocument, error) {
+	var docs []*yamlDocument
+	var doc *yamlDocument
+	var pending *yamlLine // a line of an open flow collection
+	for i, text := range strings.Split(src, "\n") {
+		num := i + 1
+		text = strings.TrimRight(text, " \t\r")
+		if pending != nil {
+			pending.text += " " + strings.TrimSpace(text)
+			if flowDepth(pending.text) == 0 {
+				doc.lines = append(doc.lines, *pending)
+				pending = nil
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(text, "---"):
+			doc = &yamlDocument{tag: strings.TrimSpace(text[3:]), line: num}
+			docs = append(docs, doc)
+			continue
+		case text == "...":
+			doc = nil
+			continue
+		case strings.TrimSpace(text) == "" || strings.HasPrefix(strings.TrimSpace(text), "#"):
+			continue
+		case strings.HasPrefix(text, "%"):
+			// a directive, such as %YAML 1.2
+			continue
+		}
+		if doc == nil {
+			// a document without "---"
+			doc = &yamlDocument{line: num}
+			docs = append(docs, doc)
+		}
+		if strings.HasPrefix(text, "\t") {
+			return nil, &yamlError{num, "tab indentation"}
+		}
+		trimmed := strings.TrimLeft(text, " ")
+		l := yamlLine{num: num, indent: len(text) - len(trimmed), text: trimmed}
+		if flowDepth(l.text) > 0 {
+			pending = &l
+			continue
+		}
+		doc.lines = append(doc.lines, l)
+	}
+	if pending != nil {
+		return nil, &yamlError{pending.num, "unterminated flow collection"}
+	}
+
+	return docs, nil
+}
+
+// flowDepth returns the depth of the flow collections open at the end of s.
+func flowDepth(s string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else if c == '\\' && quote == '"' {
+				i++
+			}
+		case c == '\'' || c == '"':
+			if i == 0 || strings.IndexByte(" [{,:", s[i-1]) >= 0 {
+				quote = c
+			}
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		case c == '#' && (i == 0 || s[i-1] == ' '):
+			return depth
+		}
+	}
+
+	return depth
+}
+
+// yamlParser parses the lines of a document.
+type yamlParser struct {
+	lines []yamlLine
+	i     int
+}
+
+// parse returns the value of the document.
+func (d *yamlDocument) parse() (interface{}, error) {
+	p := &yamlParser{lines: d.lines}
+	v, err := p.block(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.i < len(p.lines) {
+		return nil, &yamlError{p.lines[p.i].num, "unexpected indentation"}
+	}
+
+	return v, nil
+}
+
+func isSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// block returns the block value at the current line, of an indentation of
+// indent at least, or "" for an empty value.
+func (p *yamlParser) block(indent int) (interface{}, error) {
+	if p.i >= len(p.lines) || p.lines[p.i].indent < indent {
+		return "", nil
+	}
+	l := p.lines[p.i]
+	if isSequenceItem(l.text) {
+		return p.sequence(l.indent)
+	}
+	if _, _, ok := splitKey(l.text); ok {
+		return p.mapping(l.indent)
+	}
+	p.i++
+
+	return parseFlow(l.text, l.num)
+}
+
+// sequence returns the block sequence of the items at indent.
+func (p *yamlParser) sequence(indent int) ([]interface{}, error) {
+	list := []interface{}{}
+	for p.i < len(p.lines) {
+		l := p.lines[p.i]
+		if l.indent != indent || !isSequenceItem(l.text) {
+			break
+		}
+		rest := strings.TrimLeft(l.text[1:], " ")
+		if rest == "" {
+			p.i++
+			v, err := p.block(indent + 1)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+			continue
+		}
+
+		// the item starts after the "- ", like a line of its own
+		itemIndent := indent + len(l.text) - len(rest)
+		p.lines[p.i] = yamlLine{num: l.num, indent: itemIndent, text: rest}
+		v, err := p.block(itemIndent)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+
+	return list, nil
+}
+
+// mapping returns the block mapping of the keys at indent.
+func (p *yamlParser) mapping(indent int) (map[string]interface{}, error) {
+	m := make(map[string]interface{})
+	for p.i < len(p.lines) {
+		l := p.lines[p.i]
+		if l.indent < indent || l.indent == indent && isSequenceItem(l.text) {
+			break
+		}
+		if l.indent > indent {
+			return nil, &yamlError{l.num, "unexpected indentation"}
+		}
+		key, value, ok := splitKey(l.text)
+		if !ok {
+			return nil, &yamlError{l.num, "expected a key"}
+		}
+		if _, dup := m[key]; dup {
+			return nil, &yamlError{l.num, "duplicate key " + key}
+		}
+		p.i++
+
+		if value != "" {
+			v, err := parseFlow(value, l.num)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = v
+			continue
+		}
+		// the block sequences of a key may be at its indentation
+		var v interface{}
+		var err error
+		if p.i < len(p.lines) && p.lines[p.i].indent == indent && isSequenceItem(p.lines[p.i].text) {
+			v, err = p.sequence(indent)
+		} else {
+			v, err = p.block(indent + 1)
+		}
+		if err != nil {
+			return nil, err
+		}
+		m[key] = v
+	}
+
+	return m, nil
+}
+
+// splitKey splits the line text of a mapping into its key and its value.
+func splitKey(text string) (key, value string, ok bool) {
+	if text == "" || strings.IndexByte("[{'\"", text[0]) >= 0 {
+		if text == "" || text[0] == '[' || text[0] == '{' {
+			return "", "", false
+		}
+		// a quoted key
+		k, n, err := parseQuoted(text)
+		if err != nil || !strings.HasPrefix(text[n:], ":") {
+			return "", "", false
+		}
+		rest := text[n+1:]
+		if rest != "" && rest[0] != ' ' {
+			return "", "", false
+		}
+		return k, strings.TrimSpace(stripComment(rest)), true
+	}
+
+	for i := 0; i < len(text); i++ {
+		if text[i] == ':' && (i+1 == len(text) || text[i+1] == ' ') {
+			return strings.TrimSpace(text[:i]), strings.TrimSpace(stripComment(text[i+1:])), true
+		}
+		if text[i] == '#' && i > 0 && text[i-1] == ' ' {
+			break
+		}
+	}
+
+	return "", "", false
+}
+
+// stripComment strips the comment of a plain value.
+func stripComment(s string) string {
+	if strings.HasPrefix(strings.TrimSpace(s), "'") || strings.HasPrefix(strings.TrimSpace(s), "\"") {
+		return s
+	}
+	if i := strings.Index(s, " #"); i >= 0 {
+		return s[:i]
+	}
+
+	return s
+}
+
+// parseFlow parses the flow value s of the line num.
+func parseFlow(s string, num int) (interface{}, error) {
+	f := &flowParser{s: s}
+	v, err := f.value(false)
+	if err == nil {
+		f.space()
+		if f.off < len(f.s) && !strings.HasPrefix(f.s[f.off:], "#") {
+			err = errors.New("trailing characters")
+		}
+	}
+	if err != nil {
+		return nil, &yamlError{num, err.Error() + " in " + strconv.Quote(s)}
+	}
+
+	return v, nil
+}
+
+// flowParser parses a flow value.
+type flowParser struct {
+	s   string
+	off int
+}
+
+func (f *flowParser) space() {
+	for f.off < len(f.s) && f.s[f.off] == ' ' {
+		f.off++
+	}
+}
+
+// value parses a value, in a flow collection if inFlow.
+func (f *flowParser) value(inFlow bool) (interface{}, error) {
+	f.space()
+	if f.off == len(f.s) {
+		return "", nil
+	}
+	switch f.s[f.off] {
+	case '[':
+		f.off++
+		list := []interface{}{}
+		for {
+			f.space()
+			if f.off < len(f.s) && f.s[f.off] == ']' {
+				f.off++
+				return list, nil
+			}
+			v, err := f.value(true)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+			if err := f.next(']'); err != nil {
+				return nil, err
+			}
+			if f.s[f.off-1] == ']' {
+				return list, nil
+			}
+		}
+
+	case '{':
+		f.off++
+		m := make(map[string]interface{})
+		for {
+			f.space()
+			if f.off < len(f.s) && f.s[f.off] == '}' {
+				f.off++
+				return m, nil
+			}
+			k, err := f.value(true)
+			if err != nil {
+				return nil, err
+			}
+			key, ok := k.(string)
+			if !ok {
+				return nil, errors.New("invalid key")
+			}
+			f.space()
+			if f.off == len(f.s) || f.s[f.off] != ':' {
+				return nil, errors.New("expected ':'")
+			}
+			f.off++
+			v, err := f.value(true)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = v
+			if err := f.next('}'); err != nil {
+				return nil, err
+			}
+			if f.s[f.off-1] == '}' {
+				return m, nil
+			}
+		}
+
+	case '\'', '"':
+		s, n, err := parseQuoted(f.s[f.off:])
+		if err != nil {
+			return nil, err
+		}
+		f.off += n
+		return s, nil
+	}
+
+	// a plain scalar, until the indicators of the flow collections, or a
+	// key separator
+	start := f.off
+	for f.off < len(f.s) {
+		c := f.s[f.off]
+		if inFlow && (c == ',' || c == ']' || c == '}') {
+			break
+		}
+		if inFlow && c == ':' && (f.off+1 == len(f.s) || f.s[f.off+1] == ' ') {
+			break
+		}
+		if c == '#' && f.off > start && f.s[f.off-1] == ' ' {
+			break
+		}
+		f.off++
+	}
+
+	return strings.TrimSpace(f.s[start:f.off]), nil
+}
+
+// next consumes the separator of the items of a flow collection, or its end
+// end.
+func (f *flowParser) next(end byte) error {
+	f.space()
+	if f.off < len(f.s) && (f.s[f.off] == ',' || f.s[f.off] == end) {
+		f.off++
+		return nil
+	}
+
+	return errors.New("expected ',' or '" + string(end) + "'")
+}
+
+// parseQuoted parses the quoted scalar at the start of s, and returns its
+// length in s.
+func parseQuoted(s string) (string, int, error) {
+	quote := s[0]
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == quote && quote == '\'' && i+1 < len(s) && s[i+1] == '\'':
+			b.WriteByte('\'')
+			i++
+		case c == quote:
+			return b.String(), i + 1, nil
+		case c == '\\' && quote == '"' && i+1 < len(s):
+			i++
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				b.WriteByte(s[i])
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return "", 0, errors.New("unterminated quoted scalar")
+}
@@ -0,0 +1,97 @@
+/*
+     File:       CarbonCore/MacErrors.h
+
+     Contains:   OSErr codes.
+
+     Copyright:  (c) 1985-2008 by Apple Computer, Inc., all rights reserved.
+*/
+
+/*
+ * Excerpt of CarbonCore/MacErrors.h: the general, I/O, file system and memory
+ * manager result codes. Input of internal/mkosstatus.
+ */
+
+enum {
+  paramErr                      = -50,  /*error in user parameter list*/
+  noHardwareErr                 = -200, /*Sound Manager Error Returns*/
+  notEnoughHardwareErr          = -201, /*Sound Manager Error Returns*/
+  userCanceledErr               = -128,
+  qErr                          = -1,   /*queue element not found during deletion*/
+  vTypErr                       = -2,   /*invalid queue element*/
+  corErr                        = -3,   /*core routine number out of range*/
+  unimpErr                      = -4,   /*unimplemented core routine*/
+  SlpTypeErr                    = -5,   /*invalid queue element*/
+  seNoDB                        = -8,   /*no debugger installed to handle debugger command*/
+  controlErr                    = -17,  /*I/O System Errors*/
+  statusErr                     = -18,  /*I/O System Errors*/
+  readErr                       = -19,  /*I/O System Errors*/
+  writErr                       = -20,  /*I/O System Errors*/
+  badUnitErr                    = -21,  /*I/O System Errors*/
+  unitEmptyErr                  = -22,  /*I/O System Errors*/
+  openErr                       = -23,  /*I/O System Errors*/
+  closErr                       = -24,  /*I/O System Errors*/
+  dRemovErr                     = -25,  /*tried to remove an open driver*/
+  dInstErr                      = -26   /*DrvrInstall couldn't find driver in resources*/
+};
+
+enum {
+  abortErr                      = -27,  /*IO call aborted by KillIO*/
+  iIOAbortErr                   = -27,  /*IO abort error (Printing Manager)*/
+  notOpenErr                    = -28,  /*Couldn't rd/wr/ctl/sts cause driver not opened*/
+  unitTblFullErr                = -29,  /*unit table has no more entries*/
+  dceExtErr                     = -30,  /*dce extension error*/
+  slotNumErr                    = -360, /*invalid slot # error*/
+  gcrOnMFMErr                   = -400, /*gcr format on high density media error*/
+  dirFulErr                     = -33,  /*Directory full*/
+  dskFulErr                     = -34,  /*disk full*/
+  nsvErr                        = -35,  /*no such volume*/
+  ioErr                         = -36,  /*I/O error (bummers)*/
+  bdNamErr                      = -37,  /*there may be no bad names in the final system!*/
+  fnOpnErr                      = -38,  /*File not open*/
+  eofErr                        = -39,  /*End of file*/
+  posErr                        = -40,  /*tried to position to before start of file (r/w)*/
+  mFulErr                       = -41,  /*memory full (open) or file won't fit (load)*/
+  tmfoErr                       = -42,  /*too many files open*/
+  fnfErr                        = -43,  /*File not found*/
+  wPrErr                        = -44,  /*diskette is write protected.*/
+  fLckdErr                      = -45   /*file is locked*/
+};
+
+enum {
+  vLckdErr                      = -46,  /*volume is locked*/
+  fBsyErr                       = -47,  /*File is busy (delete)*/
+  dupFNErr                      = -48,  /*duplicate filename (rename)*/
+  opWrErr                       = -49,  /*file already open with with write permission*/
+  rfNumErr                      = -51,  /*refnum error*/
+  gfpErr                        = -52,  /*get file position error*/
+  volOffLinErr                  = -53,  /*volume not on line error (was Ejected)*/
+  permErr                       = -54,  /*permissions error (on file open)*/
+  volOnLinErr                   = -55,  /*drive volume already on-line at MountVol*/
+  nsDrvErr                      = -56,  /*no such drive (tried to mount a bad drive num)*/
+  noMacDskErr                   = -57,  /*not a mac diskette (sig bytes are wrong)*/
+  extFSErr                      = -58,  /*volume in question belongs to an external fs*/
+  fsRnErr                       = -59,  /*file system internal error:during rename the old entry was deleted but could not be restored.*/
+  badMDBErr                     = -60,  /*bad master directory block*/
+  wrPermErr                     = -61,  /*write permissions error*/
+  dirNFErr                      = -120, /*Directory not found*/
+  tmwdoErr                      = -121, /*No free WDCB available*/
+  badMovErr                     = -122, /*Move into offspring error*/
+  wrgVolTypErr                  = -123, /*Wrong volume type error [operation not supported for MFS]*/
+  volGoneErr                    = -124  /*Server volume has been disconnected.*/
+};
+
+enum {
+  memROZWarn                    = -99,  /*soft error in ROZ*/
+  memROZError                   = -99,  /*hard error in ROZ*/
+  memROZErr                     = -99,  /*hard error in ROZ*/
+  memFullErr                    = -108, /*Not enough room in heap zone*/
+  nilHandleErr                  = -109, /*Master Pointer was NIL in HandleZone or other*/
+  memWZErr                      = -111, /*WhichZone failed (applied to free block)*/
+  memPurErr                     = -112, /*trying to purge a locked or non-purgeable block*/
+  memAdrErr                     = -110, /*address was odd; or out of range*/
+  memAZErr                      = -113, /*Address in zone check failed*/
+  memPCErr                      = -114, /*Pointer Check failed*/
+  memBCErr                      = -115, /*Block Check failed*/
+  memSCErr                      = -116, /*Size Check failed*/
+  memLockedErr                  = -117  /*trying to move a locked block (MoveHHi)*/
+};
@@ -0,0 +1,9 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package migtest holds the MIG client stubs generated by internal/mkmig from
+// echo.defs, which are compiled and run against a fake sys.MIGClient on every
+// platform.
+package migtest
+
+//go:generate go run ../mkmig -package migtest -o zecho.go echo.defs
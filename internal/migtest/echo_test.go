@@ -0,0 +1,365 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package migtest_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/go-darwin/sys"
+	"github.com/go-darwin/sys/internal/migtest"
+)
+
+const (
+	serverPort = 0x1103
+	replyPort  = 0x1207
+)
+
+// fakeClient is a sys.MIGClient which records the encoding of the requests
+// with the amd64 layout, and returns the reply of its server function
+// through the same encoding.
+type fakeClient struct {
+	t      *testing.T
+	layout sys.MachMsgLayout
+	req    []byte
+	server func(req *sys.MachMsg) *sys.MachMsg
+}
+
+func newFakeClient(t *testing.T, server func(req *sys.MachMsg) *sys.MachMsg) *fakeClient {
+	t.Helper()
+
+	l, ok := sys.LookupMachMsgLayout("amd64")
+	if !ok {
+		t.Fatal("no MachMsgLayout for amd64")
+	}
+
+	return &fakeClient{t: t, layout: l, server: server}
+}
+
+func (c *fakeClient) roundTrip(m *sys.MachMsg) *sys.MachMsg {
+	c.t.Helper()
+
+	b, err := c.layout.Marshal(m)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	m, err = c.layout.Unmarshal(b)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+
+	return m
+}
+
+func (c *fakeClient) Send(req *sys.MachMsg) sys.KernReturn {
+	c.t.Helper()
+
+	b, err := c.layout.Marshal(req)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	c.req = b
+
+	return sys.KernSuccess
+}
+
+func (c *fakeClient) Call(req *sys.MachMsg) (*sys.MachMsg, sys.KernReturn) {
+	c.t.Helper()
+
+	req.Header.LocalPort = replyPort
+	if kr := c.Send(req); kr != sys.KernSuccess {
+		return nil, kr
+	}
+	rep := c.server(c.roundTrip(req))
+
+	return c.roundTrip(rep), sys.KernSuccess
+}
+
+// reply returns the reply to req with the descriptors descs, and the inline
+// data put by data after the NDR_record_t and, for a simple reply, the
+// RetCode.
+func reply(req *sys.MachMsg, data func(e *sys.MIGEncoder), descs ...sys.MachMsgDescriptor) *sys.MachMsg {
+	var e sys.MIGEncoder
+	e.PutNDR()
+	if len(descs) == 0 {
+		e.Put32(uint32(sys.KernSuccess))
+	}
+	if data != nil {
+		data(&e)
+	}
+
+	return &sys.MachMsg{
+		Header: sys.MachMsgHeader{
+			Bits:       sys.MakeMachMsgBits(sys.MachMsgTypeMoveSendOnce, 0, 0),
+			RemotePort: req.Header.LocalPort,
+			ID:         req.Header.ID + sys.MIGReplyIDOffset,
+		},
+		Descriptors: descs,
+		Data:        e.Bytes(),
+	}
+}
+
+// errorReply returns the error reply to req of RetCode kr.
+func errorReply(req *sys.MachMsg, kr sys.KernReturn) *sys.MachMsg {
+	rep := reply(req, nil)
+	rep.Data = rep.Data[:8]
+	var e sys.MIGEncoder
+	e.Put32(uint32(kr))
+	rep.Data = append(rep.Data, e.Bytes()...)
+
+	return rep
+}
+
+func unhex(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := hex.DecodeString(strings.Join(strings.Fields(s), ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
+
+func checkRequest(t *testing.T, c *fakeClient, want string) {
+	t.Helper()
+
+	if w := unhex(t, want); !bytes.Equal(c.req, w) {
+		t.Errorf("request\n%x\nwant\n%x", c.req, w)
+	}
+}
+
+func checkKernReturn(t *testing.T, kr, want sys.KernReturn) {
+	t.Helper()
+
+	if kr != want {
+		t.Fatalf("kern_return_t %v, want %v", kr, want)
+	}
+}
+
+func TestEchoPing(t *testing.T) {
+	c := newFakeClient(t, func(req *sys.MachMsg) *sys.MachMsg { return reply(req, nil) })
+	checkKernReturn(t, migtest.EchoPing(c, serverPort), sys.KernSuccess)
+	checkRequest(t, c, `
+		13150000 18000000 03110000 07120000 00000000 68100000`)
+}
+
+func TestEchoAdd(t *testing.T) {
+	c := newFakeClient(t, func(req *sys.MachMsg) *sys.MachMsg {
+		d := sys.NewMIGDecoder(req.Data[8:])
+		a, b := int32(d.Get32()), int64(d.Get64())
+		return reply(req, func(e *sys.MIGEncoder) { e.Put64(uint64(int64(a) + b)) })
+	})
+	sum, kr := migtest.EchoAdd(c, serverPort, 7, -2)
+	checkKernReturn(t, kr, sys.KernSuccess)
+	if sum != 5 {
+		t.Errorf("sum %d, want 5", sum)
+	}
+	checkRequest(t, c, `
+		13150000 2c000000 03110000 07120000 00000000 69100000
+		00000000 01000000
+		07000000
+		feffffff ffffffff`)
+}
+
+func TestEchoNotify(t *testing.T) {
+	c := newFakeClient(t, nil)
+	checkKernReturn(t, migtest.EchoNotify(c, serverPort, 0xbeef, "a name longer than the 32 bytes of echo_name_t"), sys.KernSuccess)
+	checkRequest(t, c, `
+		13000000 44000000 03110000 00000000 00000000 6a100000
+		00000000 01000000
+		efbe0000
+		61206e61 6d65206c 6f6e6765 72207468 616e2074 68652033 32206279 74657300`)
+}
+
+func TestEchoSwap(t *testing.T) {
+	c := newFakeClient(t, func(req *sys.MachMsg) *sys.MachMsg {
+		d := sys.NewMIGDecoder(req.Data[8:])
+		a, b := d.Get32(), d.Get32()
+		return reply(req, func(e *sys.MIGEncoder) {
+			e.Put32(b)
+			e.Put32(a)
+			e.Put8('y')
+			e.Align()
+		})
+	})
+	pair, flag, kr := migtest.EchoSwap(c, serverPort, [2]int32{1, -1})
+	checkKernReturn(t, kr, sys.KernSuccess)
+	if pair != [2]int32{-1, 1} || flag != 'y' {
+		t.Errorf("EchoSwap = %v, %q", pair, flag)
+	}
+}
+
+func TestEchoBytes(t *testing.T) {
+	c := newFakeClient(t, func(req *sys.MachMsg) *sys.MachMsg {
+		return reply(req, func(e *sys.MIGEncoder) {
+			e.Put32(3)
+			for _, v := range []byte("abc") {
+				e.Put8(v)
+			}
+			e.Align()
+			e.PutString("echo", 32)
+		})
+	})
+	out, name, kr := migtest.EchoBytes(c, serverPort, []byte{1, 2, 3, 4, 5}, []int16{-1})
+	checkKernReturn(t, kr, sys.KernSuccess)
+	if string(out) != "abc" || name != "echo" {
+		t.Errorf("EchoBytes = %q, %q", out, name)
+	}
+	checkRequest(t, c, `
+		13150000 38000000 03110000 07120000 00000000 6d100000
+		00000000 01000000
+		05000000 01020304 05000000
+		01000000 ffff0000
+		40000000`)
+
+	if _, _, kr := migtest.EchoBytes(c, serverPort, make([]byte, 65), nil); kr != sys.KernReturn(sys.MigArrayTooLarge) {
+		t.Errorf("EchoBytes of 65 bytes: %v, want MigArrayTooLarge", kr)
+	}
+}
+
+func TestEchoSendData(t *testing.T) {
+	data := []byte("out-of-line")
+	words := []int32{1, 2, 3}
+	var descs []sys.MachMsgDescriptor
+	c := newFakeClient(t, func(req *sys.MachMsg) *sys.MachMsg {
+		descs = req.Descriptors
+		return reply(req, func(e *sys.MIGEncoder) { e.Put32(0xc0ffee) })
+	})
+	sum, kr := migtest.EchoSendData(c, serverPort, data, words)
+	checkKernReturn(t, kr, sys.KernSuccess)
+	if sum != 0xc0ffee {
+		t.Errorf("checksum %#x", sum)
+	}
+
+	if len(descs) != 2 {
+		t.Fatalf("%d descriptors, want 2", len(descs))
+	}
+	for i, want := range []struct {
+		size     uint32
+		dealloc  bool
+		copyType sys.MachMsgCopyOptions
+	}{
+		{uint32(len(data)), true, sys.MachMsgVirtualCopy},
+		{uint32(4 * len(words)), false, sys.MachMsgPhysicalCopy},
+	} {
+		d, ok := descs[i].(*sys.MachMsgOOLDesc)
+		if !ok {
+			t.Fatalf("descriptor %d is %T", i, descs[i])
+		}
+		if d.Address == 0 || d.Size != want.size || d.Deallocate != want.dealloc || d.Copy != want.copyType {
+			t.Errorf("descriptor %d: %+v", i, d)
+		}
+	}
+}
+
+func TestEchoGetData(t *testing.T) {
+	c := newFakeClient(t, func(req *sys.MachMsg) *sys.MachMsg {
+		return reply(req, func(e *sys.MIGEncoder) { e.Put32(16) },
+			&sys.MachMsgOOLDesc{Address: 0x7f0000001000, Size: 16, Deallocate: true})
+	})
+	addr, n, kr := migtest.EchoGetData(c, serverPort)
+	checkKernReturn(t, kr, sys.KernSuccess)
+	if addr != 0x7f0000001000 || n != 16 {
+		t.Errorf("EchoGetData = %#x, %d", addr, n)
+	}
+
+	// the count must match the size of the descriptor
+	c.server = func(req *sys.MachMsg) *sys.MachMsg {
+		return reply(req, func(e *sys.MIGEncoder) { e.Put32(8) },
+			&sys.MachMsgOOLDesc{Address: 0x7f0000001000, Size: 16})
+	}
+	_, _, kr = migtest.EchoGetData(c, serverPort)
+	checkKernReturn(t, kr, sys.KernReturn(sys.MigTypeError))
+}
+
+func TestEchoPort(t *testing.T) {
+	c := newFakeClient(t, func(req *sys.MachMsg) *sys.MachMsg {
+		return reply(req, func(e *sys.MIGEncoder) { e.Put32(0x1503) },
+			&sys.MachMsgPortDesc{Name: 0x1403, Disposition: sys.MachMsgTypeMoveSend})
+	})
+	port, name, kr := migtest.EchoPort(c, serverPort, 0x1307)
+	checkKernReturn(t, kr, sys.KernSuccess)
+	if port != 0x1403 || name != 0x1503 {
+		t.Errorf("EchoPort = %#x, %#x", port, name)
+	}
+	checkRequest(t, c, `
+		13150080 28000000 03110000 07120000 00000000 70100000
+		01000000
+		07130000 00000000 00001400`)
+}
+
+func TestEchoErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		server func(req *sys.MachMsg) *sys.MachMsg
+		want   sys.KernReturn
+	}{
+		{
+			name:   "RetCode",
+			server: func(req *sys.MachMsg) *sys.MachMsg { return errorReply(req, sys.KernReturn(5)) },
+			want:   sys.KernReturn(5),
+		},
+		{
+			name: "reply id",
+			server: func(req *sys.MachMsg) *sys.MachMsg {
+				rep := reply(req, func(e *sys.MIGEncoder) { e.Put32(1) })
+				rep.Header.ID++
+				return rep
+			},
+			want: sys.KernReturn(sys.MigReplyMismatch),
+		},
+		{
+			name: "server died",
+			server: func(req *sys.MachMsg) *sys.MachMsg {
+				rep := reply(req, nil)
+				rep.Header.ID = 71
+				return rep
+			},
+			want: sys.KernReturn(sys.MigServerDied),
+		},
+		{
+			name:   "short reply",
+			server: func(req *sys.MachMsg) *sys.MachMsg { return reply(req, nil) },
+			want:   sys.KernReturn(sys.MigTypeError),
+		},
+		{
+			name: "long reply",
+			server: func(req *sys.MachMsg) *sys.MachMsg {
+				return reply(req, func(e *sys.MIGEncoder) { e.Put64(1) })
+			},
+			want: sys.KernReturn(sys.MigTypeError),
+		},
+		{
+			name: "complex reply",
+			server: func(req *sys.MachMsg) *sys.MachMsg {
+				return reply(req, func(e *sys.MIGEncoder) { e.Put32(1) },
+					&sys.MachMsgPortDesc{Name: 1, Disposition: sys.MachMsgTypeMoveSend})
+			},
+			want: sys.KernReturn(sys.MigTypeError),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newFakeClient(t, tt.server)
+			pid, kr := migtest.EchoPID(c, serverPort)
+			checkKernReturn(t, kr, tt.want)
+			if pid != 0 {
+				t.Errorf("pid %d on error", pid)
+			}
+		})
+	}
+
+	// a count larger than the maximum of the array
+	c := newFakeClient(t, func(req *sys.MachMsg) *sys.MachMsg {
+		return reply(req, func(e *sys.MIGEncoder) {
+			e.Put32(65)
+			e.PutString("", 32)
+		})
+	})
+	_, _, kr := migtest.EchoBytes(c, serverPort, nil, nil)
+	checkKernReturn(t, kr, sys.KernReturn(sys.MigTypeError))
+}
@@ -0,0 +1,298 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Command mkdarwinerrno generates the zdarwin_errno*.go files of package sys.
+//
+// It parses the checked-in copy of the sys/errno.h SDK header and emits the
+// DarwinErrno constants and their name and message tables, which do not depend
+// on the host, and for each supported host GOOS the table which maps a
+// DarwinErrno to the syscall.Errno of the same name on that host.
+//
+// The names defined by a host are read from the syscall package of the Go
+// installation, in $GOROOT/src/syscall/zerrors_<goos>_amd64.go.
+//
+// The message of an errno is the comment of its #define starting with a lower
+// case letter, like strerror and the syscall package error tables. The
+// reserved errno values are described like strerror, such as
+// "EMULTIHOP (Reserved)".
+//
+// Run from the repository root:
+//
+//	go run ./internal/mkdarwinerrno
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// hosts are the GOOS of the generated host tables.
+var hosts = []string{"darwin", "linux"}
+
+var (
+	flagHeader = flag.String("header", "internal/include/sys/errno.h", "path of the sys/errno.h header")
+	flagOut    = flag.String("o", ".", "output directory")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("mkdarwinerrno: ")
+	flag.Parse()
+
+	f, err := os.Open(*flagHeader)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	errnos, err := parse(f)
+	if err != nil {
+		log.Fatalf("%s: %v", *flagHeader, err)
+	}
+
+	src, err := generate(errnos)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(*flagOut, "zdarwin_errno.go"), src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+
+	for _, goos := range hosts {
+		names, err := hostNames(goos)
+		if err != nil {
+			log.Fatal(err)
+		}
+		src, err := generateHost(errnos, goos, names)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(*flagOut, hostFilename(goos)), src, 0o644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// errno is an errno defined by the header.
+type errno struct {
+	Name    string // C name, such as "EPERM"
+	Value   int    // value of the errno
+	Alias   string // C name of the aliased errno, if any
+	Comment string // comment of the #define
+}
+
+var defineRE = regexp.MustCompile(`^#define\s+(E[A-Z0-9]+)\s+(\d+|E[A-Z0-9]+)\s*(?:/\*\s*(.*?)\s*\*/)?$`)
+
+// parse returns the errno values defined by the header read from r, in the
+// order of the header.
+//
+// The definitions of the blocks for pre-UNIX03 compatibility are skipped, and
+// ELAST, which is not an errno, too.
+func parse(r io.Reader) ([]*errno, error) {
+	var (
+		errnos []*errno
+		byName = make(map[string]*errno)
+		depth  int // depth of the #if blocks
+		skip   int // depth of the skipped #if block, if any
+		lineno int
+	)
+
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		lineno++
+		line := strings.TrimSpace(sc.Text())
+
+		switch {
+		case strings.HasPrefix(line, "#if"):
+			depth++
+			if skip == 0 && strings.HasPrefix(line, "#if !__DARWIN_UNIX03") {
+				skip = depth
+			}
+			continue
+		case strings.HasPrefix(line, "#endif"):
+			if depth == skip {
+				skip = 0
+			}
+			depth--
+			continue
+		case skip > 0:
+			continue
+		}
+
+		m := defineRE.FindStringSubmatch(line)
+		if m == nil || m[1] == "ELAST" {
+			continue
+		}
+		e := &errno{Name: m[1], Comment: m[3]}
+		if _, ok := byName[e.Name]; ok {
+			return nil, fmt.Errorf("line %d: %s redefined", lineno, e.Name)
+		}
+		if n, err := strconv.Atoi(m[2]); err == nil {
+			e.Value = n
+		} else {
+			alias, ok := byName[m[2]]
+			if !ok {
+				return nil, fmt.Errorf("line %d: %s: unknown errno %s", lineno, e.Name, m[2])
+			}
+			e.Value, e.Alias = alias.Value, alias.Name
+		}
+		byName[e.Name] = e
+		errnos = append(errnos, e)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("line %d: unbalanced #if", lineno)
+	}
+	if len(errnos) == 0 {
+		return nil, fmt.Errorf("no errno definition")
+	}
+
+	return errnos, nil
+}
+
+// message returns the error message of the errno e.
+func (e *errno) message() string {
+	if e.Comment == "Reserved" {
+		return e.Name + " (Reserved)"
+	}
+
+	words := strings.SplitN(e.Comment, " ", 2)
+	if isCapitalized(words[0]) {
+		words[0] = strings.ToLower(words[0])
+	}
+
+	return strings.Join(words, " ")
+}
+
+// isCapitalized reports whether w starts with the only upper case letter of
+// the word, unlike an acronym such as "RPC".
+func isCapitalized(w string) bool {
+	for i, r := range w {
+		if i == 0 && !unicode.IsUpper(r) || i > 0 && unicode.IsUpper(r) {
+			return false
+		}
+	}
+
+	return len(w) > 1
+}
+
+// goName returns the Go name of the C name of an errno, such as "DarwinEPERM".
+func goName(name string) string {
+	return "Darwin" + name
+}
+
+// hostFilename returns the name of the host table file of goos.
+func hostFilename(goos string) string {
+	return "zdarwin_errno_" + goos + ".go"
+}
+
+// hostNames returns the set of the Errno constant names of the syscall package
+// of goos.
+func hostNames(goos string) (map[string]bool, error) {
+	path := filepath.Join(build.Default.GOROOT, "src", "syscall", "zerrors_"+goos+"_amd64.go")
+	f, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool)
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, name := range vs.Names {
+				if i >= len(vs.Values) {
+					continue
+				}
+				call, ok := vs.Values[i].(*ast.CallExpr)
+				if !ok {
+					continue
+				}
+				if fn, ok := call.Fun.(*ast.Ident); ok && fn.Name == "Errno" {
+					names[name.Name] = true
+				}
+			}
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("%s: no Errno constant", path)
+	}
+
+	return names, nil
+}
+
+// generate returns the formatted source of zdarwin_errno.go for errnos.
+func generate(errnos []*errno) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by internal/mkdarwinerrno; DO NOT EDIT.\n\n")
+	buf.WriteString("package sys\n\n")
+
+	buf.WriteString("// list of DarwinErrno.\nconst (\n")
+	for _, e := range errnos {
+		if e.Alias != "" {
+			fmt.Fprintf(&buf, "\t%s = %s // %s\n", goName(e.Name), goName(e.Alias), e.Comment)
+			continue
+		}
+		fmt.Fprintf(&buf, "\t%s DarwinErrno = %d // %s\n", goName(e.Name), e.Value, e.Comment)
+	}
+	buf.WriteString(")\n\n")
+
+	buf.WriteString("// DarwinErrno name table.\nvar darwinErrnoNames = [...]string{\n")
+	for _, e := range errnos {
+		if e.Alias == "" {
+			fmt.Fprintf(&buf, "\t%d: %q,\n", e.Value, e.Name)
+		}
+	}
+	buf.WriteString("}\n\n")
+
+	buf.WriteString("// DarwinErrno Error table.\nvar darwinErrors = [...]string{\n")
+	for _, e := range errnos {
+		if e.Alias == "" {
+			fmt.Fprintf(&buf, "\t%d: %q,\n", e.Value, e.message())
+		}
+	}
+	buf.WriteString("}\n")
+
+	return format.Source(buf.Bytes())
+}
+
+// generateHost returns the formatted source of the host table file of goos for
+// errnos, where names is the set of the Errno names defined by goos.
+func generateHost(errnos []*errno, goos string, names map[string]bool) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by internal/mkdarwinerrno; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "//go:build %s\n// +build %s\n\n", goos, goos)
+	buf.WriteString("package sys\n\n")
+	buf.WriteString("import \"syscall\"\n\n")
+
+	fmt.Fprintf(&buf, "// darwinErrnoHost maps a DarwinErrno to the %s Errno of the same name.\n", goos)
+	buf.WriteString("var darwinErrnoHost = [...]Errno{\n")
+	for _, e := range errnos {
+		if e.Alias == "" && names[e.Name] {
+			fmt.Fprintf(&buf, "\t%s: syscall.%s,\n", goName(e.Name), e.Name)
+		}
+	}
+	buf.WriteString("}\n")
+
+	return format.Source(buf.Bytes())
+}
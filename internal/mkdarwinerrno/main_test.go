@@ -0,0 +1,126 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const root = "../.."
+
+func parseTestdata(t *testing.T) []*errno {
+	t.Helper()
+
+	f, err := os.Open(filepath.Join(root, "internal", "include", "sys", "errno.h"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	errnos, err := parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return errnos
+}
+
+// TestGenerated fails when the zdarwin_errno*.go files drift from the
+// checked-in header.
+func TestGenerated(t *testing.T) {
+	errnos := parseTestdata(t)
+
+	check := func(filename string, want []byte) {
+		got, err := os.ReadFile(filepath.Join(root, filename))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s is out of date, run go generate", filename)
+		}
+	}
+
+	want, err := generate(errnos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("zdarwin_errno.go", want)
+
+	for _, goos := range hosts {
+		names, err := hostNames(goos)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := generateHost(errnos, goos, names)
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(hostFilename(goos), want)
+	}
+}
+
+func TestParseHeader(t *testing.T) {
+	byName := make(map[string]*errno)
+	for _, e := range parseTestdata(t) {
+		byName[e.Name] = e
+	}
+
+	tests := []struct {
+		name    string
+		value   int
+		alias   string
+		message string
+	}{
+		{"EPERM", 1, "", "operation not permitted"},
+		{"EAGAIN", 35, "", "resource temporarily unavailable"},
+		{"EWOULDBLOCK", 35, "EAGAIN", "operation would block"},
+		{"ENOTSUP", 45, "", "operation not supported"},
+		{"EOPNOTSUPP", 102, "", "operation not supported on socket"},
+		{"EAUTH", 80, "", "authentication error"},
+		{"EBADRPC", 72, "", "RPC struct is bad"},
+		{"ENOATTR", 93, "", "attribute not found"},
+		{"EMULTIHOP", 95, "", "EMULTIHOP (Reserved)"},
+		{"ETIME", 101, "", "STREAM ioctl timeout"},
+		{"EQFULL", 106, "", "interface output queue is full"},
+	}
+	for _, tt := range tests {
+		e, ok := byName[tt.name]
+		if !ok {
+			t.Errorf("%s not found", tt.name)
+			continue
+		}
+		if e.Value != tt.value || e.Alias != tt.alias {
+			t.Errorf("%s = %d (alias %q), want %d (alias %q)", tt.name, e.Value, e.Alias, tt.value, tt.alias)
+		}
+		if got := e.message(); got != tt.message {
+			t.Errorf("message(%s) = %q, want %q", tt.name, got, tt.message)
+		}
+	}
+
+	if _, ok := byName["ELAST"]; ok {
+		t.Error("ELAST parsed as an errno")
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		src, err string
+	}{
+		{"#define EA 1\n#define EA 2\n", "redefined"},
+		{"#define EA EB\n", "unknown errno"},
+		{"#if X\n#define EA 1\n", "unbalanced"},
+		{"#define ELAST 1\n", "no errno"},
+		{"#if !__DARWIN_UNIX03\n#define EA 1\n#endif\n", "no errno"},
+	}
+	for _, tt := range tests {
+		_, err := parse(strings.NewReader(tt.src))
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("parse(%q) error = %v, want %q", tt.src, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Command mkioreturn generates the zioreturn.go file of package sys.
+//
+// It parses the checked-in copy of the IOKit/IOReturn.h SDK header and emits,
+// from the same source, the IOKitSubsystem constants and name table, and the
+// IOReturn constants, their doc comments, and the IOReturn name and message
+// tables.
+//
+// The message of an IOReturn is the trailing comment of its #define, with its
+// continuation lines, capitalized words in lower case and without final
+// punctuation. Declarations under #ifdef PRIVATE are not part of the SDK and
+// are skipped.
+//
+// Run from the repository root:
+//
+//	go run ./internal/mkioreturn
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	flagHeader = flag.String("header", "internal/include/IOKit/IOReturn.h", "path of the IOKit/IOReturn.h header")
+	flagOut    = flag.String("o", "zioreturn.go", "output file")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("mkioreturn: ")
+	flag.Parse()
+
+	f, err := os.Open(*flagHeader)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	h, err := parse(f)
+	if err != nil {
+		log.Fatalf("%s: %v", *flagHeader, err)
+	}
+
+	src, err := generate(h)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*flagOut, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// subsystem is an IOKit subsystem defined by the header.
+type subsystem struct {
+	Name  string // C name without the sub_iokit_ prefix, such as "usb"
+	Value int    // err_sub value, in [0, 0xfff]
+}
+
+// code is an IOReturn code defined by the header.
+type code struct {
+	Name    string // C name, such as "kIOReturnBadArgument"
+	Common  bool   // whether the code is an iokit_common_err
+	Value   int    // code within the common subsystem, or 0 for kIOReturnSuccess
+	Comment string // trailing comment of the #define, with its continuation lines
+}
+
+// header is the parsed IOReturn.h header.
+type header struct {
+	Subs  []*subsystem
+	Codes []*code
+}
+
+var (
+	subRE  = regexp.MustCompile(`^#define\s+sub_iokit_(\w+)\s+err_sub\((-?\w+)\)\s*$`)
+	codeRE = regexp.MustCompile(`^#define\s+(kIOReturn\w+)\s+(?:KERN_SUCCESS|iokit_common_err\((\w+)\))\s*(?://\s*(.*))?$`)
+)
+
+// parse returns the subsystems and codes defined by the header read from r,
+// in the order of the header.
+func parse(r io.Reader) (*header, error) {
+	var (
+		h       header
+		last    *code // last code, which may have comment continuation lines
+		private int   // depth of #ifdef PRIVATE blocks
+		lineno  int
+	)
+
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		lineno++
+		raw := sc.Text()
+		line := strings.TrimSpace(raw)
+
+		switch {
+		case line == "#ifdef PRIVATE":
+			private++
+			continue
+		case private > 0 && strings.HasPrefix(line, "#if"):
+			private++
+			continue
+		case private > 0 && strings.HasPrefix(line, "#endif"):
+			private--
+			continue
+		case private > 0:
+			continue
+		}
+
+		// A continuation line of the trailing comment is indented, unlike
+		// the commented out #define lines.
+		if last != nil && raw != line && strings.HasPrefix(line, "//") {
+			last.Comment += " " + strings.TrimSpace(strings.TrimPrefix(line, "//"))
+			continue
+		}
+		last = nil
+
+		if m := subRE.FindStringSubmatch(line); m != nil {
+			v, err := strconv.ParseInt(m[2], 0, 16)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: sub_iokit_%s: invalid value %q", lineno, m[1], m[2])
+			}
+			h.Subs = append(h.Subs, &subsystem{Name: m[1], Value: int(v) & 0xfff})
+			continue
+		}
+
+		if m := codeRE.FindStringSubmatch(line); m != nil {
+			c := &code{Name: m[1], Comment: strings.TrimSpace(m[3])}
+			if m[2] != "" {
+				v, err := strconv.ParseInt(m[2], 0, 16)
+				if err != nil || v < 0 || v > 0x3fff {
+					return nil, fmt.Errorf("line %d: %s: invalid value %q", lineno, m[1], m[2])
+				}
+				c.Common = true
+				c.Value = int(v)
+			}
+			for _, prev := range h.Codes {
+				if prev.Common == c.Common && prev.Value == c.Value {
+					return nil, fmt.Errorf("line %d: %s: duplicate value of %s", lineno, c.Name, prev.Name)
+				}
+			}
+			h.Codes = append(h.Codes, c)
+			last = c
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if private > 0 {
+		return nil, fmt.Errorf("line %d: unterminated #ifdef PRIVATE", lineno)
+	}
+	if len(h.Subs) == 0 || len(h.Codes) == 0 {
+		return nil, fmt.Errorf("no sub_iokit_ or kIOReturn definition")
+	}
+
+	return &h, nil
+}
+
+// subNames are the Go names of the subsystems whose name is not the title
+// case of their C name.
+var subNames = map[string]string{
+	"usb":                           "USB",
+	"firewire":                      "FireWire",
+	"pmu":                           "PMU",
+	"acpi":                          "ACPI",
+	"smbus":                         "SMBus",
+	"ahci":                          "AHCI",
+	"powermanagement":               "PowerManagement",
+	"hidsystem":                     "HIDSystem",
+	"scsi":                          "SCSI",
+	"usbaudio":                      "USBAudio",
+	"wirelesscharging":              "WirelessCharging",
+	"apfs":                          "APFS",
+	"acpiec":                        "ACPIEC",
+	"timesync_avb":                  "TimesyncAVB",
+	"cec":                           "CEC",
+	"arc":                           "ARC",
+	"HDA":                           "HDA",
+	"hsic":                          "HSIC",
+	"sdio":                          "SDIO",
+	"wlan":                          "WLAN",
+	"appleembeddedsleepwakehandler": "AppleEmbeddedSleepWakeHandler",
+	"appleppm":                      "ApplePPM",
+}
+
+// goName returns the Go name of the subsystem s, such as "IOKitSubUSB".
+func (s *subsystem) goName() string {
+	if name, ok := subNames[s.Name]; ok {
+		return "IOKitSub" + name
+	}
+
+	var b strings.Builder
+	b.WriteString("IOKitSub")
+	for _, w := range strings.Split(s.Name, "_") {
+		b.WriteString(strings.ToUpper(w[:1]))
+		b.WriteString(w[1:])
+	}
+
+	return b.String()
+}
+
+// goName returns the Go name of the code c, such as "IOReturnBadArgument".
+func (c *code) goName() string {
+	return strings.TrimPrefix(c.Name, "k")
+}
+
+// message returns the error message of the code c.
+func (c *code) message() string {
+	words := strings.Fields(c.Comment)
+	for i, w := range words {
+		if isCapitalized(w) {
+			words[i] = strings.ToLower(w)
+		}
+	}
+
+	return strings.TrimRight(strings.Join(words, " "), ".!")
+}
+
+// isCapitalized reports whether w is an upper case letter followed by lower
+// case letters only, unlike an acronym such as "I/O" or "OK".
+func isCapitalized(w string) bool {
+	for i, r := range w {
+		if i == 0 && !unicode.IsUpper(r) || i > 0 && !unicode.IsLower(r) {
+			return false
+		}
+	}
+
+	return len(w) > 1
+}
+
+// value returns the mach_error_t value of the code c, as an unsigned number.
+func (c *code) value() uint32 {
+	if !c.Common {
+		return 0
+	}
+
+	return 0x38<<26 | uint32(c.Value)
+}
+
+// generate returns the formatted source of zioreturn.go for h.
+func generate(h *header) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by internal/mkioreturn; DO NOT EDIT.\n\n")
+	buf.WriteString("package sys\n\n")
+
+	buf.WriteString("// list of IOKitSubsystem.\nconst (\n")
+	for _, s := range h.Subs {
+		fmt.Fprintf(&buf, "\t%s IOKitSubsystem = %#x // sub_iokit_%s\n", s.goName(), s.Value, s.Name)
+	}
+	buf.WriteString(")\n\n")
+
+	buf.WriteString("// IOKitSubsystem name table.\nvar ioKitSubsystemNames = map[IOKitSubsystem]string{\n")
+	for _, s := range h.Subs {
+		fmt.Fprintf(&buf, "\t%s: %q,\n", s.goName(), s.Name)
+	}
+	buf.WriteString("}\n\n")
+
+	buf.WriteString("// list of IOReturn errors.\nconst (\n")
+	for i, c := range h.Codes {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(&buf, "\t// %s is %s (%#x): %s.\n", c.goName(), c.Name, c.value(), strings.TrimRight(c.Comment, "."))
+		if c.Common {
+			fmt.Fprintf(&buf, "\t%s IOReturn = ioKitCommonErr | %#x\n", c.goName(), c.Value)
+		} else {
+			fmt.Fprintf(&buf, "\t%s IOReturn = 0x0\n", c.goName())
+		}
+	}
+	buf.WriteString(")\n\n")
+
+	buf.WriteString("// IOReturn name table.\nvar ioReturnNames = map[IOReturn]string{\n")
+	for _, c := range h.Codes {
+		fmt.Fprintf(&buf, "\t%s: %q,\n", c.goName(), c.Name)
+	}
+	buf.WriteString("}\n\n")
+
+	buf.WriteString("// IOReturn Error table.\nvar ioReturnErrors = map[IOReturn]string{\n")
+	for _, c := range h.Codes {
+		fmt.Fprintf(&buf, "\t%s: %q,\n", c.goName(), c.message())
+	}
+	buf.WriteString("}\n")
+
+	return format.Source(buf.Bytes())
+}
@@ -0,0 +1,117 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const root = "../.."
+
+func parseHeader(t *testing.T) *header {
+	t.Helper()
+
+	f, err := os.Open(filepath.Join(root, "internal", "include", "IOKit", "IOReturn.h"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	h, err := parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return h
+}
+
+// TestGenerated fails when zioreturn.go drifts from the IOReturn.h header.
+func TestGenerated(t *testing.T) {
+	want, err := generate(parseHeader(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(filepath.Join(root, "zioreturn.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("zioreturn.go is out of date, run go generate")
+	}
+}
+
+func TestParseHeader(t *testing.T) {
+	h := parseHeader(t)
+
+	subs := make(map[string]int)
+	for _, s := range h.Subs {
+		subs[s.Name] = s.Value
+	}
+	for name, want := range map[string]int{"common": 0, "usb": 1, "HDA": 0xfe, "vendor_specific": 0xffe, "reserved": 0xfff} {
+		if got, ok := subs[name]; !ok || got != want {
+			t.Errorf("sub_iokit_%s = %#x, %t; want %#x", name, got, ok, want)
+		}
+	}
+	for _, name := range []string{"pccard", "nvme", "smc"} {
+		if _, ok := subs[name]; ok {
+			t.Errorf("sub_iokit_%s is parsed, want skipped", name)
+		}
+	}
+
+	codes := make(map[string]*code)
+	for _, c := range h.Codes {
+		codes[c.Name] = c
+	}
+	tests := []struct {
+		name    string
+		value   uint32
+		message string
+	}{
+		{"kIOReturnSuccess", 0x0, "OK"},
+		{"kIOReturnError", 0xe00002bc, "general error"},
+		{"kIOReturnExclusiveAccess", 0xe00002c5, "exclusive access and device already open"},
+		{"kIOReturnIOError", 0xe00002ca, "general I/O error"},
+		{"kIOReturnBusy", 0xe00002d5, "device busy"},
+		{"kIOReturnUnformattedMedia", 0xe00002e5, "media not formatted"},
+		{"kIOReturnIsoTooOld", 0xe00002ee, "isochronous I/O request for distant past"},
+		{"kIOReturnInvalid", 0xe0000001, "should never be seen"},
+	}
+	for _, tt := range tests {
+		c, ok := codes[tt.name]
+		if !ok {
+			t.Errorf("%s not found", tt.name)
+			continue
+		}
+		if got := c.value(); got != tt.value {
+			t.Errorf("%s = %#x, want %#x", tt.name, got, tt.value)
+		}
+		if got := c.message(); got != tt.message {
+			t.Errorf("message(%s) = %q, want %q", tt.name, got, tt.message)
+		}
+	}
+	if _, ok := codes["kIOReturn???Error"]; ok {
+		t.Error("commented out kIOReturn???Error is parsed")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		src, err string
+	}{
+		{"#define sub_iokit_common err_sub(0)\n#ifdef PRIVATE\n", "unterminated #ifdef PRIVATE"},
+		{"#define sub_iokit_common err_sub(zero)\n", "invalid value"},
+		{"#define sub_iokit_common err_sub(0)\n#define kIOReturnA iokit_common_err(0x2bc)\n#define kIOReturnB iokit_common_err(0x2bc)\n", "duplicate value"},
+		{"#define kIOReturnError iokit_common_err(0x2bc)\n", "no sub_iokit_ or kIOReturn definition"},
+	}
+	for _, tt := range tests {
+		_, err := parse(strings.NewReader(tt.src))
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("parse(%q) error = %v, want %q", tt.src, err, tt.err)
+		}
+	}
+}
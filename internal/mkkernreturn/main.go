@@ -0,0 +1,250 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Command mkkernreturn generates the zkern_return.go file of package sys.
+//
+// It parses the checked-in copy of the mach/kern_return.h SDK header and
+// emits, from the same source, the KernReturn constants, their doc comments,
+// the KernReturn name table used by String and ParseKernReturn, and the
+// message table used by Error.
+//
+// The doc comment of a constant is the comment which follows its #define in
+// the header. The message of a constant is the first sentence of that comment,
+// without its leading article and final period, in the style of the syscall
+// package error tables.
+//
+// Run from the repository root:
+//
+//	go run ./internal/mkkernreturn
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	flagHeader = flag.String("header", "internal/include/mach/kern_return.h", "path of the mach/kern_return.h header")
+	flagOut    = flag.String("o", "zkern_return.go", "output file")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("mkkernreturn: ")
+	flag.Parse()
+
+	f, err := os.Open(*flagHeader)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	codes, err := parse(f)
+	if err != nil {
+		log.Fatalf("%s: %v", *flagHeader, err)
+	}
+
+	src, err := generate(codes)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*flagOut, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// code is a kern_return_t code defined by the header.
+type code struct {
+	Name    string   // C name, such as "KERN_INVALID_VALUE"
+	Value   int32    // value of the code
+	Comment []string // lines of the comment following the #define, if any
+}
+
+var defineRE = regexp.MustCompile(`^#define\s+(KERN_[A-Z0-9_]+)\s+(\S+)\s*$`)
+
+// parse returns the kern_return_t codes defined by the header read from r,
+// in the order of the header.
+func parse(r io.Reader) ([]*code, error) {
+	var (
+		codes     []*code
+		last      *code // last code, whose comment is not read yet
+		inComment bool
+		lineno    int
+	)
+
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		lineno++
+		line := strings.TrimSpace(sc.Text())
+
+		if inComment {
+			end := strings.HasSuffix(line, "*/")
+			text := strings.TrimSuffix(line, "*/")
+			if last != nil {
+				text = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(text), "*"))
+				if text != "" {
+					last.Comment = append(last.Comment, text)
+				}
+			}
+			if end {
+				inComment = false
+				last = nil
+			}
+			continue
+		}
+
+		if strings.HasPrefix(line, "/*") {
+			text := strings.TrimPrefix(line, "/*")
+			end := strings.HasSuffix(text, "*/")
+			text = strings.TrimSuffix(text, "*/")
+			if last != nil {
+				if text = strings.TrimSpace(text); text != "" {
+					last.Comment = append(last.Comment, text)
+				}
+			}
+			if end {
+				last = nil
+			} else {
+				inComment = true
+			}
+			continue
+		}
+
+		if line == "" {
+			continue
+		}
+		last = nil
+
+		m := defineRE.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		v, err := strconv.ParseInt(m[2], 0, 32)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s: invalid value %q", lineno, m[1], m[2])
+		}
+		for _, c := range codes {
+			if c.Value == int32(v) {
+				return nil, fmt.Errorf("line %d: %s: duplicate value %#x of %s", lineno, m[1], v, c.Name)
+			}
+		}
+		last = &code{Name: m[1], Value: int32(v)}
+		codes = append(codes, last)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if inComment {
+		return nil, fmt.Errorf("line %d: unterminated comment", lineno)
+	}
+	if len(codes) == 0 {
+		return nil, fmt.Errorf("no KERN_ definition")
+	}
+
+	return codes, nil
+}
+
+// initialisms are the words of the C names which keep their case in Go names.
+var initialisms = map[string]bool{
+	"KC":  true,
+	"RPC": true,
+}
+
+// goName returns the Go name of the C name of a code, such as
+// "KernInvalidValue" for "KERN_INVALID_VALUE".
+func goName(name string) string {
+	var b strings.Builder
+	for _, w := range strings.Split(name, "_") {
+		if initialisms[w] {
+			b.WriteString(w)
+			continue
+		}
+		b.WriteString(w[:1])
+		b.WriteString(strings.ToLower(w[1:]))
+	}
+
+	return b.String()
+}
+
+// message returns the error message of the code c: the first sentence of its
+// comment, without leading article and final period, and starting with a lower
+// case letter unless the first word is an acronym.
+func message(c *code) string {
+	if len(c.Comment) == 0 {
+		if c.Value == 0 {
+			return "success"
+		}
+		return ""
+	}
+
+	s := strings.Join(c.Comment, " ")
+	if i := strings.Index(s, ". "); i >= 0 {
+		s = s[:i]
+	}
+	s = strings.TrimSuffix(strings.TrimSpace(s), ".")
+	for _, article := range []string{"The ", "An ", "A "} {
+		if strings.HasPrefix(s, article) {
+			s = strings.TrimPrefix(s, article)
+			break
+		}
+	}
+
+	r, n := utf8.DecodeRuneInString(s)
+	if r2, _ := utf8.DecodeRuneInString(s[n:]); !unicode.IsUpper(r2) {
+		s = string(unicode.ToLower(r)) + s[n:]
+	}
+
+	return s
+}
+
+// generate returns the formatted source of zkern_return.go for codes.
+func generate(codes []*code) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by internal/mkkernreturn; DO NOT EDIT.\n\n")
+	buf.WriteString("package sys\n\n")
+
+	buf.WriteString("// list of KernReturn errors.\nconst (\n")
+	for i, c := range codes {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(&buf, "\t// %s is %s.\n", goName(c.Name), c.Name)
+		if len(c.Comment) > 0 {
+			buf.WriteString("\t//\n")
+			for _, line := range c.Comment {
+				fmt.Fprintf(&buf, "\t// %s\n", line)
+			}
+		}
+		fmt.Fprintf(&buf, "\t%s KernReturn = %#x\n", goName(c.Name), c.Value)
+	}
+	buf.WriteString(")\n\n")
+
+	buf.WriteString("// KernReturn name table.\nvar kernReturnNames = [...]string{\n")
+	for _, c := range codes {
+		if c.Value >= 0 {
+			fmt.Fprintf(&buf, "\t%#x: %q,\n", c.Value, c.Name)
+		}
+	}
+	buf.WriteString("}\n\n")
+
+	buf.WriteString("// KernReturn Error table.\nvar kernReturnErrors = [...]string{\n")
+	for _, c := range codes {
+		if msg := message(c); c.Value >= 0 && msg != "" {
+			fmt.Fprintf(&buf, "\t%#x: %q,\n", c.Value, msg)
+		}
+	}
+	buf.WriteString("}\n")
+
+	return format.Source(buf.Bytes())
+}
@@ -0,0 +1,122 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const root = "../.."
+
+func parseHeader(t *testing.T) []*code {
+	t.Helper()
+
+	f, err := os.Open(filepath.Join(root, "internal", "include", "mach", "kern_return.h"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	codes, err := parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return codes
+}
+
+// TestGenerated fails when zkern_return.go drifts from the kern_return.h header.
+func TestGenerated(t *testing.T) {
+	want, err := generate(parseHeader(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(filepath.Join(root, "zkern_return.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("zkern_return.go is out of date, run go generate")
+	}
+}
+
+func TestParseHeader(t *testing.T) {
+	codes := parseHeader(t)
+
+	byName := make(map[string]*code)
+	for _, c := range codes {
+		byName[c.Name] = c
+	}
+
+	tests := []struct {
+		name    string
+		value   int32
+		message string
+	}{
+		{"KERN_SUCCESS", 0x0, "success"},
+		{"KERN_PROTECTION_FAILURE", 0x2, "specified memory is valid, but does not permit the required forms of access"},
+		{"KERN_FAILURE", 0x5, "function could not be performed"},
+		{"KERN_NOT_RECEIVER", 0x7, "task in question does not hold receive rights for the port argument"},
+		{"KERN_NO_ACCESS", 0x8, "bogus access restriction"},
+		{"KERN_MEMORY_PRESENT", 0x17, `attempt was made to supply "precious" data for memory that is already present in a memory object`},
+		{"KERN_NOT_WAITING", 0x30, "signalled thread was not actually waiting"},
+		{"KERN_MISSING_KC", 0x36, "KC on which the function is operating is missing"},
+		{"KERN_NOT_FOUND", 0x38, "search or query operation did not return a result"},
+		{"KERN_RETURN_MAX", 0x100, "maximum return value allowable"},
+	}
+	for _, tt := range tests {
+		c, ok := byName[tt.name]
+		if !ok {
+			t.Errorf("%s not found", tt.name)
+			continue
+		}
+		if c.Value != tt.value {
+			t.Errorf("%s = %#x, want %#x", tt.name, c.Value, tt.value)
+		}
+		if got := message(c); got != tt.message {
+			t.Errorf("message(%s) = %q, want %q", tt.name, got, tt.message)
+		}
+	}
+
+	for i, c := range codes[:len(codes)-1] {
+		if c.Value != int32(i) {
+			t.Errorf("codes[%d] = %s (%#x), want consecutive values", i, c.Name, c.Value)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		src, err string
+	}{
+		{"#define KERN_SUCCESS 0\n/* unterminated\n", "unterminated comment"},
+		{"#define KERN_SUCCESS 0\n#define KERN_FAILURE 0\n", "duplicate value"},
+		{"#define KERN_SUCCESS zero\n", "invalid value"},
+		{"#define MACH_MSG_SUCCESS 0\n", "no KERN_ definition"},
+	}
+	for _, tt := range tests {
+		_, err := parse(strings.NewReader(tt.src))
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("parse(%q) error = %v, want %q", tt.src, err, tt.err)
+		}
+	}
+}
+
+func TestGoName(t *testing.T) {
+	tests := map[string]string{
+		"KERN_SUCCESS":               "KernSuccess",
+		"KERN_UREFS_OVERFLOW":        "KernUrefsOverflow",
+		"KERN_RPC_SERVER_TERMINATED": "KernRPCServerTerminated",
+		"KERN_INVALID_KC":            "KernInvalidKC",
+	}
+	for name, want := range tests {
+		if got := goName(name); got != want {
+			t.Errorf("goName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
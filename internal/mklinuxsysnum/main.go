@@ -0,0 +1,265 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Command mklinuxsysnum generates the zlinux_sysnum.go file of package sys.
+//
+// It emits the linux syscall name tables of amd64 and arm64, and the linux
+// errno name and message tables, so that the linux syscalls can be decoded
+// on every host. They are read from the syscall package of the Go
+// installation, in $GOROOT/src/syscall/zsysnum_linux_<goarch>.go and
+// $GOROOT/src/syscall/zerrors_linux_amd64.go, the errno values being the same
+// on both architectures.
+//
+// When several names share an errno value, such as EAGAIN and EWOULDBLOCK,
+// the name table holds the first of them in lexical order.
+//
+// Run from the repository root:
+//
+//	go run ./internal/mklinuxsysnum
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// goarches are the GOARCH of the generated syscall name tables.
+var goarches = []string{"amd64", "arm64"}
+
+var (
+	flagGoroot = flag.String("goroot", build.Default.GOROOT, "root of the Go installation")
+	flagOut    = flag.String("o", "zlinux_sysnum.go", "output file")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("mklinuxsysnum: ")
+	flag.Parse()
+
+	t, err := load(filepath.Join(*flagGoroot, "src", "syscall"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src, err := generate(t)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*flagOut, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// tables are the tables read from the syscall package.
+type tables struct {
+	Syscalls map[string]map[int]string // syscall names by number, by GOARCH
+	Errnos   map[int]string            // errno names by value
+	Messages map[int]string            // errno messages by value
+}
+
+// load reads the tables from the syscall package directory dir.
+func load(dir string) (*tables, error) {
+	t := &tables{
+		Syscalls: make(map[string]map[int]string),
+		Errnos:   make(map[int]string),
+		Messages: make(map[int]string),
+	}
+
+	for _, goarch := range goarches {
+		f, err := parser.ParseFile(token.NewFileSet(), filepath.Join(dir, "zsysnum_linux_"+goarch+".go"), nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		if t.Syscalls[goarch], err = parseSysnum(f); err != nil {
+			return nil, fmt.Errorf("zsysnum_linux_%s.go: %w", goarch, err)
+		}
+	}
+
+	f, err := parser.ParseFile(token.NewFileSet(), filepath.Join(dir, "zerrors_linux_amd64.go"), nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := parseErrors(f, t); err != nil {
+		return nil, fmt.Errorf("zerrors_linux_amd64.go: %w", err)
+	}
+
+	return t, nil
+}
+
+// parseSysnum returns the syscall names of the SYS_* constants of f, such as
+// "read" for SYS_READ, by number. When several constants share a number, such
+// as SYS_SYNC_FILE_RANGE and SYS_SYNC_FILE_RANGE2, the first one is kept.
+func parseSysnum(f *ast.File) (map[int]string, error) {
+	names := make(map[int]string)
+	for _, vs := range constSpecs(f) {
+		for i, name := range vs.Names {
+			if !strings.HasPrefix(name.Name, "SYS_") || i >= len(vs.Values) {
+				continue
+			}
+			n, ok := intLit(vs.Values[i])
+			if !ok {
+				return nil, fmt.Errorf("%s: not an integer constant", name.Name)
+			}
+			if _, ok := names[n]; ok {
+				continue
+			}
+			names[n] = strings.ToLower(strings.TrimPrefix(name.Name, "SYS_"))
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no SYS_ constant")
+	}
+
+	return names, nil
+}
+
+// parseErrors reads the Errno constants and the errors message table of f
+// into t.
+func parseErrors(f *ast.File, t *tables) error {
+	for _, vs := range constSpecs(f) {
+		for i, name := range vs.Names {
+			if i >= len(vs.Values) {
+				continue
+			}
+			call, ok := vs.Values[i].(*ast.CallExpr)
+			if !ok || len(call.Args) != 1 {
+				continue
+			}
+			if fn, ok := call.Fun.(*ast.Ident); !ok || fn.Name != "Errno" {
+				continue
+			}
+			n, ok := intLit(call.Args[0])
+			if !ok {
+				return fmt.Errorf("%s: not an integer constant", name.Name)
+			}
+			if prev, ok := t.Errnos[n]; !ok || name.Name < prev {
+				t.Errnos[n] = name.Name
+			}
+		}
+	}
+	if len(t.Errnos) == 0 {
+		return fmt.Errorf("no Errno constant")
+	}
+
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			vs := spec.(*ast.ValueSpec)
+			if len(vs.Names) != 1 || vs.Names[0].Name != "errors" || len(vs.Values) != 1 {
+				continue
+			}
+			lit, ok := vs.Values[0].(*ast.CompositeLit)
+			if !ok {
+				return fmt.Errorf("errors: not a composite literal")
+			}
+			for _, elt := range lit.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					return fmt.Errorf("errors: unkeyed element")
+				}
+				n, ok := intLit(kv.Key)
+				s, ok2 := kv.Value.(*ast.BasicLit)
+				if !ok || !ok2 || s.Kind != token.STRING {
+					return fmt.Errorf("errors: invalid element")
+				}
+				msg, err := strconv.Unquote(s.Value)
+				if err != nil {
+					return err
+				}
+				t.Messages[n] = msg
+			}
+		}
+	}
+	if len(t.Messages) == 0 {
+		return fmt.Errorf("no errors table")
+	}
+
+	return nil
+}
+
+// constSpecs returns the specs of the const declarations of f.
+func constSpecs(f *ast.File) []*ast.ValueSpec {
+	var specs []*ast.ValueSpec
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			specs = append(specs, spec.(*ast.ValueSpec))
+		}
+	}
+
+	return specs
+}
+
+// intLit returns the value of the integer literal x.
+func intLit(x ast.Expr) (int, bool) {
+	lit, ok := x.(*ast.BasicLit)
+	if !ok || lit.Kind != token.INT {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(lit.Value, 0, 32)
+	if err != nil {
+		return 0, false
+	}
+
+	return int(n), true
+}
+
+// sortedKeys returns the keys of m in increasing order.
+func sortedKeys(m map[int]string) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+
+	return keys
+}
+
+// generate returns the formatted source of zlinux_sysnum.go for t.
+func generate(t *tables) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by internal/mklinuxsysnum; DO NOT EDIT.\n\n")
+	buf.WriteString("package sys\n")
+
+	for _, goarch := range goarches {
+		names := t.Syscalls[goarch]
+		fmt.Fprintf(&buf, "\n// linux/%s syscall name table, indexed by syscall number.\n", goarch)
+		fmt.Fprintf(&buf, "var linuxSyscallNames%s = [...]string{\n", strings.ToUpper(goarch))
+		for _, n := range sortedKeys(names) {
+			fmt.Fprintf(&buf, "\t%d: %q,\n", n, names[n])
+		}
+		buf.WriteString("}\n")
+	}
+
+	buf.WriteString("\n// linux errno name table.\nvar linuxErrnoNames = [...]string{\n")
+	for _, n := range sortedKeys(t.Errnos) {
+		fmt.Fprintf(&buf, "\t%d: %q,\n", n, t.Errnos[n])
+	}
+	buf.WriteString("}\n")
+
+	buf.WriteString("\n// linux errno Error table.\nvar linuxErrors = [...]string{\n")
+	for _, n := range sortedKeys(t.Messages) {
+		fmt.Fprintf(&buf, "\t%d: %q,\n", n, t.Messages[n])
+	}
+	buf.WriteString("}\n")
+
+	return format.Source(buf.Bytes())
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package main
+
+import (
+	"bytes"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const root = "../.."
+
+// TestGenerated fails when zlinux_sysnum.go drifts from the syscall package of
+// the Go installation.
+func TestGenerated(t *testing.T) {
+	tab, err := load(filepath.Join(build.Default.GOROOT, "src", "syscall"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := generate(tab)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(filepath.Join(root, "zlinux_sysnum.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("zlinux_sysnum.go is out of date, run go generate")
+	}
+}
+
+func TestParseSysnum(t *testing.T) {
+	src := "package syscall\n\nconst (\n\tSYS_READ = 0\n\tSYS_SYNC_FILE_RANGE = 84\n\tSYS_SYNC_FILE_RANGE2 = 84\n\tOTHER = 5\n)\n"
+	f, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names, err := parseSysnum(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 || names[0] != "read" || names[84] != "sync_file_range" {
+		t.Errorf("parseSysnum = %v", names)
+	}
+
+	f, err = parser.ParseFile(token.NewFileSet(), "", "package syscall\n\nconst SYS_X = 1 << 2\n", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parseSysnum(f); err == nil || !strings.Contains(err.Error(), "not an integer") {
+		t.Errorf("parseSysnum error = %v, want not an integer", err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	src := "package syscall\n\nconst (\n\tEWOULDBLOCK = Errno(0xb)\n\tEAGAIN = Errno(0xb)\n\tENOENT = Errno(0x2)\n)\n\n" +
+		"var errors = [...]string{\n\t2: \"no such file or directory\",\n\t11: \"resource temporarily unavailable\",\n}\n"
+	f, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tab := &tables{Errnos: make(map[int]string), Messages: make(map[int]string)}
+	if err := parseErrors(f, tab); err != nil {
+		t.Fatal(err)
+	}
+	if tab.Errnos[11] != "EAGAIN" || tab.Errnos[2] != "ENOENT" || tab.Messages[11] != "resource temporarily unavailable" {
+		t.Errorf("parseErrors = %v, %v", tab.Errnos, tab.Messages)
+	}
+
+	f, err = parser.ParseFile(token.NewFileSet(), "", "package syscall\n\nconst ENOENT = Errno(0x2)\n", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tab = &tables{Errnos: make(map[int]string), Messages: make(map[int]string)}
+	if err := parseErrors(f, tab); err == nil || !strings.Contains(err.Error(), "no errors table") {
+		t.Errorf("parseErrors error = %v, want no errors table", err)
+	}
+}
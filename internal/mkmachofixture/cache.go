@@ -0,0 +1,332 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package main
+
+import (
+	"encoding/binary"
+	"sort"
+)
+
+// the dyld shared cache fixture is a split cache of the arm64e images of
+// libsystem_c and libsystem_kernel:
+//
+//   - the main cache maps libsystem_c, and lists the images and the subcaches
+//   - the .01 subcache maps libsystem_kernel
+//   - the .02 subcache maps the __LINKEDIT data shared by the images
+//   - the .symbols file holds the local symbols of the images
+const (
+	cacheBase = 0x180000000
+
+	cacheName = "dyld_shared_cache_arm64e"
+
+	// cacheHeaderSize is the size of the dyld_cache_header, which ends with
+	// the cacheSubType field of the caches of macOS 13.
+	cacheHeaderSize = 0x200
+
+	cacheLinkVMOff   = 8 * pageSize // the __LINKEDIT mapping, in the .02 subcache
+	cacheLinkFileOff = 1 * pageSize
+
+	cacheFunc  = 0x200 // the first function of the images
+	cacheLocal = 0x280 // the local symbol of the images
+)
+
+// cacheImage is an image of the cache fixture.
+type cacheImage struct {
+	path     string
+	subcache int    // 0 for the main cache
+	vmOff    uint64 // from cacheBase
+	fileOff  uint64 // in the cache file of subcache
+	deps     []string
+	exports  []string // at cacheFunc, every 0x10 bytes
+	reexport map[string]string
+	local    string
+}
+
+var cacheImages = []cacheImage{
+	{
+		path:     "/usr/lib/system/libsystem_c.dylib",
+		vmOff:    1 * pageSize,
+		fileOff:  1 * pageSize,
+		deps:     []string{"/usr/lib/system/libsystem_kernel.dylib"},
+		exports:  []string{"_strlen", "_strcpy"},
+		reexport: map[string]string{"_write": "", "_c_getpid": "_getpid"},
+		local:    "_strlen_slow",
+	},
+	{
+		path:     "/usr/lib/system/libsystem_kernel.dylib",
+		subcache: 1,
+		vmOff:    5 * pageSize,
+		fileOff:  1 * pageSize,
+		exports:  []string{"_write", "_getpid"},
+		local:    "_kernel_trap",
+	},
+}
+
+// cacheUUID returns the UUID of the file of subcache, or of the .symbols
+// file for -1.
+func cacheUUID(subcache int) []byte {
+	uuid := make([]byte, 16)
+	for i := range uuid {
+		uuid[i] = byte(0xc0 + subcache)
+	}
+
+	return uuid
+}
+
+// cacheMapping is a dyld_cache_mapping_info.
+type cacheMapping struct {
+	vmOff, size, fileOff uint64
+	prot                 uint32
+}
+
+// cacheHeader returns the dyld_cache_header of the file of subcache, or of
+// the .symbols file for -1, and the header of the main cache sets the fields
+// of fields by offset. The mappings follow the header.
+func cacheHeader(subcache int, mappings []cacheMapping, fields map[int]uint64) []byte {
+	h := make([]byte, cacheHeaderSize)
+	copy(h, "dyld_v1  arm64e")
+	binary.LittleEndian.PutUint32(h[16:], cacheHeaderSize) // mappingOffset
+	binary.LittleEndian.PutUint32(h[20:], uint32(len(mappings)))
+	copy(h[88:], cacheUUID(subcache))
+	binary.LittleEndian.PutUint32(h[216:], 1) // PLATFORM_MACOS
+	binary.LittleEndian.PutUint64(h[224:], cacheBase)
+	for off, v := range fields {
+		switch off {
+		case 392, 396, 448, 452:
+			binary.LittleEndian.PutUint32(h[off:], uint32(v))
+		default:
+			binary.LittleEndian.PutUint64(h[off:], v)
+		}
+	}
+
+	w := &writer{}
+	w.Write(h)
+	for _, m := range mappings {
+		w.u64(cacheBase + m.vmOff)
+		w.u64(m.size)
+		w.u64(m.fileOff)
+		w.u32(m.prot)
+		w.u32(m.prot)
+	}
+
+	return w.Bytes()
+}
+
+// cacheFixtures returns the files of the cache fixture by name.
+func cacheFixtures() map[string][]byte {
+	// the shared __LINKEDIT data, of the export tries
+	var link writer
+	tries := make([][2]uint32, len(cacheImages))
+	for i, img := range cacheImages {
+		tries[i][0] = cacheLinkFileOff + uint32(link.Len())
+		link.Write(cacheExportTrie(img))
+		tries[i][1] = cacheLinkFileOff + uint32(link.Len()) - tries[i][0]
+		link.align(8)
+	}
+
+	files := [3]writer{}
+	for i, img := range cacheImages {
+		f := &files[img.subcache]
+		f.Write(make([]byte, int(img.fileOff)-f.Len()))
+		f.Write(cacheMachO(img, tries[i], uint64(link.Len())))
+	}
+
+	// the main cache: the header, the mappings, the images, the subcaches
+	// and the paths of the images
+	const (
+		imagesOff    = cacheHeaderSize + 32
+		subcachesOff = imagesOff + 2*32
+		pathsOff     = subcachesOff + 2*56
+	)
+	var main writer
+	main.Write(cacheHeader(0, []cacheMapping{{0, 2 * pageSize, 0, 5}}, map[int]uint64{
+		392: subcachesOff, // subCacheArrayOffset
+		396: 2,
+		400: binary.LittleEndian.Uint64(cacheUUID(-1)), // symbolFileUUID
+		408: binary.LittleEndian.Uint64(cacheUUID(-1)[8:]),
+		448: imagesOff, // imagesOffset
+		452: uint64(len(cacheImages)),
+	}))
+	path := uint32(pathsOff)
+	for _, img := range cacheImages {
+		main.u64(cacheBase + img.vmOff)
+		main.u64(0) // modTime
+		main.u64(0) // inode
+		main.u32(path)
+		main.u32(0)
+		path += uint32(len(img.path)) + 1
+	}
+	for i, sub := range []struct {
+		vmOff  uint64
+		suffix string
+	}{
+		{4 * pageSize, ".01"},
+		{cacheLinkVMOff, ".02"},
+	} {
+		// dyld_subcache_entry
+		main.Write(cacheUUID(i + 1))
+		main.u64(sub.vmOff)
+		main.name(sub.suffix, 32)
+	}
+	for _, img := range cacheImages {
+		main.WriteString(img.path)
+		main.WriteByte(0)
+	}
+	main.Write(files[0].Bytes()[main.Len():])
+	main.Write(make([]byte, 2*pageSize-main.Len()))
+
+	sub1 := cacheHeader(1, []cacheMapping{{4 * pageSize, 2 * pageSize, 0, 5}}, nil)
+	sub1 = append(sub1, files[1].Bytes()[len(sub1):]...)
+	sub1 = append(sub1, make([]byte, 2*pageSize-len(sub1))...)
+
+	sub2 := cacheHeader(2, []cacheMapping{{cacheLinkVMOff, pageSize, cacheLinkFileOff, 1}}, nil)
+	sub2 = append(sub2, make([]byte, cacheLinkFileOff-len(sub2))...)
+	sub2 = append(sub2, link.Bytes()...)
+	sub2 = append(sub2, make([]byte, 2*pageSize-len(sub2))...)
+
+	locals := cacheLocalSymbols()
+	symbols := cacheHeader(-1, nil, map[int]uint64{
+		72: cacheHeaderSize,     // localSymbolsOffset
+		80: uint64(len(locals)), // localSymbolsSize
+	})
+	symbols = append(symbols, locals...)
+
+	return map[string][]byte{
+		cacheName:              main.Bytes(),
+		cacheName + ".01":      sub1,
+		cacheName + ".02":      sub2,
+		cacheName + ".symbols": symbols,
+	}
+}
+
+// cacheMachO returns the mach_header and the load commands of img, and its
+// __text of return instructions, whose export trie is at trie of the
+// __LINKEDIT data of size linkSize.
+func cacheMachO(img cacheImage, trie [2]uint32, linkSize uint64) []byte {
+	var cmds writer
+	segment := func(name string, vmOff, size, fileOff, filesize uint64, prot uint32) {
+		cmds.u32(lcSegment64)
+		cmds.u32(sizeofSegment)
+		cmds.name(name, 16)
+		cmds.u64(cacheBase + vmOff)
+		cmds.u64(size)
+		cmds.u64(fileOff)
+		cmds.u64(filesize)
+		cmds.u32(prot)
+		cmds.u32(prot)
+		cmds.u32(0)
+		cmds.u32(0)
+	}
+	dylib := func(cmd uint32, name string) {
+		size := (24 + len(name) + 1 + 7) &^ 7
+		cmds.u32(cmd)
+		cmds.u32(uint32(size))
+		cmds.u32(24)
+		cmds.u32(2)
+		cmds.u32(0x10000)
+		cmds.u32(0x10000)
+		cmds.name(name, size-24)
+	}
+
+	segment("__TEXT", img.vmOff, pageSize, img.fileOff, pageSize, 5)
+	segment("__LINKEDIT", cacheLinkVMOff, pageSize, cacheLinkFileOff, linkSize, 1)
+	dylib(lcIDDylib, img.path)
+	for _, dep := range img.deps {
+		dylib(lcLoadDylib, dep)
+	}
+	cmds.u32(lcDyldExportsTrie)
+	cmds.u32(16)
+	cmds.u32(trie[0])
+	cmds.u32(trie[1])
+
+	var w writer
+	w.u32(mhMagic64)
+	w.u32(cpuARM64)
+	w.u32(2) // CPU_SUBTYPE_ARM64E
+	w.u32(mhDylib)
+	w.u32(uint32(4 + len(img.deps)))
+	w.u32(uint32(cmds.Len()))
+	w.u32(0x80000085) // MH_NOUNDEFS | MH_DYLDLINK | MH_TWOLEVEL | MH_DYLIB_IN_CACHE
+	w.u32(0)
+	w.Write(cmds.Bytes())
+	if w.Len() > cacheFunc {
+		panic("load commands overlap __text")
+	}
+	w.Write(make([]byte, cacheFunc-w.Len()))
+	for w.Len() < cacheLocal+0x10 {
+		w.u32(0xd65f03c0)
+	}
+
+	return w.Bytes()
+}
+
+// cacheExportTrie returns the export trie of img.
+func cacheExportTrie(img cacheImage) []byte {
+	root := &trieNode{}
+	terminals := make(map[string][]byte)
+	for i, name := range img.exports {
+		var t writer
+		t.uleb(0)
+		t.uleb(uint64(cacheFunc + 0x10*i))
+		terminals[name] = t.Bytes()
+	}
+	for name, imported := range img.reexport {
+		var t writer
+		t.uleb(0x08) // EXPORT_SYMBOL_FLAGS_REEXPORT
+		t.uleb(1)
+		t.WriteString(imported)
+		t.WriteByte(0)
+		terminals[name] = t.Bytes()
+	}
+	names := make([]string, 0, len(terminals))
+	for name := range terminals {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		root.insert(name, terminals[name])
+	}
+
+	return root.encode()
+}
+
+// cacheLocalSymbols returns the dyld_cache_local_symbols_info of the local
+// symbols of the images, followed by their nlist_64 entries, their string
+// table and the dyld_cache_local_symbols_entry_64 of the images.
+func cacheLocalSymbols() []byte {
+	const (
+		infoSize  = 6 * 4
+		nlistOff  = infoSize
+		stringOff = nlistOff + 2*16
+	)
+	var nlist, strs, entries writer
+	strs.WriteString(" \x00")
+	for i, img := range cacheImages {
+		nlist.u32(uint32(strs.Len()))
+		nlist.WriteByte(nSect)
+		nlist.WriteByte(1)
+		nlist.u16(0)
+		nlist.u64(cacheBase + img.vmOff + cacheLocal)
+		strs.WriteString(img.local)
+		strs.WriteByte(0)
+
+		entries.u64(img.vmOff) // dylibOffset
+		entries.u32(uint32(i)) // nlistStartIndex
+		entries.u32(1)         // nlistCount
+	}
+	strs.align(8)
+
+	var w writer
+	w.u32(nlistOff)
+	w.u32(uint32(len(cacheImages)))
+	w.u32(stringOff)
+	w.u32(uint32(strs.Len()))
+	w.u32(uint32(stringOff + strs.Len())) // entriesOffset
+	w.u32(uint32(len(cacheImages)))
+	w.Write(nlist.Bytes())
+	w.Write(strs.Bytes())
+	w.Write(entries.Bytes())
+
+	return w.Bytes()
+}
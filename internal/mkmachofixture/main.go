@@ -0,0 +1,616 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Command mkmachofixture generates the Mach-O fixtures of the tests of the
+// Mach-O symbol resolver of package sys in testdata/macho.
+//
+// The fixtures are small dylibs laid out like the ones of ld64, with no code
+// but the return instructions of their functions:
+//
+//   - libfixture.dylib is a universal file of a x86_64 dylib, whose fixup
+//     chains are of the DYLD_CHAINED_PTR_64 format, and of an arm64 dylib
+//     of the DYLD_CHAINED_PTR_64_OFFSET format. Their symbols are exported
+//     by LC_DYLD_EXPORTS_TRIE.
+//   - libfixture_dyldinfo.dylib is a x86_64 dylib exporting its symbols by
+//     the export trie of LC_DYLD_INFO_ONLY, without fixup chains.
+//   - libfixture_symtab.dylib is an arm64 dylib with a LC_SYMTAB only.
+//   - dyld_shared_cache_arm64e is a dyld shared cache split in subcaches,
+//     of two images of libSystem, and its .symbols file of local symbols.
+//
+// Run from the repository root:
+//
+//	go run ./internal/mkmachofixture
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+var flagDir = flag.String("dir", "testdata/macho", "output directory")
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("mkmachofixture: ")
+	flag.Parse()
+
+	for name, b := range fixtures() {
+		if err := os.WriteFile(filepath.Join(*flagDir, name), b, 0o644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// fixtures returns the content of the fixtures by file name.
+func fixtures() map[string][]byte {
+	files := map[string][]byte{
+		"libfixture.dylib": fat(
+			image(cpuX86_64, 0x10000, linkChained, dyldChainedPtr64),
+			image(cpuARM64, 0, linkChained, dyldChainedPtr64Offset),
+		),
+		"libfixture_dyldinfo.dylib": image(cpuX86_64, 0, linkDyldInfo, 0),
+		"libfixture_symtab.dylib":   image(cpuARM64, 0x100000000, linkSymtab, 0),
+	}
+	for name, b := range cacheFixtures() {
+		files[name] = b
+	}
+
+	return files
+}
+
+// list of the Mach-O constants.
+const (
+	cpuX86_64 = 0x01000007
+	cpuARM64  = 0x0100000c
+
+	mhMagic64 = 0xfeedfacf
+	fatMagic  = 0xcafebabe
+	mhDylib   = 6
+
+	lcSymtab            = 0x2
+	lcLoadDylib         = 0xc
+	lcIDDylib           = 0xd
+	lcSegment64         = 0x19
+	lcReexportDylib     = 0x8000001f
+	lcDyldInfoOnly      = 0x80000022
+	lcDyldExportsTrie   = 0x80000033
+	lcDyldChainedFixups = 0x80000034
+
+	nExt     = 0x01
+	nAbs     = 0x2
+	nSect    = 0xe
+	nPext    = 0x10
+	nWeakDef = 0x80
+
+	dyldChainedPtr64       = 2
+	dyldChainedPtr64Offset = 6
+
+	// pageSize is the size of the pages of the fixtures, which is smaller
+	// than the one of arm64 to keep them small.
+	pageSize = 0x1000
+)
+
+// linkedit is the kind of symbol information of a fixture.
+type linkedit int
+
+// list of linkedit.
+const (
+	linkChained  linkedit = iota // LC_DYLD_EXPORTS_TRIE and LC_DYLD_CHAINED_FIXUPS
+	linkDyldInfo                 // LC_DYLD_INFO_ONLY
+	linkSymtab                   // LC_SYMTAB only
+)
+
+// the layout of the fixtures, in offsets from the mach_header.
+const (
+	textOff  = 0x800 // __TEXT,__text
+	textSize = 0x60
+	dataOff  = 1 * pageSize // __DATA_CONST, of the __got section
+	gotSize  = 3 * 8
+	linkOff  = 2 * pageSize // __LINKEDIT, in the file
+
+	// linkVMOff is the offset of __LINKEDIT in memory, after a gap like the
+	// zero fill of __DATA, so that the file and memory layouts differ.
+	linkVMOff = 4 * pageSize
+)
+
+// symbol is a symbol of the fixtures.
+type symbol struct {
+	name     string
+	off      uint64 // offset from the mach_header, or absolute value
+	abs      bool
+	weak     bool
+	resolver uint64 // offset of the resolver of a stub-and-resolver symbol
+	local    bool   // not exported, in the symbol table only
+	hidden   bool   // private extern, in the symbol table only
+
+	// reexport of the dylib of ordinal 2, under the name reexport
+	reexported bool
+	reexport   string
+}
+
+var symbols = []symbol{
+	{name: "_fixture_add", off: textOff},
+	{name: "_fixture_sub", off: textOff + 0x10},
+	{name: "_fixture_weak", off: textOff + 0x20, weak: true},
+	{name: "_fixture_stub", off: textOff + 0x30, resolver: textOff + 0x38},
+	{name: "_fixture_local", off: textOff + 0x40, local: true},
+	{name: "_fixture_hidden", off: textOff + 0x48, hidden: true},
+	{name: "_fixture_abs", off: 0x2a, abs: true},
+	{name: "_strlen", reexported: true},
+	{name: "_fixture_strcpy", reexported: true, reexport: "_strcpy"},
+}
+
+// imports are the imports of the fixup chains, from libSystem.
+var imports = []struct {
+	name string
+	weak bool
+}{
+	{"_malloc", false},
+	{"_free", true},
+}
+
+const (
+	idDylib       = "/usr/lib/libfixture.dylib"
+	libSystem     = "/usr/lib/libSystem.B.dylib"
+	libsystemC    = "/usr/lib/system/libsystem_c.dylib"
+	bindAddend    = 4 // of the first pointer of the __got
+	rebaseTarget  = textOff
+	rebaseHigh8   = 0x80
+	sizeofSegment = 72
+	sizeofSection = 80
+)
+
+// writer appends little-endian values.
+type writer struct {
+	bytes.Buffer
+}
+
+func (w *writer) u16(v uint16) { binary.Write(w, binary.LittleEndian, v) }
+func (w *writer) u32(v uint32) { binary.Write(w, binary.LittleEndian, v) }
+func (w *writer) u64(v uint64) { binary.Write(w, binary.LittleEndian, v) }
+
+// name appends the name s in a NUL-padded field of n bytes.
+func (w *writer) name(s string, n int) {
+	w.WriteString(s)
+	w.Write(make([]byte, n-len(s)))
+}
+
+// align pads w with zeros to a multiple of n bytes.
+func (w *writer) align(n int) {
+	for w.Len()%n != 0 {
+		w.WriteByte(0)
+	}
+}
+
+func (w *writer) uleb(v uint64) {
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if v != 0 {
+			c |= 0x80
+		}
+		w.WriteByte(c)
+		if v == 0 {
+			return
+		}
+	}
+}
+
+func ulebLen(v uint64) int {
+	n := 1
+	for v >= 0x80 {
+		v >>= 7
+		n++
+	}
+
+	return n
+}
+
+// image returns a thin dylib of cputype, whose __TEXT segment is at base.
+func image(cputype uint32, base uint64, kind linkedit, format uint16) []byte {
+	// the __LINKEDIT content
+	var link writer
+	var fixups, exports [2]uint32
+	if kind == linkChained {
+		fixups[0] = linkOff + uint32(link.Len())
+		link.Write(chainedFixups(format))
+		fixups[1] = linkOff + uint32(link.Len()) - fixups[0]
+		link.align(8)
+	}
+	if kind != linkSymtab {
+		exports[0] = linkOff + uint32(link.Len())
+		link.Write(exportTrie())
+		exports[1] = linkOff + uint32(link.Len()) - exports[0]
+		link.align(8)
+	}
+	symoff := linkOff + uint32(link.Len())
+	nlist, strtab := symbolTable(base)
+	link.Write(nlist)
+	stroff := linkOff + uint32(link.Len())
+	link.Write(strtab)
+	link.align(8)
+
+	// the load commands
+	var cmds writer
+	ncmds := uint32(0)
+	segment := func(name string, addr, size, off, filesize uint64, prot uint32, sects func()) {
+		nsects := 0
+		if sects != nil {
+			nsects = 1
+		}
+		cmds.u32(lcSegment64)
+		cmds.u32(uint32(sizeofSegment + nsects*sizeofSection))
+		cmds.name(name, 16)
+		cmds.u64(base + addr)
+		cmds.u64(size)
+		cmds.u64(off)
+		cmds.u64(filesize)
+		cmds.u32(prot)
+		cmds.u32(prot)
+		cmds.u32(uint32(nsects))
+		cmds.u32(0)
+		if sects != nil {
+			sects()
+		}
+		ncmds++
+	}
+	section := func(sect, seg string, addr, size uint64, flags uint32) func() {
+		return func() {
+			cmds.name(sect, 16)
+			cmds.name(seg, 16)
+			cmds.u64(base + addr)
+			cmds.u64(size)
+			cmds.u32(uint32(addr))
+			cmds.u32(3)
+			cmds.u32(0)
+			cmds.u32(0)
+			cmds.u32(flags)
+			cmds.u32(0)
+			cmds.u32(0)
+			cmds.u32(0)
+		}
+	}
+	dylib := func(cmd uint32, name string) {
+		size := (24 + len(name) + 1 + 7) &^ 7
+		cmds.u32(cmd)
+		cmds.u32(uint32(size))
+		cmds.u32(24)
+		cmds.u32(2)       // timestamp
+		cmds.u32(0x10000) // current_version 1.0.0
+		cmds.u32(0x10000) // compatibility_version 1.0.0
+		cmds.name(name, size-24)
+		ncmds++
+	}
+	linkeditData := func(cmd uint32, data [2]uint32) {
+		cmds.u32(cmd)
+		cmds.u32(16)
+		cmds.u32(data[0])
+		cmds.u32(data[1])
+		ncmds++
+	}
+
+	segment("__TEXT", 0, pageSize, 0, pageSize, 5, section("__text", "__TEXT", textOff, textSize, 0x80000400))
+	segment("__DATA_CONST", dataOff, pageSize, dataOff, pageSize, 3, section("__got", "__DATA_CONST", dataOff, gotSize, 0x6))
+	segment("__LINKEDIT", linkVMOff, pageSize, linkOff, uint64(link.Len()), 1, nil)
+	dylib(lcIDDylib, idDylib)
+	dylib(lcLoadDylib, libSystem)
+	dylib(lcReexportDylib, libsystemC)
+	cmds.u32(lcSymtab)
+	cmds.u32(24)
+	cmds.u32(symoff)
+	cmds.u32(uint32(len(nlist) / 16))
+	cmds.u32(stroff)
+	cmds.u32(uint32(len(strtab)))
+	ncmds++
+	switch kind {
+	case linkChained:
+		linkeditData(lcDyldChainedFixups, fixups)
+		linkeditData(lcDyldExportsTrie, exports)
+	case linkDyldInfo:
+		cmds.u32(lcDyldInfoOnly)
+		cmds.u32(48)
+		for i := 0; i < 8; i++ {
+			cmds.u32(0) // rebase, bind, weak_bind and lazy_bind
+		}
+		cmds.u32(exports[0])
+		cmds.u32(exports[1])
+		ncmds++
+	}
+
+	var w writer
+	w.u32(mhMagic64)
+	w.u32(cputype)
+	if cputype == cpuX86_64 {
+		w.u32(3) // CPU_SUBTYPE_X86_64_ALL
+	} else {
+		w.u32(0) // CPU_SUBTYPE_ARM64_ALL
+	}
+	w.u32(mhDylib)
+	w.u32(ncmds)
+	w.u32(uint32(cmds.Len()))
+	w.u32(0x85) // MH_NOUNDEFS | MH_DYLDLINK | MH_TWOLEVEL
+	w.u32(0)
+	w.Write(cmds.Bytes())
+	if w.Len() > textOff {
+		panic("load commands overlap __text")
+	}
+
+	// __text, of return instructions
+	w.Write(make([]byte, textOff-w.Len()))
+	for w.Len() < textOff+textSize {
+		if cputype == cpuX86_64 {
+			w.WriteByte(0xc3)
+		} else {
+			w.u32(0xd65f03c0)
+		}
+	}
+
+	// __got
+	w.Write(make([]byte, dataOff-w.Len()))
+	if kind == linkChained {
+		// two binds to the imports, and a rebase to _fixture_add, chained
+		// with a stride of 4 bytes
+		next := uint64(8 / 4)
+		w.u64(1<<63 | next<<51 | bindAddend<<24)
+		w.u64(1<<63 | next<<51 | 1)
+		target := uint64(rebaseTarget)
+		if format == dyldChainedPtr64 {
+			target += base
+		}
+		w.u64(rebaseHigh8<<36 | target)
+	}
+
+	w.Write(make([]byte, linkOff-w.Len()))
+	w.Write(link.Bytes())
+
+	return w.Bytes()
+}
+
+// chainedFixups returns the LC_DYLD_CHAINED_FIXUPS data of the __got.
+func chainedFixups(format uint16) []byte {
+	const (
+		startsOff  = 32
+		segInfoOff = 4 + 3*4 // from startsOff, after the 3 segments
+		importsOff = startsOff + segInfoOff + 24
+	)
+	var syms writer
+	var entries []uint32
+	for i, imp := range imports {
+		// DYLD_CHAINED_IMPORT: lib_ordinal:8, weak_import:1, name_offset:23
+		e := uint32(1) | uint32(syms.Len())<<9
+		if imp.weak {
+			e |= 1 << 8
+		}
+		entries = append(entries, e)
+		syms.WriteString(imports[i].name)
+		syms.WriteByte(0)
+	}
+
+	var w writer
+	w.u32(0) // fixups_version
+	w.u32(startsOff)
+	w.u32(importsOff)
+	w.u32(importsOff + uint32(4*len(entries)))
+	w.u32(uint32(len(entries)))
+	w.u32(1) // DYLD_CHAINED_IMPORT
+	w.u32(0) // uncompressed symbols
+	w.align(8)
+
+	// dyld_chained_starts_in_image
+	w.u32(3)
+	w.u32(0)
+	w.u32(segInfoOff)
+	w.u32(0)
+
+	// dyld_chained_starts_in_segment of __DATA_CONST
+	w.u32(24)
+	w.u16(pageSize)
+	w.u16(format)
+	w.u64(dataOff)
+	w.u32(0) // max_valid_pointer
+	w.u16(1) // page_count
+	w.u16(0) // page_start
+
+	for _, e := range entries {
+		w.u32(e)
+	}
+	w.Write(syms.Bytes())
+
+	return w.Bytes()
+}
+
+// trieNode is a node of an export trie.
+type trieNode struct {
+	terminal []byte
+	edges    []trieEdge
+	off      int
+}
+
+type trieEdge struct {
+	label string
+	child *trieNode
+}
+
+// insert inserts the symbol name of the terminal information terminal
+// under n.
+func (n *trieNode) insert(name string, terminal []byte) {
+	if name == "" {
+		n.terminal = terminal
+		return
+	}
+	for i, e := range n.edges {
+		common := 0
+		for common < len(e.label) && common < len(name) && e.label[common] == name[common] {
+			common++
+		}
+		if common == 0 {
+			continue
+		}
+		if common < len(e.label) {
+			// split the edge
+			mid := &trieNode{edges: []trieEdge{{e.label[common:], e.child}}}
+			n.edges[i] = trieEdge{e.label[:common], mid}
+		}
+		n.edges[i].child.insert(name[common:], terminal)
+		return
+	}
+	child := &trieNode{}
+	n.edges = append(n.edges, trieEdge{name, child})
+	child.insert("", terminal)
+}
+
+// nodes appends the nodes under n in preorder to nodes.
+func (n *trieNode) nodes(nodes []*trieNode) []*trieNode {
+	nodes = append(nodes, n)
+	for _, e := range n.edges {
+		nodes = e.child.nodes(nodes)
+	}
+
+	return nodes
+}
+
+func (n *trieNode) size() int {
+	size := ulebLen(uint64(len(n.terminal))) + len(n.terminal) + 1
+	for _, e := range n.edges {
+		size += len(e.label) + 1 + ulebLen(uint64(e.child.off))
+	}
+
+	return size
+}
+
+// exportTrie returns the export trie of the exported symbols.
+func exportTrie() []byte {
+	root := &trieNode{}
+	sorted := append([]symbol(nil), symbols...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].name < sorted[j].name })
+	for _, s := range sorted {
+		if s.local || s.hidden {
+			continue
+		}
+		var t writer
+		switch {
+		case s.reexported:
+			t.uleb(0x08) // EXPORT_SYMBOL_FLAGS_REEXPORT
+			t.uleb(2)
+			t.WriteString(s.reexport)
+			t.WriteByte(0)
+		case s.resolver != 0:
+			t.uleb(0x10) // EXPORT_SYMBOL_FLAGS_STUB_AND_RESOLVER
+			t.uleb(s.off)
+			t.uleb(s.resolver)
+		case s.abs:
+			t.uleb(0x02) // EXPORT_SYMBOL_FLAGS_KIND_ABSOLUTE
+			t.uleb(s.off)
+		case s.weak:
+			t.uleb(0x04) // EXPORT_SYMBOL_FLAGS_WEAK_DEFINITION
+			t.uleb(s.off)
+		default:
+			t.uleb(0)
+			t.uleb(s.off)
+		}
+		root.insert(s.name, t.Bytes())
+	}
+
+	return root.encode()
+}
+
+// encode returns the export trie of root.
+func (root *trieNode) encode() []byte {
+	// lay the nodes out until the ULEB128 offsets of the children settle
+	nodes := root.nodes(nil)
+	for changed := true; changed; {
+		changed = false
+		off := 0
+		for _, n := range nodes {
+			if n.off != off {
+				n.off = off
+				changed = true
+			}
+			off += n.size()
+		}
+	}
+
+	var w writer
+	for _, n := range nodes {
+		w.uleb(uint64(len(n.terminal)))
+		w.Write(n.terminal)
+		w.WriteByte(byte(len(n.edges)))
+		for _, e := range n.edges {
+			w.WriteString(e.label)
+			w.WriteByte(0)
+			w.uleb(uint64(e.child.off))
+		}
+	}
+
+	return w.Bytes()
+}
+
+// symbolTable returns the nlist_64 entries and the string table of the
+// symbols, and of the undefined imports.
+func symbolTable(base uint64) (nlist, strtab []byte) {
+	var syms, strs writer
+	strs.WriteString(" \x00") // the index 0 is the empty name
+	add := func(name string, typ, sect uint8, desc uint16, value uint64) {
+		syms.u32(uint32(strs.Len()))
+		syms.WriteByte(typ)
+		syms.WriteByte(sect)
+		syms.u16(desc)
+		syms.u64(value)
+		strs.WriteString(name)
+		strs.WriteByte(0)
+	}
+
+	for _, s := range symbols {
+		var desc uint16
+		if s.weak {
+			desc = nWeakDef
+		}
+		switch {
+		case s.reexported:
+			// in the trie only
+		case s.abs:
+			add(s.name, nAbs|nExt, 0, 0, s.off)
+		case s.local:
+			add(s.name, nSect, 1, 0, base+s.off)
+		case s.hidden:
+			add(s.name, nSect|nExt|nPext, 1, 0, base+s.off)
+		default:
+			add(s.name, nSect|nExt, 1, desc, base+s.off)
+		}
+	}
+	for _, imp := range imports {
+		add(imp.name, nExt, 0, 1<<8, 0) // N_UNDF, of library ordinal 1
+	}
+	strs.align(8)
+
+	return syms.Bytes(), strs.Bytes()
+}
+
+// fat returns the universal file of the thin images.
+func fat(images ...[]byte) []byte {
+	var w writer
+	be := func(v uint32) { binary.Write(&w, binary.BigEndian, v) }
+	be(fatMagic)
+	be(uint32(len(images)))
+	off := uint32(pageSize)
+	for _, b := range images {
+		cputype := binary.LittleEndian.Uint32(b[4:])
+		be(cputype)
+		be(binary.LittleEndian.Uint32(b[8:]))
+		be(off)
+		be(uint32(len(b)))
+		be(12) // 2^12 alignment
+		off += (uint32(len(b)) + pageSize - 1) &^ (pageSize - 1)
+	}
+	for _, b := range images {
+		w.Write(make([]byte, (w.Len()+pageSize-1)&^(pageSize-1)-w.Len()))
+		w.Write(b)
+	}
+
+	return w.Bytes()
+}
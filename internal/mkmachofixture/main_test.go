@@ -0,0 +1,35 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const root = "../.."
+
+// TestGenerated fails when the fixtures of testdata/macho drift from the
+// generator.
+func TestGenerated(t *testing.T) {
+	for name, want := range fixtures() {
+		got, err := os.ReadFile(filepath.Join(root, "testdata", "macho", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("testdata/macho/%s is out of date, run go generate", name)
+		}
+	}
+}
+
+func TestExportTrie(t *testing.T) {
+	// the root node has no terminal, and the edges of the shared prefix
+	trie := exportTrie()
+	if trie[0] != 0 || trie[1] != 1 || !bytes.HasPrefix(trie[2:], []byte("_\x00")) {
+		t.Errorf("export trie root % x", trie[:8])
+	}
+}
@@ -0,0 +1,383 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	gotoken "go/token"
+	"strings"
+)
+
+// initialisms are the name parts spelled in upper case in Go names.
+var initialisms = map[string]bool{
+	"cpu": true, "gid": true, "id": true, "io": true, "ipc": true,
+	"ool": true, "pid": true, "uid": true, "url": true, "vm": true,
+}
+
+// goName returns the Go name of the C name s, such as ClockGetTime for
+// clock_get_time, exported or not.
+func goName(s string, exported bool) string {
+	var b strings.Builder
+	for _, part := range strings.Split(s, "_") {
+		if part == "" {
+			continue
+		}
+		lower := strings.ToLower(part)
+		switch {
+		case b.Len() == 0 && !exported:
+			b.WriteString(lower)
+		case initialisms[lower]:
+			b.WriteString(strings.ToUpper(part))
+		default:
+			b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+
+	return b.String()
+}
+
+// reserved are the identifiers of the generated stubs, which the names of
+// the arguments must not shadow.
+var reserved = map[string]bool{
+	"c": true, "d": true, "e": true, "i": true, "v": true, "ok": true, "kr": true,
+	"req": true, "rep": true, "data": true, "sys": true, "runtime": true, "unsafe": true,
+}
+
+// argName returns the Go name of the argument a.
+func argName(a *arg) string {
+	name := goName(a.Name, false)
+	if reserved[name] || gotoken.Lookup(name).IsKeyword() || strings.HasPrefix(name, "desc") {
+		name += "_"
+	}
+
+	return name
+}
+
+// resultName returns the Go name of the result of the out or inout argument
+// a, which has the Out suffix for an inout argument.
+func resultName(a *arg) string {
+	if a.Dir == dirInOut {
+		return goName(a.Name, false) + "Out"
+	}
+
+	return argName(a)
+}
+
+// goType returns the Go type of the values of t.
+func goType(t *mtype) string {
+	switch t.Kind {
+	case kindPort:
+		return "sys.MachPort"
+	case kindString:
+		return "string"
+	case kindArray:
+		if t.Variable {
+			return "[]" + t.Elem.GoType
+		}
+		return fmt.Sprintf("[%d]%s", t.Len, t.Elem.GoType)
+	}
+
+	return t.GoType
+}
+
+// zero returns the zero value of the Go type of t.
+func zero(t *mtype) string {
+	switch {
+	case t.Kind == kindString:
+		return `""`
+	case t.Kind == kindArray && t.Variable:
+		return "nil"
+	case t.Kind == kindArray:
+		return goType(t) + "{}"
+	}
+
+	return "0"
+}
+
+// unsigned reports whether the Go type of the integer type t is the one of
+// the MIGEncoder and MIGDecoder methods, which need no conversion.
+func unsigned(t *mtype) bool {
+	return t.GoType == fmt.Sprintf("uint%d", 8*t.Size) || t.GoType == "byte"
+}
+
+// put returns the statement encoding the integer v of type t.
+func put(t *mtype, v string) string {
+	if unsigned(t) {
+		return fmt.Sprintf("e.Put%d(%s)", 8*t.Size, v)
+	}
+
+	return fmt.Sprintf("e.Put%d(uint%d(%s))", 8*t.Size, 8*t.Size, v)
+}
+
+// get returns the expression decoding an integer of type t.
+func get(t *mtype) string {
+	if unsigned(t) {
+		return fmt.Sprintf("d.Get%d()", 8*t.Size)
+	}
+
+	return fmt.Sprintf("%s(d.Get%d())", t.GoType, 8*t.Size)
+}
+
+// inline reports whether the argument a has inline data in its message.
+func inline(a *arg) bool {
+	return a.Type.Kind != kindPort
+}
+
+// generate returns the formatted Go source of the client stubs of s, in the
+// package pkg, generated from the file named defs.
+func generate(s *subsystem, pkg, defs string) ([]byte, error) {
+	var body bytes.Buffer
+	needOOL := false
+	for _, r := range s.Routines {
+		if genRoutine(&body, s, r) {
+			needOOL = true
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by internal/mkmig from %s; DO NOT EDIT.\n\n", defs)
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	buf.WriteString("import (\n")
+	if needOOL {
+		buf.WriteString("\"runtime\"\n\"unsafe\"\n\n")
+	}
+	buf.WriteString("\"github.com/go-darwin/sys\"\n)\n\n")
+	fmt.Fprintf(&buf, "// %sSubsystemBase is the msgh_id of the first routine of the %s subsystem.\n", goName(s.Name, true), s.Name)
+	fmt.Fprintf(&buf, "const %sSubsystemBase = %d\n", goName(s.Name, true), s.Base)
+	buf.Write(body.Bytes())
+
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("%v\n%s", err, buf.Bytes())
+	}
+
+	return out, nil
+}
+
+// genRoutine writes the stub of the routine r to w, and reports whether it
+// sends out-of-line memory.
+func genRoutine(w *bytes.Buffer, s *subsystem, r *routine) (sendsOOL bool) {
+	port := r.Args[0]
+	args := r.Args[1:]
+
+	// signature
+	params := []string{"c sys.MIGClient", argName(port) + " sys.MachPort"}
+	var results, zeros []string
+	for _, a := range args {
+		name := argName(a)
+		if a.Dir != dirOut {
+			params = append(params, name+" "+goType(a.Type))
+		}
+		if a.Dir != dirIn {
+			name = resultName(a)
+			if a.Type.OOL {
+				results = append(results, name+" uint64", goName(a.Name, false)+"Cnt uint32")
+				zeros = append(zeros, "0", "0")
+			} else {
+				results = append(results, name+" "+goType(a.Type))
+				zeros = append(zeros, zero(a.Type))
+			}
+		}
+	}
+	results = append(results, "kr sys.KernReturn")
+	// the out arguments are left zero on error, like the MIG stubs which
+	// check the reply before copying them out
+	failed := "return"
+	if len(zeros) > 0 {
+		failed = "return " + strings.Join(zeros, ", ") + ", kr"
+	}
+
+	fmt.Fprintln(w)
+	if r.Simple {
+		fmt.Fprintf(w, "// %s sends the %s simpleroutine of the %s subsystem, msgh_id %d.\n", goName(r.Name, true), r.Name, s.Name, r.ID)
+	} else {
+		fmt.Fprintf(w, "// %s calls the %s routine of the %s subsystem, msgh_id %d.\n", goName(r.Name, true), r.Name, s.Name, r.ID)
+	}
+	for _, a := range args {
+		if a.Type.OOL && a.Dir == dirOut {
+			fmt.Fprintf(w, "//\n// The %s out-of-line memory of %sCnt elements is mapped in the task by the\n// kernel, and must be deallocated with vm_deallocate.\n", argName(a), goName(a.Name, false))
+		}
+	}
+	fmt.Fprintf(w, "func %s(%s) (%s) {\n", goName(r.Name, true), strings.Join(params, ", "), strings.Join(results, ", "))
+
+	// length checks of the inline variable arrays
+	for _, a := range args {
+		if a.Dir != dirOut && a.Type.Kind == kindArray && a.Type.Variable && !a.Type.OOL {
+			fmt.Fprintf(w, "if len(%s) > %d {\nkr = sys.KernReturn(sys.MigArrayTooLarge)\nreturn\n}\n", argName(a), a.Type.Len)
+		}
+	}
+
+	// request
+	var descs []string
+	hasData := false
+	for _, a := range args {
+		if a.Dir != dirOut && inline(a) || a.CountInOut {
+			hasData = true
+		}
+	}
+	if hasData {
+		fmt.Fprintf(w, "var e sys.MIGEncoder\ne.PutNDR()\n")
+	}
+	for _, a := range args {
+		name := argName(a)
+		t := a.Type
+		if a.Dir == dirOut {
+			if a.CountInOut {
+				fmt.Fprintf(w, "e.Put32(%d)\n", t.Len)
+			}
+			continue
+		}
+		switch {
+		case t.Kind == kindInt:
+			fmt.Fprintln(w, put(t, name))
+			if t.Size < 4 {
+				fmt.Fprintln(w, "e.Align()")
+			}
+		case t.Kind == kindPort:
+			descs = append(descs, fmt.Sprintf("&sys.MachMsgPortDesc{Name: sys.MachPortName(%s), Disposition: sys.%s}", name, t.Disposition))
+		case t.Kind == kindString:
+			fmt.Fprintf(w, "e.PutString(%s, %d)\n", name, t.Len)
+		case t.OOL:
+			sendsOOL = true
+			fmt.Fprintf(w, "e.Put32(uint32(len(%s)))\n", name)
+			addr := goName(a.Name, false) + "Addr"
+			fmt.Fprintf(w, "var %s uint64\nif len(%s) > 0 {\n%s = uint64(uintptr(unsafe.Pointer(&%s[0])))\n}\n", addr, name, addr, name)
+			size := fmt.Sprintf("len(%s)", name)
+			if t.Elem.Size > 1 {
+				size += fmt.Sprintf("*%d", t.Elem.Size)
+			}
+			copyOpt := "sys.MachMsgVirtualCopy"
+			if a.Physical {
+				copyOpt = "sys.MachMsgPhysicalCopy"
+			}
+			descs = append(descs, fmt.Sprintf("&sys.MachMsgOOLDesc{Address: %s, Size: uint32(%s), Deallocate: %t, Copy: %s}", addr, size, a.Dealloc, copyOpt))
+		default:
+			if t.Variable {
+				fmt.Fprintf(w, "e.Put32(uint32(len(%s)))\n", name)
+			}
+			fmt.Fprintf(w, "for _, v := range %s {\n%s\n}\n", name, put(t.Elem, "v"))
+			if t.Elem.Size < 4 {
+				fmt.Fprintln(w, "e.Align()")
+			}
+		}
+	}
+
+	local := "sys.MachMsgTypeMakeSendOnce"
+	if r.Simple {
+		local = "0"
+	}
+	fmt.Fprintf(w, "req := &sys.MachMsg{\nHeader: sys.MachMsgHeader{\n")
+	fmt.Fprintf(w, "Bits: sys.MakeMachMsgBits(sys.%s, %s, 0),\n", port.Type.Disposition, local)
+	fmt.Fprintf(w, "RemotePort: %s,\n", argName(port))
+	fmt.Fprintf(w, "ID: %d,\n},\n", r.ID)
+	if len(descs) > 0 {
+		fmt.Fprintf(w, "Descriptors: []sys.MachMsgDescriptor{\n%s,\n},\n", strings.Join(descs, ",\n"))
+	}
+	if hasData {
+		fmt.Fprintf(w, "Data: e.Bytes(),\n")
+	}
+	fmt.Fprintf(w, "}\n")
+
+	keepAlive := func() {
+		for _, a := range args {
+			if a.Type.OOL && a.Dir == dirIn {
+				fmt.Fprintf(w, "runtime.KeepAlive(%s)\n", argName(a))
+			}
+		}
+	}
+	if r.Simple {
+		if !sendsOOL {
+			fmt.Fprintf(w, "return c.Send(req)\n}\n")
+			return sendsOOL
+		}
+		fmt.Fprintf(w, "kr = c.Send(req)\n")
+		keepAlive()
+		fmt.Fprintf(w, "return kr\n}\n")
+		return sendsOOL
+	}
+
+	fmt.Fprintf(w, "rep, kr := c.Call(req)\n")
+	keepAlive()
+	fmt.Fprintf(w, "if kr != sys.KernSuccess {\nreturn\n}\n")
+
+	// reply
+	// the simple replies always have the NDR_record_t and the RetCode
+	ndesc := 0
+	ndr := false
+	for _, a := range args {
+		if a.Dir == dirIn {
+			continue
+		}
+		if a.Type.Kind == kindPort || a.Type.OOL {
+			ndesc++
+		}
+		if inline(a) {
+			ndr = true
+		}
+	}
+	fmt.Fprintf(w, "data, kr := sys.MIGCheckReply(rep, %d, %d, %t)\n", r.ID, ndesc, ndr || ndesc == 0)
+	fmt.Fprintf(w, "if kr != sys.KernSuccess {\nreturn\n}\n")
+
+	desc := 0
+	descNames := make(map[*arg]string)
+	for _, a := range args {
+		if a.Dir == dirIn || a.Type.Kind != kindPort && !a.Type.OOL {
+			continue
+		}
+		name := fmt.Sprintf("desc%d", desc)
+		descNames[a] = name
+		typ := "MachMsgPortDesc"
+		if a.Type.OOL {
+			typ = "MachMsgOOLDesc"
+		}
+		fmt.Fprintf(w, "%s, ok := rep.Descriptors[%d].(*sys.%s)\n", name, desc, typ)
+		fmt.Fprintf(w, "if !ok {\nkr = sys.KernReturn(sys.MigTypeError)\nreturn\n}\n")
+		desc++
+	}
+
+	fmt.Fprintf(w, "d := sys.NewMIGDecoder(data)\n")
+	for _, a := range args {
+		if a.Dir == dirIn {
+			continue
+		}
+		name := resultName(a)
+		t := a.Type
+		switch {
+		case t.Kind == kindInt:
+			fmt.Fprintf(w, "%s = %s\n", name, get(t))
+			if t.Size < 4 {
+				fmt.Fprintln(w, "d.Align()")
+			}
+		case t.Kind == kindPort:
+			fmt.Fprintf(w, "%s = sys.MachPort(%s.Name)\n", name, descNames[a])
+		case t.Kind == kindString:
+			fmt.Fprintf(w, "%s = d.GetString(%d)\n", name, t.Len)
+		case t.OOL:
+			count := goName(a.Name, false) + "Cnt"
+			fmt.Fprintf(w, "%s, %s = %s.Address, d.Get32()\n", name, count, descNames[a])
+			size := count
+			if t.Elem.Size > 1 {
+				size += fmt.Sprintf("*%d", t.Elem.Size)
+			}
+			fmt.Fprintf(w, "if %s.Size != %s {\nkr = sys.KernReturn(sys.MigTypeError)\n%s\n}\n", descNames[a], size, failed)
+		default:
+			if t.Variable {
+				fmt.Fprintf(w, "%s = make(%s, d.Count(%d))\n", name, goType(t), t.Len)
+			}
+			fmt.Fprintf(w, "for i := range %s {\n%s[i] = %s\n}\n", name, name, get(t.Elem))
+			if t.Elem.Size < 4 {
+				fmt.Fprintln(w, "d.Align()")
+			}
+		}
+	}
+	if len(zeros) == 0 {
+		fmt.Fprintf(w, "kr = d.Err()\nreturn\n}\n")
+		return sendsOOL
+	}
+	fmt.Fprintf(w, "if kr = d.Err(); kr != sys.KernSuccess {\n%s\n}\nreturn\n}\n", failed)
+
+	return sendsOOL
+}
@@ -0,0 +1,203 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Command mkmig generates Go client stubs from a MIG subsystem definition.
+//
+// It parses a .defs file and emits, for each routine and simpleroutine, a Go
+// function which builds the request with the Mach message codec of package
+// sys, sends it with a sys.MIGClient, checks and decodes the reply, and
+// returns the out arguments and a sys.KernReturn.
+//
+// The C preprocessor is not run: the #include directives are ignored, as the
+// standard MIG types are built in, and the conditional blocks are resolved
+// with no macro defined, so that #ifdef and #if NAME blocks are skipped, and
+// #ifndef and #if !NAME blocks taken.
+//
+// The supported subset of MIG is:
+//
+//   - the subsystem, routine, simpleroutine, skip and type statements, the
+//     import, userprefix and serverprefix statements being ignored;
+//   - the integer types, the port types of a single disposition, and the
+//     port names;
+//   - the fixed arrays and structs, the inline variable arrays and the
+//     out-of-line unbounded arrays of integers, and the fixed c_string;
+//   - the in, out and inout arguments, the request port being the first
+//     argument.
+//
+// Usage:
+//
+//	go run ./internal/mkmig [-package name] [-o file] file.defs
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	flagPackage = flag.String("package", "", "package name of the stubs, the name of the subsystem by default")
+	flagOut     = flag.String("o", "", "output file, z<name>.go by default")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("mkmig: ")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: mkmig [-package name] [-o file] file.defs\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	path := flag.Arg(0)
+
+	src, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s, err := parse(string(src))
+	if err != nil {
+		log.Fatalf("%s:%v", path, err)
+	}
+
+	pkg := *flagPackage
+	if pkg == "" {
+		pkg = s.Name
+	}
+	out, err := generate(s, pkg, filepath.Base(path))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	name := *flagOut
+	if name == "" {
+		name = "z" + strings.TrimSuffix(filepath.Base(path), ".defs") + ".go"
+	}
+	if err := os.WriteFile(name, out, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// subsystem is a MIG subsystem.
+type subsystem struct {
+	Name     string
+	Base     int
+	Routines []*routine
+}
+
+// routine is a routine or a simpleroutine.
+type routine struct {
+	Name   string
+	ID     int // msgh_id of the request
+	Simple bool
+	Args   []*arg // the first one is the request port
+}
+
+// direction is the direction of an argument.
+type direction int
+
+// list of direction.
+const (
+	dirIn direction = iota
+	dirOut
+	dirInOut
+)
+
+// arg is an argument of a routine.
+type arg struct {
+	Name       string
+	Dir        direction
+	Type       *mtype
+	Dealloc    bool // deallocate the out-of-line memory on send
+	Physical   bool // physically copy the out-of-line memory
+	CountInOut bool // send the capacity of an out variable array
+}
+
+// kind is the kind of a type.
+type kind int
+
+// list of kind.
+const (
+	kindInt    kind = iota // integer, or port name
+	kindPort               // port right, sent in a port descriptor
+	kindArray              // array of integers
+	kindString             // fixed c_string
+)
+
+// mtype is a MIG type.
+type mtype struct {
+	Kind kind
+
+	// Size is the size in bytes of a kindInt.
+	Size int
+
+	// Signed reports whether a kindInt is signed.
+	Signed bool
+
+	// GoType is the Go type of a kindInt, such as "int32".
+	GoType string
+
+	// Disposition is the name of the MachMsgTypeName constant of package sys
+	// of a kindPort, such as "MachMsgTypeCopySend".
+	Disposition string
+
+	// Elem is the element type of a kindArray.
+	Elem *mtype
+
+	// Len is the length of a fixed kindArray or kindString, or the maximum
+	// length of an inline variable kindArray.
+	Len int
+
+	// Variable reports whether a kindArray has a variable length.
+	Variable bool
+
+	// OOL reports whether a kindArray is sent out-of-line.
+	OOL bool
+}
+
+// intType returns a kindInt type of size bytes.
+func intType(size int, signed bool, goType string) *mtype {
+	return &mtype{Kind: kindInt, Size: size, Signed: signed, GoType: goType}
+}
+
+func portType(disposition string) *mtype {
+	return &mtype{Kind: kindPort, Disposition: disposition}
+}
+
+// builtinTypes are the types of mach/std_types.defs and mach/mach_types.defs
+// supported, and the IPC type names.
+var builtinTypes = map[string]*mtype{}
+
+func init() {
+	for _, t := range []struct {
+		names []string
+		typ   *mtype
+	}{
+		{[]string{"char", "MACH_MSG_TYPE_CHAR", "MACH_MSG_TYPE_BYTE", "byte", "uint8_t", "MACH_MSG_TYPE_INTEGER_8"}, intType(1, false, "byte")},
+		{[]string{"int8_t"}, intType(1, true, "int8")},
+		{[]string{"short", "int16", "int16_t", "MACH_MSG_TYPE_INTEGER_16"}, intType(2, true, "int16")},
+		{[]string{"uint16_t"}, intType(2, false, "uint16")},
+		{[]string{"int", "int32", "int32_t", "integer_t", "boolean_t", "kern_return_t", "MACH_MSG_TYPE_INTEGER_32", "MACH_MSG_TYPE_BOOLEAN"}, intType(4, true, "int32")},
+		{[]string{"unsigned", "unsigned32", "uint32_t", "natural_t", "mach_msg_type_number_t"}, intType(4, false, "uint32")},
+		{[]string{"int64", "int64_t", "MACH_MSG_TYPE_INTEGER_64"}, intType(8, true, "int64")},
+		{[]string{"unsigned64", "uint64_t", "mach_vm_address_t", "mach_vm_size_t", "mach_vm_offset_t"}, intType(8, false, "uint64")},
+		{[]string{"mach_port_name_t", "MACH_MSG_TYPE_PORT_NAME"}, intType(4, false, "sys.MachPortName")},
+		{[]string{"mach_port_t", "mach_port_copy_send_t", "MACH_MSG_TYPE_COPY_SEND"}, portType("MachMsgTypeCopySend")},
+		{[]string{"mach_port_move_send_t", "MACH_MSG_TYPE_MOVE_SEND"}, portType("MachMsgTypeMoveSend")},
+		{[]string{"mach_port_make_send_t", "MACH_MSG_TYPE_MAKE_SEND"}, portType("MachMsgTypeMakeSend")},
+		{[]string{"mach_port_move_send_once_t", "MACH_MSG_TYPE_MOVE_SEND_ONCE"}, portType("MachMsgTypeMoveSendOnce")},
+		{[]string{"mach_port_make_send_once_t", "MACH_MSG_TYPE_MAKE_SEND_ONCE"}, portType("MachMsgTypeMakeSendOnce")},
+		{[]string{"mach_port_move_receive_t", "MACH_MSG_TYPE_MOVE_RECEIVE"}, portType("MachMsgTypeMoveReceive")},
+		{[]string{"mach_port_copy_receive_t", "MACH_MSG_TYPE_COPY_RECEIVE"}, portType("MachMsgTypeCopyReceive")},
+	} {
+		for _, name := range t.names {
+			builtinTypes[name] = t.typ
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const root = "../.."
+
+var update = flag.Bool("update", false, "update the golden files of testdata")
+
+func parseFile(t *testing.T, path string) *subsystem {
+	t.Helper()
+
+	src, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := parse(string(src))
+	if err != nil {
+		t.Fatalf("%s:%v", path, err)
+	}
+
+	return s
+}
+
+// TestGolden compares the stubs generated from testdata/*.defs with the
+// testdata/*.golden files, in the package named after the subsystem.
+func TestGolden(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("testdata", "*.defs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("no testdata")
+	}
+
+	for _, path := range paths {
+		s := parseFile(t, path)
+		got, err := generate(s, s.Name, filepath.Base(path))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		golden := strings.TrimSuffix(path, ".defs") + ".golden"
+		if *update {
+			if err := os.WriteFile(golden, got, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		want, err := os.ReadFile(golden)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s differs from %s, run go test -update", path, golden)
+		}
+	}
+}
+
+// TestGenerated fails when internal/migtest/zecho.go drifts from echo.defs.
+func TestGenerated(t *testing.T) {
+	dir := filepath.Join(root, "internal", "migtest")
+	want, err := generate(parseFile(t, filepath.Join(dir, "echo.defs")), "migtest", "echo.defs")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, "zecho.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("internal/migtest/zecho.go is out of date, run go generate")
+	}
+}
+
+func TestParse(t *testing.T) {
+	s := parseFile(t, filepath.Join(root, "internal", "migtest", "echo.defs"))
+	if s.Name != "echo" || s.Base != 4200 {
+		t.Errorf("subsystem %s %d, want echo 4200", s.Name, s.Base)
+	}
+
+	// the skip statement advances the msgh_id, and the #ifndef block is taken
+	ids := map[string]int{}
+	for _, r := range s.Routines {
+		ids[r.Name] = r.ID
+	}
+	for name, id := range map[string]int{
+		"echo_ping":     4200,
+		"echo_notify":   4202,
+		"echo_swap":     4204,
+		"echo_get_data": 4207,
+		"echo_pid":      4209,
+	} {
+		if ids[name] != id {
+			t.Errorf("%s has msgh_id %d, want %d", name, ids[name], id)
+		}
+	}
+	if _, ok := ids["echo_pid_unavailable"]; ok {
+		t.Error("the #else block of #ifndef is taken")
+	}
+
+	args := map[string]*arg{}
+	for _, r := range s.Routines {
+		for _, a := range r.Args {
+			args[r.Name+"."+a.Name] = a
+		}
+	}
+	tests := []struct {
+		arg  string
+		want func(a *arg) bool
+	}{
+		{"echo_notify.name", func(a *arg) bool { return a.Type.Kind == kindString && a.Type.Len == 32 }},
+		{"echo_swap.pair", func(a *arg) bool {
+			return a.Dir == dirInOut && a.Type.Kind == kindArray && a.Type.Len == 2 && !a.Type.Variable
+		}},
+		{"echo_bytes.out_bytes", func(a *arg) bool { return a.Dir == dirOut && a.CountInOut && a.Type.Variable && a.Type.Len == 64 }},
+		{"echo_send_data.data", func(a *arg) bool { return a.Type.OOL && a.Dealloc && a.Type.Elem.Size == 1 }},
+		{"echo_send_data.words", func(a *arg) bool { return a.Type.OOL && a.Physical && a.Type.Elem.Size == 4 }},
+		{"echo_port.port", func(a *arg) bool { return a.Type.Kind == kindPort && a.Type.Disposition == "MachMsgTypeMakeSend" }},
+		{"echo_port.port_name", func(a *arg) bool { return a.Type.Kind == kindInt && a.Type.GoType == "sys.MachPortName" }},
+	}
+	for _, tt := range tests {
+		a, ok := args[tt.arg]
+		if !ok {
+			t.Errorf("no argument %s", tt.arg)
+			continue
+		}
+		if !tt.want(a) {
+			t.Errorf("argument %s: %+v, type %+v", tt.arg, a, a.Type)
+		}
+	}
+}
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"comment", "subsystem s 1; /* x", "1: unterminated comment"},
+		{"else", "#else\n", "1: #else without #if"},
+		{"if", "#ifdef X\nsubsystem s 1;\n", "unterminated #if"},
+		{"directive", "#error x\n", "1: unsupported directive #error"},
+		{"no routine", "subsystem s 1;\n", "no routine"},
+		{"no request port", "subsystem s 1;\nroutine r();\n", "2: r: no request port"},
+		{"simpleroutine out", "subsystem s 1;\nsimpleroutine r(p : mach_port_t; out x : int);\n", "r: out argument x of a simpleroutine"},
+		{"duplicate", "subsystem s 1;\nroutine r(p : mach_port_t; x : int; x : int);\n", "r: duplicate argument x"},
+		{"out request port", "subsystem s 1;\nroutine r(out p : mach_port_t);\n", "p: the request port is not an in port"},
+		{"unknown type", "subsystem s 1;\nroutine r(p : mach_port_t; x : foo_t);\n", "unknown type foo_t"},
+		{"variable c_string", "subsystem s 1;\ntype n = c_string[*:32];\n", "unsupported variable c_string"},
+		{"redeclared", "subsystem s 1;\ntype n = int;\ntype n = int;\n", "3: type n redeclared"},
+		{"inout port", "subsystem s 1;\nroutine r(p : mach_port_t; inout q : mach_port_t);\n", "q: unsupported inout port"},
+		{"countinout", "subsystem s 1;\nroutine r(p : mach_port_t; x : int, CountInOut);\n", "x: CountInOut of an argument"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parse(tt.src)
+			if err == nil {
+				t.Fatal("no error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error %q, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,576 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// token is a token of a .defs file.
+type token struct {
+	Text string
+	Line int
+}
+
+// preprocess removes the comments and the preprocessor directives of src,
+// and blanks the lines of the skipped conditional blocks. The lines are kept
+// in place, for the error messages.
+func preprocess(src string) (string, error) {
+	// remove the comments, keeping the newlines of the block comments
+	var b strings.Builder
+	for i := 0; i < len(src); i++ {
+		switch {
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return "", fmt.Errorf("%d: unterminated comment", strings.Count(src[:i], "\n")+1)
+			}
+			b.WriteString(strings.Repeat("\n", strings.Count(src[i:i+2+end], "\n")))
+			b.WriteByte(' ')
+			i += 2 + end + 1
+		case strings.HasPrefix(src[i:], "//"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src) - i
+			}
+			i += end - 1
+		default:
+			b.WriteByte(src[i])
+		}
+	}
+
+	// resolve the conditional blocks
+	type cond struct {
+		taken  bool // the current branch is taken
+		parent bool // the enclosing block is taken
+	}
+	var stack []cond
+	active := func() bool {
+		return len(stack) == 0 || stack[len(stack)-1].taken && stack[len(stack)-1].parent
+	}
+	lines := strings.Split(b.String(), "\n")
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || !strings.HasPrefix(fields[0], "#") {
+			if !active() {
+				lines[i] = ""
+			}
+			continue
+		}
+		lines[i] = ""
+
+		directive := strings.TrimPrefix(fields[0], "#")
+		if directive == "" && len(fields) > 1 {
+			directive, fields = fields[1], fields[1:]
+		}
+		expr := strings.Join(fields[1:], " ")
+		switch directive {
+		case "if":
+			// no macro is defined
+			taken := expr == "1" || strings.HasPrefix(expr, "!") && !strings.ContainsAny(expr[1:], "&|")
+			stack = append(stack, cond{taken: taken, parent: active()})
+		case "ifdef":
+			stack = append(stack, cond{taken: false, parent: active()})
+		case "ifndef":
+			stack = append(stack, cond{taken: true, parent: active()})
+		case "else":
+			if len(stack) == 0 {
+				return "", fmt.Errorf("%d: #else without #if", i+1)
+			}
+			stack[len(stack)-1].taken = !stack[len(stack)-1].taken
+		case "endif":
+			if len(stack) == 0 {
+				return "", fmt.Errorf("%d: #endif without #if", i+1)
+			}
+			stack = stack[:len(stack)-1]
+		case "include", "define", "undef", "pragma":
+		default:
+			return "", fmt.Errorf("%d: unsupported directive #%s", i+1, directive)
+		}
+	}
+	if len(stack) != 0 {
+		return "", fmt.Errorf("unterminated #if")
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// lex returns the tokens of the preprocessed src: the identifiers and
+// numbers, the string literals and the single punctuation characters.
+func lex(src string) ([]token, error) {
+	var toks []token
+	line := 1
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f':
+			i++
+		case isIdent(c):
+			j := i
+			for j < len(src) && isIdent(src[j]) {
+				j++
+			}
+			toks = append(toks, token{src[i:j], line})
+			i = j
+		case c == '"':
+			j := strings.IndexAny(src[i+1:], "\"\n")
+			if j < 0 || src[i+1+j] != '"' {
+				return nil, fmt.Errorf("%d: unterminated string", line)
+			}
+			toks = append(toks, token{src[i : i+2+j], line})
+			i += 2 + j
+		default:
+			toks = append(toks, token{src[i : i+1], line})
+			i++
+		}
+	}
+
+	return toks, nil
+}
+
+func isIdent(c byte) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+// parser parses the tokens of a .defs file.
+type parser struct {
+	toks  []token
+	pos   int
+	types map[string]*mtype
+}
+
+// parse parses the .defs file src.
+func parse(src string) (*subsystem, error) {
+	src, err := preprocess(src)
+	if err != nil {
+		return nil, err
+	}
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{toks: toks, types: make(map[string]*mtype)}
+	s, err := p.subsystem()
+	if err != nil {
+		if p.pos < len(p.toks) {
+			return nil, fmt.Errorf("%d: %v", p.toks[p.pos].Line, err)
+		}
+		return nil, fmt.Errorf("end of file: %v", err)
+	}
+
+	return s, nil
+}
+
+// peek returns the current token, or "" at the end.
+func (p *parser) peek() string {
+	if p.pos < len(p.toks) {
+		return p.toks[p.pos].Text
+	}
+
+	return ""
+}
+
+// next returns the current token and advances.
+func (p *parser) next() string {
+	t := p.peek()
+	if p.pos < len(p.toks) {
+		p.pos++
+	}
+
+	return t
+}
+
+// is reports whether the current token is the keyword kw, which are case
+// insensitive.
+func (p *parser) is(kw string) bool {
+	return strings.EqualFold(p.peek(), kw)
+}
+
+// expect consumes the token tok.
+func (p *parser) expect(tok string) error {
+	if !p.is(tok) {
+		return fmt.Errorf("found %q, want %q", p.peek(), tok)
+	}
+	p.pos++
+
+	return nil
+}
+
+// ident consumes an identifier.
+func (p *parser) ident() (string, error) {
+	t := p.peek()
+	if t == "" || !isIdent(t[0]) || '0' <= t[0] && t[0] <= '9' {
+		return "", fmt.Errorf("found %q, want an identifier", t)
+	}
+	p.pos++
+
+	return t, nil
+}
+
+// number consumes a number.
+func (p *parser) number() (int, error) {
+	t := p.peek()
+	n, err := strconv.ParseInt(t, 0, 32)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("found %q, want a number", t)
+	}
+	p.pos++
+
+	return int(n), nil
+}
+
+// skipStatement skips the tokens up to the end of the statement.
+func (p *parser) skipStatement() error {
+	for p.peek() != ";" {
+		if p.next() == "" {
+			return fmt.Errorf("unterminated statement")
+		}
+	}
+	p.pos++
+
+	return nil
+}
+
+func (p *parser) subsystem() (*subsystem, error) {
+	if err := p.expect("subsystem"); err != nil {
+		return nil, err
+	}
+	for p.is("KernelUser") || p.is("KernelServer") {
+		p.pos++
+	}
+	s := &subsystem{}
+	var err error
+	if s.Name, err = p.ident(); err != nil {
+		return nil, err
+	}
+	if s.Base, err = p.number(); err != nil {
+		return nil, err
+	}
+	if err := p.expect(";"); err != nil {
+		return nil, err
+	}
+
+	id := s.Base
+	for p.pos < len(p.toks) {
+		switch {
+		case p.is("routine"), p.is("simpleroutine"):
+			r, err := p.routine()
+			if err != nil {
+				return nil, err
+			}
+			r.ID = id
+			id++
+			s.Routines = append(s.Routines, r)
+		case p.is("skip"):
+			p.pos++
+			if err := p.expect(";"); err != nil {
+				return nil, err
+			}
+			id++
+		case p.is("type"):
+			if err := p.typeDecl(); err != nil {
+				return nil, err
+			}
+		case p.is("import"), p.is("uimport"), p.is("simport"), p.is("userprefix"), p.is("serverprefix"), p.is("rcsid"):
+			if err := p.skipStatement(); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unexpected %q", p.peek())
+		}
+	}
+	if len(s.Routines) == 0 {
+		return nil, fmt.Errorf("no routine")
+	}
+
+	return s, nil
+}
+
+// typeDecl parses a type statement. The C type annotations, such as ctype:
+// and intran:, are ignored.
+func (p *parser) typeDecl() error {
+	p.pos++
+	name, err := p.ident()
+	if err != nil {
+		return err
+	}
+	if _, ok := p.types[name]; ok {
+		return fmt.Errorf("type %s redeclared", name)
+	}
+	if err := p.expect("="); err != nil {
+		return err
+	}
+	t, err := p.typeSpec()
+	if err != nil {
+		return fmt.Errorf("type %s: %v", name, err)
+	}
+	p.types[name] = t
+
+	return p.skipStatement()
+}
+
+// typeSpec parses a type specification.
+func (p *parser) typeSpec() (*mtype, error) {
+	switch {
+	case p.is("^"):
+		p.pos++
+		if !p.is("array") {
+			return nil, fmt.Errorf("found %q after ^, want array", p.peek())
+		}
+		t, err := p.typeSpec()
+		if err != nil {
+			return nil, err
+		}
+		t.OOL, t.Variable = true, true
+		return t, nil
+
+	case p.is("array"):
+		p.pos++
+		if err := p.expect("["); err != nil {
+			return nil, err
+		}
+		t := &mtype{Kind: kindArray}
+		switch {
+		case p.is("]"):
+			// unbounded arrays are sent out-of-line
+			t.Variable, t.OOL = true, true
+		case p.is("*"):
+			p.pos++
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+			t.Variable = true
+			fallthrough
+		default:
+			n, err := p.number()
+			if err != nil {
+				return nil, err
+			}
+			t.Len = n
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		if err := p.expect("of"); err != nil {
+			return nil, err
+		}
+		elem, err := p.typeSpec()
+		if err != nil {
+			return nil, err
+		}
+		if elem.Kind != kindInt {
+			return nil, fmt.Errorf("unsupported array element type")
+		}
+		t.Elem = elem
+		return t, nil
+
+	case p.is("struct"):
+		p.pos++
+		if err := p.expect("["); err != nil {
+			return nil, err
+		}
+		n, err := p.number()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		if err := p.expect("of"); err != nil {
+			return nil, err
+		}
+		elem, err := p.typeSpec()
+		if err != nil {
+			return nil, err
+		}
+		if elem.Kind != kindInt {
+			return nil, fmt.Errorf("unsupported struct element type")
+		}
+		return &mtype{Kind: kindArray, Elem: elem, Len: n}, nil
+
+	case p.is("c_string"):
+		p.pos++
+		if err := p.expect("["); err != nil {
+			return nil, err
+		}
+		if p.is("*") {
+			return nil, fmt.Errorf("unsupported variable c_string")
+		}
+		n, err := p.number()
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 {
+			return nil, fmt.Errorf("empty c_string")
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		return &mtype{Kind: kindString, Len: n}, nil
+
+	case p.is("("):
+		// (IPC type name, size in bits)
+		p.pos++
+		name, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		t, ok := builtinTypes[name]
+		if !ok {
+			return nil, fmt.Errorf("unsupported IPC type %s", name)
+		}
+		if p.is(",") {
+			p.pos++
+			bits, err := p.number()
+			if err != nil {
+				return nil, err
+			}
+			if t.Kind == kindInt && bits != 8*t.Size {
+				return nil, fmt.Errorf("%s has %d bits, not %d", name, 8*t.Size, bits)
+			}
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return t, nil
+	}
+
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	if t, ok := p.types[name]; ok {
+		return copyType(t), nil
+	}
+	if t, ok := builtinTypes[name]; ok {
+		return copyType(t), nil
+	}
+
+	return nil, fmt.Errorf("unknown type %s", name)
+}
+
+// copyType returns a copy of t, which may then be changed.
+func copyType(t *mtype) *mtype {
+	c := *t
+	return &c
+}
+
+// routine parses a routine or a simpleroutine statement.
+func (p *parser) routine() (*routine, error) {
+	r := &routine{Simple: p.is("simpleroutine")}
+	p.pos++
+	var err error
+	if r.Name, err = p.ident(); err != nil {
+		return nil, err
+	}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	for !p.is(")") {
+		a, err := p.arg(len(r.Args) == 0)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", r.Name, err)
+		}
+		r.Args = append(r.Args, a)
+		if !p.is(")") {
+			if err := p.expect(";"); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// checked at the closing parenthesis, for the line of the errors
+	if len(r.Args) == 0 {
+		return nil, fmt.Errorf("%s: no request port", r.Name)
+	}
+	for _, a := range r.Args {
+		if r.Simple && a.Dir != dirIn {
+			return nil, fmt.Errorf("%s: out argument %s of a simpleroutine", r.Name, a.Name)
+		}
+		for _, b := range r.Args {
+			if a != b && a.Name == b.Name {
+				return nil, fmt.Errorf("%s: duplicate argument %s", r.Name, a.Name)
+			}
+		}
+	}
+	p.pos++
+	if err := p.expect(";"); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// arg parses an argument, the request port if first.
+func (p *parser) arg(first bool) (*arg, error) {
+	a := &arg{}
+	switch {
+	case p.is("in"):
+		p.pos++
+	case p.is("out"):
+		a.Dir = dirOut
+		p.pos++
+	case p.is("inout"):
+		a.Dir = dirInOut
+		p.pos++
+	case p.is("requestport"):
+		if !first {
+			return nil, fmt.Errorf("requestport is not the first argument")
+		}
+		p.pos++
+	case p.is("replyport"), p.is("ureplyport"), p.is("sreplyport"), p.is("waittime"), p.is("msgoption"),
+		p.is("msgseqno"), p.is("securetoken"), p.is("audittoken"), p.is("servercontext"):
+		return nil, fmt.Errorf("unsupported %s argument", strings.ToLower(p.peek()))
+	}
+
+	var err error
+	if a.Name, err = p.ident(); err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	if a.Type, err = p.typeSpec(); err != nil {
+		return nil, fmt.Errorf("%s: %v", a.Name, err)
+	}
+
+	for p.is(",") {
+		p.pos++
+		flag, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		switch strings.ToLower(flag) {
+		case "dealloc":
+			a.Dealloc = true
+		case "physicalcopy":
+			a.Physical = true
+		case "countinout":
+			a.CountInOut = true
+		case "notdealloc", "servercopy", "const", "samecount":
+		default:
+			return nil, fmt.Errorf("%s: unsupported flag %s", a.Name, flag)
+		}
+	}
+
+	t := a.Type
+	switch {
+	case first && (t.Kind != kindPort || a.Dir != dirIn):
+		return nil, fmt.Errorf("%s: the request port is not an in port", a.Name)
+	case (a.Dealloc || a.Physical) && !t.OOL:
+		return nil, fmt.Errorf("%s: dealloc or physicalcopy of an inline argument", a.Name)
+	case a.CountInOut && (a.Dir != dirOut || t.Kind != kindArray || !t.Variable || t.OOL):
+		return nil, fmt.Errorf("%s: CountInOut of an argument which is not an out inline variable array", a.Name)
+	case t.Kind == kindPort && a.Dir == dirInOut, t.OOL && a.Dir == dirInOut:
+		return nil, fmt.Errorf("%s: unsupported inout port or out-of-line array", a.Name)
+	}
+
+	return a, nil
+}
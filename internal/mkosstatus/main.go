@@ -0,0 +1,271 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Command mkosstatus generates the zosstatus.go file of package sys.
+//
+// It parses the enumerators of the checked-in copies of the Security/SecBase.h
+// and CarbonCore/MacErrors.h SDK headers and emits, from the same source, the
+// OSStatus constants, their doc comments, and the OSStatus name and message
+// tables.
+//
+// Several enumerators may share a value, such as errSecParam and paramErr. The
+// name table then holds the first of them in the order of the headers, and the
+// message table the first non-empty message. The message of an enumerator is
+// the first sentence of its trailing comment, starting with a lower case
+// letter and without final punctuation.
+//
+// Run from the repository root:
+//
+//	go run ./internal/mkosstatus
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// include is the directory of the checked-in headers.
+var include = filepath.Join("internal", "include")
+
+// headers are the parsed headers, relative to include, in priority order.
+var headers = []string{
+	"Security/SecBase.h",
+	"CarbonCore/MacErrors.h",
+}
+
+var (
+	flagInclude = flag.String("include", include, "directory of the checked-in headers")
+	flagOut     = flag.String("o", "zosstatus.go", "output file")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("mkosstatus: ")
+	flag.Parse()
+
+	files, err := parseHeaders(*flagInclude)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src, err := generate(files)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*flagOut, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// enumerator is an OSStatus enumerator defined by a header.
+type enumerator struct {
+	Name       string // C name, such as "errSecItemNotFound"
+	Value      int32  // value of the enumerator
+	Comment    string // trailing comment, if any
+	Deprecated string // C name of the replacement of a deprecated alias
+}
+
+// file is a parsed header.
+type file struct {
+	Path  string // path relative to the include directory
+	Enums []*enumerator
+}
+
+// parseHeaders parses the headers of the include directory dir.
+func parseHeaders(dir string) ([]*file, error) {
+	var (
+		files []*file
+		known = make(map[string]*enumerator)
+	)
+	for _, path := range headers {
+		f, err := os.Open(filepath.Join(dir, filepath.FromSlash(path)))
+		if err != nil {
+			return nil, err
+		}
+		enums, err := parse(f, known)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		files = append(files, &file{Path: path, Enums: enums})
+	}
+
+	return files, nil
+}
+
+var enumRE = regexp.MustCompile(`^([A-Za-z]\w*)(?:\s+__attribute__\(\(deprecated\("[^"]*"\)\)\))?\s*=\s*(-?\d+|'[^']{4}'|[A-Za-z]\w*)\s*,?\s*(?:/\*(.*?)\*/)?$`)
+
+// parse returns the enumerators of the header read from r, in the order of the
+// header. The enumerators of the previous headers are in known, which parse
+// completes, so that an enumerator may be defined as an alias of another.
+func parse(r io.Reader, known map[string]*enumerator) ([]*enumerator, error) {
+	var (
+		enums  []*enumerator
+		lineno int
+	)
+
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		lineno++
+		line := strings.TrimSpace(sc.Text())
+
+		m := enumRE.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		e := &enumerator{Name: m[1], Comment: strings.TrimSpace(m[3])}
+		if _, ok := known[e.Name]; ok {
+			return nil, fmt.Errorf("line %d: %s redefined", lineno, e.Name)
+		}
+
+		switch v := m[2]; {
+		case v[0] == '\'':
+			e.Value = int32(uint32(v[1])<<24 | uint32(v[2])<<16 | uint32(v[3])<<8 | uint32(v[4]))
+		case v[0] == '-' || '0' <= v[0] && v[0] <= '9':
+			n, err := strconv.ParseInt(v, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s: invalid value %q", lineno, e.Name, v)
+			}
+			e.Value = int32(n)
+		default:
+			alias, ok := known[v]
+			if !ok {
+				return nil, fmt.Errorf("line %d: %s: unknown enumerator %s", lineno, e.Name, v)
+			}
+			e.Value = alias.Value
+			if strings.Contains(line, "deprecated") {
+				e.Deprecated = alias.Name
+			}
+		}
+
+		known[e.Name] = e
+		enums = append(enums, e)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if len(enums) == 0 {
+		return nil, fmt.Errorf("no enumerator")
+	}
+
+	return enums, nil
+}
+
+// goName returns the Go name of the C name of an enumerator, such as
+// "ErrSecItemNotFound" for "errSecItemNotFound".
+func goName(name string) string {
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// message returns the error message of the enumerator e.
+func (e *enumerator) message() string {
+	s := e.Comment
+	if i := strings.Index(s, ". "); i >= 0 {
+		s = s[:i]
+	}
+	s = strings.TrimRight(s, ".!")
+
+	words := strings.SplitN(s, " ", 2)
+	if isCapitalized(words[0]) {
+		words[0] = strings.ToLower(words[0])
+	}
+
+	return strings.Join(words, " ")
+}
+
+// isCapitalized reports whether w starts with the only upper case letter of
+// the word, unlike an acronym such as "I/O" or "MAC".
+func isCapitalized(w string) bool {
+	for i, r := range w {
+		if i == 0 && !unicode.IsUpper(r) || i > 0 && unicode.IsUpper(r) {
+			return false
+		}
+	}
+
+	return len(w) > 1
+}
+
+// generate returns the formatted source of zosstatus.go for files.
+func generate(files []*file) ([]byte, error) {
+	var (
+		goNames  = make(map[string]string)
+		names    = make(map[int32]string) // Go name of the first enumerator of each value
+		messages = make(map[int32]string)
+	)
+	for _, f := range files {
+		for _, e := range f.Enums {
+			name := goName(e.Name)
+			if prev, ok := goNames[name]; ok {
+				return nil, fmt.Errorf("%s and %s have the same Go name %s", prev, e.Name, name)
+			}
+			goNames[name] = e.Name
+
+			if _, ok := names[e.Value]; !ok {
+				names[e.Value] = name
+			}
+			if msg := e.message(); msg != "" && messages[e.Value] == "" {
+				messages[e.Value] = msg
+			}
+		}
+	}
+
+	values := make([]int32, 0, len(names))
+	for v := range names {
+		values = append(values, v)
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] > values[j] })
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by internal/mkosstatus; DO NOT EDIT.\n\n")
+	buf.WriteString("package sys\n")
+
+	for _, f := range files {
+		fmt.Fprintf(&buf, "\n// list of OSStatus of %s.\nconst (\n", f.Path)
+		for i, e := range f.Enums {
+			if i > 0 {
+				buf.WriteString("\n")
+			}
+			if c := e.Comment; c != "" {
+				if !strings.HasSuffix(c, ".") && !strings.HasSuffix(c, "!") && !strings.HasSuffix(c, "?)") {
+					c += "."
+				}
+				fmt.Fprintf(&buf, "\t// %s is %s (%d): %s\n", goName(e.Name), e.Name, e.Value, c)
+			} else {
+				fmt.Fprintf(&buf, "\t// %s is %s (%d).\n", goName(e.Name), e.Name, e.Value)
+			}
+			if e.Deprecated != "" {
+				fmt.Fprintf(&buf, "\t//\n\t// Deprecated: Use %s.\n", goName(e.Deprecated))
+			}
+			fmt.Fprintf(&buf, "\t%s OSStatus = %d\n", goName(e.Name), e.Value)
+		}
+		buf.WriteString(")\n")
+	}
+
+	buf.WriteString("\n// OSStatus name table.\nvar osStatusNames = map[OSStatus]string{\n")
+	for _, v := range values {
+		fmt.Fprintf(&buf, "\t%s: %q,\n", names[v], goNames[names[v]])
+	}
+	buf.WriteString("}\n")
+
+	buf.WriteString("\n// OSStatus Error table.\nvar osStatusErrors = map[OSStatus]string{\n")
+	for _, v := range values {
+		if msg := messages[v]; msg != "" {
+			fmt.Fprintf(&buf, "\t%s: %q,\n", names[v], msg)
+		}
+	}
+	buf.WriteString("}\n")
+
+	return format.Source(buf.Bytes())
+}
@@ -0,0 +1,110 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const root = "../.."
+
+func parseTestdata(t *testing.T) []*file {
+	t.Helper()
+
+	files, err := parseHeaders(filepath.Join(root, include))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return files
+}
+
+// TestGenerated fails when zosstatus.go drifts from the checked-in headers.
+func TestGenerated(t *testing.T) {
+	want, err := generate(parseTestdata(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(filepath.Join(root, "zosstatus.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("zosstatus.go is out of date, run go generate")
+	}
+}
+
+func TestParseHeaders(t *testing.T) {
+	enums := make(map[string]*enumerator)
+	for _, f := range parseTestdata(t) {
+		for _, e := range f.Enums {
+			enums[e.Name] = e
+		}
+	}
+
+	tests := []struct {
+		name       string
+		value      int32
+		message    string
+		deprecated string
+	}{
+		{"errSecSuccess", 0, "no error", ""},
+		{"errSecDskFull", -34, "", "errSecDiskFull"},
+		{"errSecItemNotFound", -25300, "the specified item could not be found in the keychain", ""},
+		{"errSecNotAvailable", -25291, "no keychain is available", ""},
+		{"errSecPkcs12VerifyFailure", -25264, "MAC verification failed during PKCS12 import (wrong password?)", ""},
+		{"errSecInternalComponent", -2070, "", ""},
+		{"paramErr", -50, "error in user parameter list", ""},
+		{"notOpenErr", -28, "couldn't rd/wr/ctl/sts cause driver not opened", ""},
+		{"ioErr", -36, "I/O error (bummers)", ""},
+		{"bdNamErr", -37, "there may be no bad names in the final system", ""},
+		{"memFullErr", -108, "not enough room in heap zone", ""},
+	}
+	for _, tt := range tests {
+		e, ok := enums[tt.name]
+		if !ok {
+			t.Errorf("%s not found", tt.name)
+			continue
+		}
+		if e.Value != tt.value {
+			t.Errorf("%s = %d, want %d", tt.name, e.Value, tt.value)
+		}
+		if got := e.message(); got != tt.message {
+			t.Errorf("message(%s) = %q, want %q", tt.name, got, tt.message)
+		}
+		if e.Deprecated != tt.deprecated {
+			t.Errorf("%s deprecated for %q, want %q", tt.name, e.Deprecated, tt.deprecated)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	known := make(map[string]*enumerator)
+	enums, err := parse(strings.NewReader("enum {\n  kFmtErr = 'fmt?', /* unsupported format */\n  kAlias = kFmtErr\n};\n"), known)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(enums) != 2 || enums[0].Value != 0x666d743f || enums[1].Value != enums[0].Value {
+		t.Errorf("parse = %+v, %+v", enums[0], enums[1])
+	}
+
+	tests := []struct {
+		src, err string
+	}{
+		{"kA = 1,\nkA = 2,\n", "redefined"},
+		{"kA = kB,\n", "unknown enumerator"},
+		{"kA = 4294967296,\n", "invalid value"},
+		{"enum {};\n", "no enumerator"},
+	}
+	for _, tt := range tests {
+		_, err := parse(strings.NewReader(tt.src), make(map[string]*enumerator))
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("parse(%q) error = %v, want %q", tt.src, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Command mksysnum generates the zsysnum.go file of package sys.
+//
+// It parses the checked-in copy of the bsd/kern/syscalls.master file of xnu and
+// emits the table of the BSD syscalls of darwin, indexed by syscall number,
+// with the C prototype of each syscall.
+//
+// The conditional blocks of the file are resolved as for the configuration of
+// the macOS kernel, that is the #if branch of every block is taken and the
+// #else branch skipped. The unused numbers, implemented by nosys or enosys, are
+// left out, except the indirect syscall 0. The "sys_" prefix of the kernel
+// function names is trimmed, like makesyscalls.sh does for sys/syscall.h.
+//
+// Run from the repository root:
+//
+//	go run ./internal/mksysnum
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	flagMaster = flag.String("master", "internal/xnu/bsd/kern/syscalls.master", "path of the syscalls.master file")
+	flagOut    = flag.String("o", "zsysnum.go", "output file")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("mksysnum: ")
+	flag.Parse()
+
+	f, err := os.Open(*flagMaster)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	syscalls, err := parse(f)
+	if err != nil {
+		log.Fatalf("%s: %v", *flagMaster, err)
+	}
+
+	src, err := generate(syscalls)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*flagOut, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// syscall is a syscall defined by syscalls.master.
+type syscall struct {
+	Number int
+	Name   string // name, without the "sys_" prefix
+	Ret    string // C type of the result
+	Args   []arg
+}
+
+// arg is an argument of a syscall.
+type arg struct {
+	Type string // C type, such as "const char *"
+	Name string
+}
+
+var (
+	entryRE = regexp.MustCompile(`^(\d+)\s+(\w+)\s+(\w+)\s+\{\s*(.*?)\s*;\s*\}`)
+	protoRE = regexp.MustCompile(`^(.*?)\s*\b(\w+)\((.*)\)(?:\s+NO_SYSCALL_STUB)?$`)
+	argRE   = regexp.MustCompile(`^(.*?[\s*])(\w+)$`)
+)
+
+// parse returns the syscalls defined by the syscalls.master file read from r,
+// in number order.
+func parse(r io.Reader) ([]*syscall, error) {
+	var (
+		syscalls []*syscall
+		conds    []bool // whether each enclosing #if block is in its taken branch
+		next     int    // next expected number
+		lineno   int
+	)
+
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		lineno++
+		line := strings.TrimSpace(sc.Text())
+
+		switch {
+		case line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#include"):
+			continue
+		case strings.HasPrefix(line, "#if"):
+			conds = append(conds, true)
+			continue
+		case strings.HasPrefix(line, "#else"):
+			if len(conds) == 0 {
+				return nil, fmt.Errorf("line %d: #else without #if", lineno)
+			}
+			conds[len(conds)-1] = false
+			continue
+		case strings.HasPrefix(line, "#endif"):
+			if len(conds) == 0 {
+				return nil, fmt.Errorf("line %d: #endif without #if", lineno)
+			}
+			conds = conds[:len(conds)-1]
+			continue
+		case !taken(conds):
+			continue
+		}
+
+		m := entryRE.FindStringSubmatch(line)
+		if m == nil {
+			return nil, fmt.Errorf("line %d: invalid entry %q", lineno, line)
+		}
+		num, _ := strconv.Atoi(m[1])
+		if num != next {
+			return nil, fmt.Errorf("line %d: syscall number %d out of order, want %d", lineno, num, next)
+		}
+		next++
+
+		s, err := parseProto(m[4])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineno, err)
+		}
+		s.Number = num
+		switch {
+		case num == 0:
+			s.Name, s.Args = "syscall", nil
+		case s.Name == "nosys" || s.Name == "enosys":
+			continue
+		}
+		syscalls = append(syscalls, s)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if len(conds) != 0 {
+		return nil, fmt.Errorf("line %d: unterminated #if", lineno)
+	}
+	if len(syscalls) == 0 {
+		return nil, fmt.Errorf("no syscall")
+	}
+
+	return syscalls, nil
+}
+
+// taken reports whether all the enclosing #if blocks are in their taken
+// branch.
+func taken(conds []bool) bool {
+	for _, c := range conds {
+		if !c {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseProto parses the C prototype of a syscall, such as
+// "user_ssize_t read(int fd, user_addr_t cbuf, user_size_t nbyte)".
+func parseProto(proto string) (*syscall, error) {
+	m := protoRE.FindStringSubmatch(proto)
+	if m == nil || m[1] == "" {
+		return nil, fmt.Errorf("invalid prototype %q", proto)
+	}
+
+	s := &syscall{
+		Name: strings.TrimPrefix(m[2], "sys_"),
+		Ret:  m[1],
+	}
+	if params := strings.TrimSpace(m[3]); params != "void" {
+		for _, p := range strings.Split(params, ",") {
+			a, err := parseArg(strings.TrimSpace(p))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", s.Name, err)
+			}
+			s.Args = append(s.Args, a)
+		}
+	}
+
+	return s, nil
+}
+
+// parseArg parses a C parameter declaration, such as "char **argp", into a
+// type, normalized as "char **", and a name.
+func parseArg(p string) (arg, error) {
+	m := argRE.FindStringSubmatch(p)
+	if m == nil {
+		return arg{}, fmt.Errorf("invalid parameter %q", p)
+	}
+
+	typ := strings.TrimSpace(m[1])
+	if i := strings.IndexByte(typ, '*'); i >= 0 {
+		typ = strings.TrimSpace(typ[:i]) + " " + strings.ReplaceAll(typ[i:], " ", "")
+	}
+	if typ == "" {
+		return arg{}, fmt.Errorf("invalid parameter %q", p)
+	}
+
+	return arg{Type: typ, Name: m[2]}, nil
+}
+
+// generate returns the formatted source of zsysnum.go for syscalls.
+func generate(syscalls []*syscall) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by internal/mksysnum; DO NOT EDIT.\n\n")
+	buf.WriteString("package sys\n\n")
+
+	buf.WriteString("// BSD syscall table of darwin, indexed by syscall number.\nvar syscalls = [...]Syscall{\n")
+	for _, s := range syscalls {
+		fmt.Fprintf(&buf, "\t%d: {Number: %d, Name: %q, Ret: %q", s.Number, s.Number, s.Name, s.Ret)
+		if len(s.Args) > 0 {
+			buf.WriteString(", Args: []SyscallArg{")
+			for i, a := range s.Args {
+				if i > 0 {
+					buf.WriteString(", ")
+				}
+				fmt.Fprintf(&buf, "{%q, %q}", a.Type, a.Name)
+			}
+			buf.WriteString("}")
+		}
+		buf.WriteString("},\n")
+	}
+	buf.WriteString("}\n")
+
+	return format.Source(buf.Bytes())
+}
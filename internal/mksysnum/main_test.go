@@ -0,0 +1,127 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const root = "../.."
+
+func parseTestdata(t *testing.T) []*syscall {
+	t.Helper()
+
+	f, err := os.Open(filepath.Join(root, "internal", "xnu", "bsd", "kern", "syscalls.master"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	syscalls, err := parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return syscalls
+}
+
+// TestGenerated fails when zsysnum.go drifts from the checked-in
+// syscalls.master.
+func TestGenerated(t *testing.T) {
+	want, err := generate(parseTestdata(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(filepath.Join(root, "zsysnum.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("zsysnum.go is out of date, run go generate")
+	}
+}
+
+func TestParseMaster(t *testing.T) {
+	byName := make(map[string]*syscall)
+	for _, s := range parseTestdata(t) {
+		byName[s.Name] = s
+	}
+
+	tests := []struct {
+		name   string
+		number int
+		ret    string
+		args   []arg
+	}{
+		{"syscall", 0, "int", nil},
+		{"exit", 1, "void", []arg{{"int", "rval"}}},
+		{"read", 3, "user_ssize_t", []arg{{"int", "fd"}, {"user_addr_t", "cbuf"}, {"user_size_t", "nbyte"}}},
+		{"close", 6, "int", []arg{{"int", "fd"}}},
+		{"execve", 59, "int", []arg{{"char *", "fname"}, {"char **", "argp"}, {"char **", "envp"}}},
+		{"mmap", 197, "user_addr_t", []arg{{"caddr_t", "addr"}, {"size_t", "len"}, {"int", "prot"}, {"int", "flags"}, {"int", "fd"}, {"off_t", "pos"}}},
+		{"kdebug_typefilter", 177, "int", []arg{{"void **", "addr"}, {"size_t *", "size"}}},
+		{"socket", 97, "int", []arg{{"int", "domain"}, {"int", "type"}, {"int", "protocol"}}},
+		{"audit_session_self", 428, "mach_port_name_t", nil},
+		{"preadv", 540, "user_ssize_t", []arg{{"int", "fd"}, {"struct iovec *", "iovp"}, {"int", "iovcnt"}, {"off_t", "offset"}}},
+	}
+	for _, tt := range tests {
+		s, ok := byName[tt.name]
+		if !ok {
+			t.Errorf("%s not found", tt.name)
+			continue
+		}
+		if s.Number != tt.number || s.Ret != tt.ret || !reflect.DeepEqual(s.Args, tt.args) {
+			t.Errorf("%s = %d %s %v, want %d %s %v", tt.name, s.Number, s.Ret, s.Args, tt.number, tt.ret, tt.args)
+		}
+	}
+
+	for _, name := range []string{"nosys", "enosys", "sys_close"} {
+		if _, ok := byName[name]; ok {
+			t.Errorf("%s parsed as a syscall", name)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	src := "; comment\n#include <sys/param.h>\n" +
+		"0\tAUE_NULL\tALL\t{ int nosys(void); }\t{ indirect syscall }\n" +
+		"#if SOCKETS\n1\tAUE_NULL\tALL\t{ int sys_a(const char *p, int n) NO_SYSCALL_STUB; }\n" +
+		"#else\n1\tAUE_NULL\tALL\t{ int nosys(void); }\n#endif\n" +
+		"2\tAUE_NULL\tALL\t{ int enosys(void); }\t{ old b }\n"
+	syscalls, err := parse(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*syscall{
+		{Number: 0, Name: "syscall", Ret: "int"},
+		{Number: 1, Name: "a", Ret: "int", Args: []arg{{"const char *", "p"}, {"int", "n"}}},
+	}
+	if !reflect.DeepEqual(syscalls, want) {
+		t.Errorf("parse = %+v, want %+v", syscalls, want)
+	}
+
+	tests := []struct {
+		src, err string
+	}{
+		{"0\tAUE_NULL\tALL\t{ int a(void); }\n2\tAUE_NULL\tALL\t{ int b(void); }\n", "out of order"},
+		{"0\tAUE_NULL\tALL\t{ int a(void); }\n0\tAUE_NULL\tALL\t{ int b(void); }\n", "out of order"},
+		{"0 bogus\n", "invalid entry"},
+		{"0\tAUE_NULL\tALL\t{ a(void); }\n", "invalid prototype"},
+		{"0\tAUE_NULL\tALL\t{ int a(int); }\n", "invalid parameter"},
+		{"#if X\n0\tAUE_NULL\tALL\t{ int a(void); }\n", "unterminated"},
+		{"#endif\n", "without #if"},
+		{"; empty\n", "no syscall"},
+	}
+	for _, tt := range tests {
+		_, err := parse(strings.NewReader(tt.src))
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("parse(%q) error = %v, want %q", tt.src, err, tt.err)
+		}
+	}
+}
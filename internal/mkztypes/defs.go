@@ -0,0 +1,151 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// defs is a parsed defs file.
+type defs struct {
+	// preamble is the cgo preamble of the defs file.
+	preamble string
+
+	// decls is the list of declarations in source order.
+	decls []*decl
+}
+
+// decl is a type or const declaration of the defs file.
+type decl struct {
+	tok     token.Token
+	grouped bool
+	specs   []*spec
+}
+
+// spec is a single declared name which refers to a C type or constant.
+type spec struct {
+	// name is the declared Go name.
+	name string
+
+	// typ is the Go type of a constant.
+	typ string
+
+	// c is the C spelling of the referenced type or constant.
+	c string
+}
+
+// cgoNames maps the cgo names of the C numeric types to their C spellings.
+var cgoNames = map[string]string{
+	"schar":     "signed char",
+	"uchar":     "unsigned char",
+	"ushort":    "unsigned short",
+	"uint":      "unsigned int",
+	"ulong":     "unsigned long",
+	"longlong":  "long long",
+	"ulonglong": "unsigned long long",
+}
+
+// cSpelling returns the C spelling of the cgo name of a C type, such as
+// "struct foo" for struct_foo.
+func cSpelling(name string) string {
+	if s, ok := cgoNames[name]; ok {
+		return s
+	}
+	for _, prefix := range []string{"struct_", "union_", "enum_"} {
+		if strings.HasPrefix(name, prefix) {
+			return strings.TrimSuffix(prefix, "_") + " " + name[len(prefix):]
+		}
+	}
+
+	return name
+}
+
+// parseDefs parses the defs file at path.
+func parseDefs(path string) (*defs, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	d := new(defs)
+	for _, gd := range f.Decls {
+		gd, ok := gd.(*ast.GenDecl)
+		if !ok {
+			return nil, fmt.Errorf("%s: unexpected declaration", fset.Position(gd.Pos()))
+		}
+
+		switch gd.Tok {
+		case token.IMPORT:
+			for _, s := range gd.Specs {
+				if s.(*ast.ImportSpec).Path.Value == strconv.Quote("C") && gd.Doc != nil {
+					d.preamble = gd.Doc.Text()
+				}
+			}
+			continue
+
+		case token.TYPE, token.CONST:
+			// ok
+
+		default:
+			return nil, fmt.Errorf("%s: unexpected %s declaration", fset.Position(gd.Pos()), gd.Tok)
+		}
+
+		dc := &decl{tok: gd.Tok, grouped: gd.Lparen.IsValid()}
+		for _, s := range gd.Specs {
+			var (
+				name string
+				typ  ast.Expr
+				val  ast.Expr
+			)
+			switch s := s.(type) {
+			case *ast.TypeSpec:
+				name, val = s.Name.Name, s.Type
+			case *ast.ValueSpec:
+				if len(s.Names) != 1 || len(s.Values) != 1 {
+					return nil, fmt.Errorf("%s: const must declare a single name", fset.Position(s.Pos()))
+				}
+				name, typ, val = s.Names[0].Name, s.Type, s.Values[0]
+			}
+
+			c, ok := cRef(val)
+			if !ok {
+				return nil, fmt.Errorf("%s: %s must refer to a C name", fset.Position(s.Pos()), name)
+			}
+			sp := &spec{name: name, c: c}
+			if gd.Tok == token.TYPE {
+				sp.c = cSpelling(c)
+			}
+			if typ != nil {
+				id, ok := typ.(*ast.Ident)
+				if !ok {
+					return nil, fmt.Errorf("%s: type of %s must be an identifier", fset.Position(s.Pos()), name)
+				}
+				sp.typ = id.Name
+			}
+			dc.specs = append(dc.specs, sp)
+		}
+		d.decls = append(d.decls, dc)
+	}
+
+	return d, nil
+}
+
+// cRef returns the name of the C.name selector expression x.
+func cRef(x ast.Expr) (string, bool) {
+	sel, ok := x.(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+	if id, ok := sel.X.(*ast.Ident); !ok || id.Name != "C" {
+		return "", false
+	}
+
+	return sel.Sel.Name, true
+}
@@ -0,0 +1,237 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"strings"
+)
+
+// generator generates the ztypes file of a target.
+type generator struct {
+	l   *layout
+	buf bytes.Buffer
+
+	// structs maps the C spelling of the declared struct types to their Go name.
+	structs map[string]string
+}
+
+// generate returns the gofmt'ed ztypes file of the l target.
+func generate(d *defs, l *layout) ([]byte, error) {
+	g := &generator{l: l, structs: make(map[string]string)}
+	for _, dc := range d.decls {
+		if dc.tok != token.TYPE {
+			continue
+		}
+		for _, sp := range dc.specs {
+			if t, ok := l.Types[sp.c]; ok && t.Kind == kindStruct {
+				g.structs[sp.c] = sp.name
+			}
+		}
+	}
+
+	fmt.Fprintf(&g.buf, "// Code generated by internal/mkztypes; DO NOT EDIT.\n")
+	fmt.Fprintf(&g.buf, "// go run ./internal/mkztypes -goos %s -goarch %s\n\n", l.GOOS, l.GOARCH)
+	fmt.Fprintf(&g.buf, "//go:build %s && %s\n", l.GOOS, l.GOARCH)
+	fmt.Fprintf(&g.buf, "// +build %s,%s\n\n", l.GOOS, l.GOARCH)
+	fmt.Fprintf(&g.buf, "package sys\n")
+
+	for _, dc := range d.decls {
+		g.buf.WriteString("\n")
+		if dc.grouped {
+			fmt.Fprintf(&g.buf, "%s (\n", dc.tok)
+		}
+		for _, sp := range dc.specs {
+			if !dc.grouped {
+				fmt.Fprintf(&g.buf, "%s ", dc.tok)
+			}
+
+			var err error
+			switch dc.tok {
+			case token.TYPE:
+				err = g.typeSpec(sp)
+			case token.CONST:
+				err = g.constSpec(sp)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%s/%s: %s: %w", l.GOOS, l.GOARCH, sp.name, err)
+			}
+			g.buf.WriteString("\n")
+		}
+		if dc.grouped {
+			g.buf.WriteString(")\n")
+		}
+	}
+
+	src, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("%s/%s: %w\n%s", l.GOOS, l.GOARCH, err, g.buf.Bytes())
+	}
+
+	return src, nil
+}
+
+func (g *generator) typeSpec(sp *spec) error {
+	t, ok := g.l.Types[sp.c]
+	if !ok {
+		return fmt.Errorf("no layout of C type %q", sp.c)
+	}
+
+	fmt.Fprintf(&g.buf, "%s ", sp.name)
+	if t.Kind == kindStruct {
+		return g.structType(sp.c, t)
+	}
+
+	typ, err := g.goType(sp.c)
+	if err != nil {
+		return err
+	}
+	g.buf.WriteString(typ)
+
+	return nil
+}
+
+func (g *generator) constSpec(sp *spec) error {
+	v, ok := g.l.Constants[sp.c]
+	if !ok {
+		return fmt.Errorf("no value of C constant %q", sp.c)
+	}
+
+	fmt.Fprintf(&g.buf, "%s", sp.name)
+	if sp.typ != "" {
+		fmt.Fprintf(&g.buf, " %s", sp.typ)
+	}
+	if v < 0 {
+		fmt.Fprintf(&g.buf, " = -%#x", -v)
+	} else {
+		fmt.Fprintf(&g.buf, " = %#x", v)
+	}
+
+	return nil
+}
+
+// structType writes the Go struct type of the C struct t, named c.
+//
+// Like cmd/cgo -godefs, field names are capitalized and explicit padding is
+// inserted wherever the C layout differs from the natural Go layout. Fields
+// which the C layout does not align naturally (#pragma pack) are represented
+// as byte arrays.
+func (g *generator) structType(c string, t *ctype) error {
+	g.buf.WriteString("struct {\n")
+
+	var off int64
+	for _, f := range t.Fields {
+		if f.Offset < off {
+			return fmt.Errorf("%s.%s: field overlaps the previous field", c, f.Name)
+		}
+		if f.Offset > off {
+			fmt.Fprintf(&g.buf, "_ [%d]byte\n", f.Offset-off)
+			off = f.Offset
+		}
+
+		size, align, err := g.goLayout(f.Type)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", c, f.Name, err)
+		}
+		typ, err := g.goType(f.Type)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", c, f.Name, err)
+		}
+		if f.Offset%align != 0 {
+			typ = fmt.Sprintf("[%d]byte", size)
+		}
+		if f.Len > 0 {
+			typ = fmt.Sprintf("[%d]%s", f.Len, typ)
+			size *= f.Len
+		}
+
+		fmt.Fprintf(&g.buf, "%s %s\n", strings.ToUpper(f.Name[:1])+f.Name[1:], typ)
+		off += size
+	}
+
+	switch {
+	case off > t.Size:
+		return fmt.Errorf("%s: fields exceed the struct size %d", c, t.Size)
+	case off < t.Size:
+		fmt.Fprintf(&g.buf, "_ [%d]byte\n", t.Size-off)
+	}
+	g.buf.WriteString("}")
+
+	return nil
+}
+
+// goType returns the Go type of the C type named c.
+func (g *generator) goType(c string) (string, error) {
+	t, ok := g.l.Types[c]
+	if !ok {
+		return "", fmt.Errorf("no layout of C type %q", c)
+	}
+
+	switch t.Kind {
+	case kindInt:
+		switch t.Size {
+		case 1, 2, 4, 8:
+			if t.Signed {
+				return fmt.Sprintf("int%d", t.Size*8), nil
+			}
+			return fmt.Sprintf("uint%d", t.Size*8), nil
+		}
+
+	case kindFloat:
+		switch t.Size {
+		case 4, 8:
+			return fmt.Sprintf("float%d", t.Size*8), nil
+		default:
+			// There is no Go equivalent, such as the x87 long double.
+			return fmt.Sprintf("[%d]byte", t.Size), nil
+		}
+
+	case kindBool:
+		if t.Size == 1 {
+			return "bool", nil
+		}
+
+	case kindStruct:
+		name, ok := g.structs[c]
+		if !ok {
+			return "", fmt.Errorf("C type %q is not declared in the defs file", c)
+		}
+		return name, nil
+	}
+
+	return "", fmt.Errorf("C type %q: unsupported %d byte %s", c, t.Size, t.Kind)
+}
+
+// goLayout returns the size and alignment of the Go type of the C type named c.
+func (g *generator) goLayout(c string) (size, align int64, err error) {
+	t, ok := g.l.Types[c]
+	if !ok {
+		return 0, 0, fmt.Errorf("no layout of C type %q", c)
+	}
+
+	switch t.Kind {
+	case kindStruct:
+		align = 1
+		for _, f := range t.Fields {
+			_, a, err := g.goLayout(f.Type)
+			if err != nil {
+				return 0, 0, err
+			}
+			if f.Offset%a == 0 && a > align {
+				align = a
+			}
+		}
+		return t.Size, align, nil
+
+	case kindFloat:
+		if t.Size != 4 && t.Size != 8 {
+			return t.Size, 1, nil
+		}
+	}
+
+	return t.Size, t.Size, nil
+}
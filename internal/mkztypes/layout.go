@@ -0,0 +1,112 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// list of C type kinds of a layout fixture.
+const (
+	kindInt    = "int"
+	kindFloat  = "float"
+	kindBool   = "bool"
+	kindStruct = "struct"
+)
+
+// layout is the layout fixture of a GOOS/GOARCH target.
+type layout struct {
+	GOOS      string            `json:"goos"`
+	GOARCH    string            `json:"goarch"`
+	Types     map[string]*ctype `json:"types"`
+	Constants map[string]int64  `json:"constants"`
+}
+
+// ctype is the layout of a C type, keyed by its C spelling.
+type ctype struct {
+	Kind   string   `json:"kind"`
+	Size   int64    `json:"size"`
+	Align  int64    `json:"align"`
+	Signed bool     `json:"signed,omitempty"`
+	Fields []*field `json:"fields,omitempty"`
+}
+
+// field is a field of a C struct.
+type field struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Offset int64  `json:"offset"`
+
+	// Len is the number of elements of an array field, or zero.
+	Len int64 `json:"len,omitempty"`
+}
+
+// filename returns the name of the generated file of the target.
+func (l *layout) filename() string {
+	return "ztypes_" + l.GOOS + "_" + l.GOARCH + ".go"
+}
+
+// typeNames returns the sorted C spellings of the fixture types.
+func (l *layout) typeNames() []string {
+	names := make([]string, 0, len(l.Types))
+	for name := range l.Types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// constantNames returns the sorted names of the fixture constants.
+func (l *layout) constantNames() []string {
+	names := make([]string, 0, len(l.Constants))
+	for name := range l.Constants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// loadLayouts loads the layout fixtures in dir, sorted by target.
+func loadLayouts(dir string) ([]*layout, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	layouts := make([]*layout, 0, len(paths))
+	for _, path := range paths {
+		l, err := loadLayout(path)
+		if err != nil {
+			return nil, err
+		}
+		layouts = append(layouts, l)
+	}
+
+	return layouts, nil
+}
+
+// loadLayout loads the layout fixture at path.
+func loadLayout(path string) (*layout, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	l := new(layout)
+	if err := json.Unmarshal(data, l); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if want := l.GOOS + "_" + l.GOARCH + ".json"; filepath.Base(path) != want {
+		return nil, fmt.Errorf("%s: fixture of %s/%s must be named %s", path, l.GOOS, l.GOARCH, want)
+	}
+
+	return l, nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Command mkztypes generates the ztypes_<goos>_<goarch>.go files of package sys.
+//
+// It plays the role of cmd/cgo -godefs without requiring a C toolchain for each
+// target: the C declarations of the shared defs.go file are resolved against a
+// checked-in layout fixture per target, found in the layout directory.
+//
+// A fixture records the kind, size, alignment and signedness of each C type,
+// the fields and offsets of each C struct, and the value of each C constant
+// referenced by defs.go. The Mach declarations have no linux counterpart; the
+// linux fixtures model them with the darwin layout of the same architecture so
+// that the pure-Go Mach code builds and is testable on linux.
+//
+// Run from the repository root:
+//
+//	go run ./internal/mkztypes
+//
+// The -asserts flag prints a C file of static assertions for a single darwin
+// target instead, which verifies its fixture against the SDK headers:
+//
+//	go run ./internal/mkztypes -asserts -goos darwin -goarch arm64 > asserts.c
+//	cc -arch arm64 -fsyntax-only asserts.c
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	flagDefs    = flag.String("defs", "defs.go", "path of the shared defs file")
+	flagLayout  = flag.String("layout", filepath.Join("internal", "mkztypes", "layout"), "directory of the layout fixtures")
+	flagOut     = flag.String("o", ".", "output directory")
+	flagGOOS    = flag.String("goos", "", "generate only for this GOOS")
+	flagGOARCH  = flag.String("goarch", "", "generate only for this GOARCH")
+	flagAsserts = flag.Bool("asserts", false, "print C static assertions of the layout fixture instead")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("mkztypes: ")
+	flag.Parse()
+
+	if *flagAsserts && (*flagGOOS == "" || *flagGOARCH == "") {
+		log.Fatal("-asserts requires a single target, use -goos and -goarch")
+	}
+
+	d, err := parseDefs(*flagDefs)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	layouts, err := loadLayouts(*flagLayout)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	n := 0
+	for _, l := range layouts {
+		if (*flagGOOS != "" && l.GOOS != *flagGOOS) || (*flagGOARCH != "" && l.GOARCH != *flagGOARCH) {
+			continue
+		}
+		n++
+
+		if *flagAsserts {
+			src, err := asserts(d, l)
+			if err != nil {
+				log.Fatal(err)
+			}
+			os.Stdout.Write(src)
+			continue
+		}
+
+		src, err := generate(d, l)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(*flagOut, l.filename()), src, 0o644); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if n == 0 {
+		log.Fatalf("no layout fixture matches %s/%s", *flagGOOS, *flagGOARCH)
+	}
+}
+
+// asserts returns a C file which statically asserts that the l layout
+// fixture matches the C declarations referenced by d.
+func asserts(d *defs, l *layout) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Layout assertions of %s/%s, generated by internal/mkztypes.\n", l.GOOS, l.GOARCH)
+	for _, line := range strings.Split(strings.TrimSpace(d.preamble), "\n") {
+		if !strings.HasPrefix(line, "#cgo ") {
+			buf.WriteString(line + "\n")
+		}
+	}
+	buf.WriteString("\n#include <stddef.h>\n\n")
+
+	for _, name := range l.typeNames() {
+		t := l.Types[name]
+		fmt.Fprintf(&buf, "_Static_assert(sizeof(%s) == %d, %q);\n", name, t.Size, name+" size")
+		fmt.Fprintf(&buf, "_Static_assert(_Alignof(%s) == %d, %q);\n", name, t.Align, name+" align")
+		switch t.Kind {
+		case kindInt:
+			fmt.Fprintf(&buf, "_Static_assert(((%s)-1 < 0) == %d, %q);\n", name, b2i(t.Signed), name+" signedness")
+		case kindStruct:
+			for _, f := range t.Fields {
+				fmt.Fprintf(&buf, "_Static_assert(offsetof(%s, %s) == %d, %q);\n", name, f.Name, f.Offset, name+"."+f.Name+" offset")
+			}
+		}
+	}
+	for _, name := range l.constantNames() {
+		fmt.Fprintf(&buf, "_Static_assert(%s == %d, %q);\n", name, l.Constants[name], name)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func b2i(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
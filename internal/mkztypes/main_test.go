@@ -0,0 +1,143 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package main
+
+import (
+	"bytes"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-darwin/sys"
+)
+
+const root = "../.."
+
+func loadTestdata(t *testing.T) (*defs, []*layout) {
+	t.Helper()
+
+	d, err := parseDefs(filepath.Join(root, "defs.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	layouts, err := loadLayouts("layout")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return d, layouts
+}
+
+func TestTargets(t *testing.T) {
+	_, layouts := loadTestdata(t)
+
+	var got []string
+	for _, l := range layouts {
+		got = append(got, l.GOOS+"/"+l.GOARCH)
+	}
+	want := []string{"darwin/amd64", "darwin/arm64", "linux/amd64", "linux/arm64"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("layout fixtures of %v, want %v", got, want)
+	}
+}
+
+// TestGenerated fails when a ztypes file drifts from defs.go and the layout fixtures.
+func TestGenerated(t *testing.T) {
+	d, layouts := loadTestdata(t)
+
+	for _, l := range layouts {
+		want, err := generate(d, l)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := os.ReadFile(filepath.Join(root, l.filename()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s is out of date, run go generate", l.filename())
+		}
+	}
+}
+
+// TestLayoutMatchesCTypes checks the fixtures against the C type registry of package sys.
+func TestLayoutMatchesCTypes(t *testing.T) {
+	d, layouts := loadTestdata(t)
+
+	for _, l := range layouts {
+		for _, dc := range d.decls {
+			if dc.tok != token.TYPE {
+				continue
+			}
+			for _, sp := range dc.specs {
+				if !strings.HasPrefix(sp.name, "c_") {
+					continue
+				}
+				goName := "C_" + strings.TrimPrefix(sp.name, "c_")
+				ct, ok := sys.LookupCType(l.GOOS, l.GOARCH, goName)
+				if !ok {
+					t.Errorf("%s/%s: %s is not in the C type registry", l.GOOS, l.GOARCH, goName)
+					continue
+				}
+				lt := l.Types[sp.c]
+				if lt == nil {
+					t.Errorf("%s/%s: no layout of C type %q", l.GOOS, l.GOARCH, sp.c)
+					continue
+				}
+				if uintptr(lt.Size) != ct.Size || uintptr(lt.Align) != ct.Align || lt.Signed != ct.Signed || lt.Kind != ct.Kind.String() {
+					t.Errorf("%s/%s: %s layout is %+v, registry says %+v", l.GOOS, l.GOARCH, sp.c, *lt, ct)
+				}
+			}
+		}
+	}
+}
+
+func TestStructType(t *testing.T) {
+	l := &layout{
+		GOOS:   "darwin",
+		GOARCH: "amd64",
+		Types: map[string]*ctype{
+			"int":      {Kind: kindInt, Size: 4, Align: 4, Signed: true},
+			"uint64_t": {Kind: kindInt, Size: 8, Align: 8},
+			"struct inner": {Kind: kindStruct, Size: 8, Align: 4, Fields: []*field{
+				{Name: "a", Type: "int", Offset: 0},
+				{Name: "b", Type: "int", Offset: 4},
+			}},
+			"struct outer": {Kind: kindStruct, Size: 40, Align: 4, Fields: []*field{
+				{Name: "x", Type: "int", Offset: 0},
+				{Name: "packed", Type: "uint64_t", Offset: 4},
+				{Name: "in", Type: "struct inner", Offset: 12},
+				{Name: "arr", Type: "int", Offset: 24, Len: 3},
+			}},
+		},
+	}
+	d := &defs{decls: []*decl{{
+		tok:     token.TYPE,
+		grouped: false,
+		specs:   []*spec{{name: "inner", c: "struct inner"}},
+	}, {
+		tok:     token.TYPE,
+		grouped: false,
+		specs:   []*spec{{name: "outer", c: "struct outer"}},
+	}}}
+
+	src, err := generate(d, l)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `type outer struct {
+	X      int32
+	Packed [8]byte
+	In     inner
+	_      [4]byte
+	Arr    [3]int32
+	_      [4]byte
+}`
+	if !bytes.Contains(src, []byte(want)) {
+		t.Errorf("generated:\n%s\nwant:\n%s", src, want)
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"strconv"
+)
+
+//go:generate go run ./internal/mkioreturn
+
+// IOReturn represents an IOReturn, the kern_return_t of the IOKit calls.
+//
+// An IOReturn is a MachError of the IOKit system. The codes of the common
+// subsystem are defined by IOKit/IOReturn.h, the IOKit families define their
+// own codes in their subsystem.
+type IOReturn int32
+
+// IOKitSubsystem is the subsystem of an IOReturn, such as IOKitSubUSB.
+type IOKitSubsystem int
+
+// ioKitCommonErr is iokit_common_err(0), that is err_system(0x38) as a signed
+// kern_return_t.
+const ioKitCommonErr = -0x20000000
+
+// String returns the C name of the IOKitSubsystem without its sub_iokit_
+// prefix, such as "usb".
+func (s IOKitSubsystem) String() string {
+	if name, ok := ioKitSubsystemNames[s]; ok {
+		return name
+	}
+
+	return "sub_iokit(" + itoa(int(s)) + ")"
+}
+
+// NewIOReturn returns the IOReturn of the code in the sub subsystem, like
+// iokit_family_err.
+func NewIOReturn(sub IOKitSubsystem, code int) IOReturn {
+	return IOReturn(NewMachError(MachErrIOKit, int(sub), code))
+}
+
+// Subsystem returns the IOKit subsystem of the IOReturn.
+func (e IOReturn) Subsystem() IOKitSubsystem {
+	return IOKitSubsystem(MachError(e).Sub())
+}
+
+// Code returns the code of the IOReturn within its subsystem.
+func (e IOReturn) Code() int {
+	return MachError(e).Code()
+}
+
+// Do the interface allocations only once for common IOReturn values.
+var (
+	errIOReturnError         error = IOReturnError
+	errIOReturnNoMemory      error = IOReturnNoMemory
+	errIOReturnNoResources   error = IOReturnNoResources
+	errIOReturnNoDevice      error = IOReturnNoDevice
+	errIOReturnNotPrivileged error = IOReturnNotPrivileged
+	errIOReturnBadArgument   error = IOReturnBadArgument
+	errIOReturnUnsupported   error = IOReturnUnsupported
+	errIOReturnNotOpen       error = IOReturnNotOpen
+	errIOReturnBusy          error = IOReturnBusy
+	errIOReturnTimeout       error = IOReturnTimeout
+	errIOReturnNotReady      error = IOReturnNotReady
+	errIOReturnNotPermitted  error = IOReturnNotPermitted
+	errIOReturnAborted       error = IOReturnAborted
+	errIOReturnNotFound      error = IOReturnNotFound
+)
+
+// IOErrno returns common boxed IOReturn values, to prevent
+// allocations at runtime.
+func IOErrno(e IOReturn) error {
+	switch e {
+	case IOReturnSuccess:
+		return IOReturnSuccess
+	case IOReturnError:
+		return errIOReturnError
+	case IOReturnNoMemory:
+		return errIOReturnNoMemory
+	case IOReturnNoResources:
+		return errIOReturnNoResources
+	case IOReturnNoDevice:
+		return errIOReturnNoDevice
+	case IOReturnNotPrivileged:
+		return errIOReturnNotPrivileged
+	case IOReturnBadArgument:
+		return errIOReturnBadArgument
+	case IOReturnUnsupported:
+		return errIOReturnUnsupported
+	case IOReturnNotOpen:
+		return errIOReturnNotOpen
+	case IOReturnBusy:
+		return errIOReturnBusy
+	case IOReturnTimeout:
+		return errIOReturnTimeout
+	case IOReturnNotReady:
+		return errIOReturnNotReady
+	case IOReturnNotPermitted:
+		return errIOReturnNotPermitted
+	case IOReturnAborted:
+		return errIOReturnAborted
+	case IOReturnNotFound:
+		return errIOReturnNotFound
+	default:
+		return e
+	}
+}
+
+// Error returns a string representation of the IOReturn.
+//
+// The codes which are not defined by IOKit/IOReturn.h are described by their
+// mach_error_type, such as "(iokit/usb) unknown error code".
+func (e IOReturn) Error() string {
+	if s, ok := ioReturnErrors[e]; ok {
+		return s
+	}
+
+	return MachError(e).Type() + " " + machNoSuchError
+}
+
+// Is reports whether the IOReturn matches the target error.
+//
+// IOReturn values can be tested against error values from the io/fs, os
+// and context packages using errors.Is. For example:
+//
+//	if errors.Is(err, fs.ErrNotExist) ...
+func (e IOReturn) Is(target error) bool {
+	switch target {
+	case fs.ErrPermission:
+		return e == IOReturnNotPrivileged || e == IOReturnNotPermitted
+	case fs.ErrExist:
+		return e == IOReturnPortExists
+	case fs.ErrNotExist:
+		return e == IOReturnNoDevice || e == IOReturnNotFound
+	case fs.ErrInvalid:
+		return e == IOReturnBadArgument
+	case fs.ErrClosed:
+		return e == IOReturnNotOpen
+	case os.ErrDeadlineExceeded, context.DeadlineExceeded:
+		return e.Timeout()
+	case context.Canceled:
+		return e == IOReturnAborted
+	}
+
+	switch t := target.(type) {
+	case MachError:
+		return MachError(e) == t
+	case KernReturn:
+		return MachError(e) == MachError(t)
+	}
+
+	return false
+}
+
+// As finds the MachError representation of the IOReturn for errors.As.
+func (e IOReturn) As(target interface{}) bool {
+	if me, ok := target.(*MachError); ok {
+		*me = MachError(e)
+		return true
+	}
+
+	return false
+}
+
+// Temporary reports whether the operation may succeed if retried.
+func (e IOReturn) Temporary() bool {
+	return e == IOReturnNoMemory || e == IOReturnNoResources || e == IOReturnBusy || e == IOReturnNotReady || e.Timeout()
+}
+
+// Timeout reports whether the IOReturn is a timeout.
+func (e IOReturn) Timeout() bool {
+	return e == IOReturnTimeout
+}
+
+// String returns the C constant name of the IOReturn, such as "kIOReturnBadArgument".
+func (e IOReturn) String() string {
+	if s, ok := ioReturnNames[e]; ok {
+		return s
+	}
+
+	return "IOReturn(0x" + strconv.FormatUint(uint64(uint32(e)), 16) + ")"
+}
+
+// GoString returns the C constant name of the IOReturn, so that %#v
+// prints kIOReturnBadArgument rather than -536870206.
+func (e IOReturn) GoString() string {
+	return e.String()
+}
+
+// ParseIOReturn parses s as the C constant name of an IOReturn, such as
+// "kIOReturnBadArgument", or as a number in the base prefix syntax of
+// strconv.ParseInt, such as "0xe00002c2". A number may be given as a signed
+// or unsigned 32 bit value.
+func ParseIOReturn(s string) (IOReturn, error) {
+	for e, name := range ioReturnNames {
+		if name == s {
+			return e, nil
+		}
+	}
+
+	n, err := strconv.ParseInt(s, 0, 64)
+	if err != nil {
+		return 0, &strconv.NumError{Func: "ParseIOReturn", Num: s, Err: err.(*strconv.NumError).Err}
+	}
+	if n < -1<<31 || n > 1<<32-1 {
+		return 0, &strconv.NumError{Func: "ParseIOReturn", Num: s, Err: strconv.ErrRange}
+	}
+
+	return IOReturn(n), nil
+}
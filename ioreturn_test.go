@@ -0,0 +1,174 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/go-darwin/sys"
+)
+
+func TestIOReturnValues(t *testing.T) {
+	tests := []struct {
+		err  sys.IOReturn
+		want uint32
+		sub  sys.IOKitSubsystem
+		code int
+	}{
+		{sys.IOReturnSuccess, 0x0, sys.IOKitSubCommon, 0},
+		{sys.IOReturnError, 0xe00002bc, sys.IOKitSubCommon, 0x2bc},
+		{sys.IOReturnBadArgument, 0xe00002c2, sys.IOKitSubCommon, 0x2c2},
+		{sys.IOReturnNotFound, 0xe00002f0, sys.IOKitSubCommon, 0x2f0},
+		{sys.NewIOReturn(sys.IOKitSubUSB, 0x51), 0xe0004051, sys.IOKitSubUSB, 0x51},
+		{sys.NewIOReturn(sys.IOKitSubVendorSpecific, 1), 0xe3ff8001, sys.IOKitSubVendorSpecific, 1},
+	}
+	for _, tt := range tests {
+		if got := uint32(tt.err); got != tt.want {
+			t.Errorf("%v = %#x, want %#x", tt.err, got, tt.want)
+		}
+		if got := tt.err.Subsystem(); got != tt.sub {
+			t.Errorf("%v.Subsystem() = %v, want %v", tt.err, got, tt.sub)
+		}
+		if got := tt.err.Code(); got != tt.code {
+			t.Errorf("%v.Code() = %#x, want %#x", tt.err, got, tt.code)
+		}
+	}
+}
+
+func TestIOReturnString(t *testing.T) {
+	tests := []struct {
+		err       sys.IOReturn
+		name, msg string
+	}{
+		{sys.IOReturnSuccess, "kIOReturnSuccess", "OK"},
+		{sys.IOReturnBadArgument, "kIOReturnBadArgument", "invalid argument"},
+		{sys.IOReturnTimeout, "kIOReturnTimeout", "I/O timeout"},
+		{sys.IOReturnExclusiveAccess, "kIOReturnExclusiveAccess", "exclusive access and device already open"},
+		{sys.NewIOReturn(sys.IOKitSubUSB, 0x51), "IOReturn(0xe0004051)", "(iokit/usb) unknown error code"},
+		{sys.NewIOReturn(sys.IOKitSubCommon, 0x2cb), "IOReturn(0xe00002cb)", "(iokit/common) unknown error code"},
+	}
+	for _, tt := range tests {
+		if got := tt.err.String(); got != tt.name {
+			t.Errorf("IOReturn(%#x).String() = %q, want %q", uint32(tt.err), got, tt.name)
+		}
+		if got := fmt.Sprintf("%#v", tt.err); got != tt.name {
+			t.Errorf("Sprintf(%%#v, %#x) = %q, want %q", uint32(tt.err), got, tt.name)
+		}
+		if got := tt.err.Error(); got != tt.msg {
+			t.Errorf("IOReturn(%#x).Error() = %q, want %q", uint32(tt.err), got, tt.msg)
+		}
+	}
+
+	if got, want := sys.IOKitSubHIDSystem.String(), "hidsystem"; got != want {
+		t.Errorf("IOKitSubHIDSystem.String() = %q, want %q", got, want)
+	}
+	if got, want := sys.IOKitSubsystem(3).String(), "sub_iokit(3)"; got != want {
+		t.Errorf("IOKitSubsystem(3).String() = %q, want %q", got, want)
+	}
+}
+
+func TestParseIOReturn(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    sys.IOReturn
+		wantErr bool
+	}{
+		{s: "kIOReturnSuccess", want: sys.IOReturnSuccess},
+		{s: "kIOReturnNotFound", want: sys.IOReturnNotFound},
+		{s: "0xe00002c2", want: sys.IOReturnBadArgument},
+		{s: "-536870206", want: sys.IOReturnBadArgument},
+		{s: "IOReturnNotFound", wantErr: true},
+		{s: "0x100000000", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := sys.ParseIOReturn(tt.s)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseIOReturn(%q) error = %v, wantErr %t", tt.s, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseIOReturn(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+
+	for _, tt := range []struct {
+		s   string
+		err error
+	}{
+		{"IOReturnNotFound", strconv.ErrSyntax},
+		{"0x100000000", strconv.ErrRange},
+		{"0x8000000000000000", strconv.ErrRange},
+	} {
+		_, err := sys.ParseIOReturn(tt.s)
+		var ne *strconv.NumError
+		if !errors.As(err, &ne) || ne.Func != "ParseIOReturn" || ne.Err != tt.err {
+			t.Errorf("ParseIOReturn(%q) error = %v, want a *strconv.NumError of %v", tt.s, err, tt.err)
+		}
+	}
+}
+
+func TestIOReturnIs(t *testing.T) {
+	tests := []struct {
+		err    error
+		target error
+		want   bool
+	}{
+		{sys.IOReturnNotPrivileged, fs.ErrPermission, true},
+		{sys.IOReturnNotPermitted, fs.ErrPermission, true},
+		{sys.IOReturnNoDevice, fs.ErrNotExist, true},
+		{sys.IOReturnNotFound, fs.ErrNotExist, true},
+		{sys.IOReturnBadArgument, fs.ErrInvalid, true},
+		{sys.IOReturnNotOpen, fs.ErrClosed, true},
+		{sys.IOReturnTimeout, os.ErrDeadlineExceeded, true},
+		{sys.IOReturnAborted, context.Canceled, true},
+		{sys.IOReturnError, fs.ErrPermission, false},
+		{sys.IOReturnSuccess, sys.KernSuccess, true},
+		{sys.IOReturnBadArgument, sys.KernInvalidArgument, false},
+		{sys.IOReturnBadArgument, sys.MachError(sys.IOReturnBadArgument), true},
+		{sys.MachError(sys.IOReturnNotFound), fs.ErrNotExist, true},
+		{sys.MachError(sys.IOReturnBusy), sys.IOReturnBusy, true},
+		{sys.MachError(sys.IOReturnBusy), sys.IOReturnTimeout, false},
+	}
+	for _, tt := range tests {
+		if got := errors.Is(tt.err, tt.target); got != tt.want {
+			t.Errorf("errors.Is(%v, %v) = %t, want %t", tt.err, tt.target, got, tt.want)
+		}
+	}
+
+	var me sys.MachError
+	err := fmt.Errorf("IOServiceOpen: %w", sys.IOReturnNotPrivileged)
+	if !errors.As(err, &me) || me.System() != sys.MachErrIOKit {
+		t.Fatalf("errors.As(%v, *MachError) = %#x", err, uint32(me))
+	}
+	if got, want := me.Type(), "(iokit/common)"; got != want {
+		t.Errorf("Type() = %q, want %q", got, want)
+	}
+	if me.Temporary() != sys.IOReturnNotPrivileged.Temporary() {
+		t.Errorf("MachError.Temporary() = %t, want %t", me.Temporary(), sys.IOReturnNotPrivileged.Temporary())
+	}
+	if !sys.MachError(sys.IOReturnTimeout).Timeout() || !sys.MachError(sys.IOReturnBusy).Temporary() {
+		t.Errorf("MachError of IOReturnTimeout or IOReturnBusy is not a temporary timeout")
+	}
+}
+
+func TestIOErrno(t *testing.T) {
+	for _, e := range []sys.IOReturn{sys.IOReturnSuccess, sys.IOReturnNotFound, sys.IOReturnBusy, sys.IOReturnNoSpace} {
+		if got := sys.IOErrno(e); got != error(e) {
+			t.Errorf("IOErrno(%v) = %v", e, got)
+		}
+	}
+
+	allocs := testing.AllocsPerRun(10, func() {
+		_ = sys.IOErrno(sys.IOReturnNotFound)
+	})
+	if allocs != 0 {
+		t.Errorf("IOErrno(IOReturnNotFound) allocates %v times, want 0", allocs)
+	}
+}
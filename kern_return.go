@@ -1,11 +1,17 @@
 // Copyright 2021 The Go Darwin Authors
 // SPDX-License-Identifier: BSD-3-Clause
 
-//go:build darwin
-// +build darwin
-
 package sys
 
+import (
+	"context"
+	"io/fs"
+	"os"
+	"strconv"
+)
+
+//go:generate go run ./internal/mkkernreturn
+
 // KernErrno returns common boxed Errno values, to prevent
 // allocations at runtime.
 func KernErrno(e KernReturn) error {
@@ -19,8 +25,8 @@ func KernErrno(e KernReturn) error {
 
 // Error returns a string representation of the KernReturn.
 func (e KernReturn) Error() string {
-	if 0 <= int(e) && int(e) < len(errors) {
-		s := errors[e]
+	if 0 <= int(e) && int(e) < len(kernReturnErrors) {
+		s := kernReturnErrors[e]
 		if s != "" {
 			return s
 		}
@@ -29,61 +35,129 @@ func (e KernReturn) Error() string {
 	return "errno " + itoa(int(e))
 }
 
-// KernReturn Error table.
-var errors = [...]string{
-	0x1:   "specified address is not currently valid",
-	0x2:   "specified memory is valid, but does not permit therequired forms of access",
-	0x3:   "address range specified is already in use, or no address range of the size specified could be found",
-	0x4:   "function requested was not applicable to this type of argument, or an argument is invalid",
-	0x5:   "function could not be performed. A catch-all",
-	0x6:   "system resource could not be allocated to fulfill this request",
-	0x7:   "bogus access restriction",
-	0x8:   "task in question does not hold receive rights for the port argument",
-	0x9:   "during a page fault, the target address refers to a memory object that has been destroyed",
-	0xa:   "during a page fault, the memory object indicated that the data could not be returned",
-	0xb:   "receive right is already a member of the portset",
-	0xc:   "receive right is not a member of a port set",
-	0xd:   "name already denotes a right in the task",
-	0xe:   "operation was aborted",
-	0xf:   "name doesn't denote a right in the task",
-	0x10:  "target task isn't an active task",
-	0x11:  "name denotes a right, but not an appropriate right",
-	0x12:  "blatant range error",
-	0x13:  "operation would overflow limit on user-references",
-	0x14:  "supplied (port) capability is improper",
-	0x15:  "task already has send or receive rights for the port under another name",
-	0x16:  "target host isn't actually a host",
-	0x18:  "attempt was made to supply precious data for memory that is already present in a memory object",
-	0x19:  "page was requested of a memory manager via memory_object_data_request for an object using a MEMORY_OBJECT_COPY_CALL strategy",
-	0x1a:  "strategic copy was attempted of an object upon which a quicker copy is now possible",
-	0x1b:  "argument applied to assert processor set privilege was not a processor set control port",
-	0x1c:  "specified scheduling attributes exceed the thread's limits",
-	0x1d:  "specified scheduling policy is not currently enabled for the processor set",
-	0x1e:  "external memory manager failed to initialize the memory object",
-	0x1f:  "thread is attempting to wait for an event for which there is already a waiting thread",
-	0x20:  "attempt was made to destroy the default processor set",
-	0x21:  "attempt was made to fetch an exception port that is protected, or to abort a thread while processing a protected exception",
-	0x22:  "ledger was required but not supplied",
-	0x23:  "port was not a memory cache control port",
-	0x24:  "argument supplied to assert security privilege was not a host security port",
-	0x25:  "thread_depress_abort was called on a thread which was not currently depressed",
-	0x26:  "object has been terminated and is no longer available",
-	0x27:  "lock set has been destroyed and is no longer available",
-	0x28:  "thread holding the lock terminated before releasing the lock",
-	0x29:  "lock is already owned by another thread",
-	0x2a:  "lock is already owned by the calling thread",
-	0x2b:  "semaphore has been destroyed and is no longer available",
-	0x2c:  "return from RPC indicating the target server was terminated before it successfully replied",
-	0x2d:  "terminate an orphaned activation",
-	0x2e:  "allow an orphaned activation to continue executing",
-	0x2f:  "empty thread activation (No thread linked to it)",
-	0x30:  "remote node down or inaccessible",
-	0x31:  "signalled thread was not actually waiting",
-	0x32:  "some thread-oriented operation (semaphore_wait) timed out",
-	0x33:  "during a page fault, indicates that the page was rejected as a result of a signature check",
-	0x34:  "requested property cannot be changed at this time",
-	0x35:  "provided buffer is of insufficient size for the requested data",
-	0x36:  "KC on which the function is operating is missing",
-	0x37:  "KC on which the function is operating is invalid",
-	0x100: "maximum return value allowable",
+// Is reports whether the KernReturn matches the target error.
+//
+// KernReturn values can be tested against error values from the io/fs, os
+// and context packages using errors.Is. For example:
+//
+//	if errors.Is(err, fs.ErrPermission) ...
+func (e KernReturn) Is(target error) bool {
+	switch target {
+	case fs.ErrPermission:
+		return e == KernProtectionFailure || e == KernNoAccess || e == KernDenied
+	case fs.ErrExist:
+		return e == KernNameExists || e == KernRightExists || e == KernAlreadyInSet
+	case fs.ErrNotExist:
+		return e == KernInvalidName
+	case fs.ErrInvalid:
+		return e == KernInvalidArgument || e == KernInvalidValue
+	case fs.ErrClosed:
+		return e == KernTerminated || e == KernSemaphoreDestroyed || e == KernLockSetDestroyed
+	case os.ErrDeadlineExceeded, context.DeadlineExceeded:
+		return e.Timeout()
+	case context.Canceled:
+		return e == KernAborted
+	}
+
+	if me, ok := target.(MachError); ok {
+		return MachError(e) == me
+	}
+
+	return false
+}
+
+// As finds the MachError representation of the KernReturn for errors.As.
+func (e KernReturn) As(target interface{}) bool {
+	if me, ok := target.(*MachError); ok {
+		*me = MachError(e)
+		return true
+	}
+
+	return false
+}
+
+// Temporary reports whether the operation may succeed if retried.
+func (e KernReturn) Temporary() bool {
+	return e == KernResourceShortage || e == KernMemoryError || e == KernAborted || e.Timeout()
+}
+
+// Timeout reports whether the KernReturn is a timeout.
+func (e KernReturn) Timeout() bool {
+	return e == KernOperationTimedOut
+}
+
+// String returns the C constant name of the KernReturn, such as "KERN_INVALID_VALUE".
+func (e KernReturn) String() string {
+	if 0 <= int(e) && int(e) < len(kernReturnNames) {
+		s := kernReturnNames[e]
+		if s != "" {
+			return s
+		}
+	}
+
+	return "kern_return_t(" + itoa(int(e)) + ")"
+}
+
+// GoString returns the C constant name of the KernReturn, so that %#v
+// prints KERN_INVALID_VALUE rather than 18.
+func (e KernReturn) GoString() string {
+	return e.String()
+}
+
+// ParseKernReturn parses s as the C constant name of a KernReturn, such as
+// "KERN_INVALID_VALUE", or as a number in the base prefix syntax of
+// strconv.ParseInt, such as "18" or "0x12".
+func ParseKernReturn(s string) (KernReturn, error) {
+	for i, name := range kernReturnNames {
+		if name != "" && name == s {
+			return KernReturn(i), nil
+		}
+	}
+
+	n, err := strconv.ParseInt(s, 0, 32)
+	if err != nil {
+		return 0, &strconv.NumError{Func: "ParseKernReturn", Num: s, Err: err.(*strconv.NumError).Err}
+	}
+
+	return KernReturn(n), nil
+}
+
+// KernError records a failed Mach call and the KernReturn it returned.
+type KernError struct {
+	// Op is the name of the failed call, such as "task_for_pid".
+	Op string
+
+	// Code is the KernReturn returned by the call.
+	Code KernReturn
+}
+
+// NewKernError returns, as an error, a new KernError with the given
+// operation name and code. As a convenience, if code is KernSuccess,
+// NewKernError returns nil.
+func NewKernError(op string, code KernReturn) error {
+	if code == KernSuccess {
+		return nil
+	}
+
+	return &KernError{Op: op, Code: code}
+}
+
+// Error returns a string representation of the KernError.
+func (e *KernError) Error() string {
+	return e.Op + ": " + e.Code.String() + ": " + e.Code.Error()
+}
+
+// Unwrap returns the underlying KernReturn.
+func (e *KernError) Unwrap() error {
+	return e.Code
+}
+
+// Temporary reports whether the operation may succeed if retried.
+func (e *KernError) Temporary() bool {
+	return e.Code.Temporary()
+}
+
+// Timeout reports whether the KernError is a timeout.
+func (e *KernError) Timeout() bool {
+	return e.Code.Timeout()
 }
@@ -0,0 +1,246 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/go-darwin/sys"
+)
+
+func TestKernReturnIs(t *testing.T) {
+	tests := []struct {
+		err    sys.KernReturn
+		target error
+		want   bool
+	}{
+		{sys.KernProtectionFailure, fs.ErrPermission, true},
+		{sys.KernNoAccess, fs.ErrPermission, true},
+		{sys.KernDenied, fs.ErrPermission, true},
+		{sys.KernFailure, fs.ErrPermission, false},
+		{sys.KernNameExists, fs.ErrExist, true},
+		{sys.KernRightExists, fs.ErrExist, true},
+		{sys.KernInvalidName, fs.ErrNotExist, true},
+		{sys.KernInvalidName, fs.ErrExist, false},
+		{sys.KernInvalidArgument, fs.ErrInvalid, true},
+		{sys.KernInvalidValue, fs.ErrInvalid, true},
+		{sys.KernTerminated, fs.ErrClosed, true},
+		{sys.KernOperationTimedOut, os.ErrDeadlineExceeded, true},
+		{sys.KernOperationTimedOut, context.DeadlineExceeded, true},
+		{sys.KernAborted, context.DeadlineExceeded, false},
+		{sys.KernAborted, context.Canceled, true},
+		{sys.KernInvalidRight, sys.MachError(sys.KernInvalidRight), true},
+		{sys.KernInvalidRight, sys.MachError(sys.KernInvalidName), false},
+		{sys.KernInvalidRight, sys.MachRcvTimedOut, false},
+	}
+	for _, tt := range tests {
+		if got := errors.Is(tt.err, tt.target); got != tt.want {
+			t.Errorf("errors.Is(%#x, %v) = %t, want %t", int32(tt.err), tt.target, got, tt.want)
+		}
+
+		wrapped := fmt.Errorf("task_for_pid: %w", tt.err)
+		if got := errors.Is(wrapped, tt.target); got != tt.want {
+			t.Errorf("errors.Is(%v, %v) = %t, want %t", wrapped, tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestKernReturnAs(t *testing.T) {
+	err := fmt.Errorf("mach_port_deallocate: %w", sys.KernInvalidRight)
+
+	var kr sys.KernReturn
+	if !errors.As(err, &kr) || kr != sys.KernInvalidRight {
+		t.Errorf("errors.As(%v, *KernReturn) = %#x, want %#x", err, int32(kr), int32(sys.KernInvalidRight))
+	}
+
+	var me sys.MachError
+	if !errors.As(err, &me) || me != sys.MachError(sys.KernInvalidRight) {
+		t.Errorf("errors.As(%v, *MachError) = %#x, want %#x", err, int32(me), int32(sys.KernInvalidRight))
+	}
+	if got, want := me.Error(), "(os/kern) invalid right"; got != want {
+		t.Errorf("MachError.Error() = %q, want %q", got, want)
+	}
+}
+
+func TestKernReturnTimeout(t *testing.T) {
+	tests := []struct {
+		err                error
+		timeout, temporary bool
+	}{
+		{sys.KernOperationTimedOut, true, true},
+		{sys.KernResourceShortage, false, true},
+		{sys.KernMemoryError, false, true},
+		{sys.KernAborted, false, true},
+		{sys.KernInvalidArgument, false, false},
+		{sys.MachError(sys.KernOperationTimedOut), true, true},
+		{sys.MachError(sys.KernResourceShortage), false, true},
+		{sys.MachSendTimedOut, true, true},
+		{sys.MachRcvTimedOut, true, true},
+		{sys.MachRcvInterrupted, false, true},
+		{sys.MachSendInvalidDest, false, false},
+	}
+	for _, tt := range tests {
+		e := tt.err.(interface {
+			Timeout() bool
+			Temporary() bool
+		})
+		if got := e.Timeout(); got != tt.timeout {
+			t.Errorf("%v: Timeout() = %t, want %t", tt.err, got, tt.timeout)
+		}
+		if got := e.Temporary(); got != tt.temporary {
+			t.Errorf("%v: Temporary() = %t, want %t", tt.err, got, tt.temporary)
+		}
+	}
+}
+
+func TestMachErrorIs(t *testing.T) {
+	tests := []struct {
+		err    sys.MachError
+		target error
+		want   bool
+	}{
+		{sys.MachError(sys.KernNoAccess), fs.ErrPermission, true},
+		{sys.MachError(sys.KernNoAccess), sys.KernNoAccess, true},
+		{sys.MachError(sys.KernNoAccess), sys.KernDenied, false},
+		{sys.MachRcvTimedOut, os.ErrDeadlineExceeded, true},
+		{sys.MachRcvTimedOut, fs.ErrPermission, false},
+	}
+	for _, tt := range tests {
+		if got := errors.Is(tt.err, tt.target); got != tt.want {
+			t.Errorf("errors.Is(%#x, %v) = %t, want %t", int32(tt.err), tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestKernReturnString(t *testing.T) {
+	tests := []struct {
+		err  sys.KernReturn
+		want string
+	}{
+		{sys.KernSuccess, "KERN_SUCCESS"},
+		{sys.KernInvalidValue, "KERN_INVALID_VALUE"},
+		{sys.KernLockOwned, "KERN_LOCK_OWNED"},
+		{sys.KernNotSupported, "KERN_NOT_SUPPORTED"},
+		{sys.KernInvalidKC, "KERN_INVALID_KC"},
+		{sys.KernReturnMax, "KERN_RETURN_MAX"},
+		{sys.KernReturn(0x42), "kern_return_t(66)"},
+		{sys.KernReturn(-1), "kern_return_t(-1)"},
+	}
+	for _, tt := range tests {
+		if got := tt.err.String(); got != tt.want {
+			t.Errorf("KernReturn(%#x).String() = %q, want %q", int32(tt.err), got, tt.want)
+		}
+		if got := fmt.Sprintf("%#v", tt.err); got != tt.want {
+			t.Errorf("Sprintf(%%#v, %#x) = %q, want %q", int32(tt.err), got, tt.want)
+		}
+	}
+}
+
+func TestKernReturnError(t *testing.T) {
+	tests := []struct {
+		err  sys.KernReturn
+		want string
+	}{
+		{sys.KernInvalidAddress, "specified address is not currently valid"},
+		{sys.KernNotReceiver, "task in question does not hold receive rights for the port argument"},
+		{sys.KernNoAccess, "bogus access restriction"},
+		{sys.KernInvalidHost, "target host isn't actually a host"},
+		{sys.KernMemoryRestartCopy, "strategic copy was attempted of an object upon which a quicker copy is now possible"},
+		{sys.KernOperationTimedOut, "some thread-oriented operation (semaphore_wait) timed out"},
+		{sys.KernNotFound, "search or query operation did not return a result"},
+		{sys.KernReturn(0x42), "errno 66"},
+	}
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("%v.Error() = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestParseKernReturn(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    sys.KernReturn
+		wantErr bool
+	}{
+		{s: "KERN_SUCCESS", want: sys.KernSuccess},
+		{s: "KERN_INVALID_VALUE", want: sys.KernInvalidValue},
+		{s: "KERN_OPERATION_TIMED_OUT", want: sys.KernOperationTimedOut},
+		{s: "18", want: sys.KernInvalidValue},
+		{s: "0x12", want: sys.KernInvalidValue},
+		{s: "0x100", want: sys.KernReturnMax},
+		{s: "-1", want: sys.KernReturn(-1)},
+		{s: "kern_invalid_value", wantErr: true},
+		{s: "", wantErr: true},
+		{s: "0x100000000", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := sys.ParseKernReturn(tt.s)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseKernReturn(%q) error = %v, wantErr %t", tt.s, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseKernReturn(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+
+	for _, tt := range []struct {
+		s   string
+		err error
+	}{
+		{"kern_invalid_value", strconv.ErrSyntax},
+		{"0x100000000", strconv.ErrRange},
+	} {
+		_, err := sys.ParseKernReturn(tt.s)
+		var ne *strconv.NumError
+		if !errors.As(err, &ne) || ne.Func != "ParseKernReturn" || ne.Err != tt.err {
+			t.Errorf("ParseKernReturn(%q) error = %v, want a *strconv.NumError of %v", tt.s, err, tt.err)
+		}
+	}
+
+	for i := 0; i <= 0x100; i++ {
+		kr := sys.KernReturn(i)
+		got, err := sys.ParseKernReturn(kr.String())
+		if kr.String() == fmt.Sprintf("kern_return_t(%d)", i) {
+			continue
+		}
+		if err != nil || got != kr {
+			t.Errorf("ParseKernReturn(%q) = %v, %v; want %v", kr.String(), got, err, kr)
+		}
+	}
+}
+
+func TestKernError(t *testing.T) {
+	if err := sys.NewKernError("mach_port_deallocate", sys.KernSuccess); err != nil {
+		t.Fatalf("NewKernError(KERN_SUCCESS) = %v, want nil", err)
+	}
+
+	err := sys.NewKernError("mach_port_deallocate", sys.KernInvalidRight)
+	if got, want := err.Error(), "mach_port_deallocate: KERN_INVALID_RIGHT: "+sys.KernInvalidRight.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if !errors.Is(err, sys.KernInvalidRight) {
+		t.Errorf("errors.Is(%v, KERN_INVALID_RIGHT) = false, want true", err)
+	}
+
+	var ke *sys.KernError
+	if !errors.As(fmt.Errorf("release: %w", err), &ke) || ke.Op != "mach_port_deallocate" || ke.Code != sys.KernInvalidRight {
+		t.Errorf("errors.As(*KernError) = %+v", ke)
+	}
+
+	err = sys.NewKernError("semaphore_timedwait", sys.KernOperationTimedOut)
+	if !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Errorf("errors.Is(%v, os.ErrDeadlineExceeded) = false, want true", err)
+	}
+	if te, ok := err.(interface{ Timeout() bool }); !ok || !te.Timeout() {
+		t.Errorf("%v: Timeout() = false, want true", err)
+	}
+}
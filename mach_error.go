@@ -0,0 +1,708 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys
+
+import (
+	"context"
+	"os"
+)
+
+// MachError represents a mach_error_t.
+//
+// A mach_error_t is made of a 6 bit system, a 12 bit subsystem and a 14 bit code:
+//
+//	hi                                       lo
+//	| system(6) | subsystem(12) | code(14) |
+//
+// A kern_return_t is a mach_error_t of the kernel system, kernel subsystem.
+type MachError int32
+
+// MachErrorSystem is the system of a MachError.
+type MachErrorSystem int
+
+// list of MachErrorSystem.
+const (
+	// MachErrKern is the kernel system.
+	MachErrKern MachErrorSystem = 0x0
+
+	// MachErrUS is the user space library system.
+	MachErrUS MachErrorSystem = 0x1
+
+	// MachErrServer is the user space servers system.
+	MachErrServer MachErrorSystem = 0x2
+
+	// MachErrIPC is the old ipc errors system.
+	MachErrIPC MachErrorSystem = 0x3
+
+	// MachErrMachIPC is the mach-ipc errors system.
+	MachErrMachIPC MachErrorSystem = 0x4
+
+	// MachErrDIPC is the distributed ipc system.
+	MachErrDIPC MachErrorSystem = 0x7
+
+	// MachErrLibkern is the libkern system.
+	MachErrLibkern MachErrorSystem = 0x37
+
+	// MachErrIOKit is the IOKit system.
+	MachErrIOKit MachErrorSystem = 0x38
+
+	// MachErrLocal is the user defined errors system.
+	MachErrLocal MachErrorSystem = 0x3e
+
+	// MachErrIPCCompat is the (compatibility) mach-ipc errors system.
+	MachErrIPCCompat MachErrorSystem = 0x3f
+)
+
+// String returns a string representation of the MachErrorSystem.
+func (s MachErrorSystem) String() string {
+	switch s {
+	case MachErrKern:
+		return "kernel"
+	case MachErrUS:
+		return "user space library"
+	case MachErrServer:
+		return "user space servers"
+	case MachErrIPC:
+		return "old ipc errors"
+	case MachErrMachIPC:
+		return "mach-ipc errors"
+	case MachErrDIPC:
+		return "distributed ipc"
+	case MachErrLibkern:
+		return "libkern"
+	case MachErrIOKit:
+		return "iokit"
+	case MachErrLocal:
+		return "user defined errors"
+	case MachErrIPCCompat:
+		return "(compatibility) mach-ipc errors"
+	default:
+		return "system " + itoa(int(s))
+	}
+}
+
+const (
+	machErrSystemShift = 26
+	machErrSubShift    = 14
+	machErrSystemMask  = 0x3f
+	machErrSubMask     = 0xfff
+	machErrCodeMask    = 0x3fff
+)
+
+// NewMachError returns the MachError of the code in the sub subsystem of system.
+func NewMachError(system MachErrorSystem, sub, code int) MachError {
+	return MachError(uint32(system&machErrSystemMask)<<machErrSystemShift |
+		uint32(sub&machErrSubMask)<<machErrSubShift |
+		uint32(code&machErrCodeMask))
+}
+
+// System returns the system of the error, like err_get_system.
+func (e MachError) System() MachErrorSystem {
+	return MachErrorSystem(uint32(e) >> machErrSystemShift & machErrSystemMask)
+}
+
+// Sub returns the subsystem of the error, like err_get_sub.
+func (e MachError) Sub() int {
+	return int(uint32(e) >> machErrSubShift & machErrSubMask)
+}
+
+// Code returns the code of the error, like err_get_code.
+func (e MachError) Code() int {
+	return int(uint32(e) & machErrCodeMask)
+}
+
+// list of MachError returned by mach_msg when sending a message.
+const (
+	MachMsgSuccess           MachError = 0x00000000
+	MachSendInProgress       MachError = 0x10000001
+	MachSendInvalidData      MachError = 0x10000002
+	MachSendInvalidDest      MachError = 0x10000003
+	MachSendTimedOut         MachError = 0x10000004
+	MachSendInvalidVoucher   MachError = 0x10000005
+	MachSendInterrupted      MachError = 0x10000007
+	MachSendMsgTooSmall      MachError = 0x10000008
+	MachSendInvalidReply     MachError = 0x10000009
+	MachSendInvalidRight     MachError = 0x1000000a
+	MachSendInvalidNotify    MachError = 0x1000000b
+	MachSendInvalidMemory    MachError = 0x1000000c
+	MachSendNoBuffer         MachError = 0x1000000d
+	MachSendTooLarge         MachError = 0x1000000e
+	MachSendInvalidType      MachError = 0x1000000f
+	MachSendInvalidHeader    MachError = 0x10000010
+	MachSendInvalidTrailer   MachError = 0x10000011
+	MachSendInvalidContext   MachError = 0x10000012
+	MachSendInvalidOptions   MachError = 0x10000013
+	MachSendInvalidRtOOLSize MachError = 0x10000015
+	MachSendNoGrantDest      MachError = 0x10000016
+	MachSendMsgFiltered      MachError = 0x10000017
+	MachSendAuxTooSmall      MachError = 0x10000018
+	MachSendAuxTooLarge      MachError = 0x10000019
+)
+
+// list of MachError returned by mach_msg when receiving a message.
+const (
+	MachRcvInProgress       MachError = 0x10004001
+	MachRcvInvalidName      MachError = 0x10004002
+	MachRcvTimedOut         MachError = 0x10004003
+	MachRcvTooLarge         MachError = 0x10004004
+	MachRcvInterrupted      MachError = 0x10004005
+	MachRcvPortChanged      MachError = 0x10004006
+	MachRcvInvalidNotify    MachError = 0x10004007
+	MachRcvInvalidData      MachError = 0x10004008
+	MachRcvPortDied         MachError = 0x10004009
+	MachRcvInSet            MachError = 0x1000400a
+	MachRcvHeaderError      MachError = 0x1000400b
+	MachRcvBodyError        MachError = 0x1000400c
+	MachRcvInvalidType      MachError = 0x1000400d
+	MachRcvScatterSmall     MachError = 0x1000400e
+	MachRcvInvalidTrailer   MachError = 0x1000400f
+	MachRcvInProgressTimed  MachError = 0x10004011
+	MachRcvInvalidReply     MachError = 0x10004012
+	MachRcvInvalidArguments MachError = 0x10004013
+)
+
+// list of MachError returned by MIG generated stubs.
+const (
+	MigTypeError     MachError = -300
+	MigReplyMismatch MachError = -301
+	MigRemoteError   MachError = -302
+	MigBadID         MachError = -303
+	MigBadArguments  MachError = -304
+	MigNoReply       MachError = -305
+	MigException     MachError = -306
+	MigArrayTooLarge MachError = -307
+	MigServerDied    MachError = -308
+	MigTrailerError  MachError = -309
+)
+
+// compat maps the old error numbers to the new system and subsystem, like do_compat.
+func (e MachError) compat() MachError {
+	switch {
+	case -200 < e && e <= -100:
+		return NewMachError(MachErrIPC, 0, int(-(e + 100)))
+	case -300 < e && e <= -200:
+		return NewMachError(MachErrIPC, 1, int(-(e + 200)))
+	case -400 < e && e <= -300:
+		return NewMachError(MachErrMachIPC, 2, int(-(e + 300)))
+	case 1000 <= e && e < 1100:
+		return NewMachError(MachErrServer, 0, int(e-1000))
+	case 1600 <= e && e < 1700:
+		return NewMachError(MachErrServer, 1, int(e-1600))
+	case 27600 <= e && e < 27700:
+		return NewMachError(MachErrServer, 2, int(e-27600))
+	default:
+		return e
+	}
+}
+
+// Type returns the name of the subsystem of the error, such as "(os/kern)",
+// like mach_error_type.
+func (e MachError) Type() string {
+	e = e.compat()
+
+	sys, ok := machErrors[e.System()]
+	if !ok {
+		return "(?/?)"
+	}
+	sub, ok := sys.subs[e.Sub()]
+	if !ok {
+		return "(?/?)"
+	}
+
+	return sub.name
+}
+
+// Error returns a string representation of the MachError, like mach_error_string.
+func (e MachError) Error() string {
+	e = e.compat()
+
+	sys, ok := machErrors[e.System()]
+	if !ok {
+		return machNoSuchError
+	}
+	sub, ok := sys.subs[e.Sub()]
+	if !ok {
+		return sys.badSub
+	}
+	code := e.Code() - sub.base
+	if code < 0 || code >= len(sub.codes) || sub.codes[code] == "" {
+		return machNoSuchError
+	}
+
+	return sub.codes[code]
+}
+
+// Is reports whether the MachError matches the target error.
+//
+// A MachError of the kernel subsystem matches the same targets as the
+// corresponding KernReturn, and a MachError of the IOKit system the same
+// targets as the corresponding IOReturn.
+func (e MachError) Is(target error) bool {
+	switch t := target.(type) {
+	case KernReturn:
+		return e == MachError(t)
+	case IOReturn:
+		return e == MachError(t)
+	}
+	if e.System() == MachErrKern && e.Sub() == 0 {
+		return KernReturn(e).Is(target)
+	}
+	if e.System() == MachErrIOKit {
+		return IOReturn(e).Is(target)
+	}
+
+	switch target {
+	case os.ErrDeadlineExceeded, context.DeadlineExceeded:
+		return e.Timeout()
+	}
+
+	return false
+}
+
+// Temporary reports whether the operation may succeed if retried.
+func (e MachError) Temporary() bool {
+	if e.System() == MachErrKern && e.Sub() == 0 {
+		return KernReturn(e).Temporary()
+	}
+	if e.System() == MachErrIOKit {
+		return IOReturn(e).Temporary()
+	}
+
+	return e == MachSendInterrupted || e == MachRcvInterrupted || e == MachSendNoBuffer || e.Timeout()
+}
+
+// Timeout reports whether the MachError is a timeout.
+func (e MachError) Timeout() bool {
+	return e == MachError(KernOperationTimedOut) || e == MachError(IOReturnTimeout) || e == MachSendTimedOut || e == MachRcvTimedOut
+}
+
+// machNoSuchError is the message of an unknown code.
+const machNoSuchError = "unknown error code"
+
+// machErrorSystem is the message table of a system.
+type machErrorSystem struct {
+	badSub string
+	subs   map[int]machErrorSubsystem
+}
+
+// machErrorSubsystem is the message table of a subsystem.
+type machErrorSubsystem struct {
+	name  string
+	base  int
+	codes []string
+}
+
+// machErrors is the message table of mach_error_string.
+var machErrors = map[MachErrorSystem]machErrorSystem{
+	MachErrKern: {
+		badSub: "(operating system/?) unknown subsystem error",
+		subs: map[int]machErrorSubsystem{
+			0: {name: "(os/kern)", codes: machErrCodesKern},
+			1: {name: "(os/?)"},
+			2: {name: "(os/?)"},
+			3: {name: "(os/unix)", codes: machErrCodesUnix},
+		},
+	},
+	MachErrUS: {
+		badSub: "(user space/?) unknown subsystem error",
+	},
+	MachErrServer: {
+		badSub: "(server/?) unknown subsystem error",
+		subs: map[int]machErrorSubsystem{
+			0: {name: "(server/netname)", codes: machErrCodesNetname},
+			1: {name: "(server/env_mgr)", codes: machErrCodesEnvMgr},
+			2: {name: "(server/execd)", codes: machErrCodesExecd},
+		},
+	},
+	MachErrIPC: {
+		badSub: "(ipc/?) unknown subsystem error",
+		subs: map[int]machErrorSubsystem{
+			0: {name: "(ipc/send)", codes: machErrCodesSend},
+			1: {name: "(ipc/rcv)", codes: machErrCodesRcv},
+			2: {name: "(ipc/mig)", codes: machErrCodesMig},
+		},
+	},
+	MachErrMachIPC: {
+		badSub: "(ipc/?) unknown subsystem error",
+		subs: map[int]machErrorSubsystem{
+			0: {name: "(ipc/send)", codes: machErrCodesMachSend},
+			1: {name: "(ipc/rcv)", codes: machErrCodesMachRcv},
+			2: {name: "(ipc/mig)", codes: machErrCodesMig},
+		},
+	},
+	MachErrLibkern: {
+		badSub: "(libkern/?) unknown subsystem error",
+		subs: map[int]machErrorSubsystem{
+			0: {name: "(libkern/common)"},
+			1: {name: "(libkern/kext)", codes: machErrCodesLibkernKext},
+		},
+	},
+	MachErrIOKit: {
+		badSub: "(iokit/?) unknown subsystem error",
+		subs: map[int]machErrorSubsystem{
+			0:     {name: "(iokit/common)", base: 0x2bc, codes: machErrCodesIOKitCommon},
+			1:     {name: "(iokit/usb)"},
+			2:     {name: "(iokit/firewire)"},
+			4:     {name: "(iokit/blkstorage)"},
+			5:     {name: "(iokit/graphics)"},
+			6:     {name: "(iokit/networking)"},
+			8:     {name: "(iokit/bluetooth)"},
+			9:     {name: "(iokit/pmu)"},
+			10:    {name: "(iokit/acpi)"},
+			11:    {name: "(iokit/smbus)"},
+			12:    {name: "(iokit/ahci)"},
+			13:    {name: "(iokit/power)"},
+			14:    {name: "(iokit/hidsystem)"},
+			16:    {name: "(iokit/scsi)"},
+			17:    {name: "(iokit/usbaudio)"},
+			18:    {name: "(iokit/wirelesscharging)"},
+			29:    {name: "(iokit/thunderbolt)"},
+			30:    {name: "(iokit/graphics_acceleration)"},
+			31:    {name: "(iokit/keystore)"},
+			33:    {name: "(iokit/apfs)"},
+			34:    {name: "(iokit/acpiec)"},
+			35:    {name: "(iokit/timesync_avb)"},
+			0x2a:  {name: "(iokit/platform)"},
+			0x45:  {name: "(iokit/audio_video)"},
+			0x46:  {name: "(iokit/cec)"},
+			0x47:  {name: "(iokit/arc)"},
+			0x80:  {name: "(iokit/baseband)"},
+			0xfe:  {name: "(iokit/HDA)"},
+			0x147: {name: "(iokit/hsic)"},
+			0x174: {name: "(iokit/sdio)"},
+			0x208: {name: "(iokit/wlan)"},
+			0xffe: {name: "(iokit/vendor_specific)"},
+			0xfff: {name: "(iokit/reserved)"},
+		},
+	},
+}
+
+var machErrCodesKern = []string{
+	0x0:  "(os/kern) successful",
+	0x1:  "(os/kern) invalid address",
+	0x2:  "(os/kern) protection failure",
+	0x3:  "(os/kern) no space available",
+	0x4:  "(os/kern) invalid argument",
+	0x5:  "(os/kern) failure",
+	0x6:  "(os/kern) resource shortage",
+	0x7:  "(os/kern) not receiver",
+	0x8:  "(os/kern) no access",
+	0x9:  "(os/kern) memory failure",
+	0xa:  "(os/kern) memory error",
+	0xb:  "(os/kern) already in set",
+	0xc:  "(os/kern) not in set",
+	0xd:  "(os/kern) name exists",
+	0xe:  "(os/kern) aborted",
+	0xf:  "(os/kern) invalid name",
+	0x10: "(os/kern) invalid task",
+	0x11: "(os/kern) invalid right",
+	0x12: "(os/kern) invalid value",
+	0x13: "(os/kern) urefs overflow",
+	0x14: "(os/kern) invalid capability",
+	0x15: "(os/kern) right exists",
+	0x16: "(os/kern) invalid host",
+	0x17: "(os/kern) memory present",
+	0x18: "(os/kern) memory data moved",
+	0x19: "(os/kern) memory restart copy",
+	0x1a: "(os/kern) invalid processor set",
+	0x1b: "(os/kern) policy limit",
+	0x1c: "(os/kern) invalid policy",
+	0x1d: "(os/kern) invalid object",
+	0x1e: "(os/kern) already waiting",
+	0x1f: "(os/kern) default set",
+	0x20: "(os/kern) exception protected",
+	0x21: "(os/kern) invalid ledger",
+	0x22: "(os/kern) invalid memory control",
+	0x23: "(os/kern) invalid security",
+	0x24: "(os/kern) not depressed",
+	0x25: "(os/kern) object terminated",
+	0x26: "(os/kern) lock set destroyed",
+	0x27: "(os/kern) lock unstable",
+	0x28: "(os/kern) lock owned by another",
+	0x29: "(os/kern) lock owned by self",
+	0x2a: "(os/kern) semaphore destroyed",
+	0x2b: "(os/kern) RPC terminated",
+	0x2c: "(os/kern) terminate orphan",
+	0x2d: "(os/kern) let orphan continue",
+	0x2e: "(os/kern) service not supported",
+	0x2f: "(os/kern) remote node down",
+	0x30: "(os/kern) thread not waiting",
+	0x31: "(os/kern) operation timed out",
+	0x32: "(os/kern) code signing error",
+	0x33: "(os/kern) policy is static",
+	0x34: "(os/kern) insufficient input buffer size",
+	0x35: "(os/kern) denied",
+	0x36: "(os/kern) missing kc",
+	0x37: "(os/kern) invalid kc",
+	0x38: "(os/kern) not found",
+}
+
+var machErrCodesUnix = []string{
+	0x1:  "(os/unix) no rights to object",
+	0x2:  "(os/unix) file or directory does not exist",
+	0x3:  "(os/unix) no such process",
+	0x4:  "(os/unix) interrupted system call",
+	0x5:  "(os/unix) i/o error",
+	0x6:  "(os/unix) device does not exist",
+	0x7:  "(os/unix) argument list is too long",
+	0x8:  "(os/unix) invalid executable object format",
+	0x9:  "(os/unix) bad file descriptor number",
+	0xa:  "(os/unix) no child processes are present",
+	0xb:  "(os/unix) no more processes are available",
+	0xc:  "(os/unix) insufficient memory",
+	0xd:  "(os/unix) access denied",
+	0xe:  "(os/unix) memory access fault",
+	0xf:  "(os/unix) block device required for operation",
+	0x10: "(os/unix) mount device busy",
+	0x11: "(os/unix) file already exists",
+	0x12: "(os/unix) cross device link",
+	0x13: "(os/unix) device does not exist",
+	0x14: "(os/unix) object is not a directory",
+	0x15: "(os/unix) object is a directory",
+	0x16: "(os/unix) invalid argument",
+	0x17: "(os/unix) internal file table overflow",
+	0x18: "(os/unix) too many open files",
+	0x19: "(os/unix) inappropriate operation for device",
+	0x1a: "(os/unix) text file is busy",
+	0x1b: "(os/unix) file is too large",
+	0x1c: "(os/unix) no space is left on device",
+	0x1d: "(os/unix) illegal seek",
+	0x1e: "(os/unix) read-only file system",
+	0x1f: "(os/unix) too many links",
+	0x20: "(os/unix) broken pipe",
+	0x21: "(os/unix) argument is out of range",
+	0x22: "(os/unix) result is out of range",
+	0x23: "(os/unix) operation on device would block",
+	0x24: "(os/unix) operation is now in progress",
+	0x25: "(os/unix) operation is already in progress",
+	0x26: "(os/unix) socket operation attempted on non-socket object",
+	0x27: "(os/unix) destination address is required",
+	0x28: "(os/unix) message is too long",
+	0x29: "(os/unix) protocol type is incorrect for socket",
+	0x2a: "(os/unix) protocol type is not availaible",
+	0x2b: "(os/unix) protocol type is not supported",
+	0x2c: "(os/unix) socket type is not supported",
+	0x2d: "(os/unix) operation is not supported on sockets",
+	0x2e: "(os/unix) protocol family is not supported",
+	0x2f: "(os/unix) address family is not supported by protocol family",
+	0x30: "(os/unix) address is already in use",
+	0x31: "(os/unix) can't assign requested address",
+	0x32: "(os/unix) network is down",
+	0x33: "(os/unix) network is unreachable",
+	0x34: "(os/unix) network dropped connection on reset",
+	0x35: "(os/unix) software caused connection abort",
+	0x36: "(os/unix) connection reset by peer",
+	0x37: "(os/unix) no buffer space is available",
+	0x38: "(os/unix) socket is already connected",
+	0x39: "(os/unix) socket is not connected",
+	0x3a: "(os/unix) can't send after socket shutdown",
+	0x3b: "(os/unix) too many references; can't splice",
+	0x3c: "(os/unix) connection timed out",
+	0x3d: "(os/unix) connection was refused",
+	0x3e: "(os/unix) too many levels of symbolic links",
+	0x3f: "(os/unix) file name exceeds system maximum limit",
+	0x40: "(os/unix) host is down",
+	0x41: "(os/unix) there is no route to host",
+	0x42: "(os/unix) directory is not empty",
+	0x43: "(os/unix) quota on number of processes exceeded",
+	0x44: "(os/unix) too many users",
+	0x45: "(os/unix) quota on disk exceeded",
+}
+
+var machErrCodesNetname = []string{
+	0x0: "(server/netname) name is not yours",
+	0x1: "(server/netname) name not checked in",
+	0x2: "(server/netname) no such host",
+	0x3: "(server/netname) host not found",
+}
+
+var machErrCodesEnvMgr = []string{
+	0x1: "(server/env_mgr) variable not found",
+	0x2: "(server/env_mgr) wrong type of variable",
+	0x3: "(server/env_mgr) unknown port",
+	0x4: "(server/env_mgr) variable is read only",
+	0x5: "(server/env_mgr) no more connections available",
+	0x6: "(server/env_mgr) port table full",
+	0x7: "(server/env_mgr) attempting to enter a null port",
+}
+
+var machErrCodesExecd = []string{
+	0x1: "(server/execd) could not find file to run",
+	0x2: "(server/execd) userid or password incorrect",
+	0x3: "(server/execd) fork failed",
+}
+
+var machErrCodesSend = []string{
+	0x0: "(ipc/send) unknown error",
+	0x1: "(ipc/send) invalid memory",
+	0x2: "(ipc/send) invalid port",
+	0x3: "(ipc/send) timed out",
+	0x4: "(ipc/send) unused error",
+	0x5: "(ipc/send) will notify",
+	0x6: "(ipc/send) notify in progress",
+	0x7: "(ipc/send) kernel refused message",
+	0x8: "(ipc/send) send interrupted",
+	0x9: "(ipc/send) send message too large",
+	0xa: "(ipc/send) send message too small",
+	0xb: "(ipc/send) message size changed while being copied",
+}
+
+var machErrCodesRcv = []string{
+	0x0: "(ipc/rcv) unknown error",
+	0x1: "(ipc/rcv) invalid memory",
+	0x2: "(ipc/rcv) invalid port",
+	0x3: "(ipc/rcv) receive timed out",
+	0x4: "(ipc/rcv) message too large",
+	0x5: "(ipc/rcv) no space for message data",
+	0x6: "(ipc/rcv) only sender remaining",
+	0x7: "(ipc/rcv) receive interrupted",
+	0x8: "(ipc/rcv) port receiver changed or port became enabled",
+}
+
+var machErrCodesMig = []string{
+	0x0: "(ipc/mig) type check failure in message interface",
+	0x1: "(ipc/mig) wrong return message ID",
+	0x2: "(ipc/mig) server detected error",
+	0x3: "(ipc/mig) bad request message ID",
+	0x4: "(ipc/mig) server found wrong arguments",
+	0x5: "(ipc/mig) no reply should be sent",
+	0x6: "(ipc/mig) server raised exception",
+	0x7: "(ipc/mig) user specified array not large enough for return info",
+	0x8: "(ipc/mig) server is dead",
+	0x9: "(ipc/mig) incorrect trailer",
+}
+
+var machErrCodesMachSend = []string{
+	0x0:  "(ipc/send) no error",
+	0x1:  "(ipc/send) send in progress",
+	0x2:  "(ipc/send) invalid data",
+	0x3:  "(ipc/send) invalid destination port",
+	0x4:  "(ipc/send) timed out",
+	0x5:  "(ipc/send) invalid voucher",
+	0x6:  "(ipc/send) unused error",
+	0x7:  "(ipc/send) interrupted",
+	0x8:  "(ipc/send) msg too small",
+	0x9:  "(ipc/send) invalid reply port",
+	0xa:  "(ipc/send) invalid port right",
+	0xb:  "(ipc/send) invalid notify port",
+	0xc:  "(ipc/send) invalid memory",
+	0xd:  "(ipc/send) no msg buffer",
+	0xe:  "(ipc/send) msg too large",
+	0xf:  "(ipc/send) invalid msg-type",
+	0x10: "(ipc/send) invalid msg-header",
+	0x11: "(ipc/send) invalid msg-trailer",
+	0x12: "(ipc/send) invalid context for reply",
+	0x13: "(ipc/send) invalid options",
+	0x14: "(ipc/send) unused error",
+	0x15: "(ipc/send) out-of-line buffer too large",
+	0x16: "(ipc/send) destination does not accept OOL ports",
+	0x17: "(ipc/send) message filtered",
+	0x18: "(ipc/send) auxiliary data buffer too small",
+	0x19: "(ipc/send) auxiliary data buffer too large",
+}
+
+var machErrCodesMachRcv = []string{
+	0x0:  "(ipc/rcv) no error",
+	0x1:  "(ipc/rcv) receive in progress",
+	0x2:  "(ipc/rcv) invalid name",
+	0x3:  "(ipc/rcv) timed out",
+	0x4:  "(ipc/rcv) msg too large",
+	0x5:  "(ipc/rcv) interrupted",
+	0x6:  "(ipc/rcv) port changed",
+	0x7:  "(ipc/rcv) invalid notify port",
+	0x8:  "(ipc/rcv) invalid data",
+	0x9:  "(ipc/rcv) port died",
+	0xa:  "(ipc/rcv) port set died",
+	0xb:  "(ipc/rcv) header error",
+	0xc:  "(ipc/rcv) body error",
+	0xd:  "(ipc/rcv) invalid notify type",
+	0xe:  "(ipc/rcv) scatter buffer too small",
+	0xf:  "(ipc/rcv) invalid trailer",
+	0x10: "(ipc/rcv) unused error",
+	0x11: "(ipc/rcv) in progress timed",
+	0x12: "(ipc/rcv) invalid reply port",
+	0x13: "(ipc/rcv) invalid arguments",
+}
+
+var machErrCodesLibkernKext = []string{
+	0x1:  "(libkern/kext) internal error",
+	0x2:  "(libkern/kext) allocation failure",
+	0x3:  "(libkern/kext) resource shortage",
+	0x4:  "(libkern/kext) not privileged",
+	0x5:  "(libkern/kext) invalid argument",
+	0x6:  "(libkern/kext) not found",
+	0x7:  "(libkern/kext) bad data",
+	0x8:  "(libkern/kext) serialization failure",
+	0x9:  "(libkern/kext) unsupported",
+	0xa:  "(libkern/kext) disabled",
+	0xb:  "(libkern/kext) not a kext",
+	0xc:  "(libkern/kext) validation failure",
+	0xd:  "(libkern/kext) authentication failure",
+	0xe:  "(libkern/kext) dependencies failure",
+	0xf:  "(libkern/kext) architecture not found",
+	0x10: "(libkern/kext) cache not found",
+	0x11: "(libkern/kext) deferred",
+	0x12: "(libkern/kext) kext is in use or retained (cannot unload)",
+	0x13: "(libkern/kext) kext request timed out",
+	0x14: "(libkern/kext) kext is stopping and cannot issue requests",
+	0x15: "(libkern/kext) system policy prevents loading",
+}
+
+// machErrCodesIOKitCommon is indexed from code 0x2bc (kIOReturnError).
+var machErrCodesIOKitCommon = []string{
+	"(iokit/common) general error",
+	"(iokit/common) memory allocation error",
+	"(iokit/common) resource shortage",
+	"(iokit/common) Mach IPC failure",
+	"(iokit/common) no such device",
+	"(iokit/common) privilege violation",
+	"(iokit/common) invalid argument",
+	"(iokit/common) device is read locked",
+	"(iokit/common) device is write locked",
+	"(iokit/common) device is exclusive access",
+	"(iokit/common) sent/received messages had different msg_id",
+	"(iokit/common) unsupported function",
+	"(iokit/common) misc. VM failure",
+	"(iokit/common) internal driver error",
+	"(iokit/common) I/O error",
+	"(iokit/common) cannot acquire lock",
+	"(iokit/common) device is not open",
+	"(iokit/common) device is not readable",
+	"(iokit/common) device is not writeable",
+	"(iokit/common) alignment error",
+	"(iokit/common) media error",
+	"(iokit/common) device is still open",
+	"(iokit/common) rld failure",
+	"(iokit/common) DMA failure",
+	"(iokit/common) device is busy",
+	"(iokit/common) I/O timeout",
+	"(iokit/common) device is offline",
+	"(iokit/common) not ready",
+	"(iokit/common) device/channel is not attached",
+	"(iokit/common) no DMA channels available",
+	"(iokit/common) no space for data",
+	"(iokit/common) unused error",
+	"(iokit/common) port already exists",
+	"(iokit/common) cannot wire physical memory",
+	"(iokit/common) no interrupt attached",
+	"(iokit/common) no DMA frames enqueued",
+	"(iokit/common) oversized msg received on interrupt port",
+	"(iokit/common) not permitted",
+	"(iokit/common) no power to device",
+	"(iokit/common) media not present",
+	"(iokit/common) media not formatted properly",
+	"(iokit/common) no such mode",
+	"(iokit/common) data underrun",
+	"(iokit/common) data overrun",
+	"(iokit/common) the device is not working properly",
+	"(iokit/common) a completion routine is required",
+	"(iokit/common) operation aborted",
+	"(iokit/common) bus bandwidth would be exceeded",
+	"(iokit/common) device not responding",
+	"(iokit/common) isochronous I/O request too early",
+	"(iokit/common) isochronous I/O request too late",
+	"(iokit/common) data underrun",
+	"(iokit/common) data overrun",
+	"(iokit/common) unsupported mode",
+}
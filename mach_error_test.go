@@ -0,0 +1,94 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-darwin/sys"
+)
+
+// u32 converts the unsigned representation of a mach_error_t to a MachError.
+func u32(u uint32) sys.MachError { return sys.MachError(u) }
+
+func TestMachErrorDecode(t *testing.T) {
+	tests := []struct {
+		err    sys.MachError
+		system sys.MachErrorSystem
+		sub    int
+		code   int
+	}{
+		{sys.MachError(sys.KernInvalidValue), sys.MachErrKern, 0, 0x12},
+		{sys.MachSendTimedOut, sys.MachErrMachIPC, 0, 4},
+		{sys.MachRcvPortDied, sys.MachErrMachIPC, 1, 9},
+		{u32(0xe00002c2), sys.MachErrIOKit, 0, 0x2c2}, // kIOReturnBadArgument
+		{sys.NewMachError(sys.MachErrKern, 3, 13), sys.MachErrKern, 3, 13},
+		{sys.NewMachError(sys.MachErrLocal, 0xfff, 0x3fff), sys.MachErrLocal, 0xfff, 0x3fff},
+	}
+	for _, tt := range tests {
+		if got := tt.err.System(); got != tt.system {
+			t.Errorf("%#x.System() = %v, want %v", int32(tt.err), got, tt.system)
+		}
+		if got := tt.err.Sub(); got != tt.sub {
+			t.Errorf("%#x.Sub() = %#x, want %#x", int32(tt.err), got, tt.sub)
+		}
+		if got := tt.err.Code(); got != tt.code {
+			t.Errorf("%#x.Code() = %#x, want %#x", int32(tt.err), got, tt.code)
+		}
+		if got := sys.NewMachError(tt.system, tt.sub, tt.code); got != tt.err {
+			t.Errorf("NewMachError(%v, %#x, %#x) = %#x, want %#x", tt.system, tt.sub, tt.code, int32(got), int32(tt.err))
+		}
+	}
+}
+
+func TestMachErrorString(t *testing.T) {
+	tests := []struct {
+		err      sys.MachError
+		typ, msg string
+	}{
+		{sys.MachError(sys.KernSuccess), "(os/kern)", "(os/kern) successful"},
+		{sys.MachError(sys.KernInvalidArgument), "(os/kern)", "(os/kern) invalid argument"},
+		{sys.MachError(sys.KernOperationTimedOut), "(os/kern)", "(os/kern) operation timed out"},
+		{sys.MachError(0x1000), "(os/kern)", "unknown error code"},
+		{sys.NewMachError(sys.MachErrKern, 3, 2), "(os/unix)", "(os/unix) file or directory does not exist"},
+		{sys.NewMachError(sys.MachErrKern, 1, 2), "(os/?)", "unknown error code"},
+		{sys.NewMachError(sys.MachErrKern, 9, 2), "(?/?)", "(operating system/?) unknown subsystem error"},
+		{sys.MachSendInvalidDest, "(ipc/send)", "(ipc/send) invalid destination port"},
+		{sys.MachSendTimedOut, "(ipc/send)", "(ipc/send) timed out"},
+		{sys.MachRcvTimedOut, "(ipc/rcv)", "(ipc/rcv) timed out"},
+		{sys.MachRcvInterrupted, "(ipc/rcv)", "(ipc/rcv) interrupted"},
+		{sys.MigBadID, "(ipc/mig)", "(ipc/mig) bad request message ID"},
+		{sys.MigServerDied, "(ipc/mig)", "(ipc/mig) server is dead"},
+		{sys.MachError(-102), "(ipc/send)", "(ipc/send) invalid port"},
+		{sys.MachError(1602), "(server/env_mgr)", "(server/env_mgr) wrong type of variable"},
+		{u32(0xe00002c2), "(iokit/common)", "(iokit/common) invalid argument"},
+		{u32(0xe00002bc), "(iokit/common)", "(iokit/common) general error"},
+		{sys.NewMachError(sys.MachErrIOKit, 0, 1), "(iokit/common)", "unknown error code"},
+		{sys.NewMachError(sys.MachErrIOKit, 1, 0x51), "(iokit/usb)", "unknown error code"},
+		{sys.NewMachError(sys.MachErrIOKit, 3, 0), "(?/?)", "(iokit/?) unknown subsystem error"},
+		{sys.NewMachError(sys.MachErrLibkern, 1, 6), "(libkern/kext)", "(libkern/kext) not found"},
+		{sys.NewMachError(sys.MachErrDIPC, 0, 0), "(?/?)", "unknown error code"},
+	}
+	for _, tt := range tests {
+		if got := tt.err.Type(); got != tt.typ {
+			t.Errorf("%#x.Type() = %q, want %q", int32(tt.err), got, tt.typ)
+		}
+		if got := tt.err.Error(); got != tt.msg {
+			t.Errorf("%#x.Error() = %q, want %q", int32(tt.err), got, tt.msg)
+		}
+	}
+}
+
+func TestMachErrorAs(t *testing.T) {
+	var err error = sys.MachRcvTimedOut
+
+	var me sys.MachError
+	if !errors.As(err, &me) || me != sys.MachRcvTimedOut {
+		t.Fatalf("errors.As(%v) = %#x, want %#x", err, int32(me), int32(sys.MachRcvTimedOut))
+	}
+	if got, want := me.System().String(), "mach-ipc errors"; got != want {
+		t.Errorf("System().String() = %q, want %q", got, want)
+	}
+}
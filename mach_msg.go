@@ -0,0 +1,688 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys
+
+import (
+	"encoding/binary"
+	"runtime"
+)
+
+// MachMsgTypeName represents a mach_msg_type_name_t, the disposition of a
+// port right carried by a message.
+type MachMsgTypeName uint32
+
+// list of MachMsgTypeName.
+const (
+	MachMsgTypeMoveReceive     MachMsgTypeName = 16 // MACH_MSG_TYPE_MOVE_RECEIVE
+	MachMsgTypeMoveSend        MachMsgTypeName = 17 // MACH_MSG_TYPE_MOVE_SEND
+	MachMsgTypeMoveSendOnce    MachMsgTypeName = 18 // MACH_MSG_TYPE_MOVE_SEND_ONCE
+	MachMsgTypeCopySend        MachMsgTypeName = 19 // MACH_MSG_TYPE_COPY_SEND
+	MachMsgTypeMakeSend        MachMsgTypeName = 20 // MACH_MSG_TYPE_MAKE_SEND
+	MachMsgTypeMakeSendOnce    MachMsgTypeName = 21 // MACH_MSG_TYPE_MAKE_SEND_ONCE
+	MachMsgTypeCopyReceive     MachMsgTypeName = 22 // MACH_MSG_TYPE_COPY_RECEIVE
+	MachMsgTypeDisposeReceive  MachMsgTypeName = 24 // MACH_MSG_TYPE_DISPOSE_RECEIVE
+	MachMsgTypeDisposeSend     MachMsgTypeName = 25 // MACH_MSG_TYPE_DISPOSE_SEND
+	MachMsgTypeDisposeSendOnce MachMsgTypeName = 26 // MACH_MSG_TYPE_DISPOSE_SEND_ONCE
+
+	// MachMsgTypePortName is the disposition of a plain port name, which
+	// carries no right.
+	MachMsgTypePortName MachMsgTypeName = 15 // MACH_MSG_TYPE_PORT_NAME
+
+	// The dispositions of the rights of a received message.
+	MachMsgTypePortReceive  = MachMsgTypeMoveReceive  // MACH_MSG_TYPE_PORT_RECEIVE
+	MachMsgTypePortSend     = MachMsgTypeMoveSend     // MACH_MSG_TYPE_PORT_SEND
+	MachMsgTypePortSendOnce = MachMsgTypeMoveSendOnce // MACH_MSG_TYPE_PORT_SEND_ONCE
+)
+
+// String returns the C name of the MachMsgTypeName, such as
+// "MACH_MSG_TYPE_COPY_SEND".
+func (n MachMsgTypeName) String() string {
+	switch n {
+	case 0:
+		return "0"
+	case MachMsgTypePortName:
+		return "MACH_MSG_TYPE_PORT_NAME"
+	case MachMsgTypeMoveReceive:
+		return "MACH_MSG_TYPE_MOVE_RECEIVE"
+	case MachMsgTypeMoveSend:
+		return "MACH_MSG_TYPE_MOVE_SEND"
+	case MachMsgTypeMoveSendOnce:
+		return "MACH_MSG_TYPE_MOVE_SEND_ONCE"
+	case MachMsgTypeCopySend:
+		return "MACH_MSG_TYPE_COPY_SEND"
+	case MachMsgTypeMakeSend:
+		return "MACH_MSG_TYPE_MAKE_SEND"
+	case MachMsgTypeMakeSendOnce:
+		return "MACH_MSG_TYPE_MAKE_SEND_ONCE"
+	case MachMsgTypeCopyReceive:
+		return "MACH_MSG_TYPE_COPY_RECEIVE"
+	case MachMsgTypeDisposeReceive:
+		return "MACH_MSG_TYPE_DISPOSE_RECEIVE"
+	case MachMsgTypeDisposeSend:
+		return "MACH_MSG_TYPE_DISPOSE_SEND"
+	case MachMsgTypeDisposeSendOnce:
+		return "MACH_MSG_TYPE_DISPOSE_SEND_ONCE"
+	default:
+		return "mach_msg_type_name_t(" + uitoa(uint(n)) + ")"
+	}
+}
+
+// MachMsgBits represents a mach_msg_bits_t, the msgh_bits of a message
+// header, which holds the dispositions of the header ports and the complex
+// bit.
+type MachMsgBits uint32
+
+// list of MachMsgBits masks.
+const (
+	MachMsghBitsRemoteMask  MachMsgBits = 0x0000001f // MACH_MSGH_BITS_REMOTE_MASK
+	MachMsghBitsLocalMask   MachMsgBits = 0x00001f00 // MACH_MSGH_BITS_LOCAL_MASK
+	MachMsghBitsVoucherMask MachMsgBits = 0x001f0000 // MACH_MSGH_BITS_VOUCHER_MASK
+	MachMsghBitsPortsMask   MachMsgBits = 0x001f1f1f // MACH_MSGH_BITS_PORTS_MASK
+	MachMsghBitsComplex     MachMsgBits = 0x80000000 // MACH_MSGH_BITS_COMPLEX
+	MachMsghBitsUser        MachMsgBits = 0x801f1f1f // MACH_MSGH_BITS_USER
+)
+
+// MakeMachMsgBits returns the MachMsgBits of the remote, local and voucher
+// port dispositions, like MACH_MSGH_BITS_SET without other bits.
+func MakeMachMsgBits(remote, local, voucher MachMsgTypeName) MachMsgBits {
+	return MachMsgBits(remote)&MachMsghBitsRemoteMask |
+		MachMsgBits(local)<<8&MachMsghBitsLocalMask |
+		MachMsgBits(voucher)<<16&MachMsghBitsVoucherMask
+}
+
+// Remote returns the disposition of the remote port, like MACH_MSGH_BITS_REMOTE.
+func (b MachMsgBits) Remote() MachMsgTypeName {
+	return MachMsgTypeName(b & MachMsghBitsRemoteMask)
+}
+
+// Local returns the disposition of the local port, like MACH_MSGH_BITS_LOCAL.
+func (b MachMsgBits) Local() MachMsgTypeName {
+	return MachMsgTypeName(b & MachMsghBitsLocalMask >> 8)
+}
+
+// Voucher returns the disposition of the voucher port, like MACH_MSGH_BITS_VOUCHER.
+func (b MachMsgBits) Voucher() MachMsgTypeName {
+	return MachMsgTypeName(b & MachMsghBitsVoucherMask >> 16)
+}
+
+// Complex reports whether the complex bit is set, like MACH_MSGH_BITS_IS_COMPLEX.
+func (b MachMsgBits) Complex() bool {
+	return b&MachMsghBitsComplex != 0
+}
+
+// MachMsgHeader represents a mach_msg_header_t.
+type MachMsgHeader struct {
+	Bits        MachMsgBits
+	Size        uint32 // size of the message, without the trailer
+	RemotePort  MachPort
+	LocalPort   MachPort
+	VoucherPort MachPortName
+	ID          int32
+}
+
+// MachMsgDescriptorType represents a mach_msg_descriptor_type_t.
+type MachMsgDescriptorType uint8
+
+// list of MachMsgDescriptorType.
+const (
+	MachMsgPortDescriptor        MachMsgDescriptorType = 0 // MACH_MSG_PORT_DESCRIPTOR
+	MachMsgOOLDescriptor         MachMsgDescriptorType = 1 // MACH_MSG_OOL_DESCRIPTOR
+	MachMsgOOLPortsDescriptor    MachMsgDescriptorType = 2 // MACH_MSG_OOL_PORTS_DESCRIPTOR
+	MachMsgOOLVolatileDescriptor MachMsgDescriptorType = 3 // MACH_MSG_OOL_VOLATILE_DESCRIPTOR
+	MachMsgGuardedPortDescriptor MachMsgDescriptorType = 4 // MACH_MSG_GUARDED_PORT_DESCRIPTOR
+)
+
+// String returns the C name of the MachMsgDescriptorType, such as
+// "MACH_MSG_PORT_DESCRIPTOR".
+func (t MachMsgDescriptorType) String() string {
+	switch t {
+	case MachMsgPortDescriptor:
+		return "MACH_MSG_PORT_DESCRIPTOR"
+	case MachMsgOOLDescriptor:
+		return "MACH_MSG_OOL_DESCRIPTOR"
+	case MachMsgOOLPortsDescriptor:
+		return "MACH_MSG_OOL_PORTS_DESCRIPTOR"
+	case MachMsgOOLVolatileDescriptor:
+		return "MACH_MSG_OOL_VOLATILE_DESCRIPTOR"
+	case MachMsgGuardedPortDescriptor:
+		return "MACH_MSG_GUARDED_PORT_DESCRIPTOR"
+	default:
+		return "mach_msg_descriptor_type_t(" + uitoa(uint(t)) + ")"
+	}
+}
+
+// MachMsgCopyOptions represents a mach_msg_copy_options_t, how the kernel
+// copies out-of-line memory.
+type MachMsgCopyOptions uint8
+
+// list of MachMsgCopyOptions.
+const (
+	MachMsgPhysicalCopy MachMsgCopyOptions = 0 // MACH_MSG_PHYSICAL_COPY
+	MachMsgVirtualCopy  MachMsgCopyOptions = 1 // MACH_MSG_VIRTUAL_COPY
+	MachMsgAllocate     MachMsgCopyOptions = 2 // MACH_MSG_ALLOCATE
+)
+
+// MachMsgGuardFlags represents a mach_msg_guard_flags_t.
+type MachMsgGuardFlags uint16
+
+// list of MachMsgGuardFlags.
+const (
+	MachMsgGuardFlagsNone             MachMsgGuardFlags = 0x0 // MACH_MSG_GUARD_FLAGS_NONE
+	MachMsgGuardFlagsImmovableReceive MachMsgGuardFlags = 0x1 // MACH_MSG_GUARD_FLAGS_IMMOVABLE_RECEIVE
+	MachMsgGuardFlagsUnguardedOnSend  MachMsgGuardFlags = 0x2 // MACH_MSG_GUARD_FLAGS_UNGUARDED_ON_SEND
+)
+
+// MachMsgDescriptor is a descriptor of a complex message: a
+// *MachMsgPortDesc, *MachMsgOOLDesc, *MachMsgOOLPortsDesc or
+// *MachMsgGuardedPortDesc.
+type MachMsgDescriptor interface {
+	// Type returns the type of the descriptor.
+	Type() MachMsgDescriptorType
+
+	// append appends the descriptor in the layout l to b.
+	append(b []byte, l MachMsgLayout) []byte
+}
+
+// MachMsgPortDesc represents a mach_msg_port_descriptor_t, which carries a
+// port right.
+type MachMsgPortDesc struct {
+	Name        MachPortName
+	Disposition MachMsgTypeName
+}
+
+// MachMsgOOLDesc represents a mach_msg_ool_descriptor_t, which carries
+// out-of-line memory.
+//
+// The Address is a raw address: the memory must be kept alive by the caller
+// until the message is sent.
+type MachMsgOOLDesc struct {
+	Address    uint64
+	Size       uint32
+	Deallocate bool
+	Copy       MachMsgCopyOptions
+
+	// Volatile makes the descriptor a MACH_MSG_OOL_VOLATILE_DESCRIPTOR.
+	Volatile bool
+}
+
+// MachMsgOOLPortsDesc represents a mach_msg_ool_ports_descriptor_t, which
+// carries an out-of-line array of Count port names.
+type MachMsgOOLPortsDesc struct {
+	Address     uint64
+	Count       uint32
+	Deallocate  bool
+	Copy        MachMsgCopyOptions
+	Disposition MachMsgTypeName
+}
+
+// MachMsgGuardedPortDesc represents a mach_msg_guarded_port_descriptor_t,
+// which carries a guarded receive right.
+type MachMsgGuardedPortDesc struct {
+	Context     uint64
+	Flags       MachMsgGuardFlags
+	Disposition MachMsgTypeName
+	Name        MachPortName
+}
+
+// Type implements MachMsgDescriptor.
+func (*MachMsgPortDesc) Type() MachMsgDescriptorType { return MachMsgPortDescriptor }
+
+// Type implements MachMsgDescriptor.
+func (d *MachMsgOOLDesc) Type() MachMsgDescriptorType {
+	if d.Volatile {
+		return MachMsgOOLVolatileDescriptor
+	}
+
+	return MachMsgOOLDescriptor
+}
+
+// Type implements MachMsgDescriptor.
+func (*MachMsgOOLPortsDesc) Type() MachMsgDescriptorType { return MachMsgOOLPortsDescriptor }
+
+// Type implements MachMsgDescriptor.
+func (*MachMsgGuardedPortDesc) Type() MachMsgDescriptorType { return MachMsgGuardedPortDescriptor }
+
+// descWord returns the last word of a descriptor, whose bit-fields are, from
+// the low bits, three bytes and the descriptor type.
+func descWord(b0, b1, b2 uint8, t MachMsgDescriptorType) uint32 {
+	return uint32(b0) | uint32(b1)<<8 | uint32(b2)<<16 | uint32(t)<<24
+}
+
+func boolByte(v bool) uint8 {
+	if v {
+		return 1
+	}
+
+	return 0
+}
+
+func (d *MachMsgPortDesc) append(b []byte, l MachMsgLayout) []byte {
+	b = appendUint32(b, uint32(d.Name))
+	b = appendUint32(b, 0)
+
+	return appendUint32(b, descWord(0, 0, uint8(d.Disposition), d.Type()))
+}
+
+func (d *MachMsgOOLDesc) append(b []byte, l MachMsgLayout) []byte {
+	word := descWord(boolByte(d.Deallocate), uint8(d.Copy), 0, d.Type())
+	if l.PtrSize == 4 {
+		b = appendUint32(b, uint32(d.Address))
+		b = appendUint32(b, d.Size)
+		return appendUint32(b, word)
+	}
+	b = appendUint64(b, d.Address)
+	b = appendUint32(b, word)
+
+	return appendUint32(b, d.Size)
+}
+
+func (d *MachMsgOOLPortsDesc) append(b []byte, l MachMsgLayout) []byte {
+	word := descWord(boolByte(d.Deallocate), uint8(d.Copy), uint8(d.Disposition), d.Type())
+	if l.PtrSize == 4 {
+		b = appendUint32(b, uint32(d.Address))
+		b = appendUint32(b, d.Count)
+		return appendUint32(b, word)
+	}
+	b = appendUint64(b, d.Address)
+	b = appendUint32(b, word)
+
+	return appendUint32(b, d.Count)
+}
+
+func (d *MachMsgGuardedPortDesc) append(b []byte, l MachMsgLayout) []byte {
+	word := descWord(uint8(d.Flags), uint8(d.Flags>>8), uint8(d.Disposition), d.Type())
+	if l.PtrSize == 4 {
+		b = appendUint32(b, uint32(d.Context))
+		b = appendUint32(b, uint32(d.Name))
+		return appendUint32(b, word)
+	}
+	b = appendUint64(b, d.Context)
+	b = appendUint32(b, word)
+
+	return appendUint32(b, uint32(d.Name))
+}
+
+// MachMsgTrailerType represents a mach_msg_trailer_type_t.
+type MachMsgTrailerType uint32
+
+// MachMsgTrailerFormat0 is the only trailer format, MACH_MSG_TRAILER_FORMAT_0.
+const MachMsgTrailerFormat0 MachMsgTrailerType = 0
+
+// MachRcvTrailerElements represents the trailer elements requested by a
+// receive, the argument of MACH_RCV_TRAILER_ELEMENTS.
+type MachRcvTrailerElements uint32
+
+// list of MachRcvTrailerElements.
+const (
+	MachRcvTrailerNull   MachRcvTrailerElements = 0 // MACH_RCV_TRAILER_NULL
+	MachRcvTrailerSeqno  MachRcvTrailerElements = 1 // MACH_RCV_TRAILER_SEQNO
+	MachRcvTrailerSender MachRcvTrailerElements = 2 // MACH_RCV_TRAILER_SENDER
+	MachRcvTrailerAudit  MachRcvTrailerElements = 3 // MACH_RCV_TRAILER_AUDIT
+	MachRcvTrailerCtx    MachRcvTrailerElements = 4 // MACH_RCV_TRAILER_CTX
+	MachRcvTrailerAV     MachRcvTrailerElements = 7 // MACH_RCV_TRAILER_AV
+	MachRcvTrailerLabels MachRcvTrailerElements = 8 // MACH_RCV_TRAILER_LABELS
+)
+
+// Option returns the mach_msg receive option bits requesting the elements
+// in the MachMsgTrailerFormat0 trailer, like
+// MACH_RCV_TRAILER_TYPE(MACH_MSG_TRAILER_FORMAT_0) | MACH_RCV_TRAILER_ELEMENTS(e).
+func (e MachRcvTrailerElements) Option() uint32 {
+	return uint32(MachMsgTrailerFormat0&0xf)<<28 | uint32(e&0xf)<<24
+}
+
+// MachMsgTrailer represents the largest trailer, a mach_msg_mac_trailer_t.
+// The trailer received holds the first Size bytes of it, the fields past them
+// are zero.
+type MachMsgTrailer struct {
+	Type    MachMsgTrailerType
+	Size    uint32
+	Seqno   uint32       // since MachRcvTrailerSeqno
+	Sender  [2]uint32    // security_token_t, since MachRcvTrailerSender
+	Audit   [8]uint32    // audit_token_t, since MachRcvTrailerAudit
+	Context uint64       // mach_port_context_t, since MachRcvTrailerCtx
+	Ad      int32        // mach_msg_filter_id, since MachRcvTrailerAV
+	Labels  MachPortName // msg_labels_t, since MachRcvTrailerAV
+}
+
+// MachMsg is a Mach message: its header, the descriptors of a complex
+// message, the inline data and the trailer of a received message.
+type MachMsg struct {
+	Header      MachMsgHeader
+	Descriptors []MachMsgDescriptor
+	Data        []byte
+	Trailer     *MachMsgTrailer
+}
+
+// MachMsgLayout describes the layout of the Mach messages of a GOARCH.
+//
+// Messages are packed to 4 bytes on every architecture, and their header is
+// the same everywhere. The descriptors holding an address or a
+// mach_port_context_t are 16 bytes long on 64-bit architectures, and 12 bytes
+// on 32-bit ones, as the context of the trailer is 8 or 4 bytes long.
+type MachMsgLayout struct {
+	// GOARCH is the architecture.
+	GOARCH string
+
+	// PtrSize is the size of a pointer and of a mach_port_context_t.
+	PtrSize uintptr
+}
+
+// list of the sizes of the message parts.
+const (
+	SizeofMachMsgHeader = 24 // sizeof(mach_msg_header_t)
+	SizeofMachMsgBody   = 4  // sizeof(mach_msg_body_t)
+
+	// SizeofMachMsgPortDescriptor is the size of a
+	// mach_msg_port_descriptor_t, the same on every architecture.
+	SizeofMachMsgPortDescriptor = 12
+)
+
+// machMsgLayouts are the MachMsgLayout of the supported architectures.
+var machMsgLayouts = []MachMsgLayout{
+	{GOARCH: "amd64", PtrSize: 8},
+	{GOARCH: "arm64", PtrSize: 8},
+	{GOARCH: "386", PtrSize: 4},
+	{GOARCH: "arm", PtrSize: 4},
+}
+
+// LookupMachMsgLayout returns the MachMsgLayout of goarch.
+func LookupMachMsgLayout(goarch string) (MachMsgLayout, bool) {
+	for _, l := range machMsgLayouts {
+		if l.GOARCH == goarch {
+			return l, true
+		}
+	}
+
+	return MachMsgLayout{}, false
+}
+
+// HostMachMsgLayout is like LookupMachMsgLayout but uses the runtime.GOARCH.
+func HostMachMsgLayout() (MachMsgLayout, bool) {
+	return LookupMachMsgLayout(runtime.GOARCH)
+}
+
+// DescriptorSize returns the size of a descriptor of type t, or 0 if the
+// type is unknown.
+func (l MachMsgLayout) DescriptorSize(t MachMsgDescriptorType) uintptr {
+	switch t {
+	case MachMsgPortDescriptor:
+		return SizeofMachMsgPortDescriptor
+	case MachMsgOOLDescriptor, MachMsgOOLPortsDescriptor, MachMsgOOLVolatileDescriptor, MachMsgGuardedPortDescriptor:
+		return l.PtrSize + 8
+	}
+
+	return 0
+}
+
+// TrailerSize returns the size of the trailer holding the elements e, like
+// REQUESTED_TRAILER_SIZE.
+func (l MachMsgLayout) TrailerSize(e MachRcvTrailerElements) uintptr {
+	switch e {
+	case MachRcvTrailerNull:
+		return 8
+	case MachRcvTrailerSeqno:
+		return 12
+	case MachRcvTrailerSender:
+		return 20
+	case MachRcvTrailerAudit:
+		return 52
+	case MachRcvTrailerCtx:
+		return 52 + l.PtrSize
+	}
+
+	return l.maxTrailerSize()
+}
+
+// maxTrailerSize returns the size of a mach_msg_mac_trailer_t.
+func (l MachMsgLayout) maxTrailerSize() uintptr {
+	return 52 + l.PtrSize + 8
+}
+
+// Size returns the size of the message m, without its trailer: the
+// msgh_size of the message as Marshal encodes it.
+func (l MachMsgLayout) Size(m *MachMsg) uintptr {
+	n := uintptr(SizeofMachMsgHeader)
+	if len(m.Descriptors) > 0 || m.Header.Bits.Complex() {
+		n += SizeofMachMsgBody
+		for _, d := range m.Descriptors {
+			n += l.DescriptorSize(d.Type())
+		}
+	}
+
+	return n + roundMsg(uintptr(len(m.Data)))
+}
+
+// roundMsg rounds n up to a multiple of natural_t, like round_msg.
+func roundMsg(n uintptr) uintptr {
+	return (n + 3) &^ 3
+}
+
+// Marshal returns the encoding of the message m.
+//
+// The msgh_size of the header is set to the size of the message, and the
+// complex bit is set when m has descriptors. The inline data is padded with
+// zeros to a multiple of 4 bytes. The trailer, if any, is encoded after the
+// message, in Trailer.Size bytes, as the kernel delivers it.
+func (l MachMsgLayout) Marshal(m *MachMsg) ([]byte, error) {
+	size := l.Size(m)
+	tsize := uintptr(0)
+	if m.Trailer != nil {
+		tsize = uintptr(m.Trailer.Size)
+		if tsize < 8 || tsize > l.maxTrailerSize() || tsize%4 != 0 {
+			return nil, MachSendInvalidTrailer
+		}
+	}
+
+	bits := m.Header.Bits
+	if len(m.Descriptors) > 0 {
+		bits |= MachMsghBitsComplex
+	}
+
+	b := make([]byte, 0, size+tsize)
+	b = appendUint32(b, uint32(bits))
+	b = appendUint32(b, uint32(size))
+	b = appendUint32(b, uint32(m.Header.RemotePort))
+	b = appendUint32(b, uint32(m.Header.LocalPort))
+	b = appendUint32(b, uint32(m.Header.VoucherPort))
+	b = appendUint32(b, uint32(m.Header.ID))
+	if bits.Complex() {
+		b = appendUint32(b, uint32(len(m.Descriptors)))
+		for _, d := range m.Descriptors {
+			b = d.append(b, l)
+		}
+	}
+	b = append(b, m.Data...)
+	b = b[:size]
+
+	if m.Trailer != nil {
+		b = l.appendTrailer(b, m.Trailer)[:size+tsize]
+	}
+
+	return b, nil
+}
+
+// appendTrailer appends the whole mach_msg_mac_trailer_t t to b.
+func (l MachMsgLayout) appendTrailer(b []byte, t *MachMsgTrailer) []byte {
+	b = appendUint32(b, uint32(t.Type))
+	b = appendUint32(b, t.Size)
+	b = appendUint32(b, t.Seqno)
+	for _, v := range t.Sender {
+		b = appendUint32(b, v)
+	}
+	for _, v := range t.Audit {
+		b = appendUint32(b, v)
+	}
+	if l.PtrSize == 4 {
+		b = appendUint32(b, uint32(t.Context))
+	} else {
+		b = appendUint64(b, t.Context)
+	}
+	b = appendUint32(b, uint32(t.Ad))
+
+	return appendUint32(b, uint32(t.Labels))
+}
+
+// Unmarshal decodes the message in b, followed by its trailer if b is longer
+// than the msgh_size of the message.
+//
+// The errors are the MachError of the kernel for the same defects:
+// MachRcvHeaderError for an invalid header or size, MachRcvBodyError for
+// truncated descriptors, MachRcvInvalidType for an unknown descriptor type and
+// MachRcvInvalidTrailer for an invalid trailer.
+func (l MachMsgLayout) Unmarshal(b []byte) (*MachMsg, error) {
+	if len(b) < SizeofMachMsgHeader {
+		return nil, MachRcvHeaderError
+	}
+
+	m := &MachMsg{
+		Header: MachMsgHeader{
+			Bits:        MachMsgBits(msgOrder.Uint32(b[0:])),
+			Size:        msgOrder.Uint32(b[4:]),
+			RemotePort:  MachPort(msgOrder.Uint32(b[8:])),
+			LocalPort:   MachPort(msgOrder.Uint32(b[12:])),
+			VoucherPort: MachPortName(msgOrder.Uint32(b[16:])),
+			ID:          int32(msgOrder.Uint32(b[20:])),
+		},
+	}
+	size := uintptr(m.Header.Size)
+	if size < SizeofMachMsgHeader || size%4 != 0 || size > uintptr(len(b)) {
+		return nil, MachRcvHeaderError
+	}
+
+	off := uintptr(SizeofMachMsgHeader)
+	if m.Header.Bits.Complex() {
+		if off+SizeofMachMsgBody > size {
+			return nil, MachRcvBodyError
+		}
+		count := msgOrder.Uint32(b[off:])
+		off += SizeofMachMsgBody
+		// every descriptor is at least 12 bytes long
+		if uintptr(count) > (size-off)/SizeofMachMsgPortDescriptor {
+			return nil, MachRcvBodyError
+		}
+		m.Descriptors = make([]MachMsgDescriptor, 0, count)
+		for i := uint32(0); i < count; i++ {
+			// the type is the high byte of the third word
+			if off+SizeofMachMsgPortDescriptor > size {
+				return nil, MachRcvBodyError
+			}
+			t := MachMsgDescriptorType(b[off+11])
+			n := l.DescriptorSize(t)
+			if n == 0 {
+				return nil, MachRcvInvalidType
+			}
+			if off+n > size {
+				return nil, MachRcvBodyError
+			}
+			m.Descriptors = append(m.Descriptors, l.descriptor(b[off:off+n], t))
+			off += n
+		}
+	}
+	m.Data = append([]byte(nil), b[off:size]...)
+
+	if rest := b[size:]; len(rest) > 0 {
+		t, err := l.trailer(rest)
+		if err != nil {
+			return nil, err
+		}
+		m.Trailer = t
+	}
+
+	return m, nil
+}
+
+// descriptor decodes the descriptor of type t in b, which holds exactly it.
+func (l MachMsgLayout) descriptor(b []byte, t MachMsgDescriptorType) MachMsgDescriptor {
+	if t == MachMsgPortDescriptor {
+		return &MachMsgPortDesc{
+			Name:        MachPortName(msgOrder.Uint32(b[0:])),
+			Disposition: MachMsgTypeName(uint8(msgOrder.Uint32(b[8:]) >> 16)),
+		}
+	}
+
+	var addr uint64
+	var word, n uint32
+	if l.PtrSize == 4 {
+		addr, n, word = uint64(msgOrder.Uint32(b[0:])), msgOrder.Uint32(b[4:]), msgOrder.Uint32(b[8:])
+	} else {
+		addr, word, n = msgOrder.Uint64(b[0:]), msgOrder.Uint32(b[8:]), msgOrder.Uint32(b[12:])
+	}
+
+	switch t {
+	case MachMsgOOLDescriptor, MachMsgOOLVolatileDescriptor:
+		return &MachMsgOOLDesc{
+			Address:    addr,
+			Size:       n,
+			Deallocate: uint8(word) != 0,
+			Copy:       MachMsgCopyOptions(word >> 8),
+			Volatile:   t == MachMsgOOLVolatileDescriptor,
+		}
+	case MachMsgOOLPortsDescriptor:
+		return &MachMsgOOLPortsDesc{
+			Address:     addr,
+			Count:       n,
+			Deallocate:  uint8(word) != 0,
+			Copy:        MachMsgCopyOptions(word >> 8),
+			Disposition: MachMsgTypeName(uint8(word >> 16)),
+		}
+	}
+
+	// the context and the name of a guarded port descriptor are where the
+	// address and the size of the others are
+	return &MachMsgGuardedPortDesc{
+		Context:     addr,
+		Flags:       MachMsgGuardFlags(word),
+		Disposition: MachMsgTypeName(uint8(word >> 16)),
+		Name:        MachPortName(n),
+	}
+}
+
+// trailer decodes the trailer at the start of b.
+func (l MachMsgLayout) trailer(b []byte) (*MachMsgTrailer, error) {
+	if len(b) < 8 {
+		return nil, MachRcvInvalidTrailer
+	}
+	t := &MachMsgTrailer{
+		Type: MachMsgTrailerType(msgOrder.Uint32(b[0:])),
+		Size: msgOrder.Uint32(b[4:]),
+	}
+	size := uintptr(t.Size)
+	if t.Type != MachMsgTrailerFormat0 || size < 8 || size%4 != 0 || size > l.maxTrailerSize() || size > uintptr(len(b)) {
+		return nil, MachRcvInvalidTrailer
+	}
+
+	// decode the whole trailer from a zero-padded copy
+	full := make([]byte, l.maxTrailerSize())
+	copy(full, b[:size])
+	t.Seqno = msgOrder.Uint32(full[8:])
+	for i := range t.Sender {
+		t.Sender[i] = msgOrder.Uint32(full[12+4*i:])
+	}
+	for i := range t.Audit {
+		t.Audit[i] = msgOrder.Uint32(full[20+4*i:])
+	}
+	off := uintptr(52)
+	if l.PtrSize == 4 {
+		t.Context = uint64(msgOrder.Uint32(full[off:]))
+	} else {
+		t.Context = msgOrder.Uint64(full[off:])
+	}
+	off += l.PtrSize
+	t.Ad = int32(msgOrder.Uint32(full[off:]))
+	t.Labels = MachPortName(msgOrder.Uint32(full[off+4:]))
+
+	return t, nil
+}
+
+// msgOrder is the byte order of the Mach messages, little-endian on every
+// supported architecture.
+var msgOrder = binary.LittleEndian
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	return appendUint32(appendUint32(b, uint32(v)), uint32(v>>32))
+}
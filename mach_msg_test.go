@@ -0,0 +1,316 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-darwin/sys"
+)
+
+// unhex decodes the hexadecimal bytes s, ignoring the spaces.
+func unhex(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := hex.DecodeString(strings.Join(strings.Fields(s), ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
+
+func mustLayout(t *testing.T, goarch string) sys.MachMsgLayout {
+	t.Helper()
+
+	l, ok := sys.LookupMachMsgLayout(goarch)
+	if !ok {
+		t.Fatalf("no MachMsgLayout for %s", goarch)
+	}
+
+	return l
+}
+
+// complexMsg is a complex message with one descriptor of each type.
+func complexMsg() *sys.MachMsg {
+	return &sys.MachMsg{
+		Header: sys.MachMsgHeader{
+			Bits:       sys.MakeMachMsgBits(sys.MachMsgTypeCopySend, 0, 0),
+			RemotePort: 0x1103,
+			ID:         1234,
+		},
+		Descriptors: []sys.MachMsgDescriptor{
+			&sys.MachMsgPortDesc{Name: 0x1307, Disposition: sys.MachMsgTypeMoveSend},
+			&sys.MachMsgOOLDesc{Address: 0x7f0012345678, Size: 0x1000, Deallocate: true, Copy: sys.MachMsgVirtualCopy},
+			&sys.MachMsgOOLPortsDesc{Address: 0x600000001000, Count: 2, Copy: sys.MachMsgPhysicalCopy, Disposition: sys.MachMsgTypeCopySend},
+			&sys.MachMsgGuardedPortDesc{Context: 0xdeadbeef, Flags: sys.MachMsgGuardFlagsImmovableReceive, Disposition: sys.MachMsgTypeMoveReceive, Name: 0x1403},
+		},
+		Data: []byte{1, 2, 3, 4},
+	}
+}
+
+func TestMachMsgMarshal(t *testing.T) {
+	tests := []struct {
+		name   string
+		goarch string
+		msg    *sys.MachMsg
+		golden string
+	}{
+		{
+			"simple", "arm64",
+			&sys.MachMsg{
+				Header: sys.MachMsgHeader{
+					Bits:       sys.MakeMachMsgBits(sys.MachMsgTypeCopySend, sys.MachMsgTypeMakeSendOnce, 0),
+					RemotePort: 0x1103,
+					LocalPort:  0x1207,
+					ID:         1000,
+				},
+				Data: []byte("hi!"),
+			},
+			"13150000 1c000000 03110000 07120000 00000000 e8030000" + // header
+				"68692100", // data, padded
+		},
+		{
+			"complex", "amd64", complexMsg(),
+			"13000080 5c000000 03110000 00000000 00000000 d2040000" + // header
+				"04000000" + // body
+				"07130000 00000000 00001100" + // port
+				"78563412007f0000 01010001 00100000" + // ool
+				"0010000000600000 00001302 02000000" + // ool ports
+				"efbeadde00000000 01001004 03140000" + // guarded port
+				"01020304", // data
+		},
+		{
+			"complex", "386", complexMsg(),
+			"13000080 50000000 03110000 00000000 00000000 d2040000" + // header
+				"04000000" + // body
+				"07130000 00000000 00001100" + // port
+				"78563412 00100000 01010001" + // ool
+				"00100000 02000000 00001302" + // ool ports
+				"efbeadde 03140000 01001004" + // guarded port
+				"01020304", // data
+		},
+		{
+			"trailer", "amd64",
+			&sys.MachMsg{
+				Header:  sys.MachMsgHeader{LocalPort: 0x1207, ID: 7},
+				Trailer: &sys.MachMsgTrailer{Size: 12, Seqno: 3},
+			},
+			"00000000 18000000 00000000 07120000 00000000 07000000" + // header
+				"00000000 0c000000 03000000", // seqno trailer
+		},
+	}
+	for _, tt := range tests {
+		l := mustLayout(t, tt.goarch)
+		want := unhex(t, tt.golden)
+		got, err := l.Marshal(tt.msg)
+		if err != nil {
+			t.Errorf("%s/%s: Marshal: %v", tt.name, tt.goarch, err)
+			continue
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s/%s: Marshal =\n%x\nwant\n%x", tt.name, tt.goarch, got, want)
+		}
+		if n := l.Size(tt.msg); n != uintptr(binaryUint32(want[4:])) {
+			t.Errorf("%s/%s: Size = %d, want %d", tt.name, tt.goarch, n, binaryUint32(want[4:]))
+		}
+
+		m, err := l.Unmarshal(want)
+		if err != nil {
+			t.Errorf("%s/%s: Unmarshal: %v", tt.name, tt.goarch, err)
+			continue
+		}
+		again, err := l.Marshal(m)
+		if err != nil || !bytes.Equal(again, want) {
+			t.Errorf("%s/%s: Marshal(Unmarshal) =\n%x, %v\nwant\n%x", tt.name, tt.goarch, again, err, want)
+		}
+	}
+}
+
+func binaryUint32(b []byte) uint32 {
+	return uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16 | uint32(b[3])<<24
+}
+
+func TestMachMsgUnmarshal(t *testing.T) {
+	l := mustLayout(t, "arm64")
+	want := complexMsg()
+	b, err := l.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := l.Unmarshal(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want.Header.Bits |= sys.MachMsghBitsComplex
+	want.Header.Size = 92
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unmarshal = %+v, want %+v", got, want)
+	}
+
+	bits := got.Header.Bits
+	if bits.Remote() != sys.MachMsgTypeCopySend || bits.Local() != 0 || bits.Voucher() != 0 || !bits.Complex() {
+		t.Errorf("Bits = %#x: %v %v %v %t", uint32(bits), bits.Remote(), bits.Local(), bits.Voucher(), bits.Complex())
+	}
+	for i, typ := range []sys.MachMsgDescriptorType{sys.MachMsgPortDescriptor, sys.MachMsgOOLDescriptor, sys.MachMsgOOLPortsDescriptor, sys.MachMsgGuardedPortDescriptor} {
+		if got := got.Descriptors[i].Type(); got != typ {
+			t.Errorf("Descriptors[%d].Type() = %v, want %v", i, got, typ)
+		}
+	}
+}
+
+func TestMachMsgTrailer(t *testing.T) {
+	tests := []struct {
+		goarch string
+		e      sys.MachRcvTrailerElements
+		size   uintptr
+	}{
+		{"amd64", sys.MachRcvTrailerNull, 8},
+		{"amd64", sys.MachRcvTrailerSeqno, 12},
+		{"amd64", sys.MachRcvTrailerSender, 20},
+		{"amd64", sys.MachRcvTrailerAudit, 52},
+		{"amd64", sys.MachRcvTrailerCtx, 60},
+		{"arm64", sys.MachRcvTrailerAV, 68},
+		{"arm64", sys.MachRcvTrailerLabels, 68},
+		{"386", sys.MachRcvTrailerCtx, 56},
+		{"arm", sys.MachRcvTrailerAV, 64},
+	}
+	for _, tt := range tests {
+		l := mustLayout(t, tt.goarch)
+		if got := l.TrailerSize(tt.e); got != tt.size {
+			t.Errorf("%s: TrailerSize(%d) = %d, want %d", tt.goarch, tt.e, got, tt.size)
+		}
+
+		full := &sys.MachMsgTrailer{
+			Seqno:   1,
+			Sender:  [2]uint32{501, 20},
+			Audit:   [8]uint32{501, 501, 20, 501, 20, 1234, 100001, 4321},
+			Context: 0x1122334455667788,
+			Ad:      -1,
+			Labels:  0x1503,
+		}
+		if l.PtrSize == 4 {
+			full.Context &= 0xffffffff
+		}
+		full.Size = uint32(tt.size)
+		b, err := l.Marshal(&sys.MachMsg{Trailer: full})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(b) != sys.SizeofMachMsgHeader+int(tt.size) {
+			t.Errorf("%s: len(Marshal) = %d, want %d", tt.goarch, len(b), sys.SizeofMachMsgHeader+int(tt.size))
+		}
+		m, err := l.Unmarshal(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// the fields past the size are not received
+		want := *full
+		switch {
+		case tt.size < 12:
+			want.Seqno = 0
+			fallthrough
+		case tt.size < 20:
+			want.Sender = [2]uint32{}
+			fallthrough
+		case tt.size < 52:
+			want.Audit = [8]uint32{}
+			fallthrough
+		case tt.size < 52+l.PtrSize:
+			want.Context = 0
+			fallthrough
+		case tt.size < 60+l.PtrSize:
+			want.Ad, want.Labels = 0, 0
+		}
+		if !reflect.DeepEqual(m.Trailer, &want) {
+			t.Errorf("%s: trailer of %d bytes = %+v, want %+v", tt.goarch, tt.size, m.Trailer, &want)
+		}
+	}
+
+	if got := sys.MachRcvTrailerAudit.Option(); got != 0x03000000 {
+		t.Errorf("MachRcvTrailerAudit.Option() = %#x, want 0x3000000", got)
+	}
+}
+
+func TestMachMsgErrors(t *testing.T) {
+	l := mustLayout(t, "amd64")
+	header := "00000000 18000000 00000000 00000000 00000000 00000000"
+	complexHeader := "00000080 %s 00000000 00000000 00000000 00000000"
+
+	tests := []struct {
+		name string
+		b    string
+		err  error
+	}{
+		{"short header", "00000000 18000000", sys.MachRcvHeaderError},
+		{"short size", strings.Replace(header, "18000000", "14000000", 1), sys.MachRcvHeaderError},
+		{"unaligned size", strings.Replace(header, "18000000", "19000000", 1) + "00", sys.MachRcvHeaderError},
+		{"truncated", strings.Replace(header, "18000000", "1c000000", 1), sys.MachRcvHeaderError},
+		{"no body", strings.Replace(complexHeader, "%s", "18000000", 1), sys.MachRcvBodyError},
+		{"descriptor count", strings.Replace(complexHeader, "%s", "28000000", 1) + "02000000 00000000 00000000 00000000", sys.MachRcvBodyError},
+		{"truncated descriptor", strings.Replace(complexHeader, "%s", "28000000", 1) + "01000000 00000000 00000000 00000001", sys.MachRcvBodyError},
+		{"descriptor type", strings.Replace(complexHeader, "%s", "28000000", 1) + "01000000 00000000 00000000 00000009", sys.MachRcvInvalidType},
+		{"short trailer", header + "00000000", sys.MachRcvInvalidTrailer},
+		{"trailer size", header + "00000000 06000000", sys.MachRcvInvalidTrailer},
+		{"trailer format", header + "01000000 08000000", sys.MachRcvInvalidTrailer},
+		{"truncated trailer", header + "00000000 0c000000", sys.MachRcvInvalidTrailer},
+	}
+	for _, tt := range tests {
+		if _, err := l.Unmarshal(unhex(t, tt.b)); !errors.Is(err, tt.err) {
+			t.Errorf("%s: Unmarshal error = %v, want %v", tt.name, err, tt.err)
+		}
+	}
+
+	for _, size := range []uint32{0, 6, 14, 72} {
+		_, err := l.Marshal(&sys.MachMsg{Trailer: &sys.MachMsgTrailer{Size: size}})
+		if !errors.Is(err, sys.MachSendInvalidTrailer) {
+			t.Errorf("Marshal with a trailer of %d bytes error = %v, want %v", size, err, sys.MachSendInvalidTrailer)
+		}
+	}
+}
+
+func TestMachMsgLayout(t *testing.T) {
+	tests := []struct {
+		goarch         string
+		port, ool, gpd uintptr
+	}{
+		{"amd64", 12, 16, 16},
+		{"arm64", 12, 16, 16},
+		{"386", 12, 12, 12},
+		{"arm", 12, 12, 12},
+	}
+	for _, tt := range tests {
+		l := mustLayout(t, tt.goarch)
+		if got := l.DescriptorSize(sys.MachMsgPortDescriptor); got != tt.port {
+			t.Errorf("%s: port descriptor size = %d, want %d", tt.goarch, got, tt.port)
+		}
+		for _, typ := range []sys.MachMsgDescriptorType{sys.MachMsgOOLDescriptor, sys.MachMsgOOLPortsDescriptor, sys.MachMsgOOLVolatileDescriptor} {
+			if got := l.DescriptorSize(typ); got != tt.ool {
+				t.Errorf("%s: %v size = %d, want %d", tt.goarch, typ, got, tt.ool)
+			}
+		}
+		if got := l.DescriptorSize(sys.MachMsgGuardedPortDescriptor); got != tt.gpd {
+			t.Errorf("%s: guarded port descriptor size = %d, want %d", tt.goarch, got, tt.gpd)
+		}
+		if got := l.DescriptorSize(9); got != 0 {
+			t.Errorf("%s: unknown descriptor size = %d, want 0", tt.goarch, got)
+		}
+	}
+
+	if _, ok := sys.LookupMachMsgLayout("ppc64le"); ok {
+		t.Error("LookupMachMsgLayout(ppc64le) ok")
+	}
+	if got := sys.MachMsgTypeMakeSendOnce.String(); got != "MACH_MSG_TYPE_MAKE_SEND_ONCE" {
+		t.Errorf("MachMsgTypeMakeSendOnce.String() = %q", got)
+	}
+	if got := sys.MachMsgDescriptorType(7).String(); got != "mach_msg_descriptor_type_t(7)" {
+		t.Errorf("MachMsgDescriptorType(7).String() = %q", got)
+	}
+}
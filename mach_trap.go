@@ -0,0 +1,159 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys
+
+// MachTrap represents the number of a Mach trap, as listed in the
+// mach_trap_table of osfmk/kern/syscall_sw.c of xnu.
+//
+// The number is the plain trap number, such as 26 for mach_reply_port. On
+// amd64, the trap is invoked as SyscallClassMach.Encode(num), and on arm64 as
+// the negated number in x16. The RawMachTrap functions do that encoding.
+type MachTrap int
+
+// list of MachTrap.
+const (
+	TrapMachVMAllocate            MachTrap = 10  // _kernelrpc_mach_vm_allocate_trap
+	TrapMachVMPurgable            MachTrap = 11  // _kernelrpc_mach_vm_purgable_control_trap
+	TrapMachVMDeallocate          MachTrap = 12  // _kernelrpc_mach_vm_deallocate_trap
+	TrapTaskDyldProcessInfoNotify MachTrap = 13  // task_dyld_process_info_notify_get_trap
+	TrapMachVMProtect             MachTrap = 14  // _kernelrpc_mach_vm_protect_trap
+	TrapMachVMMap                 MachTrap = 15  // _kernelrpc_mach_vm_map_trap
+	TrapMachPortAllocate          MachTrap = 16  // _kernelrpc_mach_port_allocate_trap
+	TrapMachPortDeallocate        MachTrap = 18  // _kernelrpc_mach_port_deallocate_trap
+	TrapMachPortModRefs           MachTrap = 19  // _kernelrpc_mach_port_mod_refs_trap
+	TrapMachPortMoveMember        MachTrap = 20  // _kernelrpc_mach_port_move_member_trap
+	TrapMachPortInsertRight       MachTrap = 21  // _kernelrpc_mach_port_insert_right_trap
+	TrapMachPortInsertMember      MachTrap = 22  // _kernelrpc_mach_port_insert_member_trap
+	TrapMachPortExtractMember     MachTrap = 23  // _kernelrpc_mach_port_extract_member_trap
+	TrapMachPortConstruct         MachTrap = 24  // _kernelrpc_mach_port_construct_trap
+	TrapMachPortDestruct          MachTrap = 25  // _kernelrpc_mach_port_destruct_trap
+	TrapMachReplyPort             MachTrap = 26  // mach_reply_port
+	TrapThreadSelf                MachTrap = 27  // thread_self_trap
+	TrapTaskSelf                  MachTrap = 28  // task_self_trap
+	TrapHostSelf                  MachTrap = 29  // host_self_trap
+	TrapMachMsg                   MachTrap = 31  // mach_msg_trap
+	TrapMachMsgOverwrite          MachTrap = 32  // mach_msg_overwrite_trap
+	TrapSemaphoreSignal           MachTrap = 33  // semaphore_signal_trap
+	TrapSemaphoreSignalAll        MachTrap = 34  // semaphore_signal_all_trap
+	TrapSemaphoreSignalThread     MachTrap = 35  // semaphore_signal_thread_trap
+	TrapSemaphoreWait             MachTrap = 36  // semaphore_wait_trap
+	TrapSemaphoreWaitSignal       MachTrap = 37  // semaphore_wait_signal_trap
+	TrapSemaphoreTimedwait        MachTrap = 38  // semaphore_timedwait_trap
+	TrapSemaphoreTimedwaitSignal  MachTrap = 39  // semaphore_timedwait_signal_trap
+	TrapMachPortGuard             MachTrap = 41  // _kernelrpc_mach_port_guard_trap
+	TrapMachPortUnguard           MachTrap = 42  // _kernelrpc_mach_port_unguard_trap
+	TrapMachGenerateActivityID    MachTrap = 43  // mach_generate_activity_id
+	TrapTaskNameForPid            MachTrap = 44  // task_name_for_pid
+	TrapTaskForPid                MachTrap = 45  // task_for_pid
+	TrapPidForTask                MachTrap = 46  // pid_for_task
+	TrapThreadGetSpecialReplyPort MachTrap = 50  // thread_get_special_reply_port
+	TrapSwtchPri                  MachTrap = 59  // swtch_pri
+	TrapSwtch                     MachTrap = 60  // swtch
+	TrapThreadSwitch              MachTrap = 61  // thread_switch
+	TrapClockSleep                MachTrap = 62  // clock_sleep_trap
+	TrapHostCreateMachVoucher     MachTrap = 70  // host_create_mach_voucher_trap
+	TrapMachVoucherExtractAttr    MachTrap = 72  // mach_voucher_extract_attr_recipe_trap
+	TrapMachPortType              MachTrap = 76  // _kernelrpc_mach_port_type_trap
+	TrapMachPortRequestNotify     MachTrap = 77  // _kernelrpc_mach_port_request_notification_trap
+	TrapMachTimebaseInfo          MachTrap = 89  // mach_timebase_info_trap
+	TrapMachWaitUntil             MachTrap = 90  // mach_wait_until_trap
+	TrapMkTimerCreate             MachTrap = 91  // mk_timer_create_trap
+	TrapMkTimerDestroy            MachTrap = 92  // mk_timer_destroy_trap
+	TrapMkTimerArm                MachTrap = 93  // mk_timer_arm_trap
+	TrapMkTimerCancel             MachTrap = 94  // mk_timer_cancel_trap
+	TrapMkTimerArmLeeway          MachTrap = 95  // mk_timer_arm_leeway_trap
+	TrapDebugControlPortForPid    MachTrap = 96  // debug_control_port_for_pid
+	TrapIOKitUserClient           MachTrap = 100 // iokit_user_client_trap
+)
+
+// machTrapNames is the MachTrap name table.
+var machTrapNames = [...]string{
+	TrapMachVMAllocate:            "_kernelrpc_mach_vm_allocate_trap",
+	TrapMachVMPurgable:            "_kernelrpc_mach_vm_purgable_control_trap",
+	TrapMachVMDeallocate:          "_kernelrpc_mach_vm_deallocate_trap",
+	TrapTaskDyldProcessInfoNotify: "task_dyld_process_info_notify_get_trap",
+	TrapMachVMProtect:             "_kernelrpc_mach_vm_protect_trap",
+	TrapMachVMMap:                 "_kernelrpc_mach_vm_map_trap",
+	TrapMachPortAllocate:          "_kernelrpc_mach_port_allocate_trap",
+	TrapMachPortDeallocate:        "_kernelrpc_mach_port_deallocate_trap",
+	TrapMachPortModRefs:           "_kernelrpc_mach_port_mod_refs_trap",
+	TrapMachPortMoveMember:        "_kernelrpc_mach_port_move_member_trap",
+	TrapMachPortInsertRight:       "_kernelrpc_mach_port_insert_right_trap",
+	TrapMachPortInsertMember:      "_kernelrpc_mach_port_insert_member_trap",
+	TrapMachPortExtractMember:     "_kernelrpc_mach_port_extract_member_trap",
+	TrapMachPortConstruct:         "_kernelrpc_mach_port_construct_trap",
+	TrapMachPortDestruct:          "_kernelrpc_mach_port_destruct_trap",
+	TrapMachReplyPort:             "mach_reply_port",
+	TrapThreadSelf:                "thread_self_trap",
+	TrapTaskSelf:                  "task_self_trap",
+	TrapHostSelf:                  "host_self_trap",
+	TrapMachMsg:                   "mach_msg_trap",
+	TrapMachMsgOverwrite:          "mach_msg_overwrite_trap",
+	TrapSemaphoreSignal:           "semaphore_signal_trap",
+	TrapSemaphoreSignalAll:        "semaphore_signal_all_trap",
+	TrapSemaphoreSignalThread:     "semaphore_signal_thread_trap",
+	TrapSemaphoreWait:             "semaphore_wait_trap",
+	TrapSemaphoreWaitSignal:       "semaphore_wait_signal_trap",
+	TrapSemaphoreTimedwait:        "semaphore_timedwait_trap",
+	TrapSemaphoreTimedwaitSignal:  "semaphore_timedwait_signal_trap",
+	TrapMachPortGuard:             "_kernelrpc_mach_port_guard_trap",
+	TrapMachPortUnguard:           "_kernelrpc_mach_port_unguard_trap",
+	TrapMachGenerateActivityID:    "mach_generate_activity_id",
+	TrapTaskNameForPid:            "task_name_for_pid",
+	TrapTaskForPid:                "task_for_pid",
+	TrapPidForTask:                "pid_for_task",
+	TrapThreadGetSpecialReplyPort: "thread_get_special_reply_port",
+	TrapSwtchPri:                  "swtch_pri",
+	TrapSwtch:                     "swtch",
+	TrapThreadSwitch:              "thread_switch",
+	TrapClockSleep:                "clock_sleep_trap",
+	TrapHostCreateMachVoucher:     "host_create_mach_voucher_trap",
+	TrapMachVoucherExtractAttr:    "mach_voucher_extract_attr_recipe_trap",
+	TrapMachPortType:              "_kernelrpc_mach_port_type_trap",
+	TrapMachPortRequestNotify:     "_kernelrpc_mach_port_request_notification_trap",
+	TrapMachTimebaseInfo:          "mach_timebase_info_trap",
+	TrapMachWaitUntil:             "mach_wait_until_trap",
+	TrapMkTimerCreate:             "mk_timer_create_trap",
+	TrapMkTimerDestroy:            "mk_timer_destroy_trap",
+	TrapMkTimerArm:                "mk_timer_arm_trap",
+	TrapMkTimerCancel:             "mk_timer_cancel_trap",
+	TrapMkTimerArmLeeway:          "mk_timer_arm_leeway_trap",
+	TrapDebugControlPortForPid:    "debug_control_port_for_pid",
+	TrapIOKitUserClient:           "iokit_user_client_trap",
+}
+
+// String returns the C name of the MachTrap, such as "mach_reply_port".
+func (t MachTrap) String() string {
+	if 0 <= t && int(t) < len(machTrapNames) {
+		s := machTrapNames[t]
+		if s != "" {
+			return s
+		}
+	}
+
+	return "mach_trap(" + itoa(int(t)) + ")"
+}
+
+// Trap returns the trap number of the MachTrap for the amd64 or arm64
+// goarch: SyscallClassMach.Encode(t) on amd64, and -t on arm64, the value
+// loaded into x16.
+func (t MachTrap) Trap(goarch string) uintptr {
+	if goarch == "arm64" {
+		return uintptr(-t)
+	}
+
+	return SyscallClassMach.Encode(int(t))
+}
+
+// MachTrapByName returns the MachTrap of the C name name, such as
+// "mach_msg_trap", and whether there is one.
+func MachTrapByName(name string) (MachTrap, bool) {
+	for i, s := range machTrapNames {
+		if s != "" && s == name {
+			return MachTrap(i), true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,49 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys_test
+
+import (
+	"testing"
+
+	"github.com/go-darwin/sys"
+)
+
+func TestMachTrap(t *testing.T) {
+	tests := []struct {
+		trap  sys.MachTrap
+		name  string
+		amd64 uintptr
+		arm64 uintptr
+	}{
+		{sys.TrapMachReplyPort, "mach_reply_port", 0x100001a, ^uintptr(26 - 1)},
+		{sys.TrapTaskSelf, "task_self_trap", 0x100001c, ^uintptr(28 - 1)},
+		{sys.TrapMachMsg, "mach_msg_trap", 0x100001f, ^uintptr(31 - 1)},
+		{sys.TrapThreadSwitch, "thread_switch", 0x100003d, ^uintptr(61 - 1)},
+		{sys.TrapMachPortAllocate, "_kernelrpc_mach_port_allocate_trap", 0x1000010, ^uintptr(16 - 1)},
+	}
+	for _, tt := range tests {
+		if got := tt.trap.String(); got != tt.name {
+			t.Errorf("MachTrap(%d).String() = %q, want %q", int(tt.trap), got, tt.name)
+		}
+		if got := tt.trap.Trap("amd64"); got != tt.amd64 {
+			t.Errorf("%v.Trap(amd64) = %#x, want %#x", tt.trap, got, tt.amd64)
+		}
+		if got := tt.trap.Trap("arm64"); got != tt.arm64 {
+			t.Errorf("%v.Trap(arm64) = %#x, want %#x", tt.trap, got, tt.arm64)
+		}
+		if class, num := sys.DecodeSyscall(tt.amd64); class != sys.SyscallClassMach || num != int(tt.trap) {
+			t.Errorf("DecodeSyscall(%#x) = %v, %d", tt.amd64, class, num)
+		}
+		if trap, ok := sys.MachTrapByName(tt.name); !ok || trap != tt.trap {
+			t.Errorf("MachTrapByName(%q) = %d, %t; want %d, true", tt.name, int(trap), ok, int(tt.trap))
+		}
+	}
+
+	if got := sys.MachTrap(17).String(); got != "mach_trap(17)" {
+		t.Errorf("MachTrap(17).String() = %q", got)
+	}
+	if _, ok := sys.MachTrapByName("mach_msg"); ok {
+		t.Error(`MachTrapByName("mach_msg") ok`)
+	}
+}
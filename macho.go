@@ -0,0 +1,988 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"unsafe"
+)
+
+//go:generate go run ./internal/mkmachofixture
+
+// This file holds a resolver of the symbols of the 64-bit Mach-O images, read
+// from a file or from the memory of the loaded image. It looks up the export
+// trie of LC_DYLD_EXPORTS_TRIE or LC_DYLD_INFO, and the external symbols of
+// LC_SYMTAB for the images without one, and decodes the imports and the
+// fixup chains of LC_DYLD_CHAINED_FIXUPS.
+
+// list of Mach-O constants of mach-o/loader.h, mach-o/fat.h, mach-o/nlist.h
+// and mach-o/fixup-chains.h.
+const (
+	machoMagic64  = 0xfeedfacf // MH_MAGIC_64
+	machoMagic32  = 0xfeedface // MH_MAGIC
+	machoFatMagic = 0xcafebabe // FAT_MAGIC
+
+	sizeofMachHeader64 = 32
+	sizeofSegment64    = 72
+	sizeofSection64    = 80
+	sizeofNlist64      = 16
+
+	lcReqDyld           = 0x80000000
+	lcSymtab            = 0x2
+	lcLoadDylib         = 0xc
+	lcIDDylib           = 0xd
+	lcLoadWeakDylib     = 0x18 | lcReqDyld
+	lcSegment64         = 0x19
+	lcReexportDylib     = 0x1f | lcReqDyld
+	lcLazyLoadDylib     = 0x20
+	lcDyldInfo          = 0x22
+	lcDyldInfoOnly      = 0x22 | lcReqDyld
+	lcLoadUpwardDylib   = 0x23 | lcReqDyld
+	lcDyldExportsTrie   = 0x33 | lcReqDyld
+	lcDyldChainedFixups = 0x34 | lcReqDyld
+
+	exportSymbolFlagsKindMask        = 0x03
+	exportSymbolFlagsWeakDefinition  = 0x04
+	exportSymbolFlagsReexport        = 0x08
+	exportSymbolFlagsStubAndResolver = 0x10
+
+	nStab = 0xe0
+	nPext = 0x10
+	nType = 0x0e
+	nExt  = 0x01
+	nAbs  = 0x2
+	nSect = 0xe
+
+	nWeakDef = 0x0080
+
+	dyldChainedImport         = 1
+	dyldChainedImportAddend   = 2
+	dyldChainedImportAddend64 = 3
+
+	dyldChainedPtr64       = 2
+	dyldChainedPtr64Offset = 6
+
+	dyldChainedPtrStartNone = 0xffff
+)
+
+// MachOFormatError reports a malformed Mach-O image.
+type MachOFormatError struct {
+	Off int64  // offset in the image, or in the __LINKEDIT data
+	Msg string // description of the defect
+}
+
+// Error implements error.
+func (e *MachOFormatError) Error() string {
+	return "sys: malformed Mach-O at offset " + itoa(int(e.Off)) + ": " + e.Msg
+}
+
+// ErrSymbolNotFound is returned by the lookups of a symbol which is not
+// exported.
+var ErrSymbolNotFound = errors.New("sys: symbol not found")
+
+// MachOSegment is a LC_SEGMENT_64 segment of a Mach-O image.
+type MachOSegment struct {
+	Name     string
+	Addr     uint64 // vmaddr
+	Size     uint64 // vmsize
+	Offset   uint64 // fileoff
+	FileSize uint64 // filesize
+	MaxProt  VMProt
+	InitProt VMProt
+}
+
+// MachOSymbolKind is the kind of an exported symbol.
+type MachOSymbolKind uint8
+
+// list of MachOSymbolKind.
+const (
+	MachOSymbolRegular     MachOSymbolKind = 0 // EXPORT_SYMBOL_FLAGS_KIND_REGULAR
+	MachOSymbolThreadLocal MachOSymbolKind = 1 // EXPORT_SYMBOL_FLAGS_KIND_THREAD_LOCAL
+	MachOSymbolAbsolute    MachOSymbolKind = 2 // EXPORT_SYMBOL_FLAGS_KIND_ABSOLUTE
+	MachOSymbolReexport    MachOSymbolKind = 3 // EXPORT_SYMBOL_FLAGS_REEXPORT
+)
+
+var machOSymbolKindNames = [...]string{
+	MachOSymbolRegular:     "regular",
+	MachOSymbolThreadLocal: "thread-local",
+	MachOSymbolAbsolute:    "absolute",
+	MachOSymbolReexport:    "reexport",
+}
+
+// String returns the name of the kind, such as "reexport".
+func (k MachOSymbolKind) String() string {
+	if int(k) < len(machOSymbolKindNames) {
+		return machOSymbolKindNames[k]
+	}
+
+	return "kind(" + itoa(int(k)) + ")"
+}
+
+// MachOSymbol is an exported symbol of a Mach-O image.
+type MachOSymbol struct {
+	// Name is the Mach-O name of the symbol, with the leading underscore of
+	// the C names, such as "_strlen".
+	Name string
+
+	Kind MachOSymbolKind
+
+	// Weak reports whether the symbol is a weak definition.
+	Weak bool
+
+	// Offset is the offset of the symbol from the mach_header of the image,
+	// or its value for a MachOSymbolAbsolute symbol.
+	Offset uint64
+
+	// Resolver is the offset from the mach_header of the resolver function
+	// of a stub-and-resolver symbol, which returns the address of the
+	// implementation, and 0 otherwise. Offset is then the one of the stub.
+	Resolver uint64
+
+	// Dylib is the install name of the dylib of a reexported symbol, and
+	// ImportName its name in that dylib, or "" when it is Name.
+	Dylib      string
+	ImportName string
+}
+
+// MachOImport is an import of the LC_DYLD_CHAINED_FIXUPS of an image.
+type MachOImport struct {
+	Name string // Mach-O name, such as "_malloc"
+
+	// Ordinal is the library ordinal of the import: the 1-based index of
+	// Dylib in the dylib load commands, or 0 for the image itself, -1 for the
+	// main executable, -2 for a flat lookup and -3 for a weak lookup.
+	Ordinal int
+	Dylib   string // install name, for a positive Ordinal
+
+	Weak   bool // weak import, which may be missing at runtime
+	Addend int64
+}
+
+// MachOFixup is a pointer of a fixup chain of LC_DYLD_CHAINED_FIXUPS.
+type MachOFixup struct {
+	// Offset is the offset from the mach_header of the pointer.
+	Offset uint64
+
+	// Bind reports whether the pointer binds to the symbol of the import of
+	// index Import, plus Addend, or is rebased to the offset Target from the
+	// mach_header with the top byte High8.
+	Bind   bool
+	Import int
+	Addend int64
+	Target uint64
+	High8  uint8
+}
+
+// MachOImage is a 64-bit Mach-O image, read from a file or from the memory
+// of a loaded image, for the lookup of its symbols.
+type MachOImage struct {
+	CPUType    uint32 // cputype of the mach_header
+	CPUSubtype uint32
+	FileType   uint32 // filetype, such as MH_DYLIB
+
+	// Name is the install name of a dylib, from LC_ID_DYLIB.
+	Name string
+
+	// Dylibs are the install names of the dylibs of the library ordinals,
+	// Dylibs[0] being the one of ordinal 1.
+	Dylibs []string
+
+	Segments []MachOSegment
+
+	// Base is the address of the mach_header of a loaded image, and the
+	// vmaddr of the __TEXT segment otherwise, which the offsets of the
+	// symbols are relative to.
+	Base uint64
+
+	r      io.ReaderAt
+	size   int64 // of the image in r, to the end of its mapping in a cache, or -1
+	loaded bool
+	text   uint64 // vmaddr of __TEXT
+
+	exports      [2]uint32 // offset and size of the export trie
+	symtab       [4]uint32 // symoff, nsyms, stroff, strsize
+	chainedFixup [2]uint32 // offset and size of LC_DYLD_CHAINED_FIXUPS
+}
+
+// machOCPUTypes are the cputype of the GOARCH values.
+var machOCPUTypes = map[string]uint32{
+	"amd64": 0x01000007, // CPU_TYPE_X86_64
+	"arm64": 0x0100000c, // CPU_TYPE_ARM64
+}
+
+// OpenMachOImage reads the Mach-O image of the file name, the goarch slice
+// of a universal file. The image holds the file open until Close.
+func OpenMachOImage(name, goarch string) (*MachOImage, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	m, err := NewMachOImage(f, st.Size(), goarch)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Close closes the file of an image opened by OpenMachOImage.
+func (m *MachOImage) Close() error {
+	if c, ok := m.r.(io.Closer); ok {
+		return c.Close()
+	}
+
+	return nil
+}
+
+// NewMachOImage reads the Mach-O image of the size bytes of r, the goarch
+// slice of a universal file. A thin file is read whatever its architecture.
+func NewMachOImage(r io.ReaderAt, size int64, goarch string) (*MachOImage, error) {
+	var magic [4]byte
+	if _, err := r.ReadAt(magic[:], 0); err != nil {
+		return nil, err
+	}
+	if binary.BigEndian.Uint32(magic[:]) != machoFatMagic {
+		return newMachOImage(r, size, false)
+	}
+
+	cputype, ok := machOCPUTypes[goarch]
+	if !ok {
+		return nil, errors.New("sys: no Mach-O cputype for " + goarch)
+	}
+	var hdr [8]byte
+	if _, err := r.ReadAt(hdr[:], 0); err != nil {
+		return nil, err
+	}
+	narch := binary.BigEndian.Uint32(hdr[4:])
+	if int64(narch) > (size-8)/20 {
+		return nil, &MachOFormatError{4, "invalid nfat_arch"}
+	}
+	for i := int64(0); i < int64(narch); i++ {
+		var arch [20]byte
+		if _, err := r.ReadAt(arch[:], 8+20*i); err != nil {
+			return nil, err
+		}
+		if binary.BigEndian.Uint32(arch[0:]) != cputype {
+			continue
+		}
+		off, n := int64(binary.BigEndian.Uint32(arch[8:])), int64(binary.BigEndian.Uint32(arch[12:]))
+		if off+n > size {
+			return nil, &MachOFormatError{8 + 20*i, "fat_arch out of the file"}
+		}
+		m, err := newMachOImage(io.NewSectionReader(r, off, n), n, false)
+		if err != nil {
+			return nil, err
+		}
+		if c, ok := r.(io.Closer); ok {
+			m.r = sectionCloser{m.r, c}
+		}
+		return m, nil
+	}
+
+	return nil, errors.New("sys: no " + goarch + " slice in the universal Mach-O file")
+}
+
+// sectionCloser closes the file of the slice of an universal file.
+type sectionCloser struct {
+	io.ReaderAt
+	io.Closer
+}
+
+// NewMachOImageAt reads the Mach-O image loaded at header, the address of
+// its mach_header, such as the one of an image of the dyld shared cache.
+//
+// The segments of the image are read at their vmaddr, slid like the __TEXT
+// segment to header, so the image must be mapped entirely, or the reads
+// fault.
+func NewMachOImageAt(header unsafe.Pointer) (*MachOImage, error) {
+	m, err := newMachOImage(memReader{header}, -1, true)
+	if err != nil {
+		return nil, err
+	}
+	m.Base = uint64(uintptr(header))
+
+	return m, nil
+}
+
+// memReader reads the memory at base.
+type memReader struct {
+	base unsafe.Pointer
+}
+
+// ReadAt implements io.ReaderAt.
+func (r memReader) ReadAt(p []byte, off int64) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	return copy(p, unsafe.Slice((*byte)(unsafe.Add(r.base, off)), len(p))), nil
+}
+
+func newMachOImage(r io.ReaderAt, size int64, loaded bool) (*MachOImage, error) {
+	var hdr [sizeofMachHeader64]byte
+	if _, err := r.ReadAt(hdr[:], 0); err != nil {
+		return nil, err
+	}
+	switch binary.LittleEndian.Uint32(hdr[:]) {
+	case machoMagic64:
+	case machoMagic32:
+		return nil, &MachOFormatError{0, "32-bit Mach-O images are not supported"}
+	default:
+		return nil, &MachOFormatError{0, "invalid magic"}
+	}
+
+	m := &MachOImage{
+		CPUType:    binary.LittleEndian.Uint32(hdr[4:]),
+		CPUSubtype: binary.LittleEndian.Uint32(hdr[8:]),
+		FileType:   binary.LittleEndian.Uint32(hdr[12:]),
+		r:          r,
+		size:       size,
+		loaded:     loaded,
+	}
+	ncmds := binary.LittleEndian.Uint32(hdr[16:])
+	sizeofcmds := binary.LittleEndian.Uint32(hdr[20:])
+	if size >= 0 && int64(sizeofcmds) > size-sizeofMachHeader64 {
+		return nil, &MachOFormatError{20, "sizeofcmds out of the image"}
+	}
+	cmds := make([]byte, sizeofcmds)
+	if _, err := r.ReadAt(cmds, sizeofMachHeader64); err != nil {
+		return nil, err
+	}
+
+	off := 0
+	for i := uint32(0); i < ncmds; i++ {
+		if off+8 > len(cmds) {
+			return nil, &MachOFormatError{int64(sizeofMachHeader64 + off), "load command out of sizeofcmds"}
+		}
+		cmd := binary.LittleEndian.Uint32(cmds[off:])
+		n := int(binary.LittleEndian.Uint32(cmds[off+4:]))
+		if n < 8 || off+n > len(cmds) {
+			return nil, &MachOFormatError{int64(sizeofMachHeader64 + off), "invalid cmdsize"}
+		}
+		if err := m.loadCommand(cmd, cmds[off:off+n], int64(sizeofMachHeader64+off)); err != nil {
+			return nil, err
+		}
+		off += n
+	}
+
+	for _, s := range m.Segments {
+		if s.Name == "__TEXT" {
+			m.text = s.Addr
+		}
+	}
+	m.Base = m.text
+
+	return m, nil
+}
+
+// loadCommand decodes the load command cmd of m in b, at off.
+func (m *MachOImage) loadCommand(cmd uint32, b []byte, off int64) error {
+	short := func(n int) error {
+		if len(b) < n {
+			return &MachOFormatError{off, "truncated load command"}
+		}
+		return nil
+	}
+
+	switch cmd {
+	case lcSegment64:
+		if err := short(sizeofSegment64); err != nil {
+			return err
+		}
+		m.Segments = append(m.Segments, MachOSegment{
+			Name:     cstring(b[8:24]),
+			Addr:     binary.LittleEndian.Uint64(b[24:]),
+			Size:     binary.LittleEndian.Uint64(b[32:]),
+			Offset:   binary.LittleEndian.Uint64(b[40:]),
+			FileSize: binary.LittleEndian.Uint64(b[48:]),
+			MaxProt:  VMProt(binary.LittleEndian.Uint32(b[56:])),
+			InitProt: VMProt(binary.LittleEndian.Uint32(b[60:])),
+		})
+
+	case lcIDDylib, lcLoadDylib, lcLoadWeakDylib, lcReexportDylib, lcLazyLoadDylib, lcLoadUpwardDylib:
+		if err := short(24); err != nil {
+			return err
+		}
+		name := binary.LittleEndian.Uint32(b[8:])
+		if name < 24 || int(name) >= len(b) {
+			return &MachOFormatError{off, "dylib name out of the load command"}
+		}
+		if cmd == lcIDDylib {
+			m.Name = cstring(b[name:])
+		} else {
+			m.Dylibs = append(m.Dylibs, cstring(b[name:]))
+		}
+
+	case lcSymtab:
+		if err := short(24); err != nil {
+			return err
+		}
+		for i := range m.symtab {
+			m.symtab[i] = binary.LittleEndian.Uint32(b[8+4*i:])
+		}
+
+	case lcDyldInfo, lcDyldInfoOnly:
+		if err := short(48); err != nil {
+			return err
+		}
+		// LC_DYLD_EXPORTS_TRIE takes precedence
+		if m.exports[1] == 0 {
+			m.exports = [2]uint32{binary.LittleEndian.Uint32(b[40:]), binary.LittleEndian.Uint32(b[44:])}
+		}
+
+	case lcDyldExportsTrie, lcDyldChainedFixups:
+		if err := short(16); err != nil {
+			return err
+		}
+		data := [2]uint32{binary.LittleEndian.Uint32(b[8:]), binary.LittleEndian.Uint32(b[12:])}
+		if cmd == lcDyldExportsTrie {
+			m.exports = data
+		} else {
+			m.chainedFixup = data
+		}
+	}
+
+	return nil
+}
+
+// cstring returns the NUL-terminated string of b.
+func cstring(b []byte) string {
+	for i, c := range b {
+		if c == 0 {
+			return string(b[:i])
+		}
+	}
+
+	return string(b)
+}
+
+// readLinkedit reads the n bytes at the file offset off of the __LINKEDIT
+// data, at its vmaddr in a loaded image.
+//
+// The __LINKEDIT segment is the only one looked up in a loaded image, as the
+// file offsets of the segments of the images of the dyld shared cache are
+// in different files, which may overlap.
+func (m *MachOImage) readLinkedit(off, n uint32) ([]byte, error) {
+	pos := int64(off)
+	if m.loaded {
+		pos = -1
+		for _, s := range m.Segments {
+			if s.Name == "__LINKEDIT" && s.Offset <= uint64(off) && uint64(off)+uint64(n) <= s.Offset+s.FileSize {
+				pos = int64(s.Addr - m.text + uint64(off) - s.Offset)
+				break
+			}
+		}
+		if pos < 0 {
+			return nil, &MachOFormatError{int64(off), "data out of the segments"}
+		}
+		if r, ok := m.r.(dyldCacheReader); ok && int64(n) > r.readable(pos) {
+			return nil, &MachOFormatError{int64(off), "data out of the mappings"}
+		}
+	} else if m.size >= 0 && int64(off)+int64(n) > m.size {
+		// checked before the allocation, n being of the load commands
+		return nil, &MachOFormatError{int64(off), "data out of the file"}
+	}
+
+	b := make([]byte, n)
+	if _, err := m.r.ReadAt(b, pos); err != nil {
+		if err == io.EOF {
+			return nil, &MachOFormatError{int64(off), "data out of the file"}
+		}
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// Address returns the address of the symbol s in the image: Base plus the
+// offset of s, or the value of an absolute symbol.
+func (m *MachOImage) Address(s *MachOSymbol) uint64 {
+	if s.Kind == MachOSymbolAbsolute {
+		return s.Offset
+	}
+
+	return m.Base + s.Offset
+}
+
+// Lookup returns the exported symbol of the C name name, such as "strlen",
+// of the Mach-O name with a leading underscore. It returns
+// ErrSymbolNotFound if the image does not export it.
+//
+// It looks the export trie up, and the external symbols of the symbol table
+// for the images without one.
+func (m *MachOImage) Lookup(name string) (*MachOSymbol, error) {
+	name = "_" + name
+	if m.exports[1] == 0 {
+		return m.lookupSymtab(name)
+	}
+
+	trie, err := m.readLinkedit(m.exports[0], m.exports[1])
+	if err != nil {
+		return nil, err
+	}
+	off := 0
+	rest := name
+	// every step moves forward in name, or fails, but the offsets of the
+	// children may loop: bound the steps like the bytes of the trie
+	for steps := 0; steps <= len(trie); steps++ {
+		if rest == "" {
+			return m.exportSymbol(trie, off, name)
+		}
+		next, err := exportChild(trie, off, &rest)
+		if err != nil {
+			return nil, err
+		}
+		if next < 0 {
+			return nil, ErrSymbolNotFound
+		}
+		off = next
+	}
+
+	return nil, &MachOFormatError{int64(m.exports[0]), "export trie loops"}
+}
+
+// exportChild returns the offset of the child of the node at off of trie
+// whose edge prefixes *rest, and strips the edge from *rest, or -1.
+func exportChild(trie []byte, off int, rest *string) (int, error) {
+	d := uleb{b: trie, off: off}
+	size := d.next()
+	if d.err != nil || size > uint64(len(trie)-d.off) {
+		return 0, &MachOFormatError{int64(off), "truncated export trie node"}
+	}
+	d.off += int(size)
+	if d.off >= len(trie) {
+		return 0, &MachOFormatError{int64(off), "truncated export trie node"}
+	}
+	n := int(trie[d.off])
+	d.off++
+	for i := 0; i < n; i++ {
+		edge := d.cstring()
+		child := d.next()
+		if d.err != nil {
+			return 0, &MachOFormatError{int64(off), "truncated export trie edge"}
+		}
+		if strings.HasPrefix(*rest, edge) && edge != "" {
+			if child >= uint64(len(trie)) {
+				return 0, &MachOFormatError{int64(off), "export trie child out of the trie"}
+			}
+			*rest = (*rest)[len(edge):]
+			return int(child), nil
+		}
+	}
+
+	return -1, nil
+}
+
+// exportSymbol decodes the symbol name of the terminal node at off of trie.
+func (m *MachOImage) exportSymbol(trie []byte, off int, name string) (*MachOSymbol, error) {
+	d := uleb{b: trie, off: off}
+	size := d.next()
+	if size == 0 {
+		return nil, ErrSymbolNotFound
+	}
+	if d.err != nil || size > uint64(len(trie)-d.off) {
+		return nil, &MachOFormatError{int64(off), "truncated export trie terminal"}
+	}
+	end := d.off + int(size)
+	flags := d.next()
+
+	s := &MachOSymbol{
+		Name: name,
+		Kind: MachOSymbolKind(flags & exportSymbolFlagsKindMask),
+		Weak: flags&exportSymbolFlagsWeakDefinition != 0,
+	}
+	switch {
+	case flags&exportSymbolFlagsReexport != 0:
+		s.Kind = MachOSymbolReexport
+		ordinal := d.next()
+		s.ImportName = d.cstring()
+		if ordinal == 0 || ordinal > uint64(len(m.Dylibs)) {
+			return nil, &MachOFormatError{int64(off), "invalid reexport dylib ordinal"}
+		}
+		s.Dylib = m.Dylibs[ordinal-1]
+	case flags&exportSymbolFlagsStubAndResolver != 0:
+		s.Offset = d.next()
+		s.Resolver = d.next()
+	default:
+		s.Offset = d.next()
+	}
+	if d.err != nil || d.off > end {
+		return nil, &MachOFormatError{int64(off), "truncated export trie terminal"}
+	}
+
+	return s, nil
+}
+
+// Exports returns the symbols of the export trie, in the order of the trie,
+// or the external symbols of the symbol table for the images without one.
+func (m *MachOImage) Exports() ([]*MachOSymbol, error) {
+	if m.exports[1] == 0 {
+		return m.symtabExports()
+	}
+
+	trie, err := m.readLinkedit(m.exports[0], m.exports[1])
+	if err != nil {
+		return nil, err
+	}
+	var syms []*MachOSymbol
+	visited := make(map[int]bool)
+	var walk func(off int, prefix string) error
+	walk = func(off int, prefix string) error {
+		if visited[off] {
+			return &MachOFormatError{int64(off), "export trie loops"}
+		}
+		visited[off] = true
+
+		d := uleb{b: trie, off: off}
+		if size := d.next(); size != 0 {
+			if d.err != nil || size > uint64(len(trie)-d.off) {
+				return &MachOFormatError{int64(off), "truncated export trie terminal"}
+			}
+			s, err := m.exportSymbol(trie, off, prefix)
+			if err != nil {
+				return err
+			}
+			syms = append(syms, s)
+			d.off += int(size)
+		}
+		if d.err != nil || d.off >= len(trie) {
+			return &MachOFormatError{int64(off), "truncated export trie node"}
+		}
+		n := int(trie[d.off])
+		d.off++
+		for i := 0; i < n; i++ {
+			edge := d.cstring()
+			child := d.next()
+			if d.err != nil || child >= uint64(len(trie)) {
+				return &MachOFormatError{int64(off), "invalid export trie edge"}
+			}
+			if err := walk(int(child), prefix+edge); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(0, ""); err != nil {
+		return nil, err
+	}
+
+	return syms, nil
+}
+
+// nlist calls f with the name, type, sect, desc and value of the entries of
+// the symbol table, until f returns false.
+func (m *MachOImage) nlist(f func(name string, typ, sect uint8, desc uint16, value uint64) bool) error {
+	symoff, nsyms, stroff, strsize := m.symtab[0], m.symtab[1], m.symtab[2], m.symtab[3]
+	if nsyms == 0 {
+		return nil
+	}
+	if uint64(nsyms)*sizeofNlist64 > 1<<31 {
+		return &MachOFormatError{int64(symoff), "invalid nsyms"}
+	}
+	syms, err := m.readLinkedit(symoff, nsyms*sizeofNlist64)
+	if err != nil {
+		return err
+	}
+	strs, err := m.readLinkedit(stroff, strsize)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < int(nsyms); i++ {
+		b := syms[i*sizeofNlist64:]
+		strx := binary.LittleEndian.Uint32(b)
+		if strx >= strsize {
+			return &MachOFormatError{int64(symoff) + int64(i*sizeofNlist64), "symbol name out of the string table"}
+		}
+		if !f(cstring(strs[strx:]), b[4], b[5], binary.LittleEndian.Uint16(b[6:]), binary.LittleEndian.Uint64(b[8:])) {
+			break
+		}
+	}
+
+	return nil
+}
+
+// nlistSymbol returns the exported symbol of a nlist entry, or nil.
+func (m *MachOImage) nlistSymbol(name string, typ uint8, desc uint16, value uint64) *MachOSymbol {
+	if typ&nStab != 0 || typ&nExt == 0 || typ&nPext != 0 {
+		return nil
+	}
+	s := &MachOSymbol{Name: name, Weak: desc&nWeakDef != 0}
+	switch typ & nType {
+	case nSect:
+		s.Offset = value - m.text
+	case nAbs:
+		s.Kind = MachOSymbolAbsolute
+		s.Offset = value
+	default:
+		return nil
+	}
+
+	return s
+}
+
+func (m *MachOImage) lookupSymtab(name string) (*MachOSymbol, error) {
+	var sym *MachOSymbol
+	err := m.nlist(func(n string, typ, _ uint8, desc uint16, value uint64) bool {
+		if n == name {
+			sym = m.nlistSymbol(n, typ, desc, value)
+		}
+		return sym == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if sym == nil {
+		return nil, ErrSymbolNotFound
+	}
+
+	return sym, nil
+}
+
+func (m *MachOImage) symtabExports() ([]*MachOSymbol, error) {
+	var syms []*MachOSymbol
+	err := m.nlist(func(n string, typ, _ uint8, desc uint16, value uint64) bool {
+		if s := m.nlistSymbol(n, typ, desc, value); s != nil {
+			syms = append(syms, s)
+		}
+		return true
+	})
+
+	return syms, err
+}
+
+// chainedFixups returns the LC_DYLD_CHAINED_FIXUPS data, and its
+// starts_offset, imports_offset, symbols_offset, imports_count,
+// imports_format and symbols_format.
+func (m *MachOImage) chainedFixups() ([]byte, [6]uint32, error) {
+	var hdr [6]uint32
+	if m.chainedFixup[1] == 0 {
+		return nil, hdr, errors.New("sys: no LC_DYLD_CHAINED_FIXUPS in the Mach-O image")
+	}
+	b, err := m.readLinkedit(m.chainedFixup[0], m.chainedFixup[1])
+	if err != nil {
+		return nil, hdr, err
+	}
+	if len(b) < 28 {
+		return nil, hdr, &MachOFormatError{int64(m.chainedFixup[0]), "truncated dyld_chained_fixups_header"}
+	}
+	if v := binary.LittleEndian.Uint32(b); v != 0 {
+		return nil, hdr, &MachOFormatError{int64(m.chainedFixup[0]), "unsupported fixups_version " + uitoa(uint(v))}
+	}
+	for i := range hdr {
+		hdr[i] = binary.LittleEndian.Uint32(b[4+4*i:])
+	}
+
+	return b, hdr, nil
+}
+
+// Imports returns the imports of LC_DYLD_CHAINED_FIXUPS, whose index is the
+// Import of the binding MachOFixups.
+func (m *MachOImage) Imports() ([]MachOImport, error) {
+	b, hdr, err := m.chainedFixups()
+	if err != nil {
+		return nil, err
+	}
+	importsOff, symbolsOff, count, format := hdr[1], hdr[2], hdr[3], hdr[4]
+	if hdr[5] != 0 {
+		return nil, &MachOFormatError{int64(m.chainedFixup[0]), "compressed symbols are not supported"}
+	}
+	size := map[uint32]uint32{dyldChainedImport: 4, dyldChainedImportAddend: 8, dyldChainedImportAddend64: 16}[format]
+	if size == 0 {
+		return nil, &MachOFormatError{int64(m.chainedFixup[0]), "unsupported imports_format " + uitoa(uint(format))}
+	}
+	if uint64(importsOff)+uint64(count)*uint64(size) > uint64(len(b)) || symbolsOff > uint32(len(b)) {
+		return nil, &MachOFormatError{int64(m.chainedFixup[0]), "imports out of LC_DYLD_CHAINED_FIXUPS"}
+	}
+
+	imports := make([]MachOImport, count)
+	for i := range imports {
+		e := b[importsOff+uint32(i)*size:]
+		var ordinal, nameOff uint64
+		var weak bool
+		switch format {
+		case dyldChainedImport, dyldChainedImportAddend:
+			v := binary.LittleEndian.Uint32(e)
+			ordinal, weak, nameOff = uint64(int8(v)), v>>8&1 != 0, uint64(v>>9)
+			if format == dyldChainedImportAddend {
+				imports[i].Addend = int64(int32(binary.LittleEndian.Uint32(e[4:])))
+			}
+		case dyldChainedImportAddend64:
+			v := binary.LittleEndian.Uint64(e)
+			ordinal, weak, nameOff = uint64(int16(v)), v>>16&1 != 0, v>>32
+			imports[i].Addend = int64(binary.LittleEndian.Uint64(e[8:]))
+		}
+		if uint64(symbolsOff)+nameOff >= uint64(len(b)) {
+			return nil, &MachOFormatError{int64(m.chainedFixup[0]), "import name out of LC_DYLD_CHAINED_FIXUPS"}
+		}
+		imports[i].Name = cstring(b[uint64(symbolsOff)+nameOff:])
+		imports[i].Weak = weak
+		imports[i].Ordinal = int(int64(ordinal))
+		if o := imports[i].Ordinal; o > 0 {
+			if o > len(m.Dylibs) {
+				return nil, &MachOFormatError{int64(m.chainedFixup[0]), "invalid import lib_ordinal"}
+			}
+			imports[i].Dylib = m.Dylibs[o-1]
+		}
+	}
+
+	return imports, nil
+}
+
+// Fixups returns the pointers of the fixup chains of LC_DYLD_CHAINED_FIXUPS,
+// of the DYLD_CHAINED_PTR_64 and DYLD_CHAINED_PTR_64_OFFSET formats.
+//
+// The chains of a loaded image are overwritten by dyld, so Fixups reads the
+// images read from a file only.
+func (m *MachOImage) Fixups() ([]MachOFixup, error) {
+	if m.loaded {
+		return nil, errors.New("sys: the fixup chains of a loaded Mach-O image are applied")
+	}
+	b, hdr, err := m.chainedFixups()
+	if err != nil {
+		return nil, err
+	}
+	starts := hdr[0]
+	bad := func(msg string) error {
+		return &MachOFormatError{int64(m.chainedFixup[0]) + int64(starts), msg}
+	}
+	if uint64(starts)+4 > uint64(len(b)) {
+		return nil, bad("dyld_chained_starts_in_image out of LC_DYLD_CHAINED_FIXUPS")
+	}
+	segCount := binary.LittleEndian.Uint32(b[starts:])
+	if uint64(starts)+4+4*uint64(segCount) > uint64(len(b)) {
+		return nil, bad("truncated dyld_chained_starts_in_image")
+	}
+
+	var fixups []MachOFixup
+	for i := uint32(0); i < segCount; i++ {
+		infoOff := binary.LittleEndian.Uint32(b[starts+4+4*i:])
+		if infoOff == 0 {
+			continue
+		}
+		seg := uint64(starts) + uint64(infoOff)
+		if seg+22 > uint64(len(b)) {
+			return nil, bad("dyld_chained_starts_in_segment out of LC_DYLD_CHAINED_FIXUPS")
+		}
+		s := b[seg:]
+		pageSize := uint64(binary.LittleEndian.Uint16(s[4:]))
+		format := binary.LittleEndian.Uint16(s[6:])
+		segOff := binary.LittleEndian.Uint64(s[8:])
+		pageCount := uint64(binary.LittleEndian.Uint16(s[20:]))
+		if seg+22+2*pageCount > uint64(len(b)) {
+			return nil, bad("truncated dyld_chained_starts_in_segment")
+		}
+		if format != dyldChainedPtr64 && format != dyldChainedPtr64Offset {
+			return nil, bad("unsupported pointer_format " + uitoa(uint(format)))
+		}
+		for p := uint64(0); p < pageCount; p++ {
+			start := uint64(binary.LittleEndian.Uint16(s[22+2*p:]))
+			if start == dyldChainedPtrStartNone {
+				continue
+			}
+			fixups, err = m.walkChain(fixups, segOff+p*pageSize+start, format)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return fixups, nil
+}
+
+// walkChain appends the pointers of the fixup chain at the offset off from
+// the mach_header to fixups.
+func (m *MachOImage) walkChain(fixups []MachOFixup, off uint64, format uint16) ([]MachOFixup, error) {
+	for {
+		var ptr [8]byte
+		if err := m.readVM(ptr[:], off); err != nil {
+			return nil, err
+		}
+		v := binary.LittleEndian.Uint64(ptr[:])
+		f := MachOFixup{Offset: off, Bind: v>>63 != 0}
+		if f.Bind {
+			f.Import = int(v & (1<<24 - 1))
+			f.Addend = int64(v >> 24 & 0xff)
+		} else {
+			f.Target = v & (1<<36 - 1)
+			f.High8 = uint8(v >> 36)
+			if format == dyldChainedPtr64 {
+				// the target is a vmaddr
+				f.Target -= m.text
+			}
+		}
+		fixups = append(fixups, f)
+
+		next := v >> 51 & (1<<12 - 1)
+		if next == 0 {
+			return fixups, nil
+		}
+		off += 4 * next
+	}
+}
+
+// readVM reads b at the offset off from the mach_header, in the file
+// content of the segment mapped there.
+func (m *MachOImage) readVM(b []byte, off uint64) error {
+	addr := m.text + off
+	for _, s := range m.Segments {
+		if s.Addr <= addr && addr+uint64(len(b)) <= s.Addr+s.FileSize {
+			_, err := m.r.ReadAt(b, int64(s.Offset+addr-s.Addr))
+			return err
+		}
+	}
+
+	return &MachOFormatError{int64(off), "fixup out of the segments"}
+}
+
+// uleb decodes the ULEB128 numbers and the strings of b.
+type uleb struct {
+	b   []byte
+	off int
+	err error
+}
+
+func (d *uleb) next() uint64 {
+	var v uint64
+	for shift := uint(0); ; shift += 7 {
+		if d.off >= len(d.b) || shift >= 64 {
+			d.err = io.ErrUnexpectedEOF
+			return 0
+		}
+		c := d.b[d.off]
+		d.off++
+		v |= uint64(c&0x7f) << shift
+		if c&0x80 == 0 {
+			return v
+		}
+	}
+}
+
+func (d *uleb) cstring() string {
+	if d.off > len(d.b) {
+		d.err = io.ErrUnexpectedEOF
+		return ""
+	}
+	for i, c := range d.b[d.off:] {
+		if c == 0 {
+			s := string(d.b[d.off : d.off+i])
+			d.off += i + 1
+			return s
+		}
+	}
+	d.err = io.ErrUnexpectedEOF
+
+	return ""
+}
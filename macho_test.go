@@ -0,0 +1,399 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+	"unsafe"
+
+	"github.com/go-darwin/sys"
+)
+
+func machoFixture(name string) string {
+	return filepath.Join("testdata", "macho", name)
+}
+
+func openMachO(t *testing.T, name, goarch string) *sys.MachOImage {
+	t.Helper()
+
+	m, err := sys.OpenMachOImage(machoFixture(name), goarch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { m.Close() })
+
+	return m
+}
+
+// the symbols of the fixtures, of internal/mkmachofixture.
+var fixtureSymbols = map[string]sys.MachOSymbol{
+	"fixture_add":    {Name: "_fixture_add", Offset: 0x800},
+	"fixture_sub":    {Name: "_fixture_sub", Offset: 0x810},
+	"fixture_weak":   {Name: "_fixture_weak", Offset: 0x820, Weak: true},
+	"fixture_stub":   {Name: "_fixture_stub", Offset: 0x830, Resolver: 0x838},
+	"fixture_abs":    {Name: "_fixture_abs", Kind: sys.MachOSymbolAbsolute, Offset: 0x2a},
+	"strlen":         {Name: "_strlen", Kind: sys.MachOSymbolReexport, Dylib: "/usr/lib/system/libsystem_c.dylib"},
+	"fixture_strcpy": {Name: "_fixture_strcpy", Kind: sys.MachOSymbolReexport, Dylib: "/usr/lib/system/libsystem_c.dylib", ImportName: "_strcpy"},
+}
+
+// checkLookup checks the lookups of the symbols of the fixtures in m, whose
+// export trie has the reexports and the resolvers if trie is set.
+func checkLookup(t *testing.T, m *sys.MachOImage, trie bool) {
+	t.Helper()
+
+	for name, want := range fixtureSymbols {
+		if !trie {
+			if want.Kind == sys.MachOSymbolReexport {
+				continue
+			}
+			want.Resolver = 0
+		}
+		got, err := m.Lookup(name)
+		if err != nil {
+			t.Errorf("Lookup(%q): %v", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(*got, want) {
+			t.Errorf("Lookup(%q) = %+v, want %+v", name, *got, want)
+		}
+	}
+
+	for _, name := range []string{"fixture_local", "fixture_hidden", "malloc", "fixture", "fixture_adder", "", "_fixture_add"} {
+		if _, err := m.Lookup(name); !errors.Is(err, sys.ErrSymbolNotFound) {
+			t.Errorf("Lookup(%q) = %v, want ErrSymbolNotFound", name, err)
+		}
+	}
+
+	exports, err := m.Exports()
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := len(fixtureSymbols)
+	if !trie {
+		n -= 2
+	}
+	if len(exports) != n {
+		t.Errorf("%d exports, want %d", len(exports), n)
+	}
+}
+
+func TestMachOImage(t *testing.T) {
+	tests := []struct {
+		file    string
+		goarch  string
+		cputype uint32
+		base    uint64
+		trie    bool
+		chained bool
+	}{
+		{"libfixture.dylib", "amd64", 0x01000007, 0x10000, true, true},
+		{"libfixture.dylib", "arm64", 0x0100000c, 0, true, true},
+		{"libfixture_dyldinfo.dylib", "amd64", 0x01000007, 0, true, false},
+		{"libfixture_symtab.dylib", "arm64", 0x0100000c, 0x100000000, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file+"/"+tt.goarch, func(t *testing.T) {
+			m := openMachO(t, tt.file, tt.goarch)
+			if m.CPUType != tt.cputype || m.FileType != 6 || m.Base != tt.base {
+				t.Errorf("cputype %#x, filetype %d, base %#x", m.CPUType, m.FileType, m.Base)
+			}
+			if m.Name != "/usr/lib/libfixture.dylib" {
+				t.Errorf("Name = %q", m.Name)
+			}
+			if want := []string{"/usr/lib/libSystem.B.dylib", "/usr/lib/system/libsystem_c.dylib"}; !reflect.DeepEqual(m.Dylibs, want) {
+				t.Errorf("Dylibs = %q, want %q", m.Dylibs, want)
+			}
+			var names []string
+			for _, s := range m.Segments {
+				names = append(names, s.Name)
+			}
+			if want := []string{"__TEXT", "__DATA_CONST", "__LINKEDIT"}; !reflect.DeepEqual(names, want) {
+				t.Errorf("segments %q, want %q", names, want)
+			}
+
+			checkLookup(t, m, tt.trie)
+			s, err := m.Lookup("fixture_add")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if addr := m.Address(s); addr != tt.base+0x800 {
+				t.Errorf("Address(_fixture_add) = %#x, want %#x", addr, tt.base+0x800)
+			}
+
+			if !tt.chained {
+				if _, err := m.Imports(); err == nil {
+					t.Error("Imports without LC_DYLD_CHAINED_FIXUPS succeeded")
+				}
+				return
+			}
+			imports, err := m.Imports()
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantImports := []sys.MachOImport{
+				{Name: "_malloc", Ordinal: 1, Dylib: "/usr/lib/libSystem.B.dylib"},
+				{Name: "_free", Ordinal: 1, Dylib: "/usr/lib/libSystem.B.dylib", Weak: true},
+			}
+			if !reflect.DeepEqual(imports, wantImports) {
+				t.Errorf("Imports = %+v, want %+v", imports, wantImports)
+			}
+			fixups, err := m.Fixups()
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantFixups := []sys.MachOFixup{
+				{Offset: 0x1000, Bind: true, Import: 0, Addend: 4},
+				{Offset: 0x1008, Bind: true, Import: 1},
+				{Offset: 0x1010, Target: 0x800, High8: 0x80},
+			}
+			if !reflect.DeepEqual(fixups, wantFixups) {
+				t.Errorf("Fixups = %+v, want %+v", fixups, wantFixups)
+			}
+		})
+	}
+}
+
+// fatSlice returns the slice of cputype of the universal file b.
+func fatSlice(t *testing.T, b []byte, cputype uint32) []byte {
+	t.Helper()
+
+	n := binary.BigEndian.Uint32(b[4:])
+	for i := uint32(0); i < n; i++ {
+		arch := b[8+20*i:]
+		if binary.BigEndian.Uint32(arch) == cputype {
+			off, size := binary.BigEndian.Uint32(arch[8:]), binary.BigEndian.Uint32(arch[12:])
+			return b[off : off+size]
+		}
+	}
+	t.Fatalf("no cputype %#x", cputype)
+
+	return nil
+}
+
+// load lays the segments of the image b out in memory, like dyld.
+func load(t *testing.T, b []byte) []byte {
+	t.Helper()
+
+	m, err := sys.NewMachOImage(bytes.NewReader(b), int64(len(b)), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var size uint64
+	for _, s := range m.Segments {
+		if end := s.Addr - m.Base + s.Size; end > size {
+			size = end
+		}
+	}
+	mem := make([]byte, size)
+	for _, s := range m.Segments {
+		copy(mem[s.Addr-m.Base:], b[s.Offset:s.Offset+s.FileSize])
+	}
+
+	return mem
+}
+
+func TestMachOImageAt(t *testing.T) {
+	fat, err := os.ReadFile(machoFixture("libfixture.dylib"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	symtab, err := os.ReadFile(machoFixture("libfixture_symtab.dylib"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name  string
+		image []byte
+		trie  bool
+	}{
+		{"amd64", fatSlice(t, fat, 0x01000007), true},
+		{"arm64", fatSlice(t, fat, 0x0100000c), true},
+		{"symtab", symtab, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			mem := load(t, tt.image)
+			header := unsafe.Pointer(&mem[0])
+			m, err := sys.NewMachOImageAt(header)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if m.Base != uint64(uintptr(header)) {
+				t.Errorf("Base = %#x, want %p", m.Base, header)
+			}
+			checkLookup(t, m, tt.trie)
+			s, err := m.Lookup("fixture_sub")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if addr := m.Address(s); addr != uint64(uintptr(header))+0x810 {
+				t.Errorf("Address(_fixture_sub) = %#x, want %p+0x810", addr, header)
+			}
+			if tt.trie {
+				if _, err := m.Imports(); err != nil {
+					t.Errorf("Imports: %v", err)
+				}
+				if _, err := m.Fixups(); err == nil {
+					t.Error("Fixups of a loaded image succeeded")
+				}
+			}
+			runtime.KeepAlive(mem)
+		})
+	}
+}
+
+func TestMachOImageError(t *testing.T) {
+	if _, err := sys.OpenMachOImage(machoFixture("libfixture.dylib"), "386"); err == nil {
+		t.Error("no error for a GOARCH without cputype")
+	}
+	if _, err := sys.OpenMachOImage(machoFixture("libfixture.dylib"), "ppc64"); err == nil {
+		t.Error("no error for a GOARCH without slice")
+	}
+
+	b, err := os.ReadFile(machoFixture("libfixture_dyldinfo.dylib"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	corrupt := func(f func(b []byte) []byte) error {
+		c := f(append([]byte(nil), b...))
+		m, err := sys.NewMachOImage(bytes.NewReader(c), int64(len(c)), "amd64")
+		if err != nil {
+			return err
+		}
+		if _, err := m.Exports(); err != nil {
+			return err
+		}
+		_, err = m.Lookup("fixture_add")
+		return err
+	}
+	tests := []struct {
+		name    string
+		corrupt func(b []byte) []byte
+	}{
+		{"magic", func(b []byte) []byte { b[0] = 0; return b }},
+		{"32-bit", func(b []byte) []byte { b[0] = 0xce; return b }},
+		{"sizeofcmds", func(b []byte) []byte { binary.LittleEndian.PutUint32(b[20:], 1<<20); return b }},
+		{"cmdsize", func(b []byte) []byte { binary.LittleEndian.PutUint32(b[36:], 4); return b }},
+		{"truncated", func(b []byte) []byte { return b[:0x2008] }},
+		{"trie loop", func(b []byte) []byte {
+			// the first edge of the root, "_", leads back to the root
+			b[trieOffset(t, b)+4] = 0
+			return b
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := corrupt(tt.corrupt)
+			var fe *sys.MachOFormatError
+			if !errors.As(err, &fe) {
+				t.Errorf("error %v, want a *MachOFormatError", err)
+			}
+		})
+	}
+}
+
+// TestMachOImageTerminalSize checks that a terminal size of the export trie
+// past the trie is rejected by Lookup and Exports alike.
+func TestMachOImageTerminalSize(t *testing.T) {
+	b, err := os.ReadFile(machoFixture("libfixture_dyldinfo.dylib"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the root node gets the terminal size 1<<64-20 as a ULEB128
+	size := []byte{0xec, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}
+	copy(b[trieOffset(t, b):], size)
+
+	m, err := sys.NewMachOImage(bytes.NewReader(b), int64(len(b)), "amd64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fe *sys.MachOFormatError
+	if _, err := m.Lookup("fixture_add"); !errors.As(err, &fe) {
+		t.Errorf("Lookup error %v, want a *MachOFormatError", err)
+	}
+	if _, err := m.Exports(); !errors.As(err, &fe) {
+		t.Errorf("Exports error %v, want a *MachOFormatError", err)
+	}
+}
+
+// TestMachOImageSymtabSize checks that the sizes of an LC_SYMTAB past the
+// file are rejected before the allocation of its data.
+func TestMachOImageSymtabSize(t *testing.T) {
+	b, err := os.ReadFile(machoFixture("libfixture_symtab.dylib"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	symtab := loadCommandOffset(t, b, 0x2)
+
+	tests := []struct {
+		name    string
+		corrupt func(b []byte) []byte
+	}{
+		{"strsize", func(b []byte) []byte {
+			binary.LittleEndian.PutUint32(b[symtab+20:], 0xfffffff0)
+			return b
+		}},
+		{"nsyms", func(b []byte) []byte {
+			binary.LittleEndian.PutUint32(b[symtab+12:], 0x07ffffff)
+			return b
+		}},
+		{"truncated", func(b []byte) []byte {
+			return b[:binary.LittleEndian.Uint32(b[symtab+16:])+1]
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.corrupt(append([]byte(nil), b...))
+			m, err := sys.NewMachOImage(bytes.NewReader(c), int64(len(c)), "arm64")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var before, after runtime.MemStats
+			runtime.ReadMemStats(&before)
+			_, err = m.Lookup("fixture_add")
+			runtime.ReadMemStats(&after)
+			var fe *sys.MachOFormatError
+			if !errors.As(err, &fe) {
+				t.Errorf("error %v, want a *MachOFormatError", err)
+			}
+			if n := after.TotalAlloc - before.TotalAlloc; n > 1<<20 {
+				t.Errorf("Lookup allocated %d bytes", n)
+			}
+		})
+	}
+}
+
+// trieOffset returns the export_off of the LC_DYLD_INFO_ONLY of the image b.
+func trieOffset(t *testing.T, b []byte) int {
+	t.Helper()
+
+	off := loadCommandOffset(t, b, 0x80000022)
+	return int(binary.LittleEndian.Uint32(b[off+40:]))
+}
+
+// loadCommandOffset returns the offset of the first load command cmd of
+// the image b.
+func loadCommandOffset(t *testing.T, b []byte, cmd uint32) int {
+	t.Helper()
+
+	ncmds := binary.LittleEndian.Uint32(b[16:])
+	off := uint32(32)
+	for i := uint32(0); i < ncmds; i++ {
+		if binary.LittleEndian.Uint32(b[off:]) == cmd {
+			return int(off)
+		}
+		off += binary.LittleEndian.Uint32(b[off+4:])
+	}
+	t.Fatalf("no load command %#x", cmd)
+
+	return 0
+}
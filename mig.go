@@ -0,0 +1,229 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys
+
+// This file holds the runtime support of the MIG client stubs generated by
+// internal/mkmig.
+
+// MIGClient sends the requests of the MIG client stubs.
+type MIGClient interface {
+	// Send sends the request of a simpleroutine, which has no reply.
+	Send(req *MachMsg) KernReturn
+
+	// Call sends the request of a routine, with a send-once right to a reply
+	// port as its local port, and returns the reply received on that port.
+	Call(req *MachMsg) (*MachMsg, KernReturn)
+}
+
+// NDRRecord is the NDR_record_t preceding the inline data of the MIG
+// messages, which describes the data representation of the sender:
+// little-endian integers, ASCII characters and IEEE floats.
+var NDRRecord = [8]byte{0, 0, 0, 0, 1, 0, 0, 0}
+
+// MIGReplyIDOffset is the offset of the msgh_id of the reply of a routine
+// from the msgh_id of its request.
+const MIGReplyIDOffset = 100
+
+// machNotifySendOnce is the msgh_id of the MACH_NOTIFY_SEND_ONCE notification,
+// received in place of the reply when the server dies.
+const machNotifySendOnce = 71
+
+// sizeofNDRRecord is the size of a NDR_record_t.
+const sizeofNDRRecord = 8
+
+// MIGCheckReply checks the reply rep of the routine whose request msgh_id is
+// id, which has ndesc descriptors, and returns its inline data, past the
+// NDR_record_t and the RetCode. The reply has a NDR_record_t if ndr is set.
+//
+// It returns the RetCode of an error reply, or MigServerDied, MigReplyMismatch
+// or MigTypeError when the reply is not the one of the routine, like the
+// checks of the MIG generated stubs.
+func MIGCheckReply(rep *MachMsg, id int32, ndesc int, ndr bool) ([]byte, KernReturn) {
+	switch rep.Header.ID {
+	case id + MIGReplyIDOffset:
+	case machNotifySendOnce:
+		return nil, KernReturn(MigServerDied)
+	default:
+		return nil, KernReturn(MigReplyMismatch)
+	}
+
+	if !rep.Header.Bits.Complex() {
+		// a simple reply, or the error reply of a complex one
+		if len(rep.Descriptors) != 0 || len(rep.Data) < sizeofNDRRecord+4 {
+			return nil, KernReturn(MigTypeError)
+		}
+		data := rep.Data[sizeofNDRRecord:]
+		if kr := KernReturn(msgOrder.Uint32(data)); kr != KernSuccess {
+			if len(data) != 4 {
+				return nil, KernReturn(MigTypeError)
+			}
+			return nil, kr
+		}
+		if ndesc != 0 {
+			return nil, KernReturn(MigTypeError)
+		}
+		return data[4:], KernSuccess
+	}
+
+	if ndesc == 0 || len(rep.Descriptors) != ndesc {
+		return nil, KernReturn(MigTypeError)
+	}
+	data := rep.Data
+	if ndr {
+		if len(data) < sizeofNDRRecord {
+			return nil, KernReturn(MigTypeError)
+		}
+		data = data[sizeofNDRRecord:]
+	}
+
+	return data, KernSuccess
+}
+
+// MIGEncoder appends the inline data of a MIG request.
+//
+// The Put methods append their value unpadded, as the elements of an array;
+// the arguments are padded to 4 bytes with Align.
+type MIGEncoder struct {
+	buf []byte
+}
+
+// PutNDR appends the NDRRecord.
+func (e *MIGEncoder) PutNDR() { e.buf = append(e.buf, NDRRecord[:]...) }
+
+// Put8 appends v.
+func (e *MIGEncoder) Put8(v uint8) { e.buf = append(e.buf, v) }
+
+// Put16 appends v.
+func (e *MIGEncoder) Put16(v uint16) { e.buf = append(e.buf, byte(v), byte(v>>8)) }
+
+// Put32 appends v.
+func (e *MIGEncoder) Put32(v uint32) { e.buf = appendUint32(e.buf, v) }
+
+// Put64 appends v.
+func (e *MIGEncoder) Put64(v uint64) { e.buf = appendUint64(e.buf, v) }
+
+// PutString appends s in n bytes, truncated to n-1 bytes and padded with
+// NULs, like the mig_strncpy of the MIG stubs.
+func (e *MIGEncoder) PutString(s string, n int) {
+	if len(s) > n-1 {
+		s = s[:n-1]
+	}
+	e.buf = append(e.buf, s...)
+	e.buf = append(e.buf, make([]byte, n-len(s))...)
+}
+
+// Align pads the data with zeros to a multiple of 4 bytes.
+func (e *MIGEncoder) Align() {
+	for len(e.buf)%4 != 0 {
+		e.buf = append(e.buf, 0)
+	}
+}
+
+// Bytes returns the encoded data.
+func (e *MIGEncoder) Bytes() []byte { return e.buf }
+
+// MIGDecoder reads the inline data of a MIG reply.
+//
+// The Get methods read their value unpadded, as the elements of an array;
+// the arguments are padded to 4 bytes, skipped by Align. Reading past the
+// data makes Err report MigTypeError, and the Get methods return zero.
+type MIGDecoder struct {
+	buf []byte
+	err bool
+}
+
+// NewMIGDecoder returns a MIGDecoder reading data.
+func NewMIGDecoder(data []byte) *MIGDecoder {
+	return &MIGDecoder{buf: data}
+}
+
+// next returns the next n bytes, or nil past the data.
+func (d *MIGDecoder) next(n int) []byte {
+	if d.err || len(d.buf) < n {
+		d.err = true
+		return nil
+	}
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+
+	return b
+}
+
+// Get8 reads an uint8.
+func (d *MIGDecoder) Get8() uint8 {
+	if b := d.next(1); b != nil {
+		return b[0]
+	}
+
+	return 0
+}
+
+// Get16 reads an uint16.
+func (d *MIGDecoder) Get16() uint16 {
+	if b := d.next(2); b != nil {
+		return uint16(b[0]) | uint16(b[1])<<8
+	}
+
+	return 0
+}
+
+// Get32 reads an uint32.
+func (d *MIGDecoder) Get32() uint32 {
+	if b := d.next(4); b != nil {
+		return msgOrder.Uint32(b)
+	}
+
+	return 0
+}
+
+// Get64 reads an uint64.
+func (d *MIGDecoder) Get64() uint64 {
+	if b := d.next(8); b != nil {
+		return msgOrder.Uint64(b)
+	}
+
+	return 0
+}
+
+// GetString reads a string of n bytes, up to its first NUL.
+func (d *MIGDecoder) GetString(n int) string {
+	b := d.next(n)
+	for i, c := range b {
+		if c == 0 {
+			return string(b[:i])
+		}
+	}
+
+	return string(b)
+}
+
+// Count reads the mach_msg_type_number_t count of a variable array of at
+// most max elements, and returns 0 and makes Err report MigTypeError if the
+// count exceeds max.
+func (d *MIGDecoder) Count(max int) int {
+	n := d.Get32()
+	if uint64(n) > uint64(max) {
+		d.err = true
+		return 0
+	}
+
+	return int(n)
+}
+
+// Align skips the padding to a multiple of 4 bytes of the data.
+func (d *MIGDecoder) Align() {
+	// the data starts 4-byte aligned, so the padding is that of the
+	// remaining data
+	d.next(len(d.buf) % 4)
+}
+
+// Err returns MigTypeError if the data was short, had an invalid count, or
+// was not consumed entirely, and KernSuccess otherwise.
+func (d *MIGDecoder) Err() KernReturn {
+	if d.err || len(d.buf) != 0 {
+		return KernReturn(MigTypeError)
+	}
+
+	return KernSuccess
+}
@@ -0,0 +1,122 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-darwin/sys"
+)
+
+func TestMIGEncoder(t *testing.T) {
+	var e sys.MIGEncoder
+	e.PutNDR()
+	e.Put8(0xab)
+	e.Align()
+	e.Put16(0x1234)
+	e.Put16(0x5678)
+	e.Put32(0xdeadbeef)
+	e.Put64(0x0102030405060708)
+	e.PutString("abcdef", 4)
+	e.PutString("ab", 4)
+
+	want := unhex(t, `
+		00000000 01000000
+		ab000000
+		3412 7856
+		efbeadde
+		08070605 04030201
+		61626300
+		61620000`)
+	if !bytes.Equal(e.Bytes(), want) {
+		t.Errorf("MIGEncoder\n%x\nwant\n%x", e.Bytes(), want)
+	}
+
+	d := sys.NewMIGDecoder(e.Bytes()[8:])
+	if v := d.Get8(); v != 0xab {
+		t.Errorf("Get8 = %#x", v)
+	}
+	d.Align()
+	if v := d.Get16(); v != 0x1234 {
+		t.Errorf("Get16 = %#x", v)
+	}
+	if v := d.Get16(); v != 0x5678 {
+		t.Errorf("Get16 = %#x", v)
+	}
+	if v := d.Get32(); v != 0xdeadbeef {
+		t.Errorf("Get32 = %#x", v)
+	}
+	if v := d.Get64(); v != 0x0102030405060708 {
+		t.Errorf("Get64 = %#x", v)
+	}
+	if s := d.GetString(4); s != "abc" {
+		t.Errorf("GetString = %q", s)
+	}
+	if err := d.Err(); err != sys.KernReturn(sys.MigTypeError) {
+		t.Errorf("Err with data left = %v, want MigTypeError", err)
+	}
+	if s := d.GetString(4); s != "ab" {
+		t.Errorf("GetString = %q", s)
+	}
+	if err := d.Err(); err != sys.KernSuccess {
+		t.Errorf("Err = %v", err)
+	}
+
+	// the reads past the data return zero and are sticky
+	if v := d.Get32(); v != 0 {
+		t.Errorf("Get32 past the data = %#x", v)
+	}
+	if err := d.Err(); err != sys.KernReturn(sys.MigTypeError) {
+		t.Errorf("Err past the data = %v, want MigTypeError", err)
+	}
+
+	d = sys.NewMIGDecoder(unhex(t, "05000000"))
+	if n := d.Count(4); n != 0 || d.Err() != sys.KernReturn(sys.MigTypeError) {
+		t.Errorf("Count of 5 elements out of 4 = %d, %v", n, d.Err())
+	}
+}
+
+func TestMIGCheckReply(t *testing.T) {
+	simple := func(id int32, data string) *sys.MachMsg {
+		return &sys.MachMsg{Header: sys.MachMsgHeader{ID: id}, Data: unhex(t, data)}
+	}
+	complexMsg := func(id int32, data string) *sys.MachMsg {
+		m := simple(id, data)
+		m.Header.Bits = sys.MachMsghBitsComplex
+		m.Descriptors = []sys.MachMsgDescriptor{&sys.MachMsgPortDesc{Name: 1, Disposition: sys.MachMsgTypeMoveSend}}
+		return m
+	}
+
+	tests := []struct {
+		name     string
+		rep      *sys.MachMsg
+		ndesc    int
+		ndr      bool
+		wantData string
+		wantKr   sys.KernReturn
+	}{
+		{"simple", simple(1100, "0000000001000000 00000000 2a000000"), 0, true, "2a000000", sys.KernSuccess},
+		{"RetCode", simple(1100, "0000000001000000 05000000"), 1, true, "", sys.KernReturn(5)},
+		{"long error", simple(1100, "0000000001000000 05000000 2a000000"), 0, true, "", sys.KernReturn(sys.MigTypeError)},
+		{"short", simple(1100, "0000000001000000"), 0, true, "", sys.KernReturn(sys.MigTypeError)},
+		{"simple for complex", simple(1100, "0000000001000000 00000000"), 1, true, "", sys.KernReturn(sys.MigTypeError)},
+		{"mismatch", simple(1101, "0000000001000000 00000000"), 0, true, "", sys.KernReturn(sys.MigReplyMismatch)},
+		{"server died", simple(71, ""), 0, true, "", sys.KernReturn(sys.MigServerDied)},
+		{"complex", complexMsg(1100, "0000000001000000 2a000000"), 1, true, "2a000000", sys.KernSuccess},
+		{"complex without NDR", complexMsg(1100, ""), 1, false, "", sys.KernSuccess},
+		{"descriptors", complexMsg(1100, "0000000001000000"), 2, true, "", sys.KernReturn(sys.MigTypeError)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, kr := sys.MIGCheckReply(tt.rep, 1000, tt.ndesc, tt.ndr)
+			if kr != tt.wantKr {
+				t.Fatalf("MIGCheckReply = %v, want %v", kr, tt.wantKr)
+			}
+			if want := unhex(t, tt.wantData); !bytes.Equal(data, want) {
+				t.Errorf("data %x, want %x", data, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys
+
+import (
+	"context"
+	"io/fs"
+)
+
+//go:generate go run ./internal/mkosstatus
+
+// OSStatus represents an OSStatus, the result code of the CoreFoundation,
+// CoreServices and Security framework functions.
+//
+// Most OSStatus values are small negative numbers, such as paramErr (-50) or
+// errSecItemNotFound (-25300). Some frameworks use four-character codes
+// instead, such as 'fmt?' for an unsupported audio data format.
+type OSStatus int32
+
+// Do the interface allocations only once for common OSStatus values.
+var (
+	errErrSecParam                 error = ErrSecParam
+	errErrSecAllocate              error = ErrSecAllocate
+	errErrSecUserCanceled          error = ErrSecUserCanceled
+	errErrSecNotAvailable          error = ErrSecNotAvailable
+	errErrSecAuthFailed            error = ErrSecAuthFailed
+	errErrSecDuplicateItem         error = ErrSecDuplicateItem
+	errErrSecItemNotFound          error = ErrSecItemNotFound
+	errErrSecInteractionNotAllowed error = ErrSecInteractionNotAllowed
+	errErrSecMissingEntitlement    error = ErrSecMissingEntitlement
+	errFnfErr                      error = FnfErr
+	errPermErr                     error = PermErr
+)
+
+// OSStatusErrno returns common boxed OSStatus values, to prevent
+// allocations at runtime.
+func OSStatusErrno(e OSStatus) error {
+	switch e {
+	case ErrSecSuccess:
+		return ErrSecSuccess
+	case ErrSecParam:
+		return errErrSecParam
+	case ErrSecAllocate:
+		return errErrSecAllocate
+	case ErrSecUserCanceled:
+		return errErrSecUserCanceled
+	case ErrSecNotAvailable:
+		return errErrSecNotAvailable
+	case ErrSecAuthFailed:
+		return errErrSecAuthFailed
+	case ErrSecDuplicateItem:
+		return errErrSecDuplicateItem
+	case ErrSecItemNotFound:
+		return errErrSecItemNotFound
+	case ErrSecInteractionNotAllowed:
+		return errErrSecInteractionNotAllowed
+	case ErrSecMissingEntitlement:
+		return errErrSecMissingEntitlement
+	case FnfErr:
+		return errFnfErr
+	case PermErr:
+		return errPermErr
+	default:
+		return e
+	}
+}
+
+// FourCC returns the four-character code of the OSStatus, such as "fmt?",
+// and whether the OSStatus is one, that is made of four printable ASCII
+// characters.
+func (e OSStatus) FourCC() (string, bool) {
+	b := [4]byte{byte(uint32(e) >> 24), byte(uint32(e) >> 16), byte(uint32(e) >> 8), byte(uint32(e))}
+	for _, c := range b {
+		if c < ' ' || c > '~' {
+			return "", false
+		}
+	}
+
+	return string(b[:]), true
+}
+
+// Error returns a string representation of the OSStatus.
+func (e OSStatus) Error() string {
+	if s, ok := osStatusErrors[e]; ok {
+		return s
+	}
+
+	if fcc, ok := e.FourCC(); ok {
+		return "OSStatus '" + fcc + "'"
+	}
+
+	return "OSStatus " + itoa(int(e))
+}
+
+// Is reports whether the OSStatus matches the target error.
+//
+// OSStatus values can be tested against error values from the io/fs and
+// context packages using errors.Is. For example:
+//
+//	if errors.Is(err, fs.ErrNotExist) ...
+func (e OSStatus) Is(target error) bool {
+	switch target {
+	case fs.ErrNotExist:
+		switch e {
+		case ErrSecItemNotFound, ErrSecNoSuchKeychain, ErrSecNoSuchAttr, ErrSecNoSuchClass, ErrSecNoDefaultKeychain,
+			ErrSecPolicyNotFound, FnfErr, DirNFErr, NsvErr, NsDrvErr:
+			return true
+		}
+	case fs.ErrPermission:
+		switch e {
+		case ErrSecWrPerm, ErrSecAuthFailed, ErrSecInteractionNotAllowed, ErrSecNoAccessForItem, ErrSecMissingEntitlement,
+			ErrSecRestrictedAPI, ErrSecReadOnly, ErrSecReadOnlyAttr, PermErr, WPrErr, FLckdErr, VLckdErr:
+			return true
+		}
+	case fs.ErrExist:
+		return e == ErrSecDuplicateItem || e == ErrSecDuplicateKeychain || e == DupFNErr
+	case fs.ErrInvalid:
+		return e == ErrSecParam
+	case context.Canceled:
+		return e == ErrSecUserCanceled
+	}
+
+	return false
+}
+
+// String returns the C constant name of the OSStatus, such as
+// "errSecItemNotFound", or its four-character code, such as "'fmt?'".
+func (e OSStatus) String() string {
+	if s, ok := osStatusNames[e]; ok {
+		return s
+	}
+
+	if fcc, ok := e.FourCC(); ok {
+		return "'" + fcc + "'"
+	}
+
+	return "OSStatus(" + itoa(int(e)) + ")"
+}
+
+// GoString returns the C constant name of the OSStatus, so that %#v
+// prints errSecItemNotFound rather than -25300.
+func (e OSStatus) GoString() string {
+	return e.String()
+}
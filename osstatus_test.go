@@ -0,0 +1,104 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/go-darwin/sys"
+)
+
+func TestOSStatusString(t *testing.T) {
+	tests := []struct {
+		err       sys.OSStatus
+		name, msg string
+	}{
+		{sys.ErrSecSuccess, "errSecSuccess", "no error"},
+		{sys.ErrSecItemNotFound, "errSecItemNotFound", "the specified item could not be found in the keychain"},
+		{sys.ParamErr, "errSecParam", "one or more parameters passed to a function were not valid"},
+		{sys.FnfErr, "fnfErr", "file not found"},
+		{sys.ErrSecInternalComponent, "errSecInternalComponent", "OSStatus -2070"},
+		{sys.OSStatus(-12345), "OSStatus(-12345)", "OSStatus -12345"},
+		{sys.OSStatus(0x666d743f), "'fmt?'", "OSStatus 'fmt?'"},
+	}
+	for _, tt := range tests {
+		if got := tt.err.String(); got != tt.name {
+			t.Errorf("OSStatus(%d).String() = %q, want %q", int32(tt.err), got, tt.name)
+		}
+		if got := fmt.Sprintf("%#v", tt.err); got != tt.name {
+			t.Errorf("Sprintf(%%#v, %d) = %q, want %q", int32(tt.err), got, tt.name)
+		}
+		if got := tt.err.Error(); got != tt.msg {
+			t.Errorf("OSStatus(%d).Error() = %q, want %q", int32(tt.err), got, tt.msg)
+		}
+	}
+}
+
+func TestOSStatusFourCC(t *testing.T) {
+	tests := []struct {
+		err  sys.OSStatus
+		want string
+		ok   bool
+	}{
+		{sys.OSStatus(0x666d743f), "fmt?", true},
+		{sys.OSStatus(0x21646174), "!dat", true},
+		{sys.OSStatus(0x20202020), "    ", true},
+		{sys.ErrSecItemNotFound, "", false},
+		{sys.OSStatus(0x7f414141), "", false},
+		{sys.ErrSecSuccess, "", false},
+	}
+	for _, tt := range tests {
+		got, ok := tt.err.FourCC()
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("OSStatus(%#x).FourCC() = %q, %t; want %q, %t", uint32(tt.err), got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestOSStatusIs(t *testing.T) {
+	tests := []struct {
+		err    sys.OSStatus
+		target error
+		want   bool
+	}{
+		{sys.ErrSecItemNotFound, fs.ErrNotExist, true},
+		{sys.ErrSecNoSuchKeychain, fs.ErrNotExist, true},
+		{sys.FnfErr, fs.ErrNotExist, true},
+		{sys.DirNFErr, fs.ErrNotExist, true},
+		{sys.ErrSecItemNotFound, fs.ErrPermission, false},
+		{sys.ErrSecAuthFailed, fs.ErrPermission, true},
+		{sys.ErrSecMissingEntitlement, fs.ErrPermission, true},
+		{sys.PermErr, fs.ErrPermission, true},
+		{sys.WrPermErr, fs.ErrPermission, true},
+		{sys.ErrSecDuplicateItem, fs.ErrExist, true},
+		{sys.ParamErr, fs.ErrInvalid, true},
+		{sys.UserCanceledErr, context.Canceled, true},
+		{sys.ErrSecIO, fs.ErrNotExist, false},
+	}
+	for _, tt := range tests {
+		err := fmt.Errorf("SecItemCopyMatching: %w", tt.err)
+		if got := errors.Is(err, tt.target); got != tt.want {
+			t.Errorf("errors.Is(%v, %v) = %t, want %t", tt.err, tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestOSStatusErrno(t *testing.T) {
+	for _, e := range []sys.OSStatus{sys.ErrSecSuccess, sys.ErrSecItemNotFound, sys.PermErr, sys.OSStatus(-12345)} {
+		if got := sys.OSStatusErrno(e); got != error(e) {
+			t.Errorf("OSStatusErrno(%v) = %v", e, got)
+		}
+	}
+
+	allocs := testing.AllocsPerRun(10, func() {
+		_ = sys.OSStatusErrno(sys.ErrSecItemNotFound)
+	})
+	if allocs != 0 {
+		t.Errorf("OSStatusErrno(ErrSecItemNotFound) allocates %v times, want 0", allocs)
+	}
+}
@@ -0,0 +1,435 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys
+
+import (
+	"errors"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MachPortRight represents a mach_port_right_t, the kind of a port right.
+type MachPortRight uint32
+
+// list of MachPortRight.
+const (
+	MachPortRightSend     MachPortRight = 0 // MACH_PORT_RIGHT_SEND
+	MachPortRightReceive  MachPortRight = 1 // MACH_PORT_RIGHT_RECEIVE
+	MachPortRightSendOnce MachPortRight = 2 // MACH_PORT_RIGHT_SEND_ONCE
+	MachPortRightPortSet  MachPortRight = 3 // MACH_PORT_RIGHT_PORT_SET
+	MachPortRightDeadName MachPortRight = 4 // MACH_PORT_RIGHT_DEAD_NAME
+)
+
+var machPortRightNames = [...]string{
+	MachPortRightSend:     "send",
+	MachPortRightReceive:  "receive",
+	MachPortRightSendOnce: "send-once",
+	MachPortRightPortSet:  "port set",
+	MachPortRightDeadName: "dead name",
+}
+
+// String returns the name of the right, such as "send-once".
+func (r MachPortRight) String() string {
+	if int(r) < len(machPortRightNames) {
+		return machPortRightNames[r]
+	}
+
+	return "right(" + uitoa(uint(r)) + ")"
+}
+
+// MachPortDead is the MACH_PORT_DEAD name, which stands for a dead port in
+// the messages.
+const MachPortDead MachPortName = 0xffffffff
+
+// MachPortKernel is the kernel interface managing the port rights of the
+// IPC space of a task, which the Port handles release their rights with.
+//
+// HostMachPortKernel returns the one of the current task on darwin. The
+// other implementations are fakes, such as the one of the tests of Port on
+// every platform.
+type MachPortKernel interface {
+	// Allocate creates a right of kind right, like mach_port_allocate.
+	Allocate(right MachPortRight) (MachPortName, KernReturn)
+
+	// Deallocate releases a user reference of the send, send-once or dead
+	// name right name, like mach_port_deallocate.
+	Deallocate(name MachPortName) KernReturn
+
+	// ModRefs adds delta to the user references of the right of kind right
+	// of name, like mach_port_mod_refs.
+	ModRefs(name MachPortName, right MachPortRight, delta int32) KernReturn
+}
+
+// ErrPortClosed is returned by the methods of a closed Port.
+var ErrPortClosed = errors.New("sys: use of closed Mach port")
+
+// Port is a handle owning user references of a port right of the current
+// task.
+//
+// The handle counts the user references it owns, releases them all on Close,
+// and on garbage collection if it was not closed. A right must be owned by a
+// single Port, so that a reference is not released twice, which makes the
+// kernel fail with KernInvalidRight, or deallocate the right of another name
+// reused by the kernel.
+//
+// A Port is safe for concurrent use.
+type Port struct {
+	kernel MachPortKernel
+
+	mu    sync.Mutex
+	name  MachPortName
+	right MachPortRight
+	refs  uint32
+	leak  *PortLeak // in the leak check mode
+}
+
+// NewPort returns a Port owning one user reference of the right of kind
+// right of name, released with k.
+//
+// The right is a send, receive, send-once or dead name right.
+func NewPort(k MachPortKernel, name MachPortName, right MachPortRight) (*Port, error) {
+	switch right {
+	case MachPortRightSend, MachPortRightReceive, MachPortRightSendOnce, MachPortRightDeadName:
+	default:
+		return nil, KernInvalidRight
+	}
+	if name == MachPortName(MachPortNull) || name == MachPortDead {
+		return nil, KernInvalidName
+	}
+
+	p := &Port{kernel: k, name: name, right: right, refs: 1}
+	checkPortLeaks(p)
+	runtime.SetFinalizer(p, (*Port).finalize)
+
+	return p, nil
+}
+
+// AllocatePort allocates a right of kind right with k, a receive right or a
+// dead name, and returns its Port.
+func AllocatePort(k MachPortKernel, right MachPortRight) (*Port, error) {
+	name, kr := k.Allocate(right)
+	if kr != KernSuccess {
+		return nil, kr
+	}
+
+	p, err := NewPort(k, name, right)
+	if err != nil {
+		// the kernel returned a right NewPort does not handle
+		k.ModRefs(name, right, -1)
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Name returns the name of the right, or MACH_PORT_NULL if p is closed.
+func (p *Port) Name() MachPortName {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.name
+}
+
+// Right returns the kind of the right.
+//
+// A send right becomes a dead name when the receive right of its port is
+// destroyed, which the Port learns from the failures of the kernel only.
+func (p *Port) Right() MachPortRight {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.right
+}
+
+// Refs returns the number of user references owned by p, 0 if closed.
+func (p *Port) Refs() uint32 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.refs
+}
+
+// Retain adds a user reference to a send right or a dead name.
+//
+// The receive and send-once rights have a single user reference, for which
+// Retain returns KernInvalidValue, and it returns KernUrefsOverflow at the
+// MACH_PORT_UREFS_MAX references of the task.
+func (p *Port) Retain() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.refs == 0 {
+		return ErrPortClosed
+	}
+	if p.right != MachPortRightSend && p.right != MachPortRightDeadName {
+		return KernInvalidValue
+	}
+	if kr := p.modRefs(1); kr != KernSuccess {
+		return kr
+	}
+	p.setRefs(p.refs + 1)
+
+	return nil
+}
+
+// Release releases a user reference, and closes p with the last one.
+//
+// The reference is given up even if the kernel fails, like by Close.
+func (p *Port) Release() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.refs == 0 {
+		return ErrPortClosed
+	}
+	var kr KernReturn
+	if p.right == MachPortRightReceive {
+		kr = p.kernel.ModRefs(p.name, p.right, -1)
+	} else {
+		// mach_port_deallocate releases a send right turned into a dead
+		// name too
+		kr = p.kernel.Deallocate(p.name)
+	}
+	p.setRefs(p.refs - 1)
+	if kr != KernSuccess {
+		return kr
+	}
+
+	return nil
+}
+
+// Move gives up a user reference without releasing it, when it is moved
+// to another task or port, such as by a message with a MachMsgTypeMoveSend
+// disposition of the name, and closes p with the last one.
+func (p *Port) Move() (MachPortName, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.refs == 0 {
+		return 0, ErrPortClosed
+	}
+	name := p.name
+	p.setRefs(p.refs - 1)
+
+	return name, nil
+}
+
+// Close releases the user references owned by p.
+//
+// p is closed even if the kernel fails, as the state of the right is then
+// unknown, and releasing it again could release the right of another name.
+func (p *Port) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.refs == 0 {
+		return ErrPortClosed
+	}
+
+	return p.close()
+}
+
+// close releases the user references of p, with p.mu held.
+func (p *Port) close() error {
+	kr := p.modRefs(-int32(p.refs))
+	p.setRefs(0)
+	if kr != KernSuccess {
+		return kr
+	}
+
+	return nil
+}
+
+// modRefs adds delta to the user references of the right of p, retrying
+// with a dead name if the send right of p died.
+func (p *Port) modRefs(delta int32) KernReturn {
+	kr := p.kernel.ModRefs(p.name, p.right, delta)
+	if kr == KernInvalidRight && p.right == MachPortRightSend {
+		if p.kernel.ModRefs(p.name, MachPortRightDeadName, delta) == KernSuccess {
+			p.right = MachPortRightDeadName
+			return KernSuccess
+		}
+	}
+
+	return kr
+}
+
+// setRefs sets the user references owned by p, and forgets the right and
+// the finalizer of p with the last one.
+func (p *Port) setRefs(n uint32) {
+	p.refs = n
+	if p.leak != nil {
+		updatePortLeak(p.leak, p.right, n, n == 0)
+	}
+	if n != 0 {
+		return
+	}
+
+	p.name = MachPortName(MachPortNull)
+	p.leak = nil
+	runtime.SetFinalizer(p, nil)
+}
+
+// finalize releases the user references of a Port garbage collected
+// without Close, which the leak check mode reports.
+func (p *Port) finalize() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.refs == 0 {
+		return
+	}
+	if p.leak != nil {
+		// keep reporting the leak
+		portLeaks.Lock()
+		p.leak.Finalized = true
+		portLeaks.Unlock()
+		p.leak = nil
+	}
+	p.close()
+}
+
+// PortLeak is a Port which was not closed, created in the leak check mode.
+type PortLeak struct {
+	Name  MachPortName
+	Right MachPortRight
+	Refs  uint32 // user references owned
+
+	// Finalized reports whether the Port was garbage collected, and its
+	// user references released by its finalizer.
+	Finalized bool
+
+	stack []uintptr // creation
+}
+
+// Stack returns the stack trace of the creation of the Port.
+func (l *PortLeak) Stack() string {
+	var b strings.Builder
+	frames := runtime.CallersFrames(l.stack)
+	for {
+		f, more := frames.Next()
+		b.WriteString(f.Function)
+		b.WriteString("\n\t")
+		b.WriteString(f.File)
+		b.WriteByte(':')
+		b.WriteString(itoa(f.Line))
+		b.WriteByte('\n')
+		if !more {
+			break
+		}
+	}
+
+	return b.String()
+}
+
+// PortLeakError is returned by CheckPortLeaks.
+type PortLeakError struct {
+	Leaks []PortLeak
+}
+
+// Error implements error.
+func (e *PortLeakError) Error() string {
+	var b strings.Builder
+	b.WriteString("sys: ")
+	b.WriteString(itoa(len(e.Leaks)))
+	b.WriteString(" Mach port(s) not closed")
+	for _, l := range e.Leaks {
+		b.WriteString("\n\n")
+		b.WriteString(l.Right.String())
+		b.WriteString(" right 0x")
+		b.WriteString(strconv.FormatUint(uint64(l.Name), 16))
+		b.WriteString(", ")
+		b.WriteString(uitoa(uint(l.Refs)))
+		b.WriteString(" uref(s)")
+		if l.Finalized {
+			b.WriteString(", released by the finalizer")
+		}
+		b.WriteString(", created at:\n")
+		b.WriteString(l.Stack())
+	}
+
+	return b.String()
+}
+
+// portLeaks is the state of the leak check mode.
+var portLeaks struct {
+	sync.Mutex
+	on   bool
+	open map[*PortLeak]struct{}
+}
+
+// SetPortLeakCheck enables or disables the leak check mode, in which the
+// Ports record their creation, for CheckPortLeaks to report the ones not
+// closed. It is meant for the tests:
+//
+//	sys.SetPortLeakCheck(true)
+//	defer func() {
+//		if err := sys.CheckPortLeaks(); err != nil {
+//			t.Error(err)
+//		}
+//	}()
+func SetPortLeakCheck(on bool) {
+	portLeaks.Lock()
+	defer portLeaks.Unlock()
+
+	portLeaks.on = on
+	if on && portLeaks.open == nil {
+		portLeaks.open = make(map[*PortLeak]struct{})
+	}
+}
+
+// CheckPortLeaks returns a *PortLeakError listing the Ports created in the
+// leak check mode which were not closed, whether garbage collected or not,
+// and forgets them.
+func CheckPortLeaks() error {
+	portLeaks.Lock()
+	defer portLeaks.Unlock()
+
+	if len(portLeaks.open) == 0 {
+		return nil
+	}
+	e := &PortLeakError{}
+	for l := range portLeaks.open {
+		e.Leaks = append(e.Leaks, *l)
+	}
+	portLeaks.open = make(map[*PortLeak]struct{})
+
+	return e
+}
+
+// checkPortLeaks records the creation of p in the leak check mode.
+func checkPortLeaks(p *Port) {
+	portLeaks.Lock()
+	defer portLeaks.Unlock()
+
+	if !portLeaks.on {
+		return
+	}
+	p.leak = &PortLeak{Name: p.name, Right: p.right, Refs: p.refs, stack: callers()}
+	portLeaks.open[p.leak] = struct{}{}
+}
+
+// updatePortLeak updates the right and user references of the leak l, and
+// forgets it when its Port is closed.
+func updatePortLeak(l *PortLeak, right MachPortRight, refs uint32, closed bool) {
+	portLeaks.Lock()
+	defer portLeaks.Unlock()
+
+	l.Right = right
+	l.Refs = refs
+	if closed {
+		delete(portLeaks.open, l)
+	}
+}
+
+// callers returns the stack of the caller of NewPort, called by
+// checkPortLeaks.
+func callers() []uintptr {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(4, pcs)
+
+	return pcs[:n]
+}
@@ -0,0 +1,61 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build darwin && (amd64 || arm64) && gc
+// +build darwin
+// +build amd64 arm64
+// +build gc
+
+package sys
+
+import (
+	"sync"
+	"unsafe"
+)
+
+// trapPortKernel is the MachPortKernel of the current task, which invokes
+// the _kernelrpc Mach traps on mach_task_self.
+type trapPortKernel struct {
+	task uintptr
+}
+
+var (
+	hostPortKernelOnce sync.Once
+	hostPortKernel     trapPortKernel
+)
+
+// HostMachPortKernel returns the MachPortKernel of the current task, which
+// invokes the _kernelrpc Mach traps of mach_port_allocate,
+// mach_port_deallocate and mach_port_mod_refs.
+func HostMachPortKernel() MachPortKernel {
+	hostPortKernelOnce.Do(func() {
+		// task_self_trap adds a user reference to the task port on every
+		// call, so it is called once, like mach_task_self_ of libsystem
+		hostPortKernel.task = uintptr(RawMachTrap0(uintptr(TrapTaskSelf)))
+	})
+
+	return hostPortKernel
+}
+
+// hostTask returns mach_task_self, the task port of the current task.
+func hostTask() uintptr {
+	return HostMachPortKernel().(trapPortKernel).task
+}
+
+// Allocate implements MachPortKernel.
+func (k trapPortKernel) Allocate(right MachPortRight) (MachPortName, KernReturn) {
+	var name MachPortName
+	kr := RawMachTrap3(uintptr(TrapMachPortAllocate), k.task, uintptr(right), uintptr(unsafe.Pointer(&name)))
+
+	return name, kr
+}
+
+// Deallocate implements MachPortKernel.
+func (k trapPortKernel) Deallocate(name MachPortName) KernReturn {
+	return RawMachTrap2(uintptr(TrapMachPortDeallocate), k.task, uintptr(name))
+}
+
+// ModRefs implements MachPortKernel.
+func (k trapPortKernel) ModRefs(name MachPortName, right MachPortRight, delta int32) KernReturn {
+	return RawMachTrap4(uintptr(TrapMachPortModRefs), k.task, uintptr(name), uintptr(right), uintptr(delta))
+}
@@ -0,0 +1,49 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build darwin && (amd64 || arm64) && gc
+// +build darwin
+// +build amd64 arm64
+// +build gc
+
+package sys_test
+
+import (
+	"testing"
+
+	"github.com/go-darwin/sys"
+)
+
+func TestHostMachPortKernel(t *testing.T) {
+	sys.SetPortLeakCheck(true)
+	defer sys.SetPortLeakCheck(false)
+
+	k := sys.HostMachPortKernel()
+	p, err := sys.AllocatePort(k, sys.MachPortRightReceive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := p.Name()
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// the name is not valid anymore
+	if kr := k.ModRefs(name, sys.MachPortRightReceive, -1); kr == sys.KernSuccess {
+		t.Error("mach_port_mod_refs of a closed Port succeeded")
+	}
+
+	d, err := sys.AllocatePort(k, sys.MachPortRightDeadName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Retain(); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sys.CheckPortLeaks(); err != nil {
+		t.Error(err)
+	}
+}
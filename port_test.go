@@ -0,0 +1,499 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys_test
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-darwin/sys"
+)
+
+// machPortUrefsMax is MACH_PORT_UREFS_MAX.
+const machPortUrefsMax = 0xffff
+
+// fakeEntry is an entry of the IPC space of a fakePortKernel.
+type fakeEntry struct {
+	send     uint32 // user references of the send right
+	receive  bool
+	sendOnce bool
+	dead     uint32 // user references of the dead name
+}
+
+func (e *fakeEntry) empty() bool {
+	return e.send == 0 && !e.receive && !e.sendOnce && e.dead == 0
+}
+
+// fakePortKernel is a sys.MachPortKernel checking the user references like
+// the ipc_right functions of xnu.
+type fakePortKernel struct {
+	mu    sync.Mutex
+	next  sys.MachPortName
+	space map[sys.MachPortName]*fakeEntry
+}
+
+func newFakePortKernel() *fakePortKernel {
+	return &fakePortKernel{next: 0x1103, space: make(map[sys.MachPortName]*fakeEntry)}
+}
+
+func (k *fakePortKernel) Allocate(right sys.MachPortRight) (sys.MachPortName, sys.KernReturn) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	e := &fakeEntry{}
+	switch right {
+	case sys.MachPortRightReceive:
+		e.receive = true
+	case sys.MachPortRightDeadName:
+		e.dead = 1
+	default:
+		return 0, sys.KernInvalidValue
+	}
+	name := k.next
+	k.next += 0x100
+	k.space[name] = e
+
+	return name, sys.KernSuccess
+}
+
+func (k *fakePortKernel) Deallocate(name sys.MachPortName) sys.KernReturn {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	e, ok := k.space[name]
+	if !ok {
+		return sys.KernInvalidName
+	}
+	switch {
+	case e.send > 0:
+		e.send--
+	case e.sendOnce:
+		e.sendOnce = false
+	case e.dead > 0:
+		e.dead--
+	default:
+		return sys.KernInvalidRight
+	}
+	if e.empty() {
+		delete(k.space, name)
+	}
+
+	return sys.KernSuccess
+}
+
+func (k *fakePortKernel) ModRefs(name sys.MachPortName, right sys.MachPortRight, delta int32) sys.KernReturn {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	e, ok := k.space[name]
+	if !ok {
+		return sys.KernInvalidName
+	}
+	urefs := func(n *uint32) sys.KernReturn {
+		if *n == 0 {
+			return sys.KernInvalidRight
+		}
+		v := int64(*n) + int64(delta)
+		switch {
+		case v < 0:
+			return sys.KernInvalidValue
+		case v > machPortUrefsMax:
+			return sys.KernUrefsOverflow
+		}
+		*n = uint32(v)
+		return sys.KernSuccess
+	}
+	single := func(b *bool) sys.KernReturn {
+		switch {
+		case !*b:
+			return sys.KernInvalidRight
+		case delta > 0 || delta < -1:
+			return sys.KernInvalidValue
+		}
+		*b = delta == 0
+		return sys.KernSuccess
+	}
+
+	var kr sys.KernReturn
+	switch right {
+	case sys.MachPortRightSend:
+		kr = urefs(&e.send)
+	case sys.MachPortRightDeadName:
+		kr = urefs(&e.dead)
+	case sys.MachPortRightReceive:
+		kr = single(&e.receive)
+		if kr == sys.KernSuccess && !e.receive {
+			// the send right of the name dies with the port
+			e.dead += e.send
+			e.send = 0
+		}
+	case sys.MachPortRightSendOnce:
+		kr = single(&e.sendOnce)
+	default:
+		kr = sys.KernInvalidValue
+	}
+	if e.empty() {
+		delete(k.space, name)
+	}
+
+	return kr
+}
+
+// insert adds a right of kind right to name, like mach_port_insert_right.
+func (k *fakePortKernel) insert(name sys.MachPortName, right sys.MachPortRight) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	e, ok := k.space[name]
+	if !ok {
+		e = &fakeEntry{}
+		k.space[name] = e
+	}
+	switch right {
+	case sys.MachPortRightSend:
+		e.send++
+	case sys.MachPortRightSendOnce:
+		e.sendOnce = true
+	}
+}
+
+// kill destroys the port of name in another task, which turns its send
+// right into a dead name.
+func (k *fakePortKernel) kill(name sys.MachPortName) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	e := k.space[name]
+	e.dead += e.send
+	e.send = 0
+}
+
+func (k *fakePortKernel) entry(name sys.MachPortName) (fakeEntry, bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	e, ok := k.space[name]
+	if !ok {
+		return fakeEntry{}, false
+	}
+
+	return *e, true
+}
+
+func (k *fakePortKernel) checkEmpty(t *testing.T) {
+	t.Helper()
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	for name, e := range k.space {
+		t.Errorf("right 0x%x left in the IPC space: %+v", name, *e)
+	}
+}
+
+func TestMachPortRightString(t *testing.T) {
+	tests := map[sys.MachPortRight]string{
+		sys.MachPortRightSend:     "send",
+		sys.MachPortRightSendOnce: "send-once",
+		sys.MachPortRightDeadName: "dead name",
+		sys.MachPortRight(9):      "right(9)",
+	}
+	for r, want := range tests {
+		if got := r.String(); got != want {
+			t.Errorf("MachPortRight(%d).String() = %q, want %q", uint32(r), got, want)
+		}
+	}
+}
+
+func TestPortSend(t *testing.T) {
+	k := newFakePortKernel()
+	k.insert(0x1203, sys.MachPortRightSend)
+	p, err := sys.NewPort(k, 0x1203, sys.MachPortRightSend)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := p.Retain(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if e, _ := k.entry(0x1203); p.Refs() != 4 || e.send != 4 {
+		t.Fatalf("Refs = %d, kernel urefs %d, want 4", p.Refs(), e.send)
+	}
+	if err := p.Release(); err != nil {
+		t.Fatal(err)
+	}
+	if name, err := p.Move(); err != nil || name != 0x1203 {
+		t.Fatalf("Move = 0x%x, %v", name, err)
+	}
+	if p.Refs() != 2 {
+		t.Fatalf("Refs = %d, want 2", p.Refs())
+	}
+	// the moved reference is not the one of the Port anymore
+	if err := k.Deallocate(0x1203); err != sys.KernSuccess {
+		t.Fatal(err)
+	}
+
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if p.Name() != 0 || p.Refs() != 0 {
+		t.Errorf("closed Port has name 0x%x and %d urefs", p.Name(), p.Refs())
+	}
+	k.checkEmpty(t)
+
+	for _, f := range []func() error{p.Close, p.Retain, p.Release} {
+		if err := f(); !errors.Is(err, sys.ErrPortClosed) {
+			t.Errorf("closed Port: %v, want ErrPortClosed", err)
+		}
+	}
+}
+
+func TestPortDeadName(t *testing.T) {
+	k := newFakePortKernel()
+	k.insert(0x1303, sys.MachPortRightSend)
+	p, err := sys.NewPort(k, 0x1303, sys.MachPortRightSend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Retain(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the send right becomes a dead name, which Close releases
+	k.kill(0x1303)
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	k.checkEmpty(t)
+
+	k.insert(0x1403, sys.MachPortRightSend)
+	p, err = sys.NewPort(k, 0x1403, sys.MachPortRightSend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k.kill(0x1403)
+	if err := p.Retain(); err != nil {
+		t.Fatal(err)
+	}
+	if p.Right() != sys.MachPortRightDeadName {
+		t.Errorf("Right = %v, want dead name", p.Right())
+	}
+	for p.Refs() > 0 {
+		if err := p.Release(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	k.checkEmpty(t)
+}
+
+func TestPortReceive(t *testing.T) {
+	k := newFakePortKernel()
+	p, err := sys.AllocatePort(k, sys.MachPortRightReceive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Retain(); err != sys.KernInvalidValue {
+		t.Errorf("Retain of a receive right = %v, want KernInvalidValue", err)
+	}
+	if err := p.Release(); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Close(); err != sys.ErrPortClosed {
+		t.Errorf("Close after the last Release = %v, want ErrPortClosed", err)
+	}
+	k.checkEmpty(t)
+
+	if _, err := sys.AllocatePort(k, sys.MachPortRightSend); err != sys.KernInvalidValue {
+		t.Errorf("AllocatePort of a send right = %v, want KernInvalidValue", err)
+	}
+}
+
+func TestPortSendOnce(t *testing.T) {
+	k := newFakePortKernel()
+	k.insert(0x1503, sys.MachPortRightSendOnce)
+	p, err := sys.NewPort(k, 0x1503, sys.MachPortRightSendOnce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Retain(); err != sys.KernInvalidValue {
+		t.Errorf("Retain of a send-once right = %v, want KernInvalidValue", err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	k.checkEmpty(t)
+}
+
+func TestPortKernelError(t *testing.T) {
+	k := newFakePortKernel()
+	k.insert(0x1603, sys.MachPortRightSend)
+	p, err := sys.NewPort(k, 0x1603, sys.MachPortRightSend)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the other references of the task overflow the urefs
+	for i := 0; i < machPortUrefsMax-1; i++ {
+		k.insert(0x1603, sys.MachPortRightSend)
+	}
+	if err := p.Retain(); err != sys.KernUrefsOverflow {
+		t.Errorf("Retain at MACH_PORT_UREFS_MAX = %v, want KernUrefsOverflow", err)
+	}
+	if p.Refs() != 1 {
+		t.Errorf("Refs = %d after a failed Retain, want 1", p.Refs())
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// a right released behind the back of the Port
+	k.insert(0x1703, sys.MachPortRightSend)
+	p, err = sys.NewPort(k, 0x1703, sys.MachPortRightSend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k.Deallocate(0x1703)
+	if err := p.Close(); err != sys.KernInvalidName {
+		t.Errorf("Close of a released right = %v, want KernInvalidName", err)
+	}
+	if err := p.Close(); err != sys.ErrPortClosed {
+		t.Errorf("second Close = %v, want ErrPortClosed", err)
+	}
+
+	for _, tt := range []struct {
+		name  sys.MachPortName
+		right sys.MachPortRight
+		want  error
+	}{
+		{0, sys.MachPortRightSend, sys.KernInvalidName},
+		{sys.MachPortDead, sys.MachPortRightSend, sys.KernInvalidName},
+		{0x1803, sys.MachPortRightPortSet, sys.KernInvalidRight},
+	} {
+		if _, err := sys.NewPort(k, tt.name, tt.right); err != tt.want {
+			t.Errorf("NewPort(0x%x, %v) = %v, want %v", tt.name, tt.right, err, tt.want)
+		}
+	}
+}
+
+// waitEmpty waits for the finalizers to release the rights of k.
+func waitEmpty(t *testing.T, k *fakePortKernel) {
+	t.Helper()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		runtime.GC()
+		k.mu.Lock()
+		n := len(k.space)
+		k.mu.Unlock()
+		if n == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d rights not released by the finalizers", n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPortFinalizer(t *testing.T) {
+	k := newFakePortKernel()
+	for i := 0; i < 8; i++ {
+		if _, err := sys.AllocatePort(k, sys.MachPortRightReceive); err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitEmpty(t, k)
+}
+
+func newLeakedPort(t *testing.T, k *fakePortKernel) sys.MachPortName {
+	t.Helper()
+
+	p, err := sys.AllocatePort(k, sys.MachPortRightDeadName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return p.Name()
+}
+
+func TestPortLeakCheck(t *testing.T) {
+	sys.SetPortLeakCheck(true)
+	defer sys.SetPortLeakCheck(false)
+
+	k := newFakePortKernel()
+	closed, err := sys.AllocatePort(k, sys.MachPortRightReceive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := closed.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := sys.CheckPortLeaks(); err != nil {
+		t.Fatalf("CheckPortLeaks with every Port closed: %v", err)
+	}
+
+	open, err := sys.AllocatePort(k, sys.MachPortRightReceive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := newLeakedPort(t, k)
+	waitFinalized := func() {
+		deadline := time.Now().Add(10 * time.Second)
+		for {
+			runtime.GC()
+			if _, ok := k.entry(name); !ok {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("leaked Port not finalized")
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	waitFinalized()
+
+	err = sys.CheckPortLeaks()
+	var leakErr *sys.PortLeakError
+	if !errors.As(err, &leakErr) {
+		t.Fatalf("CheckPortLeaks = %v, want a *PortLeakError", err)
+	}
+	if len(leakErr.Leaks) != 2 {
+		t.Fatalf("%d leaks, want 2:\n%v", len(leakErr.Leaks), err)
+	}
+	for _, l := range leakErr.Leaks {
+		switch l.Name {
+		case open.Name():
+			if l.Finalized || l.Right != sys.MachPortRightReceive || l.Refs != 1 {
+				t.Errorf("open Port leak %+v", l)
+			}
+		case name:
+			if !l.Finalized || l.Right != sys.MachPortRightDeadName {
+				t.Errorf("finalized Port leak %+v", l)
+			}
+			if stack := l.Stack(); !strings.Contains(stack, "sys_test.newLeakedPort") {
+				t.Errorf("leak stack does not have the creation:\n%s", stack)
+			}
+		default:
+			t.Errorf("unexpected leak %+v", l)
+		}
+	}
+	if s := err.Error(); !strings.Contains(s, "2 Mach port(s) not closed") || !strings.Contains(s, "released by the finalizer") {
+		t.Errorf("PortLeakError.Error() = %q", s)
+	}
+
+	// the leaks are reported once
+	if err := sys.CheckPortLeaks(); err != nil {
+		t.Errorf("second CheckPortLeaks = %v", err)
+	}
+	if err := open.Close(); err != nil {
+		t.Fatal(err)
+	}
+	k.checkEmpty(t)
+}
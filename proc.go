@@ -0,0 +1,270 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// ProcBackend inspects the processes of the host, like the libproc
+// functions of darwin.
+//
+// HostProcBackend returns the one of the host: the libproc one on darwin,
+// and the one of /proc on linux, whose values are the closest linux
+// equivalents of the darwin ones, so that the process inspection code is
+// portable and testable on linux. The values without an equivalent are
+// zero.
+type ProcBackend interface {
+	// ListPids returns the IDs of the processes, like proc_listallpids.
+	ListPids() ([]int, error)
+
+	// Path returns the path of the executable of the process pid, like
+	// proc_pidpath.
+	Path(pid int) (string, error)
+
+	// BSDInfo returns the BSD information of the process pid, like
+	// proc_pidinfo with PROC_PIDTBSDINFO.
+	BSDInfo(pid int) (ProcBSDInfo, error)
+
+	// TaskInfo returns the task information of the process pid, like
+	// proc_pidinfo with PROC_PIDTASKINFO.
+	TaskInfo(pid int) (ProcTaskInfo, error)
+
+	// FDs returns the file descriptors of the process pid, like
+	// proc_pidinfo with PROC_PIDLISTFDS.
+	FDs(pid int) ([]ProcFDInfo, error)
+
+	// Rusage returns the resource usage of the process pid, like
+	// proc_pid_rusage with RUSAGE_INFO_V4.
+	Rusage(pid int) (RusageInfoV4, error)
+}
+
+// list of the process states of ProcBSDInfo.Status and KinfoProc.Stat.
+const (
+	ProcStatusIdle   = 1 // SIDL
+	ProcStatusRun    = 2 // SRUN
+	ProcStatusSleep  = 3 // SSLEEP
+	ProcStatusStop   = 4 // SSTOP
+	ProcStatusZombie = 5 // SZOMB
+)
+
+// ProcBSDInfo is the decoded struct proc_bsdinfo of a process.
+type ProcBSDInfo struct {
+	Flags     uint32 // pbi_flags, the PROC_FLAG_ flags
+	Status    uint32 // pbi_status, one of the ProcStatus constants
+	XStatus   uint32 // pbi_xstatus, the exit status
+	Pid       int32
+	PPid      int32
+	UID       uint32
+	GID       uint32
+	RUID      uint32
+	RGID      uint32
+	SVUID     uint32
+	SVGID     uint32
+	Comm      string // pbi_comm, the first 16 bytes of the command name
+	Name      string // pbi_name, the first 32 bytes of the command name
+	NFiles    uint32 // pbi_nfiles, the number of open files
+	Pgid      int32
+	PJobc     uint32
+	Tdev      int32 // e_tdev, the device of the controlling terminal, or -1
+	Tpgid     int32 // e_tpgid
+	Nice      int32
+	StartTime Timeval // pbi_start_tvsec and pbi_start_tvusec
+}
+
+// ProcTaskInfo is the struct proc_taskinfo of a process, the times being
+// in nanoseconds.
+type ProcTaskInfo struct {
+	VirtualSize      uint64
+	ResidentSize     uint64
+	TotalUser        uint64 // of the terminated threads too
+	TotalSystem      uint64
+	ThreadsUser      uint64 // of the live threads
+	ThreadsSystem    uint64
+	Policy           int32
+	Faults           int32
+	Pageins          int32
+	CowFaults        int32
+	MessagesSent     int32
+	MessagesReceived int32
+	SyscallsMach     int32
+	SyscallsUnix     int32
+	Csw              int32 // context switches
+	Threadnum        int32
+	Numrunning       int32
+	Priority         int32
+}
+
+// ProcFDType is the type of a file descriptor of ProcFDInfo.
+type ProcFDType uint32
+
+// list of ProcFDType.
+const (
+	ProcFDTypeAtalk     ProcFDType = 0  // PROX_FDTYPE_ATALK
+	ProcFDTypeVnode     ProcFDType = 1  // PROX_FDTYPE_VNODE
+	ProcFDTypeSocket    ProcFDType = 2  // PROX_FDTYPE_SOCKET
+	ProcFDTypePSHM      ProcFDType = 3  // PROX_FDTYPE_PSHM
+	ProcFDTypePSEM      ProcFDType = 4  // PROX_FDTYPE_PSEM
+	ProcFDTypeKqueue    ProcFDType = 5  // PROX_FDTYPE_KQUEUE
+	ProcFDTypePipe      ProcFDType = 6  // PROX_FDTYPE_PIPE
+	ProcFDTypeFSEvents  ProcFDType = 7  // PROX_FDTYPE_FSEVENTS
+	ProcFDTypeNetPolicy ProcFDType = 9  // PROX_FDTYPE_NETPOLICY
+	ProcFDTypeChannel   ProcFDType = 10 // PROX_FDTYPE_CHANNEL
+	ProcFDTypeNexus     ProcFDType = 11 // PROX_FDTYPE_NEXUS
+)
+
+var procFDTypeNames = [...]string{
+	ProcFDTypeAtalk:     "atalk",
+	ProcFDTypeVnode:     "vnode",
+	ProcFDTypeSocket:    "socket",
+	ProcFDTypePSHM:      "pshm",
+	ProcFDTypePSEM:      "psem",
+	ProcFDTypeKqueue:    "kqueue",
+	ProcFDTypePipe:      "pipe",
+	ProcFDTypeFSEvents:  "fsevents",
+	ProcFDTypeNetPolicy: "netpolicy",
+	ProcFDTypeChannel:   "channel",
+	ProcFDTypeNexus:     "nexus",
+}
+
+// String returns the name of the type, such as "vnode".
+func (t ProcFDType) String() string {
+	if int(t) < len(procFDTypeNames) && procFDTypeNames[t] != "" {
+		return procFDTypeNames[t]
+	}
+
+	return "fdtype(" + uitoa(uint(t)) + ")"
+}
+
+// ProcFDInfo is the struct proc_fdinfo of a file descriptor.
+type ProcFDInfo struct {
+	FD   int32
+	Type ProcFDType
+}
+
+// RusageInfoV4 is the struct rusage_info_v4 of a process. The times are in
+// the units of mach_absolute_time on darwin, and in nanoseconds on linux.
+type RusageInfoV4 struct {
+	UUID                      [16]byte
+	UserTime                  uint64
+	SystemTime                uint64
+	PkgIdleWkups              uint64
+	InterruptWkups            uint64
+	Pageins                   uint64
+	WiredSize                 uint64
+	ResidentSize              uint64
+	PhysFootprint             uint64
+	ProcStartAbstime          uint64
+	ProcExitAbstime           uint64
+	ChildUserTime             uint64
+	ChildSystemTime           uint64
+	ChildPkgIdleWkups         uint64
+	ChildInterruptWkups       uint64
+	ChildPageins              uint64
+	ChildElapsedAbstime       uint64
+	DiskioBytesread           uint64
+	DiskioByteswritten        uint64
+	CPUTimeQOSDefault         uint64
+	CPUTimeQOSMaintenance     uint64
+	CPUTimeQOSBackground      uint64
+	CPUTimeQOSUtility         uint64
+	CPUTimeQOSLegacy          uint64
+	CPUTimeQOSUserInitiated   uint64
+	CPUTimeQOSUserInteractive uint64
+	BilledSystemTime          uint64
+	ServicedSystemTime        uint64
+	LogicalWrites             uint64
+	LifetimeMaxPhysFootprint  uint64
+	Instructions              uint64
+	Cycles                    uint64
+	BilledEnergy              uint64
+	ServicedEnergy            uint64
+	IntervalMaxPhysFootprint  uint64
+	RunnableTime              uint64
+}
+
+// list of the sizes of the C structs of libproc.
+const (
+	procBSDInfoSize  = 136
+	procTaskInfoSize = 96
+	procFDInfoSize   = 8
+	rusageInfoV4Size = 296
+)
+
+// ParseProcBSDInfo decodes the struct proc_bsdinfo b, of PROC_PIDTBSDINFO.
+func ParseProcBSDInfo(b []byte) (ProcBSDInfo, error) {
+	if len(b) != procBSDInfoSize {
+		return ProcBSDInfo{}, ErrSysctlSize
+	}
+
+	le := binary.LittleEndian
+	u32 := func(off int) uint32 { return le.Uint32(b[off:]) }
+
+	return ProcBSDInfo{
+		Flags:   u32(0),
+		Status:  u32(4),
+		XStatus: u32(8),
+		Pid:     int32(u32(12)),
+		PPid:    int32(u32(16)),
+		UID:     u32(20),
+		GID:     u32(24),
+		RUID:    u32(28),
+		RGID:    u32(32),
+		SVUID:   u32(36),
+		SVGID:   u32(40),
+		Comm:    cstring(b[48:64]),
+		Name:    cstring(b[64:96]),
+		NFiles:  u32(96),
+		Pgid:    int32(u32(100)),
+		PJobc:   u32(104),
+		Tdev:    int32(u32(108)),
+		Tpgid:   int32(u32(112)),
+		Nice:    int32(u32(116)),
+		StartTime: Timeval{
+			Sec:  int64(le.Uint64(b[120:])),
+			Usec: int32(le.Uint64(b[128:])),
+		},
+	}, nil
+}
+
+// ParseProcTaskInfo decodes the struct proc_taskinfo b, of PROC_PIDTASKINFO.
+func ParseProcTaskInfo(b []byte) (ProcTaskInfo, error) {
+	var ti ProcTaskInfo
+	if len(b) != procTaskInfoSize {
+		return ti, ErrSysctlSize
+	}
+	err := binary.Read(bytes.NewReader(b), binary.LittleEndian, &ti)
+
+	return ti, err
+}
+
+// ParseProcFDInfo decodes the array of struct proc_fdinfo b, of
+// PROC_PIDLISTFDS.
+func ParseProcFDInfo(b []byte) ([]ProcFDInfo, error) {
+	if len(b)%procFDInfoSize != 0 {
+		return nil, ErrSysctlSize
+	}
+
+	fds := make([]ProcFDInfo, 0, len(b)/procFDInfoSize)
+	for ; len(b) > 0; b = b[procFDInfoSize:] {
+		fds = append(fds, ProcFDInfo{
+			FD:   int32(binary.LittleEndian.Uint32(b)),
+			Type: ProcFDType(binary.LittleEndian.Uint32(b[4:])),
+		})
+	}
+
+	return fds, nil
+}
+
+// ParseRusageInfoV4 decodes the struct rusage_info_v4 b.
+func ParseRusageInfoV4(b []byte) (RusageInfoV4, error) {
+	var ri RusageInfoV4
+	if len(b) != rusageInfoV4Size {
+		return ri, ErrSysctlSize
+	}
+	err := binary.Read(bytes.NewReader(b), binary.LittleEndian, &ri)
+
+	return ri, err
+}
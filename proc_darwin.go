@@ -0,0 +1,178 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build darwin && amd64
+// +build darwin,amd64
+
+package sys
+
+import (
+	"errors"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+// The libproc functions, called with Ccall6 through the trampolines of
+// proc_darwin.s.
+
+//go:cgo_import_dynamic libc_proc_listpids proc_listpids "/usr/lib/libSystem.B.dylib"
+//go:cgo_import_dynamic libc_proc_pidinfo proc_pidinfo "/usr/lib/libSystem.B.dylib"
+//go:cgo_import_dynamic libc_proc_pidpath proc_pidpath "/usr/lib/libSystem.B.dylib"
+//go:cgo_import_dynamic libc_proc_pid_rusage proc_pid_rusage "/usr/lib/libSystem.B.dylib"
+
+// the addresses of the trampolines, set by proc_darwin.s.
+var (
+	procListpidsTrampolineAddr  uintptr
+	procPidinfoTrampolineAddr   uintptr
+	procPidpathTrampolineAddr   uintptr
+	procPidRusageTrampolineAddr uintptr
+)
+
+// list of the constants of libproc.h and sys/proc_info.h.
+const (
+	procAllPids            = 1
+	procPidListFDs         = 1
+	procPidTBSDInfo        = 3
+	procPidTaskInfo        = 4
+	procPidPathInfoMaxSize = 4 * 1024
+	rusageInfoV4           = 4
+)
+
+// libproc is the ProcBackend of libproc.
+type libproc struct{}
+
+// HostProcBackend returns the ProcBackend of the host, which calls the
+// libproc functions on darwin.
+func HostProcBackend() ProcBackend {
+	return libproc{}
+}
+
+// procError returns the error of the libproc function name failing for the
+// process pid: the functions return 0 rather than -1 with errno, and fail on
+// the missing processes, ESRCH, and on the ones of the other users, EPERM,
+// which kill(pid, 0) reports too.
+func procError(name string, pid int) error {
+	if err := syscall.Kill(pid, 0); err == syscall.ESRCH || err == syscall.EPERM {
+		return err
+	}
+
+	return errors.New("sys: " + name + " failed for pid " + itoa(pid))
+}
+
+// listpidsError returns the error of proc_listpids, which returns 0 on
+// failure, with errno set or not.
+func listpidsError(errno Errno) error {
+	if errno != 0 {
+		return errno
+	}
+
+	return errors.New("sys: proc_listpids failed")
+}
+
+// pidinfo returns the value of flavor of the process pid, like
+// proc_pidinfo, reading size bytes, or the size it returns for NULL if
+// size is 0.
+func pidinfo(pid, flavor, size int) ([]byte, error) {
+	if size == 0 {
+		r1, _, _ := Ccall6(procPidinfoTrampolineAddr, uintptr(pid), uintptr(flavor), 0, 0, 0, 0)
+		if int32(r1) <= 0 {
+			return nil, procError("proc_pidinfo", pid)
+		}
+		// room for the files opened between the calls
+		size = int(int32(r1)) + 16*procFDInfoSize
+	}
+
+	b := make([]byte, size)
+	r1, _, _ := Ccall6(procPidinfoTrampolineAddr, uintptr(pid), uintptr(flavor), 0, uintptr(unsafe.Pointer(&b[0])), uintptr(size), 0)
+	runtime.KeepAlive(b)
+	if int32(r1) <= 0 {
+		return nil, procError("proc_pidinfo", pid)
+	}
+
+	return b[:int32(r1)], nil
+}
+
+// ListPids implements ProcBackend.
+func (libproc) ListPids() ([]int, error) {
+	for i := 0; ; i++ {
+		r1, _, err := Ccall6(procListpidsTrampolineAddr, procAllPids, 0, 0, 0, 0, 0)
+		if int32(r1) <= 0 {
+			return nil, listpidsError(err)
+		}
+		// room for the processes created between the calls
+		n := int(int32(r1)) + 64*4
+		buf := make([]int32, n/4)
+		r1, _, err = Ccall6(procListpidsTrampolineAddr, procAllPids, 0, uintptr(unsafe.Pointer(&buf[0])), uintptr(n), 0, 0)
+		runtime.KeepAlive(buf)
+		if int32(r1) <= 0 {
+			return nil, listpidsError(err)
+		}
+		if int(int32(r1)) >= n && i < maxSysctlRetries {
+			// the buffer may be too small
+			continue
+		}
+
+		pids := make([]int, 0, int32(r1)/4)
+		for _, pid := range buf[:int32(r1)/4] {
+			if pid != 0 {
+				pids = append(pids, int(pid))
+			}
+		}
+		return pids, nil
+	}
+}
+
+// Path implements ProcBackend.
+func (libproc) Path(pid int) (string, error) {
+	var buf [procPidPathInfoMaxSize]byte
+	r1, _, _ := Ccall6(procPidpathTrampolineAddr, uintptr(pid), uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)), 0, 0, 0)
+	runtime.KeepAlive(&buf)
+	if int32(r1) <= 0 {
+		return "", procError("proc_pidpath", pid)
+	}
+
+	return string(buf[:int32(r1)]), nil
+}
+
+// BSDInfo implements ProcBackend.
+func (libproc) BSDInfo(pid int) (ProcBSDInfo, error) {
+	b, err := pidinfo(pid, procPidTBSDInfo, procBSDInfoSize)
+	if err != nil {
+		return ProcBSDInfo{}, err
+	}
+
+	return ParseProcBSDInfo(b)
+}
+
+// TaskInfo implements ProcBackend.
+func (libproc) TaskInfo(pid int) (ProcTaskInfo, error) {
+	b, err := pidinfo(pid, procPidTaskInfo, procTaskInfoSize)
+	if err != nil {
+		return ProcTaskInfo{}, err
+	}
+
+	return ParseProcTaskInfo(b)
+}
+
+// FDs implements ProcBackend.
+func (libproc) FDs(pid int) ([]ProcFDInfo, error) {
+	b, err := pidinfo(pid, procPidListFDs, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseProcFDInfo(b)
+}
+
+// Rusage implements ProcBackend.
+func (libproc) Rusage(pid int) (RusageInfoV4, error) {
+	var b [rusageInfoV4Size]byte
+	_, _, err := Ccall6(procPidRusageTrampolineAddr, uintptr(pid), rusageInfoV4, uintptr(unsafe.Pointer(&b[0])), 0, 0, 0)
+	runtime.KeepAlive(&b)
+	if err != 0 {
+		return RusageInfoV4{}, err
+	}
+
+	return ParseRusageInfoV4(b[:])
+}
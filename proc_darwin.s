@@ -0,0 +1,31 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build darwin && amd64
+// +build darwin,amd64
+
+#include "textflag.h"
+
+TEXT proc_listpids_trampoline<>(SB), NOSPLIT, $0-0
+	JMP libc_proc_listpids(SB)
+
+GLOBL ·procListpidsTrampolineAddr(SB), RODATA, $8
+DATA ·procListpidsTrampolineAddr(SB)/8, $proc_listpids_trampoline<>(SB)
+
+TEXT proc_pidinfo_trampoline<>(SB), NOSPLIT, $0-0
+	JMP libc_proc_pidinfo(SB)
+
+GLOBL ·procPidinfoTrampolineAddr(SB), RODATA, $8
+DATA ·procPidinfoTrampolineAddr(SB)/8, $proc_pidinfo_trampoline<>(SB)
+
+TEXT proc_pidpath_trampoline<>(SB), NOSPLIT, $0-0
+	JMP libc_proc_pidpath(SB)
+
+GLOBL ·procPidpathTrampolineAddr(SB), RODATA, $8
+DATA ·procPidpathTrampolineAddr(SB)/8, $proc_pidpath_trampoline<>(SB)
+
+TEXT proc_pid_rusage_trampoline<>(SB), NOSPLIT, $0-0
+	JMP libc_proc_pid_rusage(SB)
+
+GLOBL ·procPidRusageTrampolineAddr(SB), RODATA, $8
+DATA ·procPidRusageTrampolineAddr(SB)/8, $proc_pid_rusage_trampoline<>(SB)
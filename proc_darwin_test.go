@@ -0,0 +1,25 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build darwin && amd64
+// +build darwin,amd64
+
+package sys_test
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/go-darwin/sys"
+)
+
+func TestHostProcBackend(t *testing.T) {
+	testHostProcBackend(t, sys.HostProcBackend())
+}
+
+func TestHostProcBackendMissing(t *testing.T) {
+	// the PIDs are below 99999 on darwin
+	if _, err := sys.HostProcBackend().BSDInfo(999999); err != syscall.ESRCH {
+		t.Errorf("BSDInfo of a missing process = %v, want ESRCH", err)
+	}
+}
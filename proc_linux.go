@@ -0,0 +1,10 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys
+
+// HostProcBackend returns the ProcBackend of the host, which reads /proc on
+// linux.
+func HostProcBackend() ProcBackend {
+	return NewProcFSBackend("/proc")
+}
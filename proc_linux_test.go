@@ -0,0 +1,14 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys_test
+
+import (
+	"testing"
+
+	"github.com/go-darwin/sys"
+)
+
+func TestHostProcBackend(t *testing.T) {
+	testHostProcBackend(t, sys.HostProcBackend())
+}
@@ -0,0 +1,27 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build !linux && !(darwin && amd64)
+// +build !linux
+// +build !darwin !amd64
+
+package sys
+
+import "syscall"
+
+// noProc is the ProcBackend of the platforms without one, failing with
+// ENOSYS.
+type noProc struct{}
+
+// HostProcBackend returns the ProcBackend of the host, which fails with
+// ENOSYS on this platform.
+func HostProcBackend() ProcBackend {
+	return noProc{}
+}
+
+func (noProc) ListPids() ([]int, error)           { return nil, syscall.ENOSYS }
+func (noProc) Path(int) (string, error)           { return "", syscall.ENOSYS }
+func (noProc) BSDInfo(int) (ProcBSDInfo, error)   { return ProcBSDInfo{}, syscall.ENOSYS }
+func (noProc) TaskInfo(int) (ProcTaskInfo, error) { return ProcTaskInfo{}, syscall.ENOSYS }
+func (noProc) FDs(int) ([]ProcFDInfo, error)      { return nil, syscall.ENOSYS }
+func (noProc) Rusage(int) (RusageInfoV4, error)   { return RusageInfoV4{}, syscall.ENOSYS }
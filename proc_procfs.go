@@ -0,0 +1,367 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// clockTicks is the USER_HZ of the times of /proc, 100 on the linux
+// architectures of the package.
+const clockTicks = 100
+
+// procFS is the ProcBackend of a linux proc filesystem.
+type procFS struct {
+	root string
+}
+
+// NewProcFSBackend returns the ProcBackend of the linux proc filesystem
+// mounted at root, such as "/proc", or a copy of its files.
+//
+// The process states of /proc/<pid>/stat are mapped to the darwin ones, the
+// epoll instances are ProcFDTypeKqueue file descriptors, and the times of
+// RusageInfoV4 are in nanoseconds, its ProcStartAbstime since the boot.
+// A process missing from root fails with ESRCH, like on darwin.
+func NewProcFSBackend(root string) ProcBackend {
+	return &procFS{root: root}
+}
+
+// ListPids implements ProcBackend.
+func (p *procFS) ListPids() ([]int, error) {
+	f, err := os.Open(p.root)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	names, err := f.Readdirnames(-1)
+	if err != nil {
+		return nil, err
+	}
+
+	var pids []int
+	for _, name := range names {
+		if pid, err := strconv.Atoi(name); err == nil && pid > 0 {
+			pids = append(pids, pid)
+		}
+	}
+	sort.Ints(pids)
+
+	return pids, nil
+}
+
+// path returns the path of the file name of the directory of pid.
+func (p *procFS) path(pid int, name string) string {
+	return filepath.Join(p.root, strconv.Itoa(pid), name)
+}
+
+// readFile reads the file name of the directory of pid.
+func (p *procFS) readFile(pid int, name string) ([]byte, error) {
+	b, err := os.ReadFile(p.path(pid, name))
+	return b, p.pidError(pid, err)
+}
+
+// pidError returns ESRCH for err if the process pid is missing.
+func (p *procFS) pidError(pid int, err error) error {
+	if err == nil || !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if _, serr := os.Stat(filepath.Join(p.root, strconv.Itoa(pid))); serr != nil {
+		return syscall.ESRCH
+	}
+
+	return err
+}
+
+// Path implements ProcBackend.
+func (p *procFS) Path(pid int) (string, error) {
+	path, err := os.Readlink(p.path(pid, "exe"))
+	return strings.TrimSuffix(path, " (deleted)"), p.pidError(pid, err)
+}
+
+// procStat is a parsed /proc/<pid>/stat file.
+type procStat struct {
+	comm   string
+	state  byte
+	fields []string // from the state on, fields[i] being the field i+3
+}
+
+// field returns the field n, numbered from 1 like in proc(5), as a number.
+func (s *procStat) field(n int) int64 {
+	if n-3 >= len(s.fields) {
+		return 0
+	}
+	v, _ := strconv.ParseInt(s.fields[n-3], 10, 64)
+	return v
+}
+
+func (p *procFS) stat(pid int) (*procStat, error) {
+	b, err := p.readFile(pid, "stat")
+	if err != nil {
+		return nil, err
+	}
+	// the command name is in parentheses, and may hold them
+	i, j := bytes.IndexByte(b, '('), bytes.LastIndexByte(b, ')')
+	if i < 0 || j < i {
+		return nil, errors.New("sys: invalid " + p.path(pid, "stat"))
+	}
+	s := &procStat{comm: string(b[i+1 : j]), fields: strings.Fields(string(b[j+1:]))}
+	if len(s.fields) < 20 || len(s.fields[0]) != 1 {
+		return nil, errors.New("sys: invalid " + p.path(pid, "stat"))
+	}
+	s.state = s.fields[0][0]
+
+	return s, nil
+}
+
+// status returns the fields of /proc/<pid>/status.
+func (p *procFS) status(pid int) (map[string]string, error) {
+	b, err := p.readFile(pid, "status")
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]string)
+	s := bufio.NewScanner(bytes.NewReader(b))
+	for s.Scan() {
+		if i := strings.IndexByte(s.Text(), ':'); i > 0 {
+			m[s.Text()[:i]] = strings.TrimSpace(s.Text()[i+1:])
+		}
+	}
+
+	return m, s.Err()
+}
+
+// ids returns the real, effective and saved IDs of the Uid or Gid line s of
+// /proc/<pid>/status.
+func ids(s string) (real, effective, saved uint32) {
+	f := strings.Fields(s)
+	id := func(i int) uint32 {
+		if i >= len(f) {
+			return 0
+		}
+		v, _ := strconv.ParseUint(f[i], 10, 32)
+		return uint32(v)
+	}
+
+	return id(0), id(1), id(2)
+}
+
+// statusKB returns the size in bytes of the field "<n> kB" s of
+// /proc/<pid>/status.
+func statusKB(s string) uint64 {
+	v, _ := strconv.ParseUint(strings.TrimSuffix(s, " kB"), 10, 64)
+	return v << 10
+}
+
+// procStates maps the states of /proc/<pid>/stat to the darwin ones.
+var procStates = map[byte]uint32{
+	'R': ProcStatusRun,
+	'S': ProcStatusSleep,
+	'D': ProcStatusSleep,
+	'I': ProcStatusSleep,
+	'T': ProcStatusStop,
+	't': ProcStatusStop,
+	'Z': ProcStatusZombie,
+	'X': ProcStatusZombie,
+}
+
+// bootTime returns the btime of the stat file of root, in seconds.
+func (p *procFS) bootTime() (int64, error) {
+	b, err := os.ReadFile(filepath.Join(p.root, "stat"))
+	if err != nil {
+		return 0, err
+	}
+	s := bufio.NewScanner(bytes.NewReader(b))
+	for s.Scan() {
+		if f := strings.Fields(s.Text()); len(f) == 2 && f[0] == "btime" {
+			return strconv.ParseInt(f[1], 10, 64)
+		}
+	}
+
+	return 0, errors.New("sys: no btime in " + filepath.Join(p.root, "stat"))
+}
+
+// BSDInfo implements ProcBackend.
+func (p *procFS) BSDInfo(pid int) (ProcBSDInfo, error) {
+	st, err := p.stat(pid)
+	if err != nil {
+		return ProcBSDInfo{}, err
+	}
+	status, err := p.status(pid)
+	if err != nil {
+		return ProcBSDInfo{}, err
+	}
+	btime, err := p.bootTime()
+	if err != nil {
+		return ProcBSDInfo{}, err
+	}
+
+	info := ProcBSDInfo{
+		Status: procStates[st.state],
+		Pid:    int32(pid),
+		PPid:   int32(st.field(4)),
+		Comm:   st.comm,
+		Name:   st.comm,
+		Pgid:   int32(st.field(5)),
+		Tdev:   int32(st.field(7)),
+		Tpgid:  int32(st.field(8)),
+		Nice:   int32(st.field(19)),
+	}
+	info.RUID, info.UID, info.SVUID = ids(status["Uid"])
+	info.RGID, info.GID, info.SVGID = ids(status["Gid"])
+	if info.Tdev == 0 {
+		info.Tdev = -1 // NODEV
+	}
+	if info.Tpgid < 0 {
+		info.Tpgid = 0
+	}
+	if len(info.Comm) > maxComLen {
+		info.Comm = info.Comm[:maxComLen]
+	}
+	start := st.field(22)
+	info.StartTime = Timeval{
+		Sec:  btime + start/clockTicks,
+		Usec: int32(start % clockTicks * (1e6 / clockTicks)),
+	}
+	// the directory is not readable for the processes of the other users
+	if fds, err := os.ReadDir(p.path(pid, "fd")); err == nil {
+		info.NFiles = uint32(len(fds))
+	}
+
+	return info, nil
+}
+
+// TaskInfo implements ProcBackend.
+func (p *procFS) TaskInfo(pid int) (ProcTaskInfo, error) {
+	st, err := p.stat(pid)
+	if err != nil {
+		return ProcTaskInfo{}, err
+	}
+	status, err := p.status(pid)
+	if err != nil {
+		return ProcTaskInfo{}, err
+	}
+
+	const ns = 1e9 / clockTicks
+	csw, _ := strconv.ParseInt(status["voluntary_ctxt_switches"], 10, 32)
+	ncsw, _ := strconv.ParseInt(status["nonvoluntary_ctxt_switches"], 10, 32)
+	ti := ProcTaskInfo{
+		VirtualSize:   uint64(st.field(23)),
+		ResidentSize:  uint64(st.field(24)) * uint64(os.Getpagesize()),
+		TotalUser:     uint64(st.field(14)) * ns,
+		TotalSystem:   uint64(st.field(15)) * ns,
+		ThreadsUser:   uint64(st.field(14)) * ns,
+		ThreadsSystem: uint64(st.field(15)) * ns,
+		Policy:        int32(st.field(41)),
+		Faults:        int32(st.field(10) + st.field(12)),
+		Pageins:       int32(st.field(12)),
+		Csw:           int32(csw + ncsw),
+		Threadnum:     int32(st.field(20)),
+		Priority:      int32(st.field(18)),
+	}
+	if st.state == 'R' {
+		ti.Numrunning = 1
+	}
+
+	return ti, nil
+}
+
+// FDs implements ProcBackend.
+func (p *procFS) FDs(pid int) ([]ProcFDInfo, error) {
+	dir := p.path(pid, "fd")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, p.pidError(pid, err)
+	}
+
+	fds := make([]ProcFDInfo, 0, len(entries))
+	for _, e := range entries {
+		fd, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		target, err := os.Readlink(filepath.Join(dir, e.Name()))
+		if err != nil {
+			// closed since ReadDir
+			continue
+		}
+		fds = append(fds, ProcFDInfo{FD: int32(fd), Type: procFDType(target)})
+	}
+	sort.Slice(fds, func(i, j int) bool { return fds[i].FD < fds[j].FD })
+
+	return fds, nil
+}
+
+// procFDType returns the ProcFDType of the target of a link of
+// /proc/<pid>/fd.
+func procFDType(target string) ProcFDType {
+	switch {
+	case strings.HasPrefix(target, "socket:"):
+		return ProcFDTypeSocket
+	case strings.HasPrefix(target, "pipe:"):
+		return ProcFDTypePipe
+	case target == "anon_inode:[eventpoll]":
+		return ProcFDTypeKqueue
+	case strings.HasPrefix(target, "/dev/shm/"):
+		return ProcFDTypePSHM
+	}
+
+	return ProcFDTypeVnode
+}
+
+// Rusage implements ProcBackend.
+func (p *procFS) Rusage(pid int) (RusageInfoV4, error) {
+	st, err := p.stat(pid)
+	if err != nil {
+		return RusageInfoV4{}, err
+	}
+	status, err := p.status(pid)
+	if err != nil {
+		return RusageInfoV4{}, err
+	}
+
+	const ns = 1e9 / clockTicks
+	ri := RusageInfoV4{
+		UserTime:                 uint64(st.field(14)) * ns,
+		SystemTime:               uint64(st.field(15)) * ns,
+		Pageins:                  uint64(st.field(12)),
+		WiredSize:                statusKB(status["VmLck"]),
+		ResidentSize:             statusKB(status["VmRSS"]),
+		PhysFootprint:            statusKB(status["VmRSS"]) + statusKB(status["VmSwap"]),
+		ProcStartAbstime:         uint64(st.field(22)) * ns,
+		ChildUserTime:            uint64(st.field(16)) * ns,
+		ChildSystemTime:          uint64(st.field(17)) * ns,
+		ChildPageins:             uint64(st.field(13)),
+		LifetimeMaxPhysFootprint: statusKB(status["VmHWM"]),
+	}
+	// the file is not readable for the processes of the other users
+	if b, err := p.readFile(pid, "io"); err == nil {
+		s := bufio.NewScanner(bytes.NewReader(b))
+		for s.Scan() {
+			f := strings.Fields(s.Text())
+			if len(f) != 2 {
+				continue
+			}
+			v, _ := strconv.ParseUint(f[1], 10, 64)
+			switch f[0] {
+			case "read_bytes:":
+				ri.DiskioBytesread = v
+			case "write_bytes:":
+				ri.DiskioByteswritten = v
+			case "wchar:":
+				ri.LogicalWrites = v
+			}
+		}
+	}
+
+	return ri, nil
+}
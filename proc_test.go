@@ -0,0 +1,326 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"syscall"
+	"testing"
+
+	"github.com/go-darwin/sys"
+)
+
+func TestParseProcBSDInfo(t *testing.T) {
+	b := readDarwinFixture(t, "libproc", "bsdinfo")
+
+	info, err := sys.ParseProcBSDInfo(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := sys.ProcBSDInfo{
+		Flags:     0x4001,
+		Status:    sys.ProcStatusRun,
+		Pid:       412,
+		PPid:      411,
+		UID:       501,
+		GID:       20,
+		RUID:      501,
+		RGID:      20,
+		SVUID:     501,
+		SVGID:     20,
+		Comm:      "zsh",
+		Name:      "zsh",
+		NFiles:    12,
+		Pgid:      412,
+		PJobc:     1,
+		Tdev:      0x10000004,
+		Tpgid:     412,
+		Nice:      -5,
+		StartTime: sys.Timeval{Sec: 1660003600, Usec: 5},
+	}
+	if info != want {
+		t.Errorf("ParseProcBSDInfo = %+v, want %+v", info, want)
+	}
+	if _, err := sys.ParseProcBSDInfo(b[:128]); !errors.Is(err, sys.ErrSysctlSize) {
+		t.Errorf("ParseProcBSDInfo of 128 bytes = %v, want ErrSysctlSize", err)
+	}
+}
+
+func TestParseProcTaskInfo(t *testing.T) {
+	b := readDarwinFixture(t, "libproc", "taskinfo")
+
+	ti, err := sys.ParseProcTaskInfo(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := sys.ProcTaskInfo{
+		VirtualSize:      34359742464,
+		ResidentSize:     5242880,
+		TotalUser:        12000000,
+		TotalSystem:      3000000,
+		ThreadsUser:      11000000,
+		ThreadsSystem:    2500000,
+		Policy:           1,
+		Faults:           1500,
+		Pageins:          3,
+		CowFaults:        120,
+		MessagesSent:     40,
+		MessagesReceived: 38,
+		SyscallsMach:     200,
+		SyscallsUnix:     900,
+		Csw:              340,
+		Threadnum:        1,
+		Priority:         31,
+	}
+	if ti != want {
+		t.Errorf("ParseProcTaskInfo = %+v, want %+v", ti, want)
+	}
+	if _, err := sys.ParseProcTaskInfo(b[:95]); !errors.Is(err, sys.ErrSysctlSize) {
+		t.Errorf("ParseProcTaskInfo of 95 bytes = %v, want ErrSysctlSize", err)
+	}
+}
+
+func TestParseProcFDInfo(t *testing.T) {
+	b := readDarwinFixture(t, "libproc", "fdinfo")
+
+	fds, err := sys.ParseProcFDInfo(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []sys.ProcFDInfo{
+		{FD: 0, Type: sys.ProcFDTypeVnode},
+		{FD: 1, Type: sys.ProcFDTypeVnode},
+		{FD: 3, Type: sys.ProcFDTypeSocket},
+		{FD: 5, Type: sys.ProcFDTypeKqueue},
+	}
+	if !reflect.DeepEqual(fds, want) {
+		t.Errorf("ParseProcFDInfo = %v, want %v", fds, want)
+	}
+	if _, err := sys.ParseProcFDInfo(b[:7]); !errors.Is(err, sys.ErrSysctlSize) {
+		t.Errorf("ParseProcFDInfo of 7 bytes = %v, want ErrSysctlSize", err)
+	}
+}
+
+func TestProcFDTypeString(t *testing.T) {
+	for typ, want := range map[sys.ProcFDType]string{
+		sys.ProcFDTypeVnode:  "vnode",
+		sys.ProcFDTypeKqueue: "kqueue",
+		sys.ProcFDTypeNexus:  "nexus",
+		8:                    "fdtype(8)",
+		42:                   "fdtype(42)",
+	} {
+		if s := typ.String(); s != want {
+			t.Errorf("ProcFDType(%d).String() = %q, want %q", uint32(typ), s, want)
+		}
+	}
+}
+
+func TestParseRusageInfoV4(t *testing.T) {
+	b := readDarwinFixture(t, "libproc", "rusage_v4")
+
+	ri, err := sys.ParseRusageInfoV4(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := sys.RusageInfoV4{
+		UUID:                     [16]byte{0xa0, 0xa1, 0xa2, 0xa3, 0xa4, 0xa5, 0xa6, 0xa7, 0xa8, 0xa9, 0xaa, 0xab, 0xac, 0xad, 0xae, 0xaf},
+		UserTime:                 12000000,
+		SystemTime:               3000000,
+		Pageins:                  3,
+		ResidentSize:             5242880,
+		PhysFootprint:            6291456,
+		ProcStartAbstime:         987654321,
+		DiskioBytesread:          4096,
+		DiskioByteswritten:       8192,
+		LogicalWrites:            8192,
+		LifetimeMaxPhysFootprint: 7340032,
+		Instructions:             1000000,
+		Cycles:                   2000000,
+		RunnableTime:             42,
+	}
+	if ri != want {
+		t.Errorf("ParseRusageInfoV4 = %+v, want %+v", ri, want)
+	}
+	if _, err := sys.ParseRusageInfoV4(b[:288]); !errors.Is(err, sys.ErrSysctlSize) {
+		t.Errorf("ParseRusageInfoV4 of 288 bytes = %v, want ErrSysctlSize", err)
+	}
+}
+
+func TestProcFSBackend(t *testing.T) {
+	p := sys.NewProcFSBackend(filepath.Join("testdata", "procfs"))
+
+	pids, err := p.ListPids()
+	if err != nil || !reflect.DeepEqual(pids, []int{412}) {
+		t.Errorf("ListPids = %v, %v, want [412]", pids, err)
+	}
+	if path, err := p.Path(412); err != nil || path != "/usr/bin/zsh" {
+		t.Errorf("Path = %q, %v", path, err)
+	}
+
+	info, err := p.BSDInfo(412)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantInfo := sys.ProcBSDInfo{
+		Status:    sys.ProcStatusSleep,
+		Pid:       412,
+		PPid:      411,
+		UID:       501,
+		GID:       20,
+		RUID:      501,
+		RGID:      20,
+		SVUID:     501,
+		SVGID:     20,
+		Comm:      "zsh (login)",
+		Name:      "zsh (login)",
+		NFiles:    5,
+		Pgid:      412,
+		Tdev:      34816,
+		Tpgid:     4242,
+		Nice:      -5,
+		StartTime: sys.Timeval{Sec: 1660003600},
+	}
+	if info != wantInfo {
+		t.Errorf("BSDInfo = %+v, want %+v", info, wantInfo)
+	}
+
+	ti, err := p.TaskInfo(412)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantTask := sys.ProcTaskInfo{
+		VirtualSize:   34359742464,
+		ResidentSize:  1280 * uint64(os.Getpagesize()),
+		TotalUser:     12e9,
+		TotalSystem:   3e9,
+		ThreadsUser:   12e9,
+		ThreadsSystem: 3e9,
+		Faults:        1503,
+		Pageins:       3,
+		Csw:           340,
+		Threadnum:     1,
+		Priority:      20,
+	}
+	if ti != wantTask {
+		t.Errorf("TaskInfo = %+v, want %+v", ti, wantTask)
+	}
+
+	fds, err := p.FDs(412)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantFDs := []sys.ProcFDInfo{
+		{FD: 0, Type: sys.ProcFDTypeVnode},
+		{FD: 1, Type: sys.ProcFDTypeSocket},
+		{FD: 3, Type: sys.ProcFDTypeKqueue},
+		{FD: 4, Type: sys.ProcFDTypePSHM},
+		{FD: 10, Type: sys.ProcFDTypePipe},
+	}
+	if !reflect.DeepEqual(fds, wantFDs) {
+		t.Errorf("FDs = %v, want %v", fds, wantFDs)
+	}
+
+	ri, err := p.Rusage(412)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantRusage := sys.RusageInfoV4{
+		UserTime:                 12e9,
+		SystemTime:               3e9,
+		Pageins:                  3,
+		WiredSize:                4 << 10,
+		ResidentSize:             5120 << 10,
+		PhysFootprint:            6144 << 10,
+		ProcStartAbstime:         3600e9,
+		ChildUserTime:            4e8,
+		ChildSystemTime:          1e8,
+		ChildPageins:             10,
+		DiskioBytesread:          4096,
+		DiskioByteswritten:       8192,
+		LogicalWrites:            8192,
+		LifetimeMaxPhysFootprint: 7168 << 10,
+	}
+	if ri != wantRusage {
+		t.Errorf("Rusage = %+v, want %+v", ri, wantRusage)
+	}
+
+	if _, err := p.BSDInfo(999); err != syscall.ESRCH {
+		t.Errorf("BSDInfo of a missing process = %v, want ESRCH", err)
+	}
+	if _, err := p.Path(999); err != syscall.ESRCH {
+		t.Errorf("Path of a missing process = %v, want ESRCH", err)
+	}
+	if _, err := p.FDs(999); err != syscall.ESRCH {
+		t.Errorf("FDs of a missing process = %v, want ESRCH", err)
+	}
+}
+
+// testHostProcBackend checks p against the current process.
+func testHostProcBackend(t *testing.T, p sys.ProcBackend) {
+	pid := os.Getpid()
+
+	pids, err := p.ListPids()
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, id := range pids {
+		found = found || id == pid
+	}
+	if !found {
+		t.Errorf("ListPids of %d processes misses %d", len(pids), pid)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path, err := p.Path(pid); err != nil || path != exe {
+		t.Errorf("Path = %q, %v, want %q", path, err, exe)
+	}
+
+	info, err := p.BSDInfo(pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int(info.Pid) != pid || int(info.PPid) != os.Getppid() || int(info.UID) != os.Geteuid() || int(info.RUID) != os.Getuid() || int(info.GID) != os.Getegid() {
+		t.Errorf("BSDInfo = %+v", info)
+	}
+	if info.Status != sys.ProcStatusRun && info.Status != sys.ProcStatusSleep {
+		t.Errorf("BSDInfo.Status = %d", info.Status)
+	}
+
+	ti, err := p.TaskInfo(pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ti.Threadnum < 1 || ti.ResidentSize == 0 || ti.VirtualSize < ti.ResidentSize {
+		t.Errorf("TaskInfo = %+v", ti)
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+	fds, err := p.FDs(pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	typ := map[int32]sys.ProcFDType{}
+	for _, fd := range fds {
+		typ[fd.FD] = fd.Type
+	}
+	if typ[int32(r.Fd())] != sys.ProcFDTypePipe || typ[int32(w.Fd())] != sys.ProcFDTypePipe {
+		t.Errorf("FDs = %v, want pipes %d and %d", fds, r.Fd(), w.Fd())
+	}
+
+	if ri, err := p.Rusage(pid); err != nil || ri.ResidentSize == 0 {
+		t.Errorf("Rusage = %+v, %v", ri, err)
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+)
+
+// MemoryRegion is a region of the address space of a process.
+type MemoryRegion struct {
+	Addr    uint64
+	Size    uint64
+	Prot    VMProt // current protection
+	MaxProt VMProt // maximum protection, VMProtAll on linux
+	Shared  bool   // shared with other processes, rather than private
+	Offset  uint64 // in the mapped file or memory object
+	Path    string // of the mapped file, or a name such as "[stack]", on linux
+}
+
+// End returns the address past the end of r.
+func (r MemoryRegion) End() uint64 {
+	return r.Addr + r.Size
+}
+
+// MemoryError records the failure of an operation of a ProcessMemory. Err is
+// the KernReturn of the Mach call on darwin, and the Errno of the system
+// call on linux.
+type MemoryError struct {
+	Op   string // "open", "read", "write" or "regions"
+	Pid  int
+	Addr uint64
+	Err  error
+}
+
+// Error implements error.
+func (e *MemoryError) Error() string {
+	s := "sys: " + e.Op + " memory of process " + itoa(e.Pid)
+	if e.Op == "read" || e.Op == "write" {
+		s += " at 0x" + strconv.FormatUint(e.Addr, 16)
+	}
+
+	return s + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *MemoryError) Unwrap() error { return e.Err }
+
+// ErrMemoryClosed is returned by the methods of a closed ProcessMemory.
+var ErrMemoryClosed = errors.New("sys: use of closed process memory")
+
+// ProcessMemory reads and writes the memory of another process, such as the
+// one of a profiled or debugged program, with the addresses of that process
+// as the offsets of ReadAt and WriteAt.
+//
+// On darwin, it holds the task port of the process, of task_for_pid, which
+// requires the com.apple.security.cs.debugger entitlement or root, and the
+// process to be debuggable. On linux, it calls process_vm_readv and
+// process_vm_writev, which require the ptrace access to the process, such
+// as the one of its parent with the kernel.yama.ptrace_scope 1.
+//
+// A ProcessMemory is safe for concurrent use.
+type ProcessMemory struct {
+	pid int
+
+	mu     sync.RWMutex
+	task   uintptr // on darwin
+	closed bool
+}
+
+// OpenProcessMemory returns the ProcessMemory of the process pid.
+func OpenProcessMemory(pid int) (*ProcessMemory, error) {
+	m := &ProcessMemory{pid: pid}
+	if err := m.open(); err != nil {
+		return nil, &MemoryError{Op: "open", Pid: pid, Err: err}
+	}
+
+	return m, nil
+}
+
+// Pid returns the ID of the process.
+func (m *ProcessMemory) Pid() int {
+	return m.pid
+}
+
+// ReadAt reads len(p) bytes of the memory at the address off into p. Like
+// io.ReaderAt, it returns an error when it reads less, such as when a page
+// is not mapped or readable, a *MemoryError.
+func (m *ProcessMemory) ReadAt(p []byte, off int64) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		return 0, ErrMemoryClosed
+	}
+
+	n, err := m.readAt(p, uint64(off))
+	if err != nil {
+		return n, &MemoryError{Op: "read", Pid: m.pid, Addr: uint64(off) + uint64(n), Err: err}
+	}
+
+	return n, nil
+}
+
+// WriteAt writes p to the memory at the address off, whose pages must be
+// writable. It returns an error when it writes less, a *MemoryError.
+func (m *ProcessMemory) WriteAt(p []byte, off int64) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		return 0, ErrMemoryClosed
+	}
+
+	n, err := m.writeAt(p, uint64(off))
+	if err != nil {
+		return n, &MemoryError{Op: "write", Pid: m.pid, Addr: uint64(off) + uint64(n), Err: err}
+	}
+
+	return n, nil
+}
+
+// Regions returns the regions of the address space of the process, in the
+// order of their addresses, like vmmap on darwin and /proc/<pid>/maps on
+// linux.
+func (m *ProcessMemory) Regions() ([]MemoryRegion, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		return nil, ErrMemoryClosed
+	}
+
+	regions, err := m.regions()
+	if err != nil {
+		return nil, &MemoryError{Op: "regions", Pid: m.pid, Err: err}
+	}
+
+	return regions, nil
+}
+
+// Close releases the resources of m, such as the task port on darwin.
+func (m *ProcessMemory) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return ErrMemoryClosed
+	}
+	m.closed = true
+
+	return m.close()
+}
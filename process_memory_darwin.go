@@ -0,0 +1,149 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build darwin && amd64
+// +build darwin,amd64
+
+package sys
+
+import (
+	"encoding/binary"
+	"os"
+	"runtime"
+	"unsafe"
+)
+
+// The MIG stubs of the mach_vm subsystem, called with Ccall6 through the
+// trampolines of process_memory_darwin.s.
+
+//go:cgo_import_dynamic libc_mach_vm_read_overwrite mach_vm_read_overwrite "/usr/lib/libSystem.B.dylib"
+//go:cgo_import_dynamic libc_mach_vm_write mach_vm_write "/usr/lib/libSystem.B.dylib"
+//go:cgo_import_dynamic libc_mach_vm_region_recurse mach_vm_region_recurse "/usr/lib/libSystem.B.dylib"
+
+// the addresses of the trampolines, set by process_memory_darwin.s.
+var (
+	machVMReadOverwriteTrampolineAddr uintptr
+	machVMWriteTrampolineAddr         uintptr
+	machVMRegionRecurseTrampolineAddr uintptr
+)
+
+// vmRegionSubmapInfoCount64 is VM_REGION_SUBMAP_INFO_COUNT_64, the size of
+// the struct vm_region_submap_info_64 in natural_t.
+const vmRegionSubmapInfoCount64 = 19
+
+// list of the share modes of vm_region_submap_info_64 of the shared regions.
+const (
+	smShared        = 4 // SM_SHARED
+	smTrueShared    = 5 // SM_TRUESHARED
+	smSharedAliased = 7 // SM_SHARED_ALIASED
+)
+
+func (m *ProcessMemory) open() error {
+	var task MachPortName
+	kr := RawMachTrap3(uintptr(TrapTaskForPid), hostTask(), uintptr(m.pid), uintptr(unsafe.Pointer(&task)))
+	if kr != KernSuccess {
+		return kr
+	}
+	m.task = uintptr(task)
+
+	return nil
+}
+
+func (m *ProcessMemory) close() error {
+	if kr := RawMachTrap2(uintptr(TrapMachPortDeallocate), hostTask(), m.task); kr != KernSuccess {
+		return kr
+	}
+
+	return nil
+}
+
+// pageChunks calls fn with the chunks of p split at the page boundaries of
+// addr, until it fails, and returns the length of the chunks done.
+func pageChunks(p []byte, addr uint64, fn func(p []byte, addr uint64) KernReturn) (int, error) {
+	page := uint64(os.Getpagesize())
+	n := 0
+	for n < len(p) {
+		a := addr + uint64(n)
+		size := page - a%page
+		if rest := uint64(len(p) - n); size > rest {
+			size = rest
+		}
+		if kr := fn(p[n:n+int(size)], a); kr != KernSuccess {
+			return n, kr
+		}
+		n += int(size)
+	}
+
+	return n, nil
+}
+
+func (m *ProcessMemory) readAt(p []byte, addr uint64) (int, error) {
+	read := func(p []byte, addr uint64) KernReturn {
+		var out uint64
+		r1, _, _ := Ccall6(machVMReadOverwriteTrampolineAddr, m.task, uintptr(addr), uintptr(len(p)), uintptr(unsafe.Pointer(&p[0])), uintptr(unsafe.Pointer(&out)), 0)
+		runtime.KeepAlive(p)
+		return KernReturn(r1)
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if kr := read(p, addr); kr == KernSuccess {
+		return len(p), nil
+	}
+
+	// the call fails without reading when a page fails
+	return pageChunks(p, addr, read)
+}
+
+func (m *ProcessMemory) writeAt(p []byte, addr uint64) (int, error) {
+	write := func(p []byte, addr uint64) KernReturn {
+		r1, _, _ := Ccall6(machVMWriteTrampolineAddr, m.task, uintptr(addr), uintptr(unsafe.Pointer(&p[0])), uintptr(len(p)), 0, 0)
+		runtime.KeepAlive(p)
+		return KernReturn(r1)
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if kr := write(p, addr); kr == KernSuccess {
+		return len(p), nil
+	}
+
+	return pageChunks(p, addr, write)
+}
+
+func (m *ProcessMemory) regions() ([]MemoryRegion, error) {
+	var regions []MemoryRegion
+	var addr, size uint64
+	var depth uint32
+	for {
+		var info [vmRegionSubmapInfoCount64 * 4]byte
+		count := uint32(vmRegionSubmapInfoCount64)
+		r1, _, _ := Ccall6(machVMRegionRecurseTrampolineAddr, m.task, uintptr(unsafe.Pointer(&addr)), uintptr(unsafe.Pointer(&size)), uintptr(unsafe.Pointer(&depth)), uintptr(unsafe.Pointer(&info[0])), uintptr(unsafe.Pointer(&count)))
+		switch kr := KernReturn(r1); kr {
+		case KernSuccess:
+		case KernInvalidAddress:
+			// past the last region
+			return regions, nil
+		default:
+			return nil, kr
+		}
+
+		// struct vm_region_submap_info_64, of #pragma pack(4)
+		le := binary.LittleEndian
+		if le.Uint32(info[48:]) != 0 {
+			// is_submap, whose regions are one level deeper
+			depth++
+			continue
+		}
+		share := info[47]
+		regions = append(regions, MemoryRegion{
+			Addr:    addr,
+			Size:    size,
+			Prot:    VMProt(le.Uint32(info[0:])),
+			MaxProt: VMProt(le.Uint32(info[4:])),
+			Shared:  share == smShared || share == smTrueShared || share == smSharedAliased,
+			Offset:  le.Uint64(info[12:]),
+		})
+		addr += size
+	}
+}
@@ -0,0 +1,25 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build darwin && amd64
+// +build darwin,amd64
+
+#include "textflag.h"
+
+TEXT mach_vm_read_overwrite_trampoline<>(SB), NOSPLIT, $0-0
+	JMP libc_mach_vm_read_overwrite(SB)
+
+GLOBL ·machVMReadOverwriteTrampolineAddr(SB), RODATA, $8
+DATA ·machVMReadOverwriteTrampolineAddr(SB)/8, $mach_vm_read_overwrite_trampoline<>(SB)
+
+TEXT mach_vm_write_trampoline<>(SB), NOSPLIT, $0-0
+	JMP libc_mach_vm_write(SB)
+
+GLOBL ·machVMWriteTrampolineAddr(SB), RODATA, $8
+DATA ·machVMWriteTrampolineAddr(SB)/8, $mach_vm_write_trampoline<>(SB)
+
+TEXT mach_vm_region_recurse_trampoline<>(SB), NOSPLIT, $0-0
+	JMP libc_mach_vm_region_recurse(SB)
+
+GLOBL ·machVMRegionRecurseTrampolineAddr(SB), RODATA, $8
+DATA ·machVMRegionRecurseTrampolineAddr(SB)/8, $mach_vm_region_recurse_trampoline<>(SB)
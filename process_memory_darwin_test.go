@@ -0,0 +1,57 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build darwin && amd64
+// +build darwin,amd64
+
+package sys_test
+
+import (
+	"errors"
+	"os"
+	"runtime"
+	"testing"
+	"unsafe"
+
+	"github.com/go-darwin/sys"
+)
+
+// TestProcessMemoryDarwin reads and writes the memory of the current
+// process, whose task port task_for_pid returns without an entitlement.
+func TestProcessMemoryDarwin(t *testing.T) {
+	m, err := sys.OpenProcessMemory(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	src := []byte("go-darwin process memory 0123456789")
+	addr := int64(uintptr(unsafe.Pointer(&src[0])))
+	buf := make([]byte, len(src))
+	if n, err := m.ReadAt(buf, addr); err != nil || n != len(src) || string(buf) != string(src) {
+		t.Errorf("ReadAt = %d, %v, %q", n, err, buf)
+	}
+	if n, err := m.WriteAt([]byte("MEMORY"), addr+18); err != nil || n != 6 {
+		t.Errorf("WriteAt = %d, %v", n, err)
+	}
+	if want := "go-darwin process MEMORY 0123456789"; string(src) != want {
+		t.Errorf("WriteAt wrote %q, want %q", src, want)
+	}
+	runtime.KeepAlive(src)
+
+	if _, err := m.ReadAt(buf, 0); !errors.Is(err, sys.KernInvalidAddress) {
+		t.Errorf("ReadAt of address 0 = %v, want KernInvalidAddress", err)
+	}
+
+	regions, err := m.Regions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, r := range regions {
+		found = found || r.Addr <= uint64(addr) && uint64(addr) < r.End() && r.Prot&sys.VMProtWrite != 0
+	}
+	if !found {
+		t.Errorf("no writable region of %#x in %d regions", addr, len(regions))
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+func (m *ProcessMemory) open() error {
+	if _, err := os.Stat("/proc/" + strconv.Itoa(m.pid)); err != nil {
+		return syscall.ESRCH
+	}
+
+	return nil
+}
+
+func (m *ProcessMemory) close() error {
+	return nil
+}
+
+// processVM calls process_vm_readv or process_vm_writev, trap, to transfer
+// p from or to the memory at addr, which may be partial.
+func (m *ProcessMemory) processVM(trap uintptr, p []byte, addr uint64) (int, error) {
+	n := 0
+	for n < len(p) {
+		local := syscall.Iovec{Base: &p[n]}
+		local.SetLen(len(p) - n)
+		remote := struct {
+			base uintptr
+			len  uintptr
+		}{uintptr(addr) + uintptr(n), uintptr(len(p) - n)}
+		r1, _, errno := syscall.Syscall6(trap, uintptr(m.pid), uintptr(unsafe.Pointer(&local)), 1, uintptr(unsafe.Pointer(&remote)), 1, 0)
+		if errno != 0 {
+			return n, errno
+		}
+		if r1 == 0 {
+			return n, syscall.EFAULT
+		}
+		n += int(r1)
+	}
+
+	return n, nil
+}
+
+func (m *ProcessMemory) readAt(p []byte, addr uint64) (int, error) {
+	return m.processVM(sysProcessVMReadv, p, addr)
+}
+
+func (m *ProcessMemory) writeAt(p []byte, addr uint64) (int, error) {
+	return m.processVM(sysProcessVMWritev, p, addr)
+}
+
+func (m *ProcessMemory) regions() ([]MemoryRegion, error) {
+	b, err := os.ReadFile("/proc/" + strconv.Itoa(m.pid) + "/maps")
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, syscall.ESRCH
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return parseMaps(b)
+}
+
+// parseMaps parses the lines of a /proc/<pid>/maps file, such as
+//
+//	7f6c8c000000-7f6c8c021000 rw-p 00000000 00:00 0 [heap]
+func parseMaps(b []byte) ([]MemoryRegion, error) {
+	var regions []MemoryRegion
+	s := bufio.NewScanner(bytes.NewReader(b))
+	for s.Scan() {
+		f := strings.Fields(s.Text())
+		if len(f) < 5 {
+			continue
+		}
+		i := strings.IndexByte(f[0], '-')
+		if i < 0 || len(f[1]) != 4 {
+			return nil, errors.New("sys: invalid maps line " + strconv.Quote(s.Text()))
+		}
+		start, err1 := strconv.ParseUint(f[0][:i], 16, 64)
+		end, err2 := strconv.ParseUint(f[0][i+1:], 16, 64)
+		off, err3 := strconv.ParseUint(f[2], 16, 64)
+		if err1 != nil || err2 != nil || err3 != nil || end < start {
+			return nil, errors.New("sys: invalid maps line " + strconv.Quote(s.Text()))
+		}
+
+		r := MemoryRegion{
+			Addr:    start,
+			Size:    end - start,
+			MaxProt: VMProtAll,
+			Shared:  f[1][3] == 's',
+			Offset:  off,
+		}
+		for j, prot := range []VMProt{VMProtRead, VMProtWrite, VMProtExecute} {
+			if f[1][j] != '-' {
+				r.Prot |= prot
+			}
+		}
+		// the path, past the inode, may hold spaces
+		rest := s.Text()
+		for j := 0; j < 5 && rest != ""; j++ {
+			rest = strings.TrimLeft(rest, " ")
+			if k := strings.IndexByte(rest, ' '); k >= 0 {
+				rest = rest[k:]
+			} else {
+				rest = ""
+			}
+		}
+		r.Path = strings.TrimSpace(rest)
+		regions = append(regions, r)
+	}
+
+	return regions, s.Err()
+}
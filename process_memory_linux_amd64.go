@@ -0,0 +1,10 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys
+
+// The numbers of the system calls missing from package syscall on amd64.
+const (
+	sysProcessVMReadv  = 310
+	sysProcessVMWritev = 311
+)
@@ -0,0 +1,11 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys
+
+import "syscall"
+
+const (
+	sysProcessVMReadv  = syscall.SYS_PROCESS_VM_READV
+	sysProcessVMWritev = syscall.SYS_PROCESS_VM_WRITEV
+)
@@ -0,0 +1,129 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys_test
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/go-darwin/sys"
+	"github.com/go-darwin/sys/testenv"
+)
+
+// TestProcessMemoryLinux reads and writes the buffer of a child process,
+// testdata/memprog.
+func TestProcessMemoryLinux(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+
+	exe := filepath.Join(t.TempDir(), "memprog")
+	cmd := testenv.CleanCmdEnv(exec.Command(testenv.GoToolPath(t), "build", "-o", exe, "."))
+	cmd.Dir = "testdata/memprog"
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("building memprog: %v\n%s", err, out)
+	}
+
+	cmd = exec.Command(exe)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer cmd.Wait()
+	defer stdin.Close()
+	r := bufio.NewReader(stdout)
+	var addr uint64
+	var size int
+	if _, err := fmt.Fscanf(r, "%v %d\n", &addr, &size); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := sys.OpenProcessMemory(cmd.Process.Pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Pid() != cmd.Process.Pid {
+		t.Errorf("Pid = %d, want %d", m.Pid(), cmd.Process.Pid)
+	}
+
+	buf := make([]byte, size)
+	n, err := m.ReadAt(buf, int64(addr))
+	if errors.Is(err, syscall.EPERM) || errors.Is(err, syscall.ENOSYS) {
+		t.Skipf("skipping test: %v", err)
+	}
+	if err != nil || n != size {
+		t.Fatalf("ReadAt = %d, %v", n, err)
+	}
+	if want := "go-darwin process memory 0123456789"; string(buf) != want {
+		t.Errorf("ReadAt read %q, want %q", buf, want)
+	}
+
+	if n, err := m.WriteAt([]byte("MEMORY"), int64(addr)+18); err != nil || n != 6 {
+		t.Errorf("WriteAt = %d, %v", n, err)
+	}
+	if _, err := m.ReadAt(buf[:10], 0); !errors.Is(err, syscall.EFAULT) {
+		t.Errorf("ReadAt of address 0 = %v, want EFAULT", err)
+	} else if me := (*sys.MemoryError)(nil); !errors.As(err, &me) || me.Op != "read" || me.Pid != m.Pid() || me.Addr != 0 {
+		t.Errorf("ReadAt of address 0 = %#v", err)
+	}
+
+	regions, err := m.Regions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	found, text := false, false
+	for i, reg := range regions {
+		if i > 0 && reg.Addr < regions[i-1].End() {
+			t.Errorf("region %d at %#x overlaps the previous one", i, reg.Addr)
+		}
+		if reg.Addr <= addr && addr+uint64(size) <= reg.End() {
+			found = true
+			if reg.Prot&(sys.VMProtRead|sys.VMProtWrite) != sys.VMProtRead|sys.VMProtWrite || reg.Shared {
+				t.Errorf("region of the buffer = %+v", reg)
+			}
+		}
+		if reg.Path == exe && reg.Prot&sys.VMProtExecute != 0 {
+			text = true
+		}
+	}
+	if !found {
+		t.Errorf("no region of the buffer at %#x in %d regions", addr, len(regions))
+	}
+	if !text {
+		t.Errorf("no executable region of %s in %d regions", exe, len(regions))
+	}
+
+	if err := m.Close(); err != nil {
+		t.Errorf("Close = %v", err)
+	}
+	if err := m.Close(); err != sys.ErrMemoryClosed {
+		t.Errorf("second Close = %v, want ErrMemoryClosed", err)
+	}
+	if _, err := m.ReadAt(buf, int64(addr)); err != sys.ErrMemoryClosed {
+		t.Errorf("ReadAt after Close = %v, want ErrMemoryClosed", err)
+	}
+
+	fmt.Fprintln(stdin)
+	line, err := r.ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "go-darwin process MEMORY 0123456789\n"; line != want {
+		t.Errorf("memprog printed %q, want %q", line, want)
+	}
+
+	if _, err := sys.OpenProcessMemory(1<<31 - 1); !errors.Is(err, syscall.ESRCH) {
+		t.Errorf("OpenProcessMemory of a missing process = %v, want ESRCH", err)
+	}
+}
@@ -0,0 +1,22 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build !linux && !(darwin && amd64)
+// +build !linux
+// +build !darwin !amd64
+
+package sys
+
+import "syscall"
+
+func (m *ProcessMemory) open() error {
+	return syscall.ENOSYS
+}
+
+func (m *ProcessMemory) close() error { return nil }
+
+func (m *ProcessMemory) readAt(p []byte, addr uint64) (int, error) { return 0, syscall.ENOSYS }
+
+func (m *ProcessMemory) writeAt(p []byte, addr uint64) (int, error) { return 0, syscall.ENOSYS }
+
+func (m *ProcessMemory) regions() ([]MemoryRegion, error) { return nil, syscall.ENOSYS }
@@ -0,0 +1,38 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys_test
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+
+	"github.com/go-darwin/sys"
+)
+
+func TestMemoryError(t *testing.T) {
+	tests := []struct {
+		err  *sys.MemoryError
+		want string
+	}{
+		{&sys.MemoryError{Op: "read", Pid: 42, Addr: 0x7f0010, Err: syscall.EFAULT}, "sys: read memory of process 42 at 0x7f0010: bad address"},
+		{&sys.MemoryError{Op: "write", Pid: 42, Addr: 0x1000, Err: sys.KernProtectionFailure}, "sys: write memory of process 42 at 0x1000: " + sys.KernProtectionFailure.Error()},
+		{&sys.MemoryError{Op: "open", Pid: 7, Err: sys.KernFailure}, "sys: open memory of process 7: " + sys.KernFailure.Error()},
+	}
+	for _, tt := range tests {
+		if s := tt.err.Error(); s != tt.want {
+			t.Errorf("Error = %q, want %q", s, tt.want)
+		}
+		if !errors.Is(tt.err, tt.err.Err) {
+			t.Errorf("%v does not match %v", tt.err, tt.err.Err)
+		}
+	}
+}
+
+func TestMemoryRegionEnd(t *testing.T) {
+	r := sys.MemoryRegion{Addr: 0x100000000, Size: 0x4000}
+	if end := r.End(); end != 0x100004000 {
+		t.Errorf("End = %#x, want 0x100004000", end)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build darwin && (amd64 || arm64) && gc
+// +build darwin
+// +build amd64 arm64
+// +build gc
+
+package sys
+
+// The RawMachTrap functions invoke the Mach trap num directly, with the
+// supervisor call instruction, bypassing the trap stubs of libsystem_kernel.
+//
+// num is the plain MachTrap number, such as TrapMachReplyPort. The trap is
+// invoked as SyscallClassMach.Encode(num) in rax on amd64, and as -num in x16
+// on arm64.
+//
+// The result is the value the trap returns in rax or x0. Most traps return a
+// kern_return_t, but some return a value of another type as the KernReturn,
+// such as the mach_port_name_t returned by mach_reply_port and task_self_trap:
+//
+//	port := MachPort(RawMachTrap0(uintptr(TrapMachReplyPort)))
+//
+// Like RawCcall9, they do not notify the Go runtime of a blocking call, so
+// traps which may block, such as mach_msg_trap with a receive timeout, should
+// be called from the system stack or a locked OS thread.
+
+// RawMachTrap0 invokes the Mach trap num without argument.
+//
+//go:noescape
+//go:nosplit
+func RawMachTrap0(num uintptr) KernReturn
+
+// RawMachTrap1 invokes the Mach trap num with 1 argument.
+//
+//go:noescape
+//go:nosplit
+func RawMachTrap1(num, a1 uintptr) KernReturn
+
+// RawMachTrap2 invokes the Mach trap num with 2 arguments.
+//
+//go:noescape
+//go:nosplit
+func RawMachTrap2(num, a1, a2 uintptr) KernReturn
+
+// RawMachTrap3 invokes the Mach trap num with 3 arguments.
+//
+//go:noescape
+//go:nosplit
+func RawMachTrap3(num, a1, a2, a3 uintptr) KernReturn
+
+// RawMachTrap4 invokes the Mach trap num with 4 arguments.
+//
+//go:noescape
+//go:nosplit
+func RawMachTrap4(num, a1, a2, a3, a4 uintptr) KernReturn
+
+// RawMachTrap5 invokes the Mach trap num with 5 arguments.
+//
+//go:noescape
+//go:nosplit
+func RawMachTrap5(num, a1, a2, a3, a4, a5 uintptr) KernReturn
+
+// RawMachTrap6 invokes the Mach trap num with 6 arguments.
+//
+//go:noescape
+//go:nosplit
+func RawMachTrap6(num, a1, a2, a3, a4, a5, a6 uintptr) KernReturn
+
+// RawMachTrap7 invokes the Mach trap num with 7 arguments.
+//
+//go:noescape
+//go:nosplit
+func RawMachTrap7(num, a1, a2, a3, a4, a5, a6, a7 uintptr) KernReturn
+
+// RawMachTrap8 invokes the Mach trap num with 8 arguments.
+//
+//go:noescape
+//go:nosplit
+func RawMachTrap8(num, a1, a2, a3, a4, a5, a6, a7, a8 uintptr) KernReturn
+
+// RawMachTrap9 invokes the Mach trap num with 9 arguments.
+//
+//go:noescape
+//go:nosplit
+func RawMachTrap9(num, a1, a2, a3, a4, a5, a6, a7, a8, a9 uintptr) KernReturn
@@ -0,0 +1,146 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build darwin && gc
+// +build darwin,gc
+
+#include "textflag.h"
+
+#define SYSCALL_CLASS_MACH 0x1000000
+
+// func RawMachTrap0(num uintptr) (ret KernReturn)
+TEXT ·RawMachTrap0(SB), NOSPLIT, $0-12
+	MOVQ num+0(FP), AX
+	ADDQ $SYSCALL_CLASS_MACH, AX
+	SYSCALL
+	MOVL AX, ret+8(FP)
+	RET
+
+// func RawMachTrap1(num, a1 uintptr) (ret KernReturn)
+TEXT ·RawMachTrap1(SB), NOSPLIT, $0-20
+	MOVQ num+0(FP), AX
+	MOVQ a1+8(FP), DI
+	ADDQ $SYSCALL_CLASS_MACH, AX
+	SYSCALL
+	MOVL AX, ret+16(FP)
+	RET
+
+// func RawMachTrap2(num, a1, a2 uintptr) (ret KernReturn)
+TEXT ·RawMachTrap2(SB), NOSPLIT, $0-28
+	MOVQ num+0(FP), AX
+	MOVQ a1+8(FP), DI
+	MOVQ a2+16(FP), SI
+	ADDQ $SYSCALL_CLASS_MACH, AX
+	SYSCALL
+	MOVL AX, ret+24(FP)
+	RET
+
+// func RawMachTrap3(num, a1, a2, a3 uintptr) (ret KernReturn)
+TEXT ·RawMachTrap3(SB), NOSPLIT, $0-36
+	MOVQ num+0(FP), AX
+	MOVQ a1+8(FP), DI
+	MOVQ a2+16(FP), SI
+	MOVQ a3+24(FP), DX
+	ADDQ $SYSCALL_CLASS_MACH, AX
+	SYSCALL
+	MOVL AX, ret+32(FP)
+	RET
+
+// func RawMachTrap4(num, a1, a2, a3, a4 uintptr) (ret KernReturn)
+TEXT ·RawMachTrap4(SB), NOSPLIT, $0-44
+	MOVQ num+0(FP), AX
+	MOVQ a1+8(FP), DI
+	MOVQ a2+16(FP), SI
+	MOVQ a3+24(FP), DX
+	MOVQ a4+32(FP), R10
+	ADDQ $SYSCALL_CLASS_MACH, AX
+	SYSCALL
+	MOVL AX, ret+40(FP)
+	RET
+
+// func RawMachTrap5(num, a1, a2, a3, a4, a5 uintptr) (ret KernReturn)
+TEXT ·RawMachTrap5(SB), NOSPLIT, $0-52
+	MOVQ num+0(FP), AX
+	MOVQ a1+8(FP), DI
+	MOVQ a2+16(FP), SI
+	MOVQ a3+24(FP), DX
+	MOVQ a4+32(FP), R10
+	MOVQ a5+40(FP), R8
+	ADDQ $SYSCALL_CLASS_MACH, AX
+	SYSCALL
+	MOVL AX, ret+48(FP)
+	RET
+
+// func RawMachTrap6(num, a1, a2, a3, a4, a5, a6 uintptr) (ret KernReturn)
+TEXT ·RawMachTrap6(SB), NOSPLIT, $0-60
+	MOVQ num+0(FP), AX
+	MOVQ a1+8(FP), DI
+	MOVQ a2+16(FP), SI
+	MOVQ a3+24(FP), DX
+	MOVQ a4+32(FP), R10
+	MOVQ a5+40(FP), R8
+	MOVQ a6+48(FP), R9
+	ADDQ $SYSCALL_CLASS_MACH, AX
+	SYSCALL
+	MOVL AX, ret+56(FP)
+	RET
+
+// func RawMachTrap7(num, a1, a2, a3, a4, a5, a6, a7 uintptr) (ret KernReturn)
+TEXT ·RawMachTrap7(SB), NOSPLIT, $0-68
+	MOVQ num+0(FP), AX
+	MOVQ a1+8(FP), DI
+	MOVQ a2+16(FP), SI
+	MOVQ a3+24(FP), DX
+	MOVQ a4+32(FP), R10
+	MOVQ a5+40(FP), R8
+	MOVQ a6+48(FP), R9
+	MOVQ a7+56(FP), R11
+	SUBQ $32, SP
+	MOVQ R11, 8(SP)
+	ADDQ $SYSCALL_CLASS_MACH, AX
+	SYSCALL
+	ADDQ $32, SP
+	MOVL AX, ret+64(FP)
+	RET
+
+// func RawMachTrap8(num, a1, a2, a3, a4, a5, a6, a7, a8 uintptr) (ret KernReturn)
+TEXT ·RawMachTrap8(SB), NOSPLIT, $0-76
+	MOVQ num+0(FP), AX
+	MOVQ a1+8(FP), DI
+	MOVQ a2+16(FP), SI
+	MOVQ a3+24(FP), DX
+	MOVQ a4+32(FP), R10
+	MOVQ a5+40(FP), R8
+	MOVQ a6+48(FP), R9
+	MOVQ a7+56(FP), R11
+	MOVQ a8+64(FP), R12
+	SUBQ $32, SP
+	MOVQ R11, 8(SP)
+	MOVQ R12, 16(SP)
+	ADDQ $SYSCALL_CLASS_MACH, AX
+	SYSCALL
+	ADDQ $32, SP
+	MOVL AX, ret+72(FP)
+	RET
+
+// func RawMachTrap9(num, a1, a2, a3, a4, a5, a6, a7, a8, a9 uintptr) (ret KernReturn)
+TEXT ·RawMachTrap9(SB), NOSPLIT, $0-84
+	MOVQ num+0(FP), AX
+	MOVQ a1+8(FP), DI
+	MOVQ a2+16(FP), SI
+	MOVQ a3+24(FP), DX
+	MOVQ a4+32(FP), R10
+	MOVQ a5+40(FP), R8
+	MOVQ a6+48(FP), R9
+	MOVQ a7+56(FP), R11
+	MOVQ a8+64(FP), R12
+	MOVQ a9+72(FP), R13
+	SUBQ $32, SP
+	MOVQ R11, 8(SP)
+	MOVQ R12, 16(SP)
+	MOVQ R13, 24(SP)
+	ADDQ $SYSCALL_CLASS_MACH, AX
+	SYSCALL
+	ADDQ $32, SP
+	MOVL AX, ret+80(FP)
+	RET
@@ -0,0 +1,135 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build darwin && gc
+// +build darwin,gc
+
+#include "textflag.h"
+
+// func RawMachTrap0(num uintptr) (ret KernReturn)
+TEXT ·RawMachTrap0(SB), NOSPLIT, $0-12
+	MOVD num+0(FP), R16
+	NEG R16, R16
+	SVC $0x80
+	MOVW R0, ret+8(FP)
+	RET
+
+// func RawMachTrap1(num, a1 uintptr) (ret KernReturn)
+TEXT ·RawMachTrap1(SB), NOSPLIT, $0-20
+	MOVD num+0(FP), R16
+	MOVD a1+8(FP), R0
+	NEG R16, R16
+	SVC $0x80
+	MOVW R0, ret+16(FP)
+	RET
+
+// func RawMachTrap2(num, a1, a2 uintptr) (ret KernReturn)
+TEXT ·RawMachTrap2(SB), NOSPLIT, $0-28
+	MOVD num+0(FP), R16
+	MOVD a1+8(FP), R0
+	MOVD a2+16(FP), R1
+	NEG R16, R16
+	SVC $0x80
+	MOVW R0, ret+24(FP)
+	RET
+
+// func RawMachTrap3(num, a1, a2, a3 uintptr) (ret KernReturn)
+TEXT ·RawMachTrap3(SB), NOSPLIT, $0-36
+	MOVD num+0(FP), R16
+	MOVD a1+8(FP), R0
+	MOVD a2+16(FP), R1
+	MOVD a3+24(FP), R2
+	NEG R16, R16
+	SVC $0x80
+	MOVW R0, ret+32(FP)
+	RET
+
+// func RawMachTrap4(num, a1, a2, a3, a4 uintptr) (ret KernReturn)
+TEXT ·RawMachTrap4(SB), NOSPLIT, $0-44
+	MOVD num+0(FP), R16
+	MOVD a1+8(FP), R0
+	MOVD a2+16(FP), R1
+	MOVD a3+24(FP), R2
+	MOVD a4+32(FP), R3
+	NEG R16, R16
+	SVC $0x80
+	MOVW R0, ret+40(FP)
+	RET
+
+// func RawMachTrap5(num, a1, a2, a3, a4, a5 uintptr) (ret KernReturn)
+TEXT ·RawMachTrap5(SB), NOSPLIT, $0-52
+	MOVD num+0(FP), R16
+	MOVD a1+8(FP), R0
+	MOVD a2+16(FP), R1
+	MOVD a3+24(FP), R2
+	MOVD a4+32(FP), R3
+	MOVD a5+40(FP), R4
+	NEG R16, R16
+	SVC $0x80
+	MOVW R0, ret+48(FP)
+	RET
+
+// func RawMachTrap6(num, a1, a2, a3, a4, a5, a6 uintptr) (ret KernReturn)
+TEXT ·RawMachTrap6(SB), NOSPLIT, $0-60
+	MOVD num+0(FP), R16
+	MOVD a1+8(FP), R0
+	MOVD a2+16(FP), R1
+	MOVD a3+24(FP), R2
+	MOVD a4+32(FP), R3
+	MOVD a5+40(FP), R4
+	MOVD a6+48(FP), R5
+	NEG R16, R16
+	SVC $0x80
+	MOVW R0, ret+56(FP)
+	RET
+
+// func RawMachTrap7(num, a1, a2, a3, a4, a5, a6, a7 uintptr) (ret KernReturn)
+TEXT ·RawMachTrap7(SB), NOSPLIT, $0-68
+	MOVD num+0(FP), R16
+	MOVD a1+8(FP), R0
+	MOVD a2+16(FP), R1
+	MOVD a3+24(FP), R2
+	MOVD a4+32(FP), R3
+	MOVD a5+40(FP), R4
+	MOVD a6+48(FP), R5
+	MOVD a7+56(FP), R6
+	NEG R16, R16
+	SVC $0x80
+	MOVW R0, ret+64(FP)
+	RET
+
+// func RawMachTrap8(num, a1, a2, a3, a4, a5, a6, a7, a8 uintptr) (ret KernReturn)
+TEXT ·RawMachTrap8(SB), NOSPLIT, $0-76
+	MOVD num+0(FP), R16
+	MOVD a1+8(FP), R0
+	MOVD a2+16(FP), R1
+	MOVD a3+24(FP), R2
+	MOVD a4+32(FP), R3
+	MOVD a5+40(FP), R4
+	MOVD a6+48(FP), R5
+	MOVD a7+56(FP), R6
+	MOVD a8+64(FP), R7
+	NEG R16, R16
+	SVC $0x80
+	MOVW R0, ret+72(FP)
+	RET
+
+// func RawMachTrap9(num, a1, a2, a3, a4, a5, a6, a7, a8, a9 uintptr) (ret KernReturn)
+//
+// The kernel reads the arguments of the Mach traps from x0-x8 only, never
+// from the stack: the 9th one goes in R8.
+TEXT ·RawMachTrap9(SB), NOSPLIT, $0-84
+	MOVD num+0(FP), R16
+	MOVD a1+8(FP), R0
+	MOVD a2+16(FP), R1
+	MOVD a3+24(FP), R2
+	MOVD a4+32(FP), R3
+	MOVD a5+40(FP), R4
+	MOVD a6+48(FP), R5
+	MOVD a7+56(FP), R6
+	MOVD a8+64(FP), R7
+	MOVD a9+72(FP), R8
+	NEG R16, R16
+	SVC $0x80
+	MOVW R0, ret+80(FP)
+	RET
@@ -0,0 +1,82 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build darwin && (amd64 || arm64) && gc
+// +build darwin
+// +build amd64 arm64
+// +build gc
+
+package sys_test
+
+import (
+	"encoding/binary"
+	"runtime"
+	"testing"
+	"unsafe"
+
+	"github.com/go-darwin/sys"
+)
+
+func TestRawMachTrap(t *testing.T) {
+	task := sys.RawMachTrap0(uintptr(sys.TrapTaskSelf))
+	if task == 0 {
+		t.Fatal("task_self_trap returned MACH_PORT_NULL")
+	}
+
+	reply := sys.RawMachTrap0(uintptr(sys.TrapMachReplyPort))
+	if reply == 0 {
+		t.Fatal("mach_reply_port returned MACH_PORT_NULL")
+	}
+	// mach_port_mod_refs(task, reply, MACH_PORT_RIGHT_RECEIVE, -1)
+	if kr := sys.RawMachTrap4(uintptr(sys.TrapMachPortModRefs), uintptr(task), uintptr(reply), 1, ^uintptr(0)); kr != sys.KernSuccess {
+		t.Errorf("_kernelrpc_mach_port_mod_refs_trap = %v", kr)
+	}
+
+	// thread_switch(MACH_PORT_NULL, SWITCH_OPTION_NONE, 0)
+	if kr := sys.RawMachTrap3(uintptr(sys.TrapThreadSwitch), 0, 0, 0); kr != sys.KernSuccess {
+		t.Errorf("thread_switch = %v", kr)
+	}
+}
+
+// TestRawMachTrap9 checks the order of the 9 arguments with
+// mach_msg_overwrite_trap, whose 9th argument is the receive buffer: the
+// message sent from msg to the reply port must be received in rcv.
+func TestRawMachTrap9(t *testing.T) {
+	task := sys.RawMachTrap0(uintptr(sys.TrapTaskSelf))
+	reply := sys.RawMachTrap0(uintptr(sys.TrapMachReplyPort))
+	if reply == 0 {
+		t.Fatal("mach_reply_port returned MACH_PORT_NULL")
+	}
+	defer sys.RawMachTrap4(uintptr(sys.TrapMachPortModRefs), uintptr(task), uintptr(reply), 1, ^uintptr(0))
+
+	l, ok := sys.HostMachMsgLayout()
+	if !ok {
+		t.Skip("no Mach message layout")
+	}
+	const id = 0x5a17
+	msg, err := l.Marshal(&sys.MachMsg{
+		Header: sys.MachMsgHeader{
+			Bits:       sys.MakeMachMsgBits(sys.MachMsgTypeMakeSend, 0, 0),
+			RemotePort: sys.MachPort(reply),
+			ID:         id,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rcv := make([]byte, 256)
+
+	// MACH_SEND_MSG|MACH_RCV_MSG|MACH_SEND_TIMEOUT|MACH_RCV_TIMEOUT
+	const option = 0x1 | 0x2 | 0x10 | 0x100
+	kr := sys.RawMachTrap9(uintptr(sys.TrapMachMsgOverwrite),
+		uintptr(unsafe.Pointer(&msg[0])), option, uintptr(len(msg)), uintptr(len(rcv)),
+		uintptr(reply), 0, 0, 0, uintptr(unsafe.Pointer(&rcv[0])))
+	runtime.KeepAlive(msg)
+	runtime.KeepAlive(rcv)
+	if kr != sys.KernSuccess {
+		t.Fatalf("mach_msg_overwrite_trap = %v", kr)
+	}
+	if got := int32(binary.LittleEndian.Uint32(rcv[20:])); got != id {
+		t.Errorf("received msgh_id %#x in the 9th argument, want %#x", got, id)
+	}
+}
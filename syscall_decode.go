@@ -0,0 +1,533 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys
+
+import (
+	"errors"
+	"io"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+//go:generate go run ./internal/mklinuxsysnum
+
+// SyscallCall records a syscall and its results.
+type SyscallCall struct {
+	// Num is the syscall number, as passed to the syscall entry, such as the
+	// first argument of RawCcall9.
+	Num uintptr
+
+	// Args are the raw arguments of the syscall.
+	Args []uintptr
+
+	// R1 is the first result of the syscall.
+	R1 uintptr
+
+	// Err is the errno of the syscall, zero on success.
+	Err uintptr
+}
+
+// argKind is the kind of a syscall argument or result, which selects how
+// SyscallDecoder renders it.
+type argKind uint8
+
+const (
+	argHex        argKind = iota // hexadecimal number
+	argInt                       // signed decimal number
+	argUint                      // unsigned decimal number
+	argPtr                       // pointer, NULL when zero
+	argFD                        // file descriptor
+	argDirFD                     // directory file descriptor, or AT_FDCWD
+	argMode                      // octal file mode
+	argOpenFlags                 // O_* flags
+	argProt                      // PROT_* flags
+	argMapFlags                  // MAP_* flags
+	argKernReturn                // kern_return_t
+)
+
+// syscallFormat describes how to render a syscall.
+type syscallFormat struct {
+	ret  argKind
+	args []argKind
+}
+
+// syscallFormats are the formats of the syscalls, by name. The names are
+// shared by darwin and linux, which pass the same arguments to the syscalls of
+// the same name.
+var syscallFormats = map[string]syscallFormat{
+	"read":            {argInt, []argKind{argFD, argPtr, argUint}},
+	"write":           {argInt, []argKind{argFD, argPtr, argUint}},
+	"read_nocancel":   {argInt, []argKind{argFD, argPtr, argUint}},
+	"write_nocancel":  {argInt, []argKind{argFD, argPtr, argUint}},
+	"pread":           {argInt, []argKind{argFD, argPtr, argUint, argInt}},
+	"pwrite":          {argInt, []argKind{argFD, argPtr, argUint, argInt}},
+	"pread64":         {argInt, []argKind{argFD, argPtr, argUint, argInt}},
+	"pwrite64":        {argInt, []argKind{argFD, argPtr, argUint, argInt}},
+	"readv":           {argInt, []argKind{argFD, argPtr, argInt}},
+	"writev":          {argInt, []argKind{argFD, argPtr, argInt}},
+	"open":            {argFD, []argKind{argPtr, argOpenFlags, argMode}},
+	"open_nocancel":   {argFD, []argKind{argPtr, argOpenFlags, argMode}},
+	"openat":          {argFD, []argKind{argDirFD, argPtr, argOpenFlags, argMode}},
+	"openat_nocancel": {argFD, []argKind{argDirFD, argPtr, argOpenFlags, argMode}},
+	"creat":           {argFD, []argKind{argPtr, argMode}},
+	"shm_open":        {argFD, []argKind{argPtr, argOpenFlags, argMode}},
+	"close":           {argInt, []argKind{argFD}},
+	"close_nocancel":  {argInt, []argKind{argFD}},
+	"dup":             {argFD, []argKind{argFD}},
+	"dup2":            {argFD, []argKind{argFD, argFD}},
+	"dup3":            {argFD, []argKind{argFD, argFD, argOpenFlags}},
+	"pipe2":           {argInt, []argKind{argPtr, argOpenFlags}},
+	"lseek":           {argInt, []argKind{argFD, argInt, argInt}},
+	"fsync":           {argInt, []argKind{argFD}},
+	"fdatasync":       {argInt, []argKind{argFD}},
+	"fchdir":          {argInt, []argKind{argFD}},
+	"truncate":        {argInt, []argKind{argPtr, argInt}},
+	"ftruncate":       {argInt, []argKind{argFD, argInt}},
+	"stat":            {argInt, []argKind{argPtr, argPtr}},
+	"lstat":           {argInt, []argKind{argPtr, argPtr}},
+	"stat64":          {argInt, []argKind{argPtr, argPtr}},
+	"lstat64":         {argInt, []argKind{argPtr, argPtr}},
+	"fstat":           {argInt, []argKind{argFD, argPtr}},
+	"fstat64":         {argInt, []argKind{argFD, argPtr}},
+	"fstatat":         {argInt, []argKind{argDirFD, argPtr, argPtr, argHex}},
+	"fstatat64":       {argInt, []argKind{argDirFD, argPtr, argPtr, argHex}},
+	"newfstatat":      {argInt, []argKind{argDirFD, argPtr, argPtr, argHex}},
+	"access":          {argInt, []argKind{argPtr, argHex}},
+	"faccessat":       {argInt, []argKind{argDirFD, argPtr, argHex, argHex}},
+	"chdir":           {argInt, []argKind{argPtr}},
+	"chroot":          {argInt, []argKind{argPtr}},
+	"unlink":          {argInt, []argKind{argPtr}},
+	"rmdir":           {argInt, []argKind{argPtr}},
+	"unlinkat":        {argInt, []argKind{argDirFD, argPtr, argHex}},
+	"mkdir":           {argInt, []argKind{argPtr, argMode}},
+	"mkdirat":         {argInt, []argKind{argDirFD, argPtr, argMode}},
+	"chmod":           {argInt, []argKind{argPtr, argMode}},
+	"fchmod":          {argInt, []argKind{argFD, argMode}},
+	"fchmodat":        {argInt, []argKind{argDirFD, argPtr, argMode, argHex}},
+	"readlink":        {argInt, []argKind{argPtr, argPtr, argUint}},
+	"readlinkat":      {argInt, []argKind{argDirFD, argPtr, argPtr, argUint}},
+	"mmap":            {argPtr, []argKind{argPtr, argUint, argProt, argMapFlags, argFD, argHex}},
+	"munmap":          {argInt, []argKind{argPtr, argUint}},
+	"mprotect":        {argInt, []argKind{argPtr, argUint, argProt}},
+	"madvise":         {argInt, []argKind{argPtr, argUint, argInt}},
+	"msync":           {argInt, []argKind{argPtr, argUint, argHex}},
+	"mlock":           {argInt, []argKind{argPtr, argUint}},
+	"munlock":         {argInt, []argKind{argPtr, argUint}},
+	"mincore":         {argInt, []argKind{argPtr, argUint, argPtr}},
+	"ioctl":           {argInt, []argKind{argFD, argHex, argPtr}},
+	"fcntl":           {argInt, []argKind{argFD, argInt, argHex}},
+	"fcntl_nocancel":  {argInt, []argKind{argFD, argInt, argHex}},
+	"socket":          {argFD, []argKind{argInt, argInt, argInt}},
+	"connect":         {argInt, []argKind{argFD, argPtr, argUint}},
+	"bind":            {argInt, []argKind{argFD, argPtr, argUint}},
+	"listen":          {argInt, []argKind{argFD, argInt}},
+	"accept":          {argFD, []argKind{argFD, argPtr, argPtr}},
+	"execve":          {argInt, []argKind{argPtr, argPtr, argPtr}},
+	"exit":            {argInt, []argKind{argInt}},
+	"exit_group":      {argInt, []argKind{argInt}},
+	"kill":            {argInt, []argKind{argInt, argInt}},
+	"wait4":           {argInt, []argKind{argInt, argPtr, argHex, argPtr}},
+	"getentropy":      {argInt, []argKind{argPtr, argUint}},
+	"getrandom":       {argInt, []argKind{argPtr, argUint, argHex}},
+	"sysctl":          {argInt, []argKind{argPtr, argUint, argPtr, argPtr, argPtr, argUint}},
+	"sysctlbyname":    {argInt, []argKind{argPtr, argUint, argPtr, argPtr, argPtr, argUint}},
+
+	// Mach traps returning a port name rather than a kern_return_t.
+	"mach_reply_port":               {argHex, nil},
+	"thread_self_trap":              {argHex, nil},
+	"task_self_trap":                {argHex, nil},
+	"host_self_trap":                {argHex, nil},
+	"thread_get_special_reply_port": {argHex, nil},
+}
+
+// flag is a named flag or value of a flagSet.
+type flag struct {
+	name  string
+	value uintptr
+}
+
+// flagSet describes a set of flags, such as the O_* flags of open.
+type flagSet struct {
+	mask   uintptr // mask of the enumerated field, if any
+	values []flag  // values of the enumerated field
+	bits   []flag  // bit flags, the composite flags before their parts
+	zero   string  // rendering of zero, when there is no field
+}
+
+// append appends the rendering of the flags v, such as "O_RDWR|O_CREAT", to b.
+func (fs *flagSet) append(b []byte, v uintptr) []byte {
+	n := 0
+	sep := func() {
+		if n > 0 {
+			b = append(b, '|')
+		}
+		n++
+	}
+
+	if fs.mask != 0 {
+		field := v & fs.mask
+		v &^= fs.mask
+		name := ""
+		for _, f := range fs.values {
+			if f.value == field {
+				name = f.name
+				break
+			}
+		}
+		switch {
+		case name != "":
+			sep()
+			b = append(b, name...)
+		case field != 0:
+			sep()
+			b = appendHex(b, field)
+		}
+	}
+
+	for _, f := range fs.bits {
+		if v&f.value == f.value {
+			sep()
+			b = append(b, f.name...)
+			v &^= f.value
+		}
+	}
+
+	switch {
+	case v != 0:
+		sep()
+		b = appendHex(b, v)
+	case n == 0 && fs.zero != "":
+		b = append(b, fs.zero...)
+	case n == 0:
+		b = append(b, '0')
+	}
+
+	return b
+}
+
+var accmodeValues = []flag{{"O_RDONLY", 0x0}, {"O_WRONLY", 0x1}, {"O_RDWR", 0x2}}
+
+var protFlags = flagSet{
+	bits: []flag{{"PROT_READ", 0x1}, {"PROT_WRITE", 0x2}, {"PROT_EXEC", 0x4}},
+	zero: "PROT_NONE",
+}
+
+var darwinOpenFlags = flagSet{
+	mask:   0x3,
+	values: accmodeValues,
+	bits: []flag{
+		{"O_NONBLOCK", 0x4}, {"O_APPEND", 0x8}, {"O_SHLOCK", 0x10}, {"O_EXLOCK", 0x20},
+		{"O_ASYNC", 0x40}, {"O_SYNC", 0x80}, {"O_NOFOLLOW", 0x100}, {"O_CREAT", 0x200},
+		{"O_TRUNC", 0x400}, {"O_EXCL", 0x800}, {"O_EVTONLY", 0x8000}, {"O_NOCTTY", 0x20000},
+		{"O_DIRECTORY", 0x100000}, {"O_SYMLINK", 0x200000}, {"O_DSYNC", 0x400000},
+		{"O_CLOEXEC", 0x1000000}, {"O_NOFOLLOW_ANY", 0x20000000},
+	},
+}
+
+var darwinMapFlags = flagSet{
+	bits: []flag{
+		{"MAP_SHARED", 0x1}, {"MAP_PRIVATE", 0x2}, {"MAP_FIXED", 0x10}, {"MAP_RENAME", 0x20},
+		{"MAP_NORESERVE", 0x40}, {"MAP_NOEXTEND", 0x100}, {"MAP_HASSEMAPHORE", 0x200},
+		{"MAP_NOCACHE", 0x400}, {"MAP_JIT", 0x800}, {"MAP_ANON", 0x1000},
+		{"MAP_RESILIENT_CODESIGN", 0x2000}, {"MAP_RESILIENT_MEDIA", 0x4000}, {"MAP_32BIT", 0x8000},
+		{"MAP_TRANSLATED_ALLOW_EXECUTE", 0x20000}, {"MAP_UNIX03", 0x40000},
+	},
+}
+
+// linuxOpenFlags returns the O_* flags of linux, whose values of O_DIRECT,
+// O_LARGEFILE, O_DIRECTORY and O_NOFOLLOW depend on goarch.
+func linuxOpenFlags(goarch string) flagSet {
+	direct, largefile, directory, nofollow := uintptr(0x4000), uintptr(0x8000), uintptr(0x10000), uintptr(0x20000)
+	if goarch == "arm64" {
+		directory, nofollow, direct, largefile = 0x4000, 0x8000, 0x10000, 0x20000
+	}
+
+	return flagSet{
+		mask:   0x3,
+		values: accmodeValues,
+		bits: []flag{
+			{"O_SYNC", 0x101000}, {"O_TMPFILE", 0x400000 | directory},
+			{"O_CREAT", 0x40}, {"O_EXCL", 0x80}, {"O_NOCTTY", 0x100}, {"O_TRUNC", 0x200},
+			{"O_APPEND", 0x400}, {"O_NONBLOCK", 0x800}, {"O_DSYNC", 0x1000}, {"O_ASYNC", 0x2000},
+			{"O_DIRECT", direct}, {"O_LARGEFILE", largefile}, {"O_DIRECTORY", directory},
+			{"O_NOFOLLOW", nofollow}, {"O_NOATIME", 0x40000}, {"O_CLOEXEC", 0x80000},
+			{"O_PATH", 0x200000},
+		},
+	}
+}
+
+// linuxMapFlags returns the MAP_* flags of linux for goarch.
+func linuxMapFlags(goarch string) flagSet {
+	fs := flagSet{
+		mask:   0x3,
+		values: []flag{{"MAP_SHARED", 0x1}, {"MAP_PRIVATE", 0x2}, {"MAP_SHARED_VALIDATE", 0x3}},
+		bits: []flag{
+			{"MAP_FIXED", 0x10}, {"MAP_ANONYMOUS", 0x20}, {"MAP_GROWSDOWN", 0x100},
+			{"MAP_DENYWRITE", 0x800}, {"MAP_EXECUTABLE", 0x1000}, {"MAP_LOCKED", 0x2000},
+			{"MAP_NORESERVE", 0x4000}, {"MAP_POPULATE", 0x8000}, {"MAP_NONBLOCK", 0x10000},
+			{"MAP_STACK", 0x20000}, {"MAP_HUGETLB", 0x40000}, {"MAP_SYNC", 0x80000},
+			{"MAP_FIXED_NOREPLACE", 0x100000},
+		},
+	}
+	if goarch == "amd64" {
+		fs.bits = append(fs.bits, flag{"MAP_32BIT", 0x40})
+	}
+
+	return fs
+}
+
+// SyscallDecoder renders the syscalls of a GOOS and GOARCH as strace-style
+// lines, such as:
+//
+//	openat(AT_FDCWD, 0xc000014090, O_RDONLY|O_CLOEXEC, 0) = -1 ENOENT (no such file or directory)
+//
+// The syscall numbers, the flag values and the errno values are those of the
+// decoded GOOS and GOARCH, whatever the host is. The pointer arguments are
+// rendered as addresses, and never dereferenced.
+type SyscallDecoder struct {
+	goos      string
+	names     []string // linux syscall names
+	atFDCWD   int32
+	openFlags flagSet
+	mapFlags  flagSet
+}
+
+// errUnsupportedSyscallABI is returned for a GOOS and GOARCH without decoder.
+var errUnsupportedSyscallABI = errors.New("sys: no syscall decoder for GOOS/GOARCH")
+
+// NewSyscallDecoder returns the SyscallDecoder of the syscalls of goos and
+// goarch. The supported pairs are darwin and linux on amd64 and arm64.
+func NewSyscallDecoder(goos, goarch string) (*SyscallDecoder, error) {
+	if goarch != "amd64" && goarch != "arm64" {
+		return nil, errUnsupportedSyscallABI
+	}
+
+	switch goos {
+	case "darwin":
+		return &SyscallDecoder{
+			goos:      goos,
+			atFDCWD:   -2,
+			openFlags: darwinOpenFlags,
+			mapFlags:  darwinMapFlags,
+		}, nil
+	case "linux":
+		d := &SyscallDecoder{
+			goos:      goos,
+			names:     linuxSyscallNamesAMD64[:],
+			atFDCWD:   -100,
+			openFlags: linuxOpenFlags(goarch),
+			mapFlags:  linuxMapFlags(goarch),
+		}
+		if goarch == "arm64" {
+			d.names = linuxSyscallNamesARM64[:]
+		}
+		return d, nil
+	}
+
+	return nil, errUnsupportedSyscallABI
+}
+
+// lookup returns the name of the syscall num, its number of arguments, or -1
+// if unknown, and whether it is a Mach trap.
+func (d *SyscallDecoder) lookup(num uintptr) (name string, nargs int, mach bool) {
+	if d.goos == "linux" {
+		if num < uintptr(len(d.names)) && d.names[num] != "" {
+			return d.names[num], -1, false
+		}
+		return "syscall_" + uitoa(uint(num)), -1, false
+	}
+
+	// Mach traps are negative on arm64.
+	if int(num) < 0 {
+		return MachTrap(-int(num)).String(), -1, true
+	}
+
+	switch class, n := DecodeSyscall(num); class {
+	case SyscallClassNone, SyscallClassUnix:
+		if s, ok := SyscallByNumber(n); ok {
+			return s.Name, len(s.Args), false
+		}
+		return "syscall_" + itoa(n), -1, false
+	case SyscallClassMach:
+		return MachTrap(n).String(), -1, true
+	}
+
+	return "syscall_" + string(appendHex(nil, num)), -1, false
+}
+
+// Name returns the name of the syscall num, such as "openat", or the C name of
+// the Mach trap num on darwin, such as "mach_msg_trap".
+func (d *SyscallDecoder) Name(num uintptr) string {
+	name, _, _ := d.lookup(num)
+	return name
+}
+
+// Format returns the strace-style line of the syscall c, without newline.
+func (d *SyscallDecoder) Format(c *SyscallCall) string {
+	return string(d.append(make([]byte, 0, 128), c))
+}
+
+func (d *SyscallDecoder) append(b []byte, c *SyscallCall) []byte {
+	name, nargs, mach := d.lookup(c.Num)
+	format, ok := syscallFormats[name]
+	switch {
+	case !ok && mach:
+		format.ret = argKernReturn
+	case !ok:
+		format.ret = argInt
+	}
+	if nargs < 0 {
+		nargs = len(c.Args)
+		if ok && len(format.args) < nargs {
+			nargs = len(format.args)
+		}
+	}
+
+	b = append(b, name...)
+	b = append(b, '(')
+	for i := 0; i < nargs && i < len(c.Args); i++ {
+		if i > 0 {
+			b = append(b, ", "...)
+		}
+		kind := argHex
+		if i < len(format.args) {
+			kind = format.args[i]
+		}
+		b = d.appendArg(b, kind, c.Args[i])
+	}
+	b = append(b, ") = "...)
+
+	if c.Err != 0 {
+		b = append(b, "-1 "...)
+		name, msg := d.errno(c.Err)
+		b = append(b, name...)
+		if msg != "" {
+			b = append(b, " ("...)
+			b = append(b, msg...)
+			b = append(b, ')')
+		}
+		return b
+	}
+
+	return d.appendArg(b, format.ret, c.R1)
+}
+
+// appendArg appends the rendering of the argument or result v of kind to b.
+func (d *SyscallDecoder) appendArg(b []byte, kind argKind, v uintptr) []byte {
+	switch kind {
+	case argInt:
+		return strconv.AppendInt(b, int64(v), 10)
+	case argUint:
+		return strconv.AppendUint(b, uint64(v), 10)
+	case argPtr:
+		if v == 0 {
+			return append(b, "NULL"...)
+		}
+		return appendHex(b, v)
+	case argFD:
+		return strconv.AppendInt(b, int64(int32(v)), 10)
+	case argDirFD:
+		if int32(v) == d.atFDCWD {
+			return append(b, "AT_FDCWD"...)
+		}
+		return strconv.AppendInt(b, int64(int32(v)), 10)
+	case argMode:
+		if v == 0 {
+			return append(b, '0')
+		}
+		return strconv.AppendUint(append(b, '0'), uint64(v), 8)
+	case argOpenFlags:
+		return d.openFlags.append(b, v)
+	case argProt:
+		return protFlags.append(b, v)
+	case argMapFlags:
+		return d.mapFlags.append(b, v)
+	case argKernReturn:
+		return append(b, KernReturn(int32(v)).String()...)
+	}
+
+	return appendHex(b, v)
+}
+
+// errno returns the name and the message of the errno e.
+func (d *SyscallDecoder) errno(e uintptr) (name, msg string) {
+	if d.goos == "darwin" {
+		if e < uintptr(len(darwinErrnoNames)) && darwinErrnoNames[e] != "" {
+			return darwinErrnoNames[e], darwinErrors[e]
+		}
+	} else if e < uintptr(len(linuxErrnoNames)) && linuxErrnoNames[e] != "" {
+		return linuxErrnoNames[e], linuxErrors[e]
+	}
+
+	return "errno " + uitoa(uint(e)), ""
+}
+
+// appendHex appends v in hexadecimal with the 0x prefix, or 0, to b.
+func appendHex(b []byte, v uintptr) []byte {
+	if v == 0 {
+		return append(b, '0')
+	}
+
+	return strconv.AppendUint(append(b, "0x"...), uint64(v), 16)
+}
+
+// syscallTraceOn is non-zero while a syscall trace is set, so that the traced
+// calls check it without locking.
+var syscallTraceOn uint32
+
+var syscallTrace struct {
+	sync.Mutex
+	w   io.Writer
+	d   *SyscallDecoder
+	buf []byte
+}
+
+// SetSyscallTrace sets w as the syscall trace, to which TraceSyscall writes
+// one decoded line per syscall, decoded for the host GOOS and GOARCH. The
+// syscalls made by TracedCcall9 are traced. A nil w stops the tracing.
+//
+// It returns an error if there is no SyscallDecoder for the host.
+func SetSyscallTrace(w io.Writer) error {
+	var d *SyscallDecoder
+	if w != nil {
+		var err error
+		if d, err = NewSyscallDecoder(runtime.GOOS, runtime.GOARCH); err != nil {
+			return err
+		}
+	}
+
+	syscallTrace.Lock()
+	syscallTrace.w, syscallTrace.d = w, d
+	if w != nil {
+		atomic.StoreUint32(&syscallTraceOn, 1)
+	} else {
+		atomic.StoreUint32(&syscallTraceOn, 0)
+	}
+	syscallTrace.Unlock()
+
+	return nil
+}
+
+// TraceSyscall writes the decoded line of the syscall c to the syscall trace,
+// if one is set by SetSyscallTrace.
+//
+// It lets the callers of the other raw syscall entry points, such as
+// syscall.RawSyscall6, trace their syscalls like TracedCcall9 does.
+func TraceSyscall(c *SyscallCall) {
+	if atomic.LoadUint32(&syscallTraceOn) == 0 {
+		return
+	}
+
+	syscallTrace.Lock()
+	defer syscallTrace.Unlock()
+	if syscallTrace.w == nil {
+		return
+	}
+	b := syscallTrace.d.append(syscallTrace.buf[:0], c)
+	b = append(b, '\n')
+	syscallTrace.w.Write(b) //nolint:errcheck
+	syscallTrace.buf = b
+}
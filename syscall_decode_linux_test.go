@@ -0,0 +1,40 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build linux && (amd64 || arm64)
+// +build linux
+// +build amd64 arm64
+
+package sys_test
+
+import (
+	"bytes"
+	"strconv"
+	"syscall"
+	"testing"
+
+	"github.com/go-darwin/sys"
+)
+
+func TestTraceSyscall(t *testing.T) {
+	var buf bytes.Buffer
+	if err := sys.SetSyscallTrace(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	pid, _, errno := syscall.RawSyscall(syscall.SYS_GETPID, 0, 0, 0)
+	sys.TraceSyscall(&sys.SyscallCall{Num: syscall.SYS_GETPID, R1: pid, Err: uintptr(errno)})
+	_, _, errno = syscall.RawSyscall(syscall.SYS_CLOSE, ^uintptr(0), 0, 0)
+	sys.TraceSyscall(&sys.SyscallCall{Num: syscall.SYS_CLOSE, Args: []uintptr{^uintptr(0)}, Err: uintptr(errno)})
+
+	if err := sys.SetSyscallTrace(nil); err != nil {
+		t.Fatal(err)
+	}
+	sys.TraceSyscall(&sys.SyscallCall{Num: syscall.SYS_GETPID, R1: pid})
+
+	want := "getpid() = " + strconv.Itoa(int(pid)) + "\n" +
+		"close(-1) = -1 EBADF (bad file descriptor)\n"
+	if got := buf.String(); got != want {
+		t.Errorf("trace = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys_test
+
+import (
+	"testing"
+
+	"github.com/go-darwin/sys"
+)
+
+// neg returns the two's complement of n, as a negative argument is passed.
+func neg(n uintptr) uintptr { return -n }
+
+func TestSyscallDecoder(t *testing.T) {
+	tests := []struct {
+		goos, goarch string
+		call         sys.SyscallCall
+		want         string
+	}{
+		{
+			"linux", "amd64",
+			sys.SyscallCall{Num: 257, Args: []uintptr{neg(100), 0xc000012345, 0x80000, 0}, Err: 2},
+			"openat(AT_FDCWD, 0xc000012345, O_RDONLY|O_CLOEXEC, 0) = -1 ENOENT (no such file or directory)",
+		},
+		{
+			"linux", "amd64",
+			sys.SyscallCall{Num: 9, Args: []uintptr{0, 4096, 0x3, 0x22, neg(1), 0}, R1: 0x7f0000000000},
+			"mmap(NULL, 4096, PROT_READ|PROT_WRITE, MAP_PRIVATE|MAP_ANONYMOUS, -1, 0) = 0x7f0000000000",
+		},
+		{
+			"linux", "amd64",
+			sys.SyscallCall{Num: 10, Args: []uintptr{0x7f0000000000, 4096, 0}},
+			"mprotect(0x7f0000000000, 4096, PROT_NONE) = 0",
+		},
+		{
+			"linux", "amd64",
+			sys.SyscallCall{Num: 10, Args: []uintptr{0x7f0000000000, 4096, 0x1000001}, Err: 22},
+			"mprotect(0x7f0000000000, 4096, PROT_READ|0x1000000) = -1 EINVAL (invalid argument)",
+		},
+		{
+			"linux", "amd64",
+			sys.SyscallCall{Num: 2, Args: []uintptr{0x1000, 0x101041, 0o600}, R1: 4},
+			"open(0x1000, O_WRONLY|O_SYNC|O_CREAT, 0600) = 4",
+		},
+		{
+			"linux", "amd64",
+			sys.SyscallCall{Num: 257, Args: []uintptr{3, 0x1000, 0x410002, 0o600}, R1: 5},
+			"openat(3, 0x1000, O_RDWR|O_TMPFILE, 0600) = 5",
+		},
+		{
+			"linux", "amd64",
+			sys.SyscallCall{Num: 0, Args: []uintptr{3, 0xc0000a0000, 512}, Err: 11},
+			"read(3, 0xc0000a0000, 512) = -1 EAGAIN (resource temporarily unavailable)",
+		},
+		{
+			"linux", "amd64",
+			sys.SyscallCall{Num: 999, Args: []uintptr{1, 2}, Err: 4095},
+			"syscall_999(0x1, 0x2) = -1 errno 4095",
+		},
+		{
+			"linux", "arm64",
+			sys.SyscallCall{Num: 56, Args: []uintptr{3, 0x1000, 0x84800, 0}, R1: 7},
+			"openat(3, 0x1000, O_RDONLY|O_NONBLOCK|O_DIRECTORY|O_CLOEXEC, 0) = 7",
+		},
+		{
+			"linux", "arm64",
+			sys.SyscallCall{Num: 222, Args: []uintptr{0, 8192, 0x5, 0x40, 3, 0}, R1: 0xffff00000000},
+			"mmap(NULL, 8192, PROT_READ|PROT_EXEC, 0x40, 3, 0) = 0xffff00000000",
+		},
+		{
+			"darwin", "amd64",
+			sys.SyscallCall{Num: 463, Args: []uintptr{neg(2), 0x1000, 0x1000201, 0o644}, R1: 3},
+			"openat(AT_FDCWD, 0x1000, O_WRONLY|O_CREAT|O_CLOEXEC, 0644) = 3",
+		},
+		{
+			"darwin", "amd64",
+			sys.SyscallCall{Num: 197, Args: []uintptr{0, 16384, 0x5, 0x1802, neg(1), 0}, R1: 0x100000000},
+			"mmap(NULL, 16384, PROT_READ|PROT_EXEC, MAP_PRIVATE|MAP_JIT|MAP_ANON, -1, 0) = 0x100000000",
+		},
+		{
+			"darwin", "amd64",
+			sys.SyscallCall{Num: 0x2000003, Args: []uintptr{3, 0xc000100000, 512, 0, 0, 0, 0, 0, 0}, Err: 35},
+			"read(3, 0xc000100000, 512) = -1 EAGAIN (resource temporarily unavailable)",
+		},
+		{
+			"darwin", "amd64",
+			sys.SyscallCall{Num: 37, Args: []uintptr{123, 9, 1}},
+			"kill(123, 9, 0x1) = 0",
+		},
+		{
+			"darwin", "amd64",
+			sys.SyscallCall{Num: 20, Args: make([]uintptr, 9), R1: 501},
+			"getpid() = 501",
+		},
+		{
+			"darwin", "amd64",
+			sys.SyscallCall{Num: 0x100001a, R1: 0x1103},
+			"mach_reply_port() = 0x1103",
+		},
+		{
+			"darwin", "arm64",
+			sys.SyscallCall{Num: neg(36), Args: []uintptr{0x2303}, R1: 14},
+			"semaphore_wait_trap(0x2303) = KERN_ABORTED",
+		},
+		{
+			"darwin", "arm64",
+			sys.SyscallCall{Num: 8},
+			"syscall_8() = 0",
+		},
+		{
+			"darwin", "arm64",
+			sys.SyscallCall{Num: 0x3000003, Args: []uintptr{1}},
+			"syscall_0x3000003(0x1) = 0",
+		},
+	}
+	for _, tt := range tests {
+		d, err := sys.NewSyscallDecoder(tt.goos, tt.goarch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := d.Format(&tt.call); got != tt.want {
+			t.Errorf("%s/%s: Format(%+v)\n got %s\nwant %s", tt.goos, tt.goarch, tt.call, got, tt.want)
+		}
+	}
+}
+
+func TestSyscallDecoderName(t *testing.T) {
+	tests := []struct {
+		goos, goarch string
+		num          uintptr
+		want         string
+	}{
+		{"linux", "amd64", 0, "read"},
+		{"linux", "amd64", 231, "exit_group"},
+		{"linux", "arm64", 63, "read"},
+		{"linux", "arm64", 94, "exit_group"},
+		{"darwin", "amd64", 3, "read"},
+		{"darwin", "amd64", 0x2000003, "read"},
+		{"darwin", "amd64", sys.TrapMachMsg.Trap("amd64"), "mach_msg_trap"},
+		{"darwin", "arm64", sys.TrapMachMsg.Trap("arm64"), "mach_msg_trap"},
+		{"darwin", "arm64", neg(3), "mach_trap(3)"},
+	}
+	for _, tt := range tests {
+		d, err := sys.NewSyscallDecoder(tt.goos, tt.goarch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := d.Name(tt.num); got != tt.want {
+			t.Errorf("%s/%s: Name(%#x) = %q, want %q", tt.goos, tt.goarch, tt.num, got, tt.want)
+		}
+	}
+
+	for _, p := range [][2]string{{"windows", "amd64"}, {"linux", "386"}, {"darwin", "ppc64"}} {
+		if _, err := sys.NewSyscallDecoder(p[0], p[1]); err == nil {
+			t.Errorf("NewSyscallDecoder(%q, %q) succeeded", p[0], p[1])
+		}
+	}
+}
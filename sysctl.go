@@ -0,0 +1,210 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"time"
+)
+
+// This file holds the decoders of the values of the sysctls, which are in
+// the layout of the 64-bit darwin targets, little-endian, on every platform
+// so that they are testable against the bytes of a darwin host.
+
+// Timeval is a struct timeval, such as the value of kern.boottime.
+type Timeval struct {
+	Sec  int64
+	Usec int32
+	_    [4]byte
+}
+
+// Time returns tv as a time.Time.
+func (tv Timeval) Time() time.Time {
+	return time.Unix(tv.Sec, int64(tv.Usec)*1e3)
+}
+
+// Loadavg is the struct loadavg of vm.loadavg, the load averages over 1, 5
+// and 15 minutes.
+type Loadavg struct {
+	Load  [3]uint32 // fixed-point, in units of 1/Scale
+	Scale int64     // fscale
+}
+
+// Float returns the load averages over 1, 5 and 15 minutes.
+func (l Loadavg) Float() [3]float64 {
+	var f [3]float64
+	if l.Scale == 0 {
+		return f
+	}
+	for i, v := range l.Load {
+		f[i] = float64(v) / float64(l.Scale)
+	}
+
+	return f
+}
+
+// list of the sizes of the C structs of the sysctls.
+const (
+	timevalSize   = 16
+	loadavgSize   = 24
+	kinfoProcSize = 648
+)
+
+// KinfoProc is the decoded struct kinfo_proc of a process, of the kern.proc
+// sysctls, which is the struct extern_proc of the process followed by its
+// struct eproc.
+type KinfoProc struct {
+	// extern_proc
+	StartTime Timeval // p_starttime
+	Flag      int32   // p_flag, the P_ flags of sys/proc.h
+	Stat      int8    // p_stat, such as SRUN
+	Pid       int32   // p_pid
+	Priority  uint8   // p_priority
+	Nice      int8    // p_nice
+	Comm      string  // p_comm, the first 16 bytes of the command name
+
+	// eproc
+	RUID  uint32 // p_ruid of e_pcred
+	SVUID uint32 // p_svuid of e_pcred
+	RGID  uint32 // p_rgid of e_pcred
+	SVGID uint32 // p_svgid of e_pcred
+	UID   uint32 // cr_uid of e_ucred
+	PPid  int32  // e_ppid
+	Pgid  int32  // e_pgid
+	Tdev  int32  // e_tdev, the device of the controlling terminal, or -1
+	Tpgid int32  // e_tpgid
+	EFlag int32  // e_flag, the E flags of sys/sysctl.h
+}
+
+// the offsets of the fields of struct kinfo_proc, struct eproc starting
+// at 296.
+const (
+	kinfoStartTime = 0
+	kinfoFlag      = 32
+	kinfoStat      = 36
+	kinfoPid       = 40
+	kinfoPriority  = 240
+	kinfoNice      = 242
+	kinfoComm      = 243 // char[MAXCOMLEN+1]
+	kinfoRUID      = 296 + 96
+	kinfoSVUID     = 296 + 100
+	kinfoRGID      = 296 + 104
+	kinfoSVGID     = 296 + 108
+	kinfoUID       = 296 + 124
+	kinfoPPid      = 296 + 264
+	kinfoPgid      = 296 + 268
+	kinfoTdev      = 296 + 276
+	kinfoTpgid     = 296 + 280
+	kinfoEFlag     = 296 + 316
+
+	maxComLen = 16
+)
+
+// ErrSysctlSize is returned by the decoders of the sysctl and libproc values
+// when the size of the value is not the one of its type.
+var ErrSysctlSize = errors.New("sys: sysctl value of an unexpected size")
+
+// UnmarshalSysctl decodes the value b of a sysctl into v, which is one of
+// *uint32, *uint64, *int32, *int64, *string, *Timeval, *Loadavg,
+// *[]KinfoProc or a pointer to another fixed-size value, such as a struct
+// of fixed-size fields, decoded like by encoding/binary.
+//
+// The string of a value is the one before its first NUL byte. The value of
+// the other types is of their exact size, and b holds a whole number of
+// struct kinfo_proc for a *[]KinfoProc. UnmarshalSysctl returns
+// ErrSysctlSize otherwise.
+func UnmarshalSysctl(b []byte, v interface{}) error {
+	switch v := v.(type) {
+	case *string:
+		*v = cstring(b)
+		return nil
+	case *Timeval:
+		tv, err := ParseTimeval(b)
+		*v = tv
+		return err
+	case *Loadavg:
+		l, err := ParseLoadavg(b)
+		*v = l
+		return err
+	case *[]KinfoProc:
+		procs, err := ParseKinfoProc(b)
+		*v = procs
+		return err
+	}
+
+	size := binary.Size(v)
+	if size < 0 {
+		return errors.New("sys: UnmarshalSysctl of an unsupported " + reflect.TypeOf(v).String())
+	}
+	if len(b) != size {
+		return ErrSysctlSize
+	}
+
+	return binary.Read(bytes.NewReader(b), binary.LittleEndian, v)
+}
+
+// ParseTimeval decodes the struct timeval b.
+func ParseTimeval(b []byte) (Timeval, error) {
+	if len(b) != timevalSize {
+		return Timeval{}, ErrSysctlSize
+	}
+
+	return Timeval{
+		Sec:  int64(binary.LittleEndian.Uint64(b)),
+		Usec: int32(binary.LittleEndian.Uint32(b[8:])),
+	}, nil
+}
+
+// ParseLoadavg decodes the struct loadavg b of vm.loadavg.
+func ParseLoadavg(b []byte) (Loadavg, error) {
+	if len(b) != loadavgSize {
+		return Loadavg{}, ErrSysctlSize
+	}
+
+	var l Loadavg
+	for i := range l.Load {
+		l.Load[i] = binary.LittleEndian.Uint32(b[4*i:])
+	}
+	l.Scale = int64(binary.LittleEndian.Uint64(b[16:]))
+
+	return l, nil
+}
+
+// ParseKinfoProc decodes the array of struct kinfo_proc b of a kern.proc
+// sysctl, such as kern.proc.all.
+func ParseKinfoProc(b []byte) ([]KinfoProc, error) {
+	if len(b)%kinfoProcSize != 0 {
+		return nil, ErrSysctlSize
+	}
+
+	procs := make([]KinfoProc, 0, len(b)/kinfoProcSize)
+	for ; len(b) > 0; b = b[kinfoProcSize:] {
+		le := binary.LittleEndian
+		tv, _ := ParseTimeval(b[kinfoStartTime : kinfoStartTime+timevalSize])
+		procs = append(procs, KinfoProc{
+			StartTime: tv,
+			Flag:      int32(le.Uint32(b[kinfoFlag:])),
+			Stat:      int8(b[kinfoStat]),
+			Pid:       int32(le.Uint32(b[kinfoPid:])),
+			Priority:  b[kinfoPriority],
+			Nice:      int8(b[kinfoNice]),
+			Comm:      cstring(b[kinfoComm : kinfoComm+maxComLen+1]),
+			RUID:      le.Uint32(b[kinfoRUID:]),
+			SVUID:     le.Uint32(b[kinfoSVUID:]),
+			RGID:      le.Uint32(b[kinfoRGID:]),
+			SVGID:     le.Uint32(b[kinfoSVGID:]),
+			UID:       le.Uint32(b[kinfoUID:]),
+			PPid:      int32(le.Uint32(b[kinfoPPid:])),
+			Pgid:      int32(le.Uint32(b[kinfoPgid:])),
+			Tdev:      int32(le.Uint32(b[kinfoTdev:])),
+			Tpgid:     int32(le.Uint32(b[kinfoTpgid:])),
+			EFlag:     int32(le.Uint32(b[kinfoEFlag:])),
+		})
+	}
+
+	return procs, nil
+}
@@ -0,0 +1,186 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build darwin && amd64
+// +build darwin,amd64
+
+package sys
+
+import (
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+// The sysctl functions of libc, called with Ccall6 through the trampolines
+// of sysctl_darwin.s.
+
+//go:cgo_import_dynamic libc_sysctl sysctl "/usr/lib/libSystem.B.dylib"
+//go:cgo_import_dynamic libc_sysctlbyname sysctlbyname "/usr/lib/libSystem.B.dylib"
+//go:cgo_import_dynamic libc_sysctlnametomib sysctlnametomib "/usr/lib/libSystem.B.dylib"
+
+// the addresses of the trampolines, set by sysctl_darwin.s.
+var (
+	sysctlTrampolineAddr          uintptr
+	sysctlbynameTrampolineAddr    uintptr
+	sysctlnametomibTrampolineAddr uintptr
+)
+
+// ctlMaxName is CTL_MAXNAME, the maximum length of a MIB.
+const ctlMaxName = 12
+
+// maxSysctlRetries is the number of times a sysctl is read again when its
+// value grew past the size of the first call, like kern.proc.all.
+const maxSysctlRetries = 8
+
+// readSysctl reads a value with call, which calls the sysctl function with
+// the buffer old of size *oldlen, NULL for the size of the value.
+func readSysctl(call func(old unsafe.Pointer, oldlen *uintptr) Errno) ([]byte, error) {
+	for i := 0; ; i++ {
+		var n uintptr
+		if err := call(nil, &n); err != 0 {
+			return nil, err
+		}
+		if n == 0 {
+			return []byte{}, nil
+		}
+		// room for the entries added between the calls
+		n += n / 8
+		b := make([]byte, n)
+		if err := call(unsafe.Pointer(&b[0]), &n); err != 0 {
+			if err == syscall.ENOMEM && i < maxSysctlRetries {
+				continue
+			}
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+
+// Sysctl returns the value of the sysctl of the Management Information Base
+// mib, like sysctl(3), probing its size first.
+func Sysctl(mib []int32) ([]byte, error) {
+	if len(mib) == 0 {
+		return nil, syscall.EINVAL
+	}
+
+	return readSysctl(func(old unsafe.Pointer, oldlen *uintptr) Errno {
+		_, _, err := Ccall6(sysctlTrampolineAddr, uintptr(unsafe.Pointer(&mib[0])), uintptr(len(mib)), uintptr(old), uintptr(unsafe.Pointer(oldlen)), 0, 0)
+		runtime.KeepAlive(mib)
+		runtime.KeepAlive(oldlen)
+		return err
+	})
+}
+
+// SysctlByName returns the value of the sysctl name, such as "hw.ncpu", like
+// sysctlbyname(3), probing its size first.
+func SysctlByName(name string) ([]byte, error) {
+	p, err := syscall.BytePtrFromString(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return readSysctl(func(old unsafe.Pointer, oldlen *uintptr) Errno {
+		_, _, err := Ccall6(sysctlbynameTrampolineAddr, uintptr(unsafe.Pointer(p)), uintptr(old), uintptr(unsafe.Pointer(oldlen)), 0, 0, 0)
+		runtime.KeepAlive(p)
+		runtime.KeepAlive(oldlen)
+		return err
+	})
+}
+
+// SysctlNameToMIB returns the Management Information Base of the sysctl
+// name, like sysctlnametomib(3), for the sysctls whose MIB takes
+// arguments, such as the process ID of kern.proc.pid.
+func SysctlNameToMIB(name string) ([]int32, error) {
+	p, err := syscall.BytePtrFromString(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var mib [ctlMaxName]int32
+	n := uintptr(len(mib))
+	_, _, errno := Ccall6(sysctlnametomibTrampolineAddr, uintptr(unsafe.Pointer(p)), uintptr(unsafe.Pointer(&mib[0])), uintptr(unsafe.Pointer(&n)), 0, 0, 0)
+	runtime.KeepAlive(p)
+	runtime.KeepAlive(&mib)
+	runtime.KeepAlive(&n)
+	if errno != 0 {
+		return nil, errno
+	}
+
+	return mib[:n:n], nil
+}
+
+// sysctlValue decodes the value of the sysctl name into v.
+func sysctlValue(name string, v interface{}) error {
+	b, err := SysctlByName(name)
+	if err != nil {
+		return err
+	}
+
+	return UnmarshalSysctl(b, v)
+}
+
+// SysctlUint32 returns the 32-bit value of the sysctl name, such as
+// hw.ncpu.
+func SysctlUint32(name string) (uint32, error) {
+	var v uint32
+	err := sysctlValue(name, &v)
+	return v, err
+}
+
+// SysctlUint64 returns the 64-bit value of the sysctl name, such as
+// hw.memsize.
+func SysctlUint64(name string) (uint64, error) {
+	var v uint64
+	err := sysctlValue(name, &v)
+	return v, err
+}
+
+// SysctlString returns the string value of the sysctl name, such as
+// kern.ostype.
+func SysctlString(name string) (string, error) {
+	var v string
+	err := sysctlValue(name, &v)
+	return v, err
+}
+
+// SysctlTimeval returns the struct timeval value of the sysctl name, such
+// as kern.boottime.
+func SysctlTimeval(name string) (Timeval, error) {
+	var v Timeval
+	err := sysctlValue(name, &v)
+	return v, err
+}
+
+// SysctlStruct decodes the value of the sysctl name into v, a pointer to a
+// fixed-size value of the layout of the C one, like UnmarshalSysctl. It is
+// the typed getter of the structs, the module supporting Go releases
+// without type parameters.
+func SysctlStruct(name string, v interface{}) error {
+	return sysctlValue(name, v)
+}
+
+// SysctlLoadavg returns the load averages of vm.loadavg.
+func SysctlLoadavg() (Loadavg, error) {
+	var v Loadavg
+	err := sysctlValue("vm.loadavg", &v)
+	return v, err
+}
+
+// KinfoProcs returns the processes of the kern.proc sysctl name, such as
+// kern.proc.all or kern.proc.pid, with the arguments args of its MIB, such
+// as the process ID of kern.proc.pid.
+//
+//	procs, err := sys.KinfoProcs("kern.proc.pid", int32(os.Getpid()))
+func KinfoProcs(name string, args ...int32) ([]KinfoProc, error) {
+	mib, err := SysctlNameToMIB(name)
+	if err != nil {
+		return nil, err
+	}
+	b, err := Sysctl(append(mib, args...))
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseKinfoProc(b)
+}
@@ -0,0 +1,25 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build darwin && amd64
+// +build darwin,amd64
+
+#include "textflag.h"
+
+TEXT sysctl_trampoline<>(SB), NOSPLIT, $0-0
+	JMP libc_sysctl(SB)
+
+GLOBL ·sysctlTrampolineAddr(SB), RODATA, $8
+DATA ·sysctlTrampolineAddr(SB)/8, $sysctl_trampoline<>(SB)
+
+TEXT sysctlbyname_trampoline<>(SB), NOSPLIT, $0-0
+	JMP libc_sysctlbyname(SB)
+
+GLOBL ·sysctlbynameTrampolineAddr(SB), RODATA, $8
+DATA ·sysctlbynameTrampolineAddr(SB)/8, $sysctlbyname_trampoline<>(SB)
+
+TEXT sysctlnametomib_trampoline<>(SB), NOSPLIT, $0-0
+	JMP libc_sysctlnametomib(SB)
+
+GLOBL ·sysctlnametomibTrampolineAddr(SB), RODATA, $8
+DATA ·sysctlnametomibTrampolineAddr(SB)/8, $sysctlnametomib_trampoline<>(SB)
@@ -0,0 +1,79 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build darwin && amd64
+// +build darwin,amd64
+
+package sys_test
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/go-darwin/sys"
+)
+
+func TestSysctl(t *testing.T) {
+	ncpu, err := sys.SysctlUint32("hw.ncpu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int(ncpu) != runtime.NumCPU() {
+		t.Errorf("hw.ncpu = %d, want %d", ncpu, runtime.NumCPU())
+	}
+
+	if memsize, err := sys.SysctlUint64("hw.memsize"); err != nil || memsize == 0 {
+		t.Errorf("hw.memsize = %d, %v", memsize, err)
+	}
+	if ostype, err := sys.SysctlString("kern.ostype"); err != nil || ostype != "Darwin" {
+		t.Errorf("kern.ostype = %q, %v", ostype, err)
+	}
+	boottime, err := sys.SysctlTimeval("kern.boottime")
+	if err != nil || !boottime.Time().Before(time.Now()) {
+		t.Errorf("kern.boottime = %v, %v", boottime.Time(), err)
+	}
+	if l, err := sys.SysctlLoadavg(); err != nil || l.Scale == 0 {
+		t.Errorf("vm.loadavg = %+v, %v", l, err)
+	}
+
+	mib, err := sys.SysctlNameToMIB("kern.ostype")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := sys.Sysctl(mib)
+	if err != nil || string(b) != "Darwin\x00" {
+		t.Errorf("Sysctl(%v) = %q, %v", mib, b, err)
+	}
+	if _, err := sys.SysctlByName("godarwin.missing"); err != syscall.ENOENT {
+		t.Errorf("SysctlByName of a missing sysctl = %v, want ENOENT", err)
+	}
+}
+
+func TestKinfoProcs(t *testing.T) {
+	procs, err := sys.KinfoProcs("kern.proc.pid", int32(os.Getpid()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(procs) != 1 {
+		t.Fatalf("kern.proc.pid returned %d processes", len(procs))
+	}
+	p := procs[0]
+	if int(p.Pid) != os.Getpid() || int(p.PPid) != os.Getppid() || int(p.UID) != os.Geteuid() || int(p.RUID) != os.Getuid() {
+		t.Errorf("kern.proc.pid = %+v", p)
+	}
+
+	all, err := sys.KinfoProcs("kern.proc.all")
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, p := range all {
+		found = found || p.Pid == 1
+	}
+	if !found {
+		t.Errorf("kern.proc.all of %d processes has no launchd", len(all))
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-darwin/sys"
+)
+
+func TestParseKinfoProc(t *testing.T) {
+	b := readDarwinFixture(t, "sysctl", "kern.proc.all")
+
+	procs, err := sys.ParseKinfoProc(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []sys.KinfoProc{
+		{
+			StartTime: sys.Timeval{Sec: 1660000001, Usec: 250000},
+			Flag:      0x4004,
+			Stat:      2,
+			Pid:       1,
+			Priority:  31,
+			Comm:      "launchd",
+			Pgid:      1,
+			Tdev:      -1,
+		},
+		{
+			StartTime: sys.Timeval{Sec: 1660003600, Usec: 5},
+			Flag:      0x4006,
+			Stat:      2,
+			Pid:       412,
+			Priority:  31,
+			Nice:      -5,
+			Comm:      "zsh",
+			RUID:      501,
+			SVUID:     501,
+			RGID:      20,
+			SVGID:     20,
+			UID:       501,
+			PPid:      411,
+			Pgid:      412,
+			Tdev:      0x10000004,
+			Tpgid:     412,
+			EFlag:     1,
+		},
+	}
+	if len(procs) != len(want) {
+		t.Fatalf("ParseKinfoProc returned %d processes, want %d", len(procs), len(want))
+	}
+	for i := range want {
+		if procs[i] != want[i] {
+			t.Errorf("process %d = %+v, want %+v", i, procs[i], want[i])
+		}
+	}
+
+	var v []sys.KinfoProc
+	if err := sys.UnmarshalSysctl(b[:648], &v); err != nil || len(v) != 1 || v[0] != want[0] {
+		t.Errorf("UnmarshalSysctl = %+v, %v", v, err)
+	}
+	if _, err := sys.ParseKinfoProc(b[:647]); !errors.Is(err, sys.ErrSysctlSize) {
+		t.Errorf("ParseKinfoProc of 647 bytes = %v, want ErrSysctlSize", err)
+	}
+	if procs, err := sys.ParseKinfoProc(nil); err != nil || len(procs) != 0 {
+		t.Errorf("ParseKinfoProc(nil) = %v, %v", procs, err)
+	}
+}
+
+func TestParseLoadavg(t *testing.T) {
+	b := readDarwinFixture(t, "sysctl", "vm.loadavg")
+
+	l, err := sys.ParseLoadavg(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (sys.Loadavg{Load: [3]uint32{3461, 3189, 2970}, Scale: 2048}); l != want {
+		t.Errorf("ParseLoadavg = %+v, want %+v", l, want)
+	}
+	f := l.Float()
+	for i, want := range []float64{3461.0 / 2048, 3189.0 / 2048, 2970.0 / 2048} {
+		if f[i] != want {
+			t.Errorf("Float()[%d] = %v, want %v", i, f[i], want)
+		}
+	}
+	if f := (sys.Loadavg{}).Float(); f != [3]float64{} {
+		t.Errorf("Float of a zero scale = %v", f)
+	}
+	if _, err := sys.ParseLoadavg(b[:20]); !errors.Is(err, sys.ErrSysctlSize) {
+		t.Errorf("ParseLoadavg of 20 bytes = %v, want ErrSysctlSize", err)
+	}
+}
+
+func TestParseTimeval(t *testing.T) {
+	b := readDarwinFixture(t, "sysctl", "kern.boottime")
+
+	tv, err := sys.ParseTimeval(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (sys.Timeval{Sec: 1660000000, Usec: 123456}); tv != want {
+		t.Errorf("ParseTimeval = %+v, want %+v", tv, want)
+	}
+	if got, want := tv.Time(), time.Unix(1660000000, 123456000); !got.Equal(want) {
+		t.Errorf("Time = %v, want %v", got, want)
+	}
+	if _, err := sys.ParseTimeval(b[:8]); !errors.Is(err, sys.ErrSysctlSize) {
+		t.Errorf("ParseTimeval of 8 bytes = %v, want ErrSysctlSize", err)
+	}
+}
+
+func TestUnmarshalSysctl(t *testing.T) {
+	var u32 uint32
+	if err := sys.UnmarshalSysctl([]byte{8, 0, 0, 0}, &u32); err != nil || u32 != 8 {
+		t.Errorf("UnmarshalSysctl uint32 = %d, %v", u32, err)
+	}
+	var u64 uint64
+	if err := sys.UnmarshalSysctl([]byte{0, 0, 0, 0, 4, 0, 0, 0}, &u64); err != nil || u64 != 4<<32 {
+		t.Errorf("UnmarshalSysctl uint64 = %#x, %v", u64, err)
+	}
+	if err := sys.UnmarshalSysctl([]byte{8, 0, 0, 0}, &u64); !errors.Is(err, sys.ErrSysctlSize) {
+		t.Errorf("UnmarshalSysctl uint64 of 4 bytes = %v, want ErrSysctlSize", err)
+	}
+	var s string
+	if err := sys.UnmarshalSysctl([]byte("Darwin\x00"), &s); err != nil || s != "Darwin" {
+		t.Errorf("UnmarshalSysctl string = %q, %v", s, err)
+	}
+
+	// struct clockinfo of kern.clockrate
+	var ci struct {
+		Hz, Tick, TickAdj, StatHz, ProfHz int32
+	}
+	b := []byte{100, 0, 0, 0, 0x10, 0x27, 0, 0, 0, 0, 0, 0, 100, 0, 0, 0, 100, 0, 0, 0}
+	if err := sys.UnmarshalSysctl(b, &ci); err != nil || ci.Hz != 100 || ci.Tick != 10000 || ci.ProfHz != 100 {
+		t.Errorf("UnmarshalSysctl struct = %+v, %v", ci, err)
+	}
+	var tv sys.Timeval
+	if err := sys.UnmarshalSysctl(readDarwinFixture(t, "sysctl", "kern.boottime"), &tv); err != nil || tv.Sec != 1660000000 {
+		t.Errorf("UnmarshalSysctl Timeval = %+v, %v", tv, err)
+	}
+	var m map[string]int
+	if err := sys.UnmarshalSysctl(b, &m); err == nil {
+		t.Error("UnmarshalSysctl of a map succeeded")
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys
+
+//go:generate go run ./internal/mksysnum
+
+// SyscallClass represents the class of a darwin trap, encoded in the high
+// byte of the trap number by the amd64 syscall ABI of xnu.
+//
+// RawCcall9, for example, invokes the BSD syscall whose number is its first
+// argument as SyscallClassUnix.Encode(num), the 0x2000000 | num trap number.
+//
+// On arm64, the class is not encoded in the trap number: the BSD syscalls have
+// their positive number in x16, and the Mach traps their negated number.
+type SyscallClass uint8
+
+// list of SyscallClass.
+const (
+	SyscallClassNone SyscallClass = 0 // invalid
+	SyscallClassMach SyscallClass = 1 // Mach traps
+	SyscallClassUnix SyscallClass = 2 // Unix/BSD syscalls
+	SyscallClassMDEP SyscallClass = 3 // machine-dependent calls
+	SyscallClassDiag SyscallClass = 4 // diagnostics
+	SyscallClassIPC  SyscallClass = 5 // Mach IPC
+)
+
+const (
+	syscallClassShift = 24
+	syscallNumberMask = 1<<syscallClassShift - 1
+)
+
+// syscallClassNames is the SyscallClass name table.
+var syscallClassNames = [...]string{
+	SyscallClassNone: "SYSCALL_CLASS_NONE",
+	SyscallClassMach: "SYSCALL_CLASS_MACH",
+	SyscallClassUnix: "SYSCALL_CLASS_UNIX",
+	SyscallClassMDEP: "SYSCALL_CLASS_MDEP",
+	SyscallClassDiag: "SYSCALL_CLASS_DIAG",
+	SyscallClassIPC:  "SYSCALL_CLASS_IPC",
+}
+
+// String returns the C constant name of the SyscallClass, such as
+// "SYSCALL_CLASS_UNIX".
+func (c SyscallClass) String() string {
+	if int(c) < len(syscallClassNames) {
+		return syscallClassNames[c]
+	}
+
+	return "SYSCALL_CLASS(" + itoa(int(c)) + ")"
+}
+
+// Encode returns the amd64 trap number of the call num of the SyscallClass,
+// like the SYSCALL_CONSTRUCT_* macros of mach/i386/syscall_sw.h.
+func (c SyscallClass) Encode(num int) uintptr {
+	return uintptr(c)<<syscallClassShift | uintptr(num)&syscallNumberMask
+}
+
+// DecodeSyscall returns the SyscallClass and the call number of the amd64 trap
+// number trap.
+func DecodeSyscall(trap uintptr) (SyscallClass, int) {
+	return SyscallClass(trap >> syscallClassShift), int(trap & syscallNumberMask)
+}
+
+// Syscall describes a BSD syscall of darwin, as declared by the
+// bsd/kern/syscalls.master file of xnu.
+type Syscall struct {
+	// Number is the syscall number, without the class.
+	Number int
+
+	// Name is the name of the syscall, such as "read".
+	Name string
+
+	// Ret is the C type of the result, such as "user_ssize_t".
+	Ret string
+
+	// Args are the arguments of the syscall.
+	Args []SyscallArg
+}
+
+// SyscallArg is an argument of a Syscall.
+type SyscallArg struct {
+	// Type is the C type of the argument, such as "user_addr_t" or "char **".
+	Type string
+
+	// Name is the name of the argument.
+	Name string
+}
+
+// String returns the C prototype of the syscall, such as
+// "user_ssize_t read(int fd, user_addr_t cbuf, user_size_t nbyte)".
+func (s Syscall) String() string {
+	b := make([]byte, 0, 64)
+	b = append(b, s.Ret...)
+	b = append(b, ' ')
+	b = append(b, s.Name...)
+	b = append(b, '(')
+	if len(s.Args) == 0 {
+		b = append(b, "void"...)
+	}
+	for i, a := range s.Args {
+		if i > 0 {
+			b = append(b, ", "...)
+		}
+		b = append(b, a.Type...)
+		if a.Type[len(a.Type)-1] != '*' {
+			b = append(b, ' ')
+		}
+		b = append(b, a.Name...)
+	}
+	b = append(b, ')')
+
+	return string(b)
+}
+
+// Trap returns the amd64 trap number of the syscall.
+func (s Syscall) Trap() uintptr {
+	return SyscallClassUnix.Encode(s.Number)
+}
+
+// SyscallByNumber returns the BSD syscall of darwin numbered num, and whether
+// there is one.
+func SyscallByNumber(num int) (Syscall, bool) {
+	if num < 0 || num >= len(syscalls) {
+		return Syscall{}, false
+	}
+
+	s := syscalls[num]
+
+	return s, s.Name != ""
+}
+
+// SyscallByName returns the BSD syscall of darwin named name, such as "read",
+// and whether there is one.
+func SyscallByName(name string) (Syscall, bool) {
+	for _, s := range syscalls {
+		if s.Name != "" && s.Name == name {
+			return s, true
+		}
+	}
+
+	return Syscall{}, false
+}
@@ -0,0 +1,105 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys_test
+
+import (
+	"testing"
+
+	"github.com/go-darwin/sys"
+)
+
+func TestSyscallClass(t *testing.T) {
+	tests := []struct {
+		class sys.SyscallClass
+		num   int
+		trap  uintptr
+		name  string
+	}{
+		{sys.SyscallClassUnix, 20, 0x2000014, "SYSCALL_CLASS_UNIX"},
+		{sys.SyscallClassMach, 26, 0x100001a, "SYSCALL_CLASS_MACH"},
+		{sys.SyscallClassMDEP, 3, 0x3000003, "SYSCALL_CLASS_MDEP"},
+		{sys.SyscallClassDiag, 0, 0x4000000, "SYSCALL_CLASS_DIAG"},
+		{sys.SyscallClassIPC, 1, 0x5000001, "SYSCALL_CLASS_IPC"},
+		{sys.SyscallClass(9), 1, 0x9000001, "SYSCALL_CLASS(9)"},
+	}
+	for _, tt := range tests {
+		if got := tt.class.Encode(tt.num); got != tt.trap {
+			t.Errorf("%v.Encode(%d) = %#x, want %#x", tt.class, tt.num, got, tt.trap)
+		}
+		class, num := sys.DecodeSyscall(tt.trap)
+		if class != tt.class || num != tt.num {
+			t.Errorf("DecodeSyscall(%#x) = %v, %d; want %v, %d", tt.trap, class, num, tt.class, tt.num)
+		}
+		if got := tt.class.String(); got != tt.name {
+			t.Errorf("SyscallClass(%d).String() = %q, want %q", uint8(tt.class), got, tt.name)
+		}
+	}
+
+	if got := sys.SyscallClassUnix.Encode(0x1000001); got != 0x2000001 {
+		t.Errorf("Encode does not mask the class bits of the number: %#x", got)
+	}
+}
+
+func TestSyscallByNumber(t *testing.T) {
+	tests := []struct {
+		num   int
+		proto string
+		trap  uintptr
+	}{
+		{3, "user_ssize_t read(int fd, user_addr_t cbuf, user_size_t nbyte)", 0x2000003},
+		{20, "int getpid(void)", 0x2000014},
+		{59, "int execve(char *fname, char **argp, char **envp)", 0x200003b},
+		{197, "user_addr_t mmap(caddr_t addr, size_t len, int prot, int flags, int fd, off_t pos)", 0x20000c5},
+		{202, "int sysctl(int *name, u_int namelen, void *old, size_t *oldlenp, void *new, size_t newlen)", 0x20000ca},
+		{274, "int sysctlbyname(const char *name, size_t namelen, void *old, size_t *oldlenp, void *new, size_t newlen)", 0x2000112},
+	}
+	for _, tt := range tests {
+		s, ok := sys.SyscallByNumber(tt.num)
+		if !ok {
+			t.Errorf("SyscallByNumber(%d) not found", tt.num)
+			continue
+		}
+		if got := s.String(); got != tt.proto {
+			t.Errorf("SyscallByNumber(%d) = %q, want %q", tt.num, got, tt.proto)
+		}
+		if got := s.Trap(); got != tt.trap {
+			t.Errorf("%s.Trap() = %#x, want %#x", s.Name, got, tt.trap)
+		}
+	}
+
+	for _, num := range []int{-1, 8, 63, 100000} {
+		if s, ok := sys.SyscallByNumber(num); ok {
+			t.Errorf("SyscallByNumber(%d) = %v, want none", num, s)
+		}
+	}
+}
+
+func TestSyscallByName(t *testing.T) {
+	tests := []struct {
+		name string
+		num  int
+	}{
+		{"syscall", 0},
+		{"exit", 1},
+		{"close", 6},
+		{"fcntl", 92},
+		{"__pthread_kill", 328},
+		{"kevent64", 369},
+		{"openat", 463},
+		{"getentropy", 500},
+		{"ulock_wait", 515},
+	}
+	for _, tt := range tests {
+		s, ok := sys.SyscallByName(tt.name)
+		if !ok || s.Number != tt.num {
+			t.Errorf("SyscallByName(%q) = %d, %t; want %d, true", tt.name, s.Number, ok, tt.num)
+		}
+	}
+
+	for _, name := range []string{"", "nosys", "sys_close", "clone"} {
+		if s, ok := sys.SyscallByName(name); ok {
+			t.Errorf("SyscallByName(%q) = %v, want none", name, s)
+		}
+	}
+}
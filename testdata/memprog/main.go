@@ -0,0 +1,23 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Command memprog holds a buffer for the tests of sys.ProcessMemory: it
+// prints the address and the length of the buffer, waits for a line on its
+// standard input, and prints the buffer then.
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"runtime"
+)
+
+var buf = []byte("go-darwin process memory 0123456789")
+
+func main() {
+	fmt.Printf("%#x %d\n", &buf[0], len(buf))
+	bufio.NewReader(os.Stdin).ReadString('\n')
+	fmt.Printf("%s\n", buf)
+	runtime.KeepAlive(buf)
+}
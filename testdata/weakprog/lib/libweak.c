@@ -0,0 +1,8 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// libweak is the shared library of weakprog, which lacks weak_missing.
+
+int weak_present(int x) {
+	return x + 1;
+}
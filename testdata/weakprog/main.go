@@ -0,0 +1,61 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Command weakprog resolves the weak symbols of libweak.so, built from
+// lib/libweak.c, which lacks weak_missing.
+package main
+
+/*
+#cgo LDFLAGS: -lweak -ldl
+
+#include <dlfcn.h>
+
+int weak_present(int);
+
+static void *lookup(const char *name) {
+	void *h = dlopen("libweak.so", RTLD_LAZY | RTLD_NOLOAD);
+	return h ? dlsym(h, name) : 0;
+}
+
+static int call(void *fn, int x) {
+	return ((int (*)(int))fn)(x);
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"github.com/go-darwin/sys"
+)
+
+var (
+	present = sys.NewWeakSymbol("libweak.so", "weak_present")
+	missing = sys.NewWeakSymbol("libweak.so", "weak_missing")
+)
+
+// weakMissing is the wrapper of weak_missing.
+func weakMissing(x int) (int, error) {
+	fn, ok := missing.Addr()
+	if !ok {
+		return 0, missing.Err()
+	}
+
+	return int(C.call(unsafe.Pointer(fn), C.int(x))), nil
+}
+
+func main() {
+	// load libweak.so, linked as needed
+	C.weak_present(0)
+
+	fn, ok := present.Addr()
+	name := C.CString("weak_present")
+	dlsym := uintptr(C.lookup(name))
+	fmt.Println("weak_present", ok, fn == dlsym, present.Err(), C.call(unsafe.Pointer(fn), 41))
+
+	_, ok = missing.Addr()
+	_, err := weakMissing(41)
+	fmt.Println("weak_missing", ok, errors.Is(err, sys.ErrUnavailable), err)
+}
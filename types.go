@@ -0,0 +1,168 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys
+
+import (
+	"encoding/binary"
+	"unsafe"
+)
+
+//go:generate go run ./internal/mkztypes
+
+// list of common C types.
+type (
+	C_short       = c_short
+	C_int         = c_int
+	C_int8        = c_int8
+	C_int16       = c_int16
+	C_int32       = c_int32
+	C_int64       = c_int64
+	C_long        = c_long
+	C_longLong    = c_longLong
+	C_uint        = c_uint
+	C_uint8       = c_uint8
+	C_uint16      = c_uint16
+	C_uint32      = c_uint32
+	C_uint64      = c_uint64
+	C_char        = c_char
+	C_float       = c_float
+	C_double      = c_double
+	C_size_t      = c_size_t
+	C_ssize_t     = c_ssize_t
+	C_uintptr_t   = c_uintptr_t
+	C_bool        = c_bool
+	C_long_double = c_long_double
+)
+
+// list of common C types size.
+//
+// The sizes are those of the build target. Use CTypes to query
+// the layout of the other targets.
+const (
+	Sizeof_C_short       = unsafe.Sizeof(*new(c_short))
+	Sizeof_C_int         = unsafe.Sizeof(*new(c_int))
+	Sizeof_C_int8        = unsafe.Sizeof(*new(c_int8))
+	Sizeof_C_int16       = unsafe.Sizeof(*new(c_int16))
+	Sizeof_C_int32       = unsafe.Sizeof(*new(c_int32))
+	Sizeof_C_int64       = unsafe.Sizeof(*new(c_int64))
+	Sizeof_C_long        = unsafe.Sizeof(*new(c_long))
+	Sizeof_C_longLong    = unsafe.Sizeof(*new(c_longLong))
+	Sizeof_C_uint        = unsafe.Sizeof(*new(c_uint))
+	Sizeof_C_uint8       = unsafe.Sizeof(*new(c_uint8))
+	Sizeof_C_uint16      = unsafe.Sizeof(*new(c_uint16))
+	Sizeof_C_uint32      = unsafe.Sizeof(*new(c_uint32))
+	Sizeof_C_uint64      = unsafe.Sizeof(*new(c_uint64))
+	Sizeof_C_char        = unsafe.Sizeof(*new(c_char))
+	Sizeof_C_float       = unsafe.Sizeof(*new(c_float))
+	Sizeof_C_double      = unsafe.Sizeof(*new(c_double))
+	Sizeof_C_size_t      = unsafe.Sizeof(*new(c_size_t))
+	Sizeof_C_ssize_t     = unsafe.Sizeof(*new(c_ssize_t))
+	Sizeof_C_uintptr_t   = unsafe.Sizeof(*new(c_uintptr_t))
+	Sizeof_C_bool        = unsafe.Sizeof(*new(c_bool))
+	Sizeof_C_long_double = unsafe.Sizeof(*new(c_long_double))
+)
+
+// KernReturn represents a kern_return_t.
+type KernReturn = kernReturn
+
+// list of Mach kernel types.
+type (
+	// Natural represents a natural_t, the natural unsigned integer of the Mach interfaces.
+	Natural = natural
+
+	// Integer represents an integer_t, the natural signed integer of the Mach interfaces.
+	Integer = integer
+
+	// Boolean represents a boolean_t.
+	//
+	// It is unsigned on amd64 and signed on arm64.
+	Boolean = boolean
+
+	// MachPort represents a mach_port_t.
+	MachPort = machPort
+
+	// MachPortName represents a mach_port_name_t.
+	MachPortName = machPortName
+
+	// Task represents a task_t, the port of a task.
+	Task = task
+
+	// ThreadAct represents a thread_act_t, the port of a thread.
+	ThreadAct = threadAct
+
+	// VMAddress represents a vm_address_t.
+	VMAddress = vmAddress
+
+	// VMSize represents a vm_size_t.
+	VMSize = vmSize
+
+	// VMProt represents a vm_prot_t, a set of virtual memory protection bits.
+	VMProt = vmProt
+
+	// MachMsgTypeNumber represents a mach_msg_type_number_t, the number of
+	// natural_t elements of an out-of-line or info array.
+	MachMsgTypeNumber = machMsgTypeNumber
+)
+
+// MachPortNull is the null mach_port_t.
+const MachPortNull MachPort = machPortNull
+
+// list of VMProt values.
+const (
+	VMProtNone    VMProt = vmProtNone
+	VMProtRead    VMProt = vmProtRead
+	VMProtWrite   VMProt = vmProtWrite
+	VMProtExecute VMProt = vmProtExecute
+
+	// VMProtDefault is the default protection for newly-created virtual memory.
+	VMProtDefault VMProt = vmProtDefault
+
+	// VMProtAll is the maximum privileges possible, for parameter checking.
+	VMProtAll VMProt = vmProtAll
+)
+
+// list of Mach info structs.
+type (
+	// TimeValue represents a time_value_t.
+	TimeValue = timeValue
+
+	// TaskBasicInfo represents a struct task_basic_info, returned by task_info
+	// for the TaskBasicInfoFlavor flavor.
+	//
+	// The C struct is packed to 4 bytes, use the VirtualSize and ResidentSize
+	// methods to read its misaligned fields.
+	TaskBasicInfo = taskBasicInfo
+
+	// ThreadBasicInfo represents a struct thread_basic_info, returned by
+	// thread_info for the ThreadBasicInfoFlavor flavor.
+	ThreadBasicInfo = threadBasicInfo
+
+	// VMStatistics64 represents a struct vm_statistics64, returned by
+	// host_statistics64 for the HostVMInfo64Flavor flavor.
+	VMStatistics64 = vmStatistics64
+)
+
+// list of info flavors.
+const (
+	TaskBasicInfoFlavor   = taskBasicInfoFlavor
+	ThreadBasicInfoFlavor = threadBasicInfoFlavor
+	HostVMInfo64Flavor    = hostVMInfo64Flavor
+)
+
+// list of info structs size in natural_t units.
+const (
+	TaskBasicInfoCount   MachMsgTypeNumber = taskBasicInfoCount
+	ThreadBasicInfoCount MachMsgTypeNumber = threadBasicInfoCount
+	HostVMInfo64Count    MachMsgTypeNumber = hostVMInfo64Count
+)
+
+// VirtualSize returns the virtual memory size of the task in bytes.
+func (i *TaskBasicInfo) VirtualSize() VMSize {
+	return VMSize(binary.LittleEndian.Uint64(i.Virtual_size[:]))
+}
+
+// ResidentSize returns the resident memory size of the task in bytes.
+func (i *TaskBasicInfo) ResidentSize() VMSize {
+	return VMSize(binary.LittleEndian.Uint64(i.Resident_size[:]))
+}
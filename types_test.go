@@ -0,0 +1,59 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys_test
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/go-darwin/sys"
+)
+
+func TestMachInfoSize(t *testing.T) {
+	tests := []struct {
+		name  string
+		size  uintptr
+		count sys.MachMsgTypeNumber
+	}{
+		{"TaskBasicInfo", unsafe.Sizeof(sys.TaskBasicInfo{}), sys.TaskBasicInfoCount},
+		{"ThreadBasicInfo", unsafe.Sizeof(sys.ThreadBasicInfo{}), sys.ThreadBasicInfoCount},
+		{"VMStatistics64", unsafe.Sizeof(sys.VMStatistics64{}), sys.HostVMInfo64Count},
+	}
+	for _, tt := range tests {
+		if want := uintptr(tt.count) * unsafe.Sizeof(sys.Natural(0)); tt.size != want {
+			t.Errorf("sizeof(%s) = %d, want %d", tt.name, tt.size, want)
+		}
+	}
+
+	if got := unsafe.Offsetof(sys.VMStatistics64{}.Total_uncompressed_pages_in_compressor); got != 144 {
+		t.Errorf("offsetof(VMStatistics64.Total_uncompressed_pages_in_compressor) = %d, want 144", got)
+	}
+}
+
+func TestTaskBasicInfo(t *testing.T) {
+	var buf [sys.TaskBasicInfoCount]sys.Natural
+	buf[0] = 3          // suspend_count
+	buf[1] = 0x89abcdef // virtual_size low
+	buf[2] = 0x1        // virtual_size high
+	buf[3] = 0x1000     // resident_size low
+	buf[5] = 42         // user_time.seconds
+	buf[9] = 1          // policy
+
+	info := (*sys.TaskBasicInfo)(unsafe.Pointer(&buf[0]))
+	if got, want := info.Suspend_count, int32(3); got != want {
+		t.Errorf("Suspend_count = %d, want %d", got, want)
+	}
+	if got, want := info.VirtualSize(), sys.VMSize(0x189abcdef); got != want {
+		t.Errorf("VirtualSize() = %#x, want %#x", got, want)
+	}
+	if got, want := info.ResidentSize(), sys.VMSize(0x1000); got != want {
+		t.Errorf("ResidentSize() = %#x, want %#x", got, want)
+	}
+	if got, want := info.User_time.Seconds, int32(42); got != want {
+		t.Errorf("User_time.Seconds = %d, want %d", got, want)
+	}
+	if got, want := info.Policy, int32(1); got != want {
+		t.Errorf("Policy = %d, want %d", got, want)
+	}
+}
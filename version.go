@@ -0,0 +1,224 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// OSVersion is the version of an operating system release.
+type OSVersion struct {
+	Name    string // product name, such as "macOS", or the NAME of os-release
+	Version string // product version, such as "12.5.1"
+	Build   string // build version, such as "21G72", or the BUILD_ID of os-release
+
+	// the leading numeric components of Version, 0 if missing
+	Major, Minor, Patch int
+}
+
+// String returns the name, version and build of v, such as
+// "macOS 12.5.1 (21G72)".
+func (v OSVersion) String() string {
+	s := v.Name
+	if v.Version != "" {
+		s += " " + v.Version
+	}
+	if v.Build != "" {
+		s += " (" + v.Build + ")"
+	}
+
+	return strings.TrimSpace(s)
+}
+
+// AtLeast reports whether v is major.minor.patch or later.
+func (v OSVersion) AtLeast(major, minor, patch int) bool {
+	if v.Major != major {
+		return v.Major > major
+	}
+	if v.Minor != minor {
+		return v.Minor > minor
+	}
+
+	return v.Patch >= patch
+}
+
+// setVersion sets the product version of v and its numeric components,
+// parsed up to the first component not being a decimal number, such as
+// "04" of "22.04" or none of "rolling".
+func (v *OSVersion) setVersion(s string) {
+	v.Version = s
+	nums := [3]*int{&v.Major, &v.Minor, &v.Patch}
+	for i, c := range strings.SplitN(s, ".", len(nums)+1) {
+		if i == len(nums) {
+			break
+		}
+		n, err := strconv.Atoi(c)
+		if err != nil || n < 0 {
+			break
+		}
+		*nums[i] = n
+	}
+}
+
+var osVersion struct {
+	once sync.Once
+	v    OSVersion
+	err  error
+}
+
+// Version returns the version of the running operating system.
+//
+// On darwin, it is the product version of the kern.osproductversion sysctl
+// and the build of kern.osversion, or those of
+// /System/Library/CoreServices/SystemVersion.plist on the releases without
+// kern.osproductversion, older than macOS 10.13.4. On linux, it is the
+// version of the distribution of /etc/os-release, or /usr/lib/os-release,
+// so that the callers of Version and Available are portable.
+func Version() (OSVersion, error) {
+	osVersion.once.Do(func() {
+		osVersion.v, osVersion.err = readVersion()
+	})
+
+	return osVersion.v, osVersion.err
+}
+
+// Available reports whether the running operating system is of the version
+// major.minor.patch or later, like the @available(macOS major.minor.patch, *)
+// condition of Objective-C and the #available one of Swift. It reports
+// false if its version is unknown.
+//
+//	if sys.Available(13, 0, 0) {
+//		// call an API of macOS 13
+//	}
+func Available(major, minor, patch int) bool {
+	v, err := Version()
+	return err == nil && v.AtLeast(major, minor, patch)
+}
+
+// ErrNoVersion is returned by ParseSystemVersion and ParseOSRelease when the
+// file holds no product version.
+var ErrNoVersion = errors.New("sys: no product version")
+
+// ParseSystemVersion parses the property list of the SystemVersion.plist
+// file of macOS, read from r, whose ProductName, ProductVersion and
+// ProductBuildVersion keys are the ones of the version.
+func ParseSystemVersion(r io.Reader) (OSVersion, error) {
+	d := xml.NewDecoder(r)
+	depth := 0 // of the elements in the <plist> one
+	var key string
+	var v OSVersion
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return OSVersion{}, errors.New("sys: SystemVersion.plist: " + err.Error())
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			depth++
+			if depth != 3 {
+				// the <plist> and top <dict> elements, or the
+				// elements of a value
+				if depth == 2 && tok.Name.Local != "dict" {
+					return OSVersion{}, errors.New("sys: SystemVersion.plist: not a dictionary")
+				}
+				continue
+			}
+			var s string
+			if err := d.DecodeElement(&s, &tok); err != nil {
+				return OSVersion{}, errors.New("sys: SystemVersion.plist: " + err.Error())
+			}
+			depth--
+			if tok.Name.Local == "key" {
+				key = s
+				continue
+			}
+			switch key {
+			case "ProductName":
+				v.Name = s
+			case "ProductVersion":
+				v.setVersion(s)
+			case "ProductBuildVersion":
+				v.Build = s
+			}
+			key = ""
+		case xml.EndElement:
+			depth--
+		}
+	}
+	if v.Version == "" {
+		return OSVersion{}, ErrNoVersion
+	}
+
+	return v, nil
+}
+
+// ParseOSRelease parses the os-release file of a linux distribution, read
+// from r, whose NAME, VERSION_ID and BUILD_ID variables are the ones of the
+// version. The numeric components of a VERSION_ID such as "22.04" are those
+// of the version, and a distribution without VERSION_ID, such as a rolling
+// release, has its BUILD_ID as its product version.
+func ParseOSRelease(r io.Reader) (OSVersion, error) {
+	vars := make(map[string]string)
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		i := strings.IndexByte(line, '=')
+		if i <= 0 {
+			continue
+		}
+		vars[line[:i]] = unquoteOSRelease(line[i+1:])
+	}
+	if err := s.Err(); err != nil {
+		return OSVersion{}, err
+	}
+
+	v := OSVersion{Name: vars["NAME"], Build: vars["BUILD_ID"]}
+	if v.Name == "" {
+		v.Name = "Linux" // the default of os-release(5)
+	}
+	version := vars["VERSION_ID"]
+	if version == "" {
+		version = v.Build
+	}
+	if version == "" {
+		return OSVersion{}, ErrNoVersion
+	}
+	v.setVersion(version)
+
+	return v, nil
+}
+
+// unquoteOSRelease returns the value of an assignment of os-release, with
+// the shell quoting and escapes of os-release(5).
+func unquoteOSRelease(s string) string {
+	if len(s) < 2 || s[0] != s[len(s)-1] || s[0] != '"' && s[0] != '\'' {
+		return s
+	}
+	quote := s[0]
+	s = s[1 : len(s)-1]
+	if quote == '\'' {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && strings.IndexByte("\"\\`$", s[i+1]) >= 0 {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
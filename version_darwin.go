@@ -0,0 +1,39 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys
+
+import (
+	"os"
+	"syscall"
+)
+
+// systemVersionPlist is the file of the version of macOS.
+const systemVersionPlist = "/System/Library/CoreServices/SystemVersion.plist"
+
+func readVersion() (OSVersion, error) {
+	// Unlike SystemVersion.plist, which reads as 10.16 on macOS 11 for the
+	// programs linked with an older SDK, the sysctls hold the real version.
+	product, err := syscall.Sysctl("kern.osproductversion")
+	if err != nil {
+		return readSystemVersion()
+	}
+	build, err := syscall.Sysctl("kern.osversion")
+	if err != nil {
+		return OSVersion{}, err
+	}
+	v := OSVersion{Name: "macOS", Build: build}
+	v.setVersion(product)
+
+	return v, nil
+}
+
+func readSystemVersion() (OSVersion, error) {
+	f, err := os.Open(systemVersionPlist)
+	if err != nil {
+		return OSVersion{}, err
+	}
+	defer f.Close()
+
+	return ParseSystemVersion(f)
+}
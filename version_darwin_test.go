@@ -0,0 +1,37 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys_test
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/go-darwin/sys"
+)
+
+func TestVersionSwVers(t *testing.T) {
+	out, err := exec.Command("sw_vers", "-productVersion").Output()
+	if err != nil {
+		t.Skip(err)
+	}
+	build, err := exec.Command("sw_vers", "-buildVersion").Output()
+	if err != nil {
+		t.Skip(err)
+	}
+
+	v, err := sys.Version()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := strings.TrimSpace(string(out)); v.Version != want {
+		t.Errorf("Version = %q, want %q", v.Version, want)
+	}
+	if want := strings.TrimSpace(string(build)); v.Build != want {
+		t.Errorf("Build = %q, want %q", v.Build, want)
+	}
+	if v.Major < 10 {
+		t.Errorf("Major = %d", v.Major)
+	}
+}
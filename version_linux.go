@@ -0,0 +1,23 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys
+
+import "os"
+
+// osReleaseFiles are the os-release files, in the order of os-release(5).
+var osReleaseFiles = []string{"/etc/os-release", "/usr/lib/os-release"}
+
+func readVersion() (OSVersion, error) {
+	var err error
+	for _, name := range osReleaseFiles {
+		var f *os.File
+		if f, err = os.Open(name); err != nil {
+			continue
+		}
+		defer f.Close()
+		return ParseOSRelease(f)
+	}
+
+	return OSVersion{}, err
+}
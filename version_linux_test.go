@@ -0,0 +1,31 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/go-darwin/sys"
+)
+
+func TestVersionOSRelease(t *testing.T) {
+	f, err := os.Open("/etc/os-release")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer f.Close()
+	want, err := sys.ParseOSRelease(f)
+	if err != nil {
+		t.Skip(err)
+	}
+
+	v, err := sys.Version()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != want {
+		t.Errorf("Version = %+v, want %+v", v, want)
+	}
+}
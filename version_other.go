@@ -0,0 +1,13 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build !darwin && !linux
+// +build !darwin,!linux
+
+package sys
+
+import "errors"
+
+func readVersion() (OSVersion, error) {
+	return OSVersion{}, errors.New("sys: Version not implemented on this platform")
+}
@@ -0,0 +1,148 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-darwin/sys"
+)
+
+func TestParseSystemVersion(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "version", "SystemVersion.plist"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	v, err := sys.ParseSystemVersion(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := sys.OSVersion{Name: "macOS", Version: "12.5", Build: "21G72", Major: 12, Minor: 5}
+	if v != want {
+		t.Errorf("ParseSystemVersion = %+v, want %+v", v, want)
+	}
+	if s, want := v.String(), "macOS 12.5 (21G72)"; s != want {
+		t.Errorf("String = %q, want %q", s, want)
+	}
+}
+
+func TestParseSystemVersionError(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"no version", `<plist><dict><key>ProductName</key><string>macOS</string></dict></plist>`, "no product version"},
+		{"array", `<plist><array><string>12.5</string></array></plist>`, "not a dictionary"},
+		{"syntax", `<plist><dict><key>ProductVersion</key><string>12.5</dict></plist>`, "SystemVersion.plist: XML syntax error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := sys.ParseSystemVersion(strings.NewReader(tt.src))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseSystemVersion = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOSRelease(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "version", "os-release"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	v, err := sys.ParseOSRelease(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := sys.OSVersion{Name: "Ubuntu", Version: "22.04", Major: 22, Minor: 4}
+	if v != want {
+		t.Errorf("ParseOSRelease = %+v, want %+v", v, want)
+	}
+
+	tests := []struct {
+		name string
+		src  string
+		want sys.OSVersion
+	}{
+		{
+			"quoting",
+			"NAME='Alpine Linux'\nVERSION_ID=3.16.2\nBUILD_ID=\"b\\\"1\\\\\"\n",
+			sys.OSVersion{Name: "Alpine Linux", Version: "3.16.2", Build: `b"1\`, Major: 3, Minor: 16, Patch: 2},
+		},
+		{
+			"rolling",
+			"NAME=\"Arch Linux\"\nBUILD_ID=rolling\n",
+			sys.OSVersion{Name: "Arch Linux", Version: "rolling", Build: "rolling"},
+		},
+		{
+			"default name",
+			"VERSION_ID=\"11.4-beta\"\n",
+			sys.OSVersion{Name: "Linux", Version: "11.4-beta", Major: 11},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := sys.ParseOSRelease(strings.NewReader(tt.src))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v != tt.want {
+				t.Errorf("ParseOSRelease = %+v, want %+v", v, tt.want)
+			}
+		})
+	}
+
+	if _, err := sys.ParseOSRelease(strings.NewReader("NAME=Linux\n")); !errors.Is(err, sys.ErrNoVersion) {
+		t.Errorf("ParseOSRelease without version = %v, want ErrNoVersion", err)
+	}
+}
+
+func TestOSVersionAtLeast(t *testing.T) {
+	v := sys.OSVersion{Major: 12, Minor: 5, Patch: 1}
+	tests := []struct {
+		major, minor, patch int
+		want                bool
+	}{
+		{11, 0, 0, true},
+		{12, 0, 0, true},
+		{12, 5, 0, true},
+		{12, 5, 1, true},
+		{12, 5, 2, false},
+		{12, 6, 0, false},
+		{13, 0, 0, false},
+		{11, 7, 9, true},
+	}
+	for _, tt := range tests {
+		if got := v.AtLeast(tt.major, tt.minor, tt.patch); got != tt.want {
+			t.Errorf("AtLeast(%d, %d, %d) = %v, want %v", tt.major, tt.minor, tt.patch, got, tt.want)
+		}
+	}
+}
+
+func TestAvailable(t *testing.T) {
+	v, err := sys.Version()
+	if err != nil {
+		if sys.Available(0, 0, 0) {
+			t.Errorf("Available(0, 0, 0) = true with Version error %v", err)
+		}
+		t.Skip(err)
+	}
+	t.Logf("Version = %v", v)
+
+	if !sys.Available(v.Major, v.Minor, v.Patch) {
+		t.Errorf("Available(%d, %d, %d) = false", v.Major, v.Minor, v.Patch)
+	}
+	if sys.Available(v.Major+1, 0, 0) {
+		t.Errorf("Available(%d, 0, 0) = true", v.Major+1)
+	}
+}
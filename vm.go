@@ -0,0 +1,213 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"unsafe"
+)
+
+// PageSize returns the size of the pages of the virtual memory, 16 KiB on
+// darwin/arm64 and 4 KiB on darwin/amd64 and most linux hosts.
+func PageSize() int {
+	return os.Getpagesize()
+}
+
+// RoundPage rounds n up to a multiple of the page size.
+func RoundPage(n int) int {
+	page := PageSize()
+	return (n + page - 1) &^ (page - 1)
+}
+
+// mappedBytes returns the slice of the length bytes of the memory mapped at
+// addr, outside of the Go heap.
+func mappedBytes(addr uintptr, length int) []byte {
+	var p unsafe.Pointer
+	*(*uintptr)(unsafe.Pointer(&p)) = addr
+
+	return unsafe.Slice((*byte)(p), length)
+}
+
+// ErrWriteExecute is returned by Region.Protect for a protection both
+// writable and executable.
+var ErrWriteExecute = errors.New("sys: memory both writable and executable")
+
+// ErrRegionFreed is returned by the methods of a freed Region.
+var ErrRegionFreed = errors.New("sys: use of freed memory region")
+
+// RegionOptions are the options of AllocateRegion.
+type RegionOptions struct {
+	// GuardPages is the number of the inaccessible pages before and after
+	// the region, which make its overflows fault.
+	GuardPages int
+
+	// JIT maps the region with MAP_JIT on darwin, for the code generated at
+	// runtime by the programs of the hardened runtime, and toggles its
+	// protection per thread with pthread_jit_write_protect_np on Apple
+	// silicon. It is a plain region elsewhere.
+	JIT bool
+}
+
+// Region is a private anonymous mapping of whole pages, whose protection
+// moves between writable and executable, but never both: W^X.
+//
+// A region is created writable. The protection of a JIT region of Apple
+// silicon is set for the calling thread only, which must call
+// runtime.LockOSThread before changing it, and keep it locked while writing
+// or executing the region; the other threads keep theirs. The other regions
+// have the same protection on every thread, set with mprotect.
+//
+// A Region is safe for concurrent use.
+type Region struct {
+	mu     sync.Mutex
+	mem    []byte // the whole mapping, with the guard pages
+	data   []byte
+	prot   VMProt
+	jit    bool // toggled with pthread_jit_write_protect_np
+	freed  bool
+	guards int // the size of the guard pages at each end
+}
+
+// AllocateRegion maps a Region of size bytes rounded up to whole pages,
+// readable and writable, with the options opts, which may be nil.
+func AllocateRegion(size int, opts *RegionOptions) (*Region, error) {
+	if size <= 0 {
+		return nil, os.ErrInvalid
+	}
+	var o RegionOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.GuardPages < 0 {
+		return nil, os.ErrInvalid
+	}
+	r := &Region{prot: VMProtRead | VMProtWrite}
+	size = RoundPage(size)
+	r.guards = o.GuardPages * PageSize()
+	total := size + 2*r.guards
+
+	// A MAP_JIT mapping must be created writable and executable, the
+	// other mappings are created inaccessible, except their data.
+	var err error
+	if o.JIT && MapJIT != 0 {
+		r.mem, err = Mmap(0, total, VMProtRead|VMProtWrite|VMProtExecute, MapPrivate|MapAnon|MapJIT, -1, 0)
+	} else {
+		r.mem, err = Mmap(0, total, VMProtNone, MapPrivate|MapAnon, -1, 0)
+	}
+	if err != nil {
+		return nil, err
+	}
+	r.data = r.mem[r.guards : r.guards+size : r.guards+size]
+
+	if o.JIT && jitWriteProtectSupported() {
+		r.jit = true
+		jitWriteProtect(false)
+		err = r.protectGuards()
+	} else {
+		if err = r.protectGuards(); err == nil {
+			err = Mprotect(r.data, r.prot)
+		}
+	}
+	if err != nil {
+		Munmap(r.mem)
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// protectGuards makes the guard pages inaccessible.
+func (r *Region) protectGuards() error {
+	if r.guards == 0 {
+		return nil
+	}
+	if err := Mprotect(r.mem[:r.guards], VMProtNone); err != nil {
+		return err
+	}
+
+	return Mprotect(r.mem[len(r.mem)-r.guards:], VMProtNone)
+}
+
+// Bytes returns the memory of the region, without its guard pages. The
+// slice must not be used after Free.
+func (r *Region) Bytes() []byte {
+	return r.data
+}
+
+// Prot returns the last protection set by Protect, on any thread. For a JIT
+// region of Apple silicon, it may not be the one of the calling thread,
+// whose protection is not queryable.
+func (r *Region) Prot() VMProt {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.prot
+}
+
+// Protect sets the protection of the region to prot, which must not be
+// both writable and executable.
+func (r *Region) Protect(prot VMProt) error {
+	if prot&(VMProtWrite|VMProtExecute) == VMProtWrite|VMProtExecute {
+		return ErrWriteExecute
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.freed {
+		return ErrRegionFreed
+	}
+	if r.jit {
+		// the mapping stays RWX, the thread sees it RW or RX
+		if prot != VMProtRead|VMProtWrite && prot != VMProtRead|VMProtExecute {
+			return os.ErrInvalid
+		}
+		jitWriteProtect(prot&VMProtExecute != 0)
+	} else if err := Mprotect(r.data, prot); err != nil {
+		return err
+	}
+	if prot&VMProtExecute != 0 && r.prot&VMProtExecute == 0 {
+		invalidateICache(r.data)
+	}
+	r.prot = prot
+
+	return nil
+}
+
+// MakeWritable makes the region readable and writable, not executable.
+func (r *Region) MakeWritable() error {
+	return r.Protect(VMProtRead | VMProtWrite)
+}
+
+// MakeExecutable makes the region readable and executable, not writable,
+// invalidating the instruction cache of the region on darwin and on
+// linux/arm64.
+func (r *Region) MakeExecutable() error {
+	return r.Protect(VMProtRead | VMProtExecute)
+}
+
+// Advise advises the kernel of the use of the region, with one of the Madv
+// constants, like madvise.
+func (r *Region) Advise(advice int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.freed {
+		return ErrRegionFreed
+	}
+
+	return Madvise(r.data, advice)
+}
+
+// Free unmaps the region and its guard pages.
+func (r *Region) Free() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.freed {
+		return ErrRegionFreed
+	}
+	r.freed = true
+
+	return Munmap(r.mem)
+}
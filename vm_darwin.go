@@ -0,0 +1,196 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build darwin && (amd64 || arm64) && gc
+// +build darwin
+// +build amd64 arm64
+// +build gc
+
+package sys
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// list of the flags of Mmap, of sys/mman.h.
+const (
+	MapShared  = 0x1
+	MapPrivate = 0x2
+	MapFixed   = 0x10
+	MapAnon    = 0x1000
+
+	// MapJIT maps the memory of the code generated at runtime, whose
+	// protection is toggled per thread on Apple silicon, and which may be
+	// writable and executable for the programs of the hardened runtime
+	// with the com.apple.security.cs.allow-jit entitlement.
+	MapJIT = 0x800
+)
+
+// list of the advices of Madvise, of sys/mman.h.
+const (
+	MadvNormal     = 0
+	MadvRandom     = 1
+	MadvSequential = 2
+	MadvWillNeed   = 3
+	MadvDontNeed   = 4
+	MadvFree       = 5
+)
+
+// list of the flags of VMAllocate, of mach/vm_statistics.h.
+const (
+	VMFlagsFixed    = 0x0
+	VMFlagsAnywhere = 0x1
+)
+
+// The functions of libsystem_kernel and libsystem_platform, called through
+// the trampolines of vm_darwin.s.
+
+//go:cgo_import_dynamic libc_mmap mmap "/usr/lib/libSystem.B.dylib"
+//go:cgo_import_dynamic libc_mprotect mprotect "/usr/lib/libSystem.B.dylib"
+//go:cgo_import_dynamic libc_munmap munmap "/usr/lib/libSystem.B.dylib"
+//go:cgo_import_dynamic libc_madvise madvise "/usr/lib/libSystem.B.dylib"
+//go:cgo_import_dynamic libc_sys_icache_invalidate sys_icache_invalidate "/usr/lib/libSystem.B.dylib"
+
+// the addresses of the trampolines, set by vm_darwin.s.
+var (
+	mmapTrampolineAddr                uintptr
+	mprotectTrampolineAddr            uintptr
+	munmapTrampolineAddr              uintptr
+	madviseTrampolineAddr             uintptr
+	sysIcacheInvalidateTrampolineAddr uintptr
+)
+
+// pthread_jit_write_protect_np is in the macOS releases from 11.0 only.
+var (
+	libc_pthread_jit_write_protect_np           = NewWeakSymbol("/usr/lib/libSystem.B.dylib", "pthread_jit_write_protect_np")
+	libc_pthread_jit_write_protect_supported_np = NewWeakSymbol("/usr/lib/libSystem.B.dylib", "pthread_jit_write_protect_supported_np")
+)
+
+// vmcall and vmcallX are the functions of Ccall6 and Ccall6X, which are
+// declared on amd64 only.
+
+//go:linkname vmcall syscall.syscall6
+//go:noescape
+func vmcall(fn, a1, a2, a3, a4, a5, a6 uintptr) (r1, r2 uintptr, err Errno)
+
+//go:linkname vmcallX syscall.syscall6X
+//go:noescape
+func vmcallX(fn, a1, a2, a3, a4, a5, a6 uintptr) (r1, r2 uintptr, err Errno)
+
+// Mmap maps length bytes of the file fd at offset, or of anonymous memory
+// with MapAnon and the fd -1, at addr or at an address of the kernel if addr
+// is 0, like mmap.
+//
+// The returned slice must be unmapped with Munmap.
+func Mmap(addr uintptr, length int, prot VMProt, flags, fd int, offset int64) ([]byte, error) {
+	if length <= 0 {
+		return nil, syscall.EINVAL
+	}
+	r1, _, errno := vmcallX(mmapTrampolineAddr, addr, uintptr(length), uintptr(prot), uintptr(flags), uintptr(fd), uintptr(offset))
+	if errno != 0 {
+		return nil, errno
+	}
+
+	return mappedBytes(r1, length), nil
+}
+
+// Mprotect sets the protection of the pages of b to prot, like mprotect.
+func Mprotect(b []byte, prot VMProt) error {
+	if len(b) == 0 {
+		return nil
+	}
+	if _, _, errno := vmcall(mprotectTrampolineAddr, uintptr(unsafe.Pointer(&b[0])), uintptr(len(b)), uintptr(prot), 0, 0, 0); errno != 0 {
+		return errno
+	}
+
+	return nil
+}
+
+// Munmap unmaps the pages of b, of Mmap, like munmap.
+func Munmap(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	if _, _, errno := vmcall(munmapTrampolineAddr, uintptr(unsafe.Pointer(&b[0])), uintptr(len(b)), 0, 0, 0, 0); errno != 0 {
+		return errno
+	}
+
+	return nil
+}
+
+// Madvise advises the kernel of the use of the pages of b, with one of the
+// Madv constants, like madvise.
+func Madvise(b []byte, advice int) error {
+	if len(b) == 0 {
+		return nil
+	}
+	if _, _, errno := vmcall(madviseTrampolineAddr, uintptr(unsafe.Pointer(&b[0])), uintptr(len(b)), uintptr(advice), 0, 0, 0); errno != 0 {
+		return errno
+	}
+
+	return nil
+}
+
+// invalidateICache invalidates the instruction cache of b, of the code
+// written into it, with sys_icache_invalidate.
+func invalidateICache(b []byte) {
+	if len(b) == 0 {
+		return
+	}
+	vmcall(sysIcacheInvalidateTrampolineAddr, uintptr(unsafe.Pointer(&b[0])), uintptr(len(b)), 0, 0, 0, 0)
+}
+
+// jitWriteProtectSupported reports whether the protection of the MAP_JIT
+// memory is toggled per thread, on Apple silicon.
+func jitWriteProtectSupported() bool {
+	fn, ok := libc_pthread_jit_write_protect_supported_np.Addr()
+	if !ok {
+		return false
+	}
+	r1, _, _ := vmcall(fn, 0, 0, 0, 0, 0, 0)
+
+	return int32(r1) != 0
+}
+
+// jitWriteProtect makes the MAP_JIT memory executable for the calling
+// thread if enabled, and writable otherwise, with
+// pthread_jit_write_protect_np.
+func jitWriteProtect(enabled bool) {
+	fn, ok := libc_pthread_jit_write_protect_np.Addr()
+	if !ok {
+		return
+	}
+	var v uintptr
+	if enabled {
+		v = 1
+	}
+	vmcall(fn, v, 0, 0, 0, 0, 0)
+}
+
+// VMAllocate allocates size bytes of zero-filled memory in the current
+// task, like mach_vm_allocate, at an address of the kernel with
+// VMFlagsAnywhere, and at addr otherwise.
+func VMAllocate(addr, size uint64, flags int) (uint64, KernReturn) {
+	kr := RawMachTrap4(uintptr(TrapMachVMAllocate), hostTask(), uintptr(unsafe.Pointer(&addr)), uintptr(size), uintptr(flags))
+
+	return addr, kr
+}
+
+// VMDeallocate deallocates the size bytes at addr of the current task, like
+// mach_vm_deallocate.
+func VMDeallocate(addr, size uint64) KernReturn {
+	return RawMachTrap3(uintptr(TrapMachVMDeallocate), hostTask(), uintptr(addr), uintptr(size))
+}
+
+// VMProtect sets the protection of the size bytes at addr of the current
+// task to prot, like mach_vm_protect, or their maximum protection if
+// setMaximum.
+func VMProtect(addr, size uint64, setMaximum bool, prot VMProt) KernReturn {
+	var max uintptr
+	if setMaximum {
+		max = 1
+	}
+
+	return RawMachTrap5(uintptr(TrapMachVMProtect), hostTask(), uintptr(addr), uintptr(size), max, uintptr(prot))
+}
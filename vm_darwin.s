@@ -0,0 +1,39 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build darwin && (amd64 || arm64) && gc
+// +build darwin
+// +build amd64 arm64
+// +build gc
+
+#include "textflag.h"
+
+TEXT mmap_trampoline<>(SB), NOSPLIT, $0-0
+	JMP libc_mmap(SB)
+
+GLOBL ·mmapTrampolineAddr(SB), RODATA, $8
+DATA ·mmapTrampolineAddr(SB)/8, $mmap_trampoline<>(SB)
+
+TEXT mprotect_trampoline<>(SB), NOSPLIT, $0-0
+	JMP libc_mprotect(SB)
+
+GLOBL ·mprotectTrampolineAddr(SB), RODATA, $8
+DATA ·mprotectTrampolineAddr(SB)/8, $mprotect_trampoline<>(SB)
+
+TEXT munmap_trampoline<>(SB), NOSPLIT, $0-0
+	JMP libc_munmap(SB)
+
+GLOBL ·munmapTrampolineAddr(SB), RODATA, $8
+DATA ·munmapTrampolineAddr(SB)/8, $munmap_trampoline<>(SB)
+
+TEXT madvise_trampoline<>(SB), NOSPLIT, $0-0
+	JMP libc_madvise(SB)
+
+GLOBL ·madviseTrampolineAddr(SB), RODATA, $8
+DATA ·madviseTrampolineAddr(SB)/8, $madvise_trampoline<>(SB)
+
+TEXT sys_icache_invalidate_trampoline<>(SB), NOSPLIT, $0-0
+	JMP libc_sys_icache_invalidate(SB)
+
+GLOBL ·sysIcacheInvalidateTrampolineAddr(SB), RODATA, $8
+DATA ·sysIcacheInvalidateTrampolineAddr(SB)/8, $sys_icache_invalidate_trampoline<>(SB)
@@ -0,0 +1,53 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build darwin && (amd64 || arm64) && gc
+// +build darwin
+// +build amd64 arm64
+// +build gc
+
+package sys_test
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/go-darwin/sys"
+)
+
+func TestVMAllocate(t *testing.T) {
+	size := uint64(sys.PageSize())
+	addr, kr := sys.VMAllocate(0, size, sys.VMFlagsAnywhere)
+	if kr != sys.KernSuccess {
+		t.Fatal(kr)
+	}
+	if addr == 0 || addr%size != 0 {
+		t.Fatalf("VMAllocate = %#x", addr)
+	}
+
+	var p unsafe.Pointer
+	*(*uintptr)(unsafe.Pointer(&p)) = uintptr(addr)
+	b := unsafe.Slice((*byte)(p), size)
+	b[0] = 42
+
+	if kr := sys.VMProtect(addr, size, false, sys.VMProtRead); kr != sys.KernSuccess {
+		t.Fatal(kr)
+	}
+	if !faults(func() { b[0] = 1 }) {
+		t.Error("write to read-only memory did not fault")
+	}
+	// the maximum protection cannot be raised
+	if kr := sys.VMProtect(addr, size, true, sys.VMProtRead); kr != sys.KernSuccess {
+		t.Fatal(kr)
+	}
+	if kr := sys.VMProtect(addr, size, false, sys.VMProtRead|sys.VMProtWrite); kr != sys.KernProtectionFailure {
+		t.Errorf("VMProtect above the maximum = %v, want %v", kr, sys.KernProtectionFailure)
+	}
+	if b[0] != 42 {
+		t.Errorf("b[0] = %d, want 42", b[0])
+	}
+
+	if kr := sys.VMDeallocate(addr, size); kr != sys.KernSuccess {
+		t.Fatal(kr)
+	}
+}
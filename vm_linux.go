@@ -0,0 +1,81 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// list of the flags of Mmap, of sys/mman.h.
+const (
+	MapShared  = 0x1
+	MapPrivate = 0x2
+	MapFixed   = 0x10
+	MapAnon    = 0x20
+
+	// MapJIT is 0 on linux, whose executable mappings are not restricted,
+	// so that the JIT regions are plain ones.
+	MapJIT = 0x0
+)
+
+// list of the advices of Madvise, of sys/mman.h.
+const (
+	MadvNormal     = 0
+	MadvRandom     = 1
+	MadvSequential = 2
+	MadvWillNeed   = 3
+	MadvDontNeed   = 4
+	MadvFree       = 8
+)
+
+// Mmap maps length bytes of the file fd at offset, or of anonymous memory
+// with MapAnon and the fd -1, at addr or at an address of the kernel if addr
+// is 0, like mmap.
+//
+// The returned slice must be unmapped with Munmap.
+func Mmap(addr uintptr, length int, prot VMProt, flags, fd int, offset int64) ([]byte, error) {
+	if length <= 0 {
+		return nil, syscall.EINVAL
+	}
+	r1, _, errno := syscall.Syscall6(syscall.SYS_MMAP, addr, uintptr(length), uintptr(prot), uintptr(flags), uintptr(fd), uintptr(offset))
+	if errno != 0 {
+		return nil, errno
+	}
+
+	return mappedBytes(r1, length), nil
+}
+
+// Mprotect sets the protection of the pages of b to prot, like mprotect.
+func Mprotect(b []byte, prot VMProt) error {
+	return vmSyscall(syscall.SYS_MPROTECT, b, uintptr(prot))
+}
+
+// Munmap unmaps the pages of b, of Mmap, like munmap.
+func Munmap(b []byte) error {
+	return vmSyscall(syscall.SYS_MUNMAP, b, 0)
+}
+
+// Madvise advises the kernel of the use of the pages of b, with one of the
+// Madv constants, like madvise.
+func Madvise(b []byte, advice int) error {
+	return vmSyscall(syscall.SYS_MADVISE, b, uintptr(advice))
+}
+
+// vmSyscall calls the system call trap with the address and the length of
+// b, and arg.
+func vmSyscall(trap uintptr, b []byte, arg uintptr) error {
+	if len(b) == 0 {
+		return nil
+	}
+	if _, _, errno := syscall.Syscall(trap, uintptr(unsafe.Pointer(&b[0])), uintptr(len(b)), arg); errno != 0 {
+		return errno
+	}
+
+	return nil
+}
+
+func jitWriteProtectSupported() bool { return false }
+
+func jitWriteProtect(enabled bool) {}
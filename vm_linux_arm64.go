@@ -0,0 +1,26 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys
+
+import "unsafe"
+
+// invalidateICache cleans the data cache and invalidates the instruction
+// cache of b, of the code written into it, like __clear_cache.
+//
+// The kernel does not do it for the pages made executable again: it cleans
+// the caches of a page once, on its first mapping as executable.
+func invalidateICache(b []byte) {
+	if len(b) == 0 {
+		return
+	}
+	start := uintptr(unsafe.Pointer(&b[0]))
+	clearCache(start, start+uintptr(len(b)))
+}
+
+// clearCache cleans the data cache to the point of unification and
+// invalidates the instruction cache of the addresses [start, end), by
+// the cache lines of CTR_EL0.
+//
+//go:noescape
+func clearCache(start, end uintptr)
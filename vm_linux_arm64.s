@@ -0,0 +1,39 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+#include "textflag.h"
+
+// func clearCache(start, end uintptr)
+TEXT ·clearCache(SB), NOSPLIT, $0-16
+	MOVD start+0(FP), R0
+	MOVD end+8(FP), R1
+	MRS  CTR_EL0, R2
+
+	// DC CVAU by the data cache lines of 4<<CTR_EL0.DminLine bytes
+	UBFX $16, R2, $4, R3
+	MOVD $4, R4
+	LSL  R3, R4, R3
+	SUB  $1, R3, R5
+	BIC  R5, R0, R6
+
+dcache:
+	DC   CVAU, R6
+	ADD  R3, R6
+	CMP  R1, R6
+	BLO  dcache
+	DSB  $11 // ISH
+
+	// IC IVAU by the instruction cache lines of 4<<CTR_EL0.IminLine bytes
+	AND  $15, R2, R3
+	LSL  R3, R4, R3
+	SUB  $1, R3, R5
+	BIC  R5, R0, R6
+
+icache:
+	WORD $0xd50b7526 // IC IVAU, R6
+	ADD  R3, R6
+	CMP  R1, R6
+	BLO  icache
+	DSB  $11 // ISH
+	ISB  $15
+	RET
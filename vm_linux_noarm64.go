@@ -0,0 +1,11 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build linux && !arm64
+// +build linux,!arm64
+
+package sys
+
+// invalidateICache is a no-op on amd64, whose instruction cache is coherent
+// with the stores to the data cache.
+func invalidateICache(b []byte) {}
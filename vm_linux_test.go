@@ -0,0 +1,24 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys_test
+
+import (
+	"testing"
+
+	"github.com/go-darwin/sys"
+)
+
+// TestRegionAdviseLinux checks that MADV_DONTNEED zeroes the pages of a
+// private anonymous region, on linux.
+func TestRegionAdviseLinux(t *testing.T) {
+	r := newRegion(t, 1, nil)
+	b := r.Bytes()
+	b[0] = 42
+	if err := r.Advise(sys.MadvDontNeed); err != nil {
+		t.Fatal(err)
+	}
+	if b[0] != 0 {
+		t.Errorf("b[0] = %d after MadvDontNeed, want 0", b[0])
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build !linux && !(darwin && (amd64 || arm64) && gc)
+// +build !linux
+// +build !darwin !amd64,!arm64 !gc
+
+package sys
+
+import "syscall"
+
+// list of the flags of Mmap.
+const (
+	MapShared  = 0x1
+	MapPrivate = 0x2
+	MapFixed   = 0x10
+	MapAnon    = 0x1000
+	MapJIT     = 0x0
+)
+
+// list of the advices of Madvise.
+const (
+	MadvNormal     = 0
+	MadvRandom     = 1
+	MadvSequential = 2
+	MadvWillNeed   = 3
+	MadvDontNeed   = 4
+	MadvFree       = 5
+)
+
+// Mmap is not implemented on the other platforms.
+func Mmap(addr uintptr, length int, prot VMProt, flags, fd int, offset int64) ([]byte, error) {
+	return nil, syscall.ENOSYS
+}
+
+// Mprotect is not implemented on the other platforms.
+func Mprotect(b []byte, prot VMProt) error { return syscall.ENOSYS }
+
+// Munmap is not implemented on the other platforms.
+func Munmap(b []byte) error { return syscall.ENOSYS }
+
+// Madvise is not implemented on the other platforms.
+func Madvise(b []byte, advice int) error { return syscall.ENOSYS }
+
+func invalidateICache(b []byte) {}
+
+func jitWriteProtectSupported() bool { return false }
+
+func jitWriteProtect(enabled bool) {}
@@ -0,0 +1,250 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys_test
+
+import (
+	"errors"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"syscall"
+	"testing"
+	"unsafe"
+
+	"github.com/go-darwin/sys"
+)
+
+// newRegion returns a Region of size bytes, skipping the test on the
+// platforms without Mmap.
+func newRegion(t *testing.T, size int, opts *sys.RegionOptions) *sys.Region {
+	t.Helper()
+	r, err := sys.AllocateRegion(size, opts)
+	if errors.Is(err, syscall.ENOSYS) {
+		t.Skip("Mmap is not implemented on", runtime.GOOS+"/"+runtime.GOARCH)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { r.Free() })
+
+	return r
+}
+
+// sink keeps the reads of faults.
+var sink byte
+
+// faults reports whether f faults.
+func faults(f func()) (faulted bool) {
+	defer debug.SetPanicOnFault(debug.SetPanicOnFault(true))
+	defer func() {
+		faulted = recover() != nil
+	}()
+	f()
+
+	return false
+}
+
+func TestPageSize(t *testing.T) {
+	page := sys.PageSize()
+	if page != os.Getpagesize() || page&(page-1) != 0 {
+		t.Fatalf("PageSize = %d", page)
+	}
+
+	tests := []struct {
+		n, want int
+	}{
+		{0, 0},
+		{1, page},
+		{page, page},
+		{page + 1, 2 * page},
+	}
+	for _, tt := range tests {
+		if got := sys.RoundPage(tt.n); got != tt.want {
+			t.Errorf("RoundPage(%d) = %d, want %d", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestRegion(t *testing.T) {
+	r := newRegion(t, 100, &sys.RegionOptions{GuardPages: 1})
+	b := r.Bytes()
+	if len(b) != sys.PageSize() {
+		t.Fatalf("len(Bytes) = %d, want %d", len(b), sys.PageSize())
+	}
+	if prot := r.Prot(); prot != sys.VMProtRead|sys.VMProtWrite {
+		t.Fatalf("Prot = %v, want RW", prot)
+	}
+	for i := range b {
+		b[i] = byte(i)
+	}
+
+	if err := r.Protect(sys.VMProtAll); err != sys.ErrWriteExecute {
+		t.Fatalf("Protect(VMProtAll) = %v, want %v", err, sys.ErrWriteExecute)
+	}
+	if err := r.MakeExecutable(); err != nil {
+		t.Fatal(err)
+	}
+	if prot := r.Prot(); prot != sys.VMProtRead|sys.VMProtExecute {
+		t.Fatalf("Prot = %v, want RX", prot)
+	}
+	if b[42] != 42 {
+		t.Errorf("b[42] = %d after MakeExecutable", b[42])
+	}
+	if !faults(func() { b[0] = 1 }) {
+		t.Error("write to an executable region did not fault")
+	}
+
+	if err := r.MakeWritable(); err != nil {
+		t.Fatal(err)
+	}
+	if faults(func() { b[0] = 1 }) {
+		t.Error("write to a writable region faulted")
+	}
+	if err := r.Advise(sys.MadvWillNeed); err != nil {
+		t.Error(err)
+	}
+
+	p := unsafe.Pointer(&b[0])
+	if !faults(func() { sink = *(*byte)(unsafe.Add(p, -1)) }) {
+		t.Error("read of the guard page before the region did not fault")
+	}
+	if !faults(func() { sink = *(*byte)(unsafe.Add(p, len(b))) }) {
+		t.Error("read of the guard page after the region did not fault")
+	}
+
+	if err := r.Free(); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Free(); err != sys.ErrRegionFreed {
+		t.Errorf("second Free = %v, want %v", err, sys.ErrRegionFreed)
+	}
+	if err := r.MakeExecutable(); err != sys.ErrRegionFreed {
+		t.Errorf("MakeExecutable after Free = %v, want %v", err, sys.ErrRegionFreed)
+	}
+}
+
+func TestAllocateRegionInvalid(t *testing.T) {
+	if _, err := sys.AllocateRegion(0, nil); err == nil {
+		t.Error("AllocateRegion(0) succeeded")
+	}
+	if _, err := sys.AllocateRegion(1, &sys.RegionOptions{GuardPages: -1}); err == nil {
+		t.Error("AllocateRegion with -1 guard pages succeeded")
+	}
+}
+
+// the code of a function returning 42.
+var code42 = map[string][]byte{
+	"amd64": {0xb8, 0x2a, 0x00, 0x00, 0x00, 0xc3},             // MOVL $42, AX; RET
+	"arm64": {0x40, 0x05, 0x80, 0x52, 0xc0, 0x03, 0x5f, 0xd6}, // MOVW $42, R0; RET
+}
+
+// the code of a function returning 7, of the size of code42.
+var code7 = map[string][]byte{
+	"amd64": {0xb8, 0x07, 0x00, 0x00, 0x00, 0xc3},             // MOVL $7, AX; RET
+	"arm64": {0xe0, 0x00, 0x80, 0x52, 0xc0, 0x03, 0x5f, 0xd6}, // MOVW $7, R0; RET
+}
+
+// callRegion calls the function at the start of the region r, with the
+// result register of the internal ABI.
+func callRegion(r *sys.Region) int {
+	// a func value is a pointer to a pointer to its code
+	pc := unsafe.Pointer(&r.Bytes()[0])
+	fv := &pc
+	fn := *(*func() int)(unsafe.Pointer(&fv))
+
+	return fn()
+}
+
+// TestRegionJIT writes a function to a JIT region and calls it, with the
+// result register of the internal ABI.
+func TestRegionJIT(t *testing.T) {
+	code, ok := code42[runtime.GOARCH]
+	if !ok {
+		t.Skip("no code for", runtime.GOARCH)
+	}
+
+	// the protection of a JIT region of Apple silicon is per thread
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	r := newRegion(t, len(code), &sys.RegionOptions{GuardPages: 1, JIT: true})
+	copy(r.Bytes(), code)
+	if err := r.MakeExecutable(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := callRegion(r); got != 42 {
+		t.Fatalf("fn() = %d, want 42", got)
+	}
+}
+
+// TestRegionRewrite rewrites the code of a region made executable before,
+// which must not run the stale instructions of the first code.
+func TestRegionRewrite(t *testing.T) {
+	code, ok := code42[runtime.GOARCH]
+	if !ok {
+		t.Skip("no code for", runtime.GOARCH)
+	}
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	r := newRegion(t, len(code), &sys.RegionOptions{JIT: true})
+	for i, tt := range []struct {
+		code []byte
+		want int
+	}{
+		{code, 42},
+		{code7[runtime.GOARCH], 7},
+		{code, 42},
+	} {
+		if i > 0 {
+			if err := r.MakeWritable(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		copy(r.Bytes(), tt.code)
+		if err := r.MakeExecutable(); err != nil {
+			t.Fatal(err)
+		}
+		if got := callRegion(r); got != tt.want {
+			t.Fatalf("fn() = %d after %d writes, want %d", got, i+1, tt.want)
+		}
+	}
+}
+
+func TestMmap(t *testing.T) {
+	page := sys.PageSize()
+	b, err := sys.Mmap(0, 2*page, sys.VMProtRead|sys.VMProtWrite, sys.MapPrivate|sys.MapAnon, -1, 0)
+	if errors.Is(err, syscall.ENOSYS) {
+		t.Skip("Mmap is not implemented on", runtime.GOOS+"/"+runtime.GOARCH)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b) != 2*page {
+		t.Fatalf("len = %d, want %d", len(b), 2*page)
+	}
+	b[0], b[len(b)-1] = 1, 2
+
+	if err := sys.Mprotect(b[page:], sys.VMProtRead); err != nil {
+		t.Fatal(err)
+	}
+	if !faults(func() { b[len(b)-1] = 3 }) {
+		t.Error("write to a read-only page did not fault")
+	}
+	if b[len(b)-1] != 2 {
+		t.Errorf("b[%d] = %d, want 2", len(b)-1, b[len(b)-1])
+	}
+	if err := sys.Madvise(b, sys.MadvDontNeed); err != nil {
+		t.Error(err)
+	}
+	if err := sys.Munmap(b); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := sys.Mmap(0, 0, sys.VMProtRead, sys.MapPrivate|sys.MapAnon, -1, 0); err == nil {
+		t.Error("Mmap of 0 bytes succeeded")
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys
+
+import "errors"
+
+// ErrUnavailable is matched by the errors of the wrappers of the weak
+// symbols missing at runtime, a *UnavailableError.
+var ErrUnavailable = errors.New("sys: symbol unavailable")
+
+// UnavailableError is returned by the wrapper of a WeakSymbol missing at
+// runtime, such as a function of a later macOS release.
+type UnavailableError struct {
+	Library string
+	Name    string
+}
+
+// Error implements error.
+func (e *UnavailableError) Error() string {
+	return "sys: " + e.Name + " is unavailable in " + e.Library
+}
+
+// Is reports whether target is ErrUnavailable.
+func (e *UnavailableError) Is(target error) bool {
+	return target == ErrUnavailable
+}
+
+// WeakSymbol is an optional symbol of a library, which may be missing at
+// runtime, like the functions of the macOS releases later than the minimum
+// one. Importing such a symbol with //go:cgo_import_dynamic makes dyld fail
+// the launch of the program on the releases without it.
+//
+// A binding declares the symbol in a package variable, resolved at the
+// initialization of the package, and its wrapper returns the error of the
+// symbol rather than calling it when it is missing:
+//
+//	var libc_foo = sys.NewWeakSymbol("/usr/lib/libSystem.B.dylib", "foo")
+//
+//	func Foo(x int) error {
+//		fn, ok := libc_foo.Addr()
+//		if !ok {
+//			return libc_foo.Err()
+//		}
+//		_, _, errno := sys.Ccall(fn, uintptr(x), 0, 0)
+//		...
+//	}
+//
+// The callers fall back to an older API on errors matching ErrUnavailable.
+type WeakSymbol struct {
+	Library string // install name, or the path or soname of a shared object
+	Name    string // C name, such as "foo"
+
+	addr uintptr
+}
+
+// NewWeakSymbol returns the WeakSymbol name of library, resolved.
+//
+// On darwin, the library is loaded like by dlopen, and the symbol looked up
+// like by dlsym. On linux, the library must be loaded already, by the
+// dynamic linker of a program built with cgo, and the symbol is looked up in
+// its dynamic symbol table. The symbols are missing on the other platforms.
+func NewWeakSymbol(library, name string) *WeakSymbol {
+	s := &WeakSymbol{Library: library, Name: name}
+	if addr, ok := resolveSymbol(library, name); ok {
+		s.addr = addr
+	}
+
+	return s
+}
+
+// Addr returns the address of the symbol, and whether it was found.
+func (s *WeakSymbol) Addr() (uintptr, bool) {
+	return s.addr, s.addr != 0
+}
+
+// Err returns an *UnavailableError if the symbol was not found, and nil
+// otherwise.
+func (s *WeakSymbol) Err() error {
+	if s.addr != 0 {
+		return nil
+	}
+
+	return &UnavailableError{Library: s.Library, Name: s.Name}
+}
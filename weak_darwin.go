@@ -0,0 +1,55 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build darwin && (amd64 || arm64) && gc
+// +build darwin
+// +build amd64 arm64
+// +build gc
+
+package sys
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+// list of the dlopen modes of dlfcn.h.
+const (
+	rtldLazy  = 0x1
+	rtldLocal = 0x4
+)
+
+// dlopen and dlsym are in every macOS release, and imported by the
+// trampolines of weak_darwin.s.
+
+//go:cgo_import_dynamic libc_dlopen dlopen "/usr/lib/libSystem.B.dylib"
+//go:cgo_import_dynamic libc_dlsym dlsym "/usr/lib/libSystem.B.dylib"
+
+// the addresses of the trampolines, set by weak_darwin.s.
+var (
+	dlopenTrampolineAddr uintptr
+	dlsymTrampolineAddr  uintptr
+)
+
+//go:linkname dlcall syscall.syscallPtr
+//go:noescape
+func dlcall(fn, a1, a2, a3 uintptr) (r1, r2 uintptr, err Errno)
+
+// resolveSymbol loads library with dlopen, and looks name up with dlsym.
+//
+// The library is never closed, the address of the symbol being used until
+// the exit of the program.
+func resolveSymbol(library, name string) (uintptr, bool) {
+	path := append([]byte(library), 0)
+	handle, _, _ := dlcall(dlopenTrampolineAddr, uintptr(unsafe.Pointer(&path[0])), rtldLazy|rtldLocal, 0)
+	runtime.KeepAlive(path)
+	if handle == 0 {
+		return 0, false
+	}
+
+	sym := append([]byte(name), 0)
+	addr, _, _ := dlcall(dlsymTrampolineAddr, handle, uintptr(unsafe.Pointer(&sym[0])), 0)
+	runtime.KeepAlive(sym)
+
+	return addr, addr != 0
+}
@@ -0,0 +1,21 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build darwin && (amd64 || arm64) && gc
+// +build darwin
+// +build amd64 arm64
+// +build gc
+
+#include "textflag.h"
+
+TEXT dlopen_trampoline<>(SB), NOSPLIT, $0-0
+	JMP libc_dlopen(SB)
+
+GLOBL ·dlopenTrampolineAddr(SB), RODATA, $8
+DATA ·dlopenTrampolineAddr(SB)/8, $dlopen_trampoline<>(SB)
+
+TEXT dlsym_trampoline<>(SB), NOSPLIT, $0-0
+	JMP libc_dlsym(SB)
+
+GLOBL ·dlsymTrampolineAddr(SB), RODATA, $8
+DATA ·dlsymTrampolineAddr(SB)/8, $dlsym_trampoline<>(SB)
@@ -0,0 +1,28 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build darwin && (amd64 || arm64) && gc
+// +build darwin
+// +build amd64 arm64
+// +build gc
+
+package sys_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-darwin/sys"
+)
+
+func TestWeakSymbolDarwin(t *testing.T) {
+	s := sys.NewWeakSymbol("/usr/lib/libSystem.B.dylib", "getpid")
+	if _, ok := s.Addr(); !ok || s.Err() != nil {
+		t.Errorf("getpid unavailable: %v", s.Err())
+	}
+
+	s = sys.NewWeakSymbol("/usr/lib/libSystem.B.dylib", "godarwin_missing")
+	if _, ok := s.Addr(); ok || !errors.Is(s.Err(), sys.ErrUnavailable) {
+		t.Errorf("godarwin_missing available: %v", s.Err())
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys
+
+import (
+	"bufio"
+	"debug/elf"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// resolveSymbol looks name up in the dynamic symbol table of the shared
+// object library, of its path or base name, mapped in the process by the
+// dynamic linker.
+func resolveSymbol(library, name string) (uintptr, bool) {
+	path, start, ok := mappedObject(library)
+	if !ok {
+		return 0, false
+	}
+	f, err := elf.Open(path)
+	if err != nil {
+		return 0, false
+	}
+	defer f.Close()
+
+	// the mapping of the file offset 0 is the one of the first PT_LOAD
+	// segment, at its page-aligned vaddr
+	var bias uint64
+	found := false
+	for _, p := range f.Progs {
+		if p.Type == elf.PT_LOAD {
+			bias = start - p.Vaddr&^(uint64(os.Getpagesize())-1)
+			found = true
+			break
+		}
+	}
+	if !found {
+		return 0, false
+	}
+
+	syms, err := f.DynamicSymbols()
+	if err != nil {
+		return 0, false
+	}
+	for _, s := range syms {
+		typ := elf.ST_TYPE(s.Info)
+		if s.Name != name || s.Section == elf.SHN_UNDEF || typ != elf.STT_FUNC && typ != elf.STT_OBJECT {
+			continue
+		}
+		if elf.ST_BIND(s.Info) == elf.STB_LOCAL {
+			continue
+		}
+		return uintptr(bias + s.Value), true
+	}
+
+	return 0, false
+}
+
+// mappedObject returns the path and the start of the mapping of the file
+// offset 0 of the shared object library in /proc/self/maps.
+func mappedObject(library string) (string, uint64, bool) {
+	f, err := os.Open("/proc/self/maps")
+	if err != nil {
+		return "", 0, false
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		// start-end perms offset dev inode path
+		fields := strings.Fields(s.Text())
+		if len(fields) < 6 || fields[2] != "00000000" {
+			continue
+		}
+		path := strings.Join(fields[5:], " ")
+		if path != library && filepath.Base(path) != library {
+			continue
+		}
+		start, err := strconv.ParseUint(fields[0][:strings.IndexByte(fields[0], '-')], 16, 64)
+		if err != nil {
+			return "", 0, false
+		}
+		return path, start, true
+	}
+
+	return "", 0, false
+}
@@ -0,0 +1,52 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-darwin/sys/testenv"
+)
+
+// TestWeakSymbolLinux resolves the weak symbols of a shared library built
+// with the C compiler, in testdata/weakprog.
+func TestWeakSymbolLinux(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	cc, err := exec.LookPath("cc")
+	if err != nil {
+		t.Skip("skipping test: no C compiler")
+	}
+	if out, err := exec.Command(testenv.GoToolPath(t), "env", "CGO_ENABLED").Output(); err != nil || strings.TrimSpace(string(out)) != "1" {
+		t.Skip("skipping test: cgo disabled")
+	}
+
+	dir := t.TempDir()
+	lib := filepath.Join(dir, "libweak.so")
+	if out, err := exec.Command(cc, "-shared", "-fPIC", "-o", lib, "testdata/weakprog/lib/libweak.c").CombinedOutput(); err != nil {
+		t.Fatalf("building libweak.so: %v\n%s", err, out)
+	}
+	exe := filepath.Join(dir, "weakprog")
+	cmd := testenv.CleanCmdEnv(exec.Command(testenv.GoToolPath(t), "build", "-o", exe, "."))
+	cmd.Dir = "testdata/weakprog"
+	cmd.Env = append(cmd.Env, "CGO_LDFLAGS=-L"+dir)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("building weakprog: %v\n%s", err, out)
+	}
+
+	cmd = exec.Command(exe)
+	cmd.Env = append(os.Environ(), "LD_LIBRARY_PATH="+dir)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("weakprog: %v\n%s", err, out)
+	}
+	want := "weak_present true true <nil> 42\n" +
+		"weak_missing false true sys: weak_missing is unavailable in libweak.so\n"
+	if string(out) != want {
+		t.Errorf("weakprog:\n%s\nwant:\n%s", out, want)
+	}
+}
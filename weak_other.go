@@ -0,0 +1,12 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build !linux && !(darwin && (amd64 || arm64) && gc)
+// +build !linux
+// +build !darwin !amd64,!arm64 !gc
+
+package sys
+
+func resolveSymbol(library, name string) (uintptr, bool) {
+	return 0, false
+}
@@ -0,0 +1,33 @@
+// Copyright 2021 The Go Darwin Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sys_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-darwin/sys"
+)
+
+func TestWeakSymbolUnavailable(t *testing.T) {
+	s := sys.NewWeakSymbol("/usr/lib/libgodarwinmissing.dylib", "missing")
+	if addr, ok := s.Addr(); ok || addr != 0 {
+		t.Errorf("Addr = %#x, %v, want 0, false", addr, ok)
+	}
+
+	err := s.Err()
+	if !errors.Is(err, sys.ErrUnavailable) {
+		t.Errorf("Err = %v, want ErrUnavailable", err)
+	}
+	var ue *sys.UnavailableError
+	if !errors.As(err, &ue) || ue.Library != s.Library || ue.Name != "missing" {
+		t.Errorf("Err = %#v", err)
+	}
+	if want := "sys: missing is unavailable in /usr/lib/libgodarwinmissing.dylib"; err.Error() != want {
+		t.Errorf("Error = %q, want %q", err.Error(), want)
+	}
+	if errors.Is(&sys.UnavailableError{}, sys.ErrSymbolNotFound) {
+		t.Error("UnavailableError matches ErrSymbolNotFound")
+	}
+}